@@ -15,6 +15,7 @@ import (
 	adminmw "github.com/trv3wood/kuaizu-server/internal/admin/middleware"
 	"github.com/trv3wood/kuaizu-server/internal/db"
 	"github.com/trv3wood/kuaizu-server/internal/oss"
+	"github.com/trv3wood/kuaizu-server/internal/realtime"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 	"github.com/trv3wood/kuaizu-server/internal/service"
 )
@@ -55,7 +56,11 @@ func main() {
 	}
 
 	repo := repository.New(pool)
-	svc := service.New(repo, ossClient)
+
+	// Audit results are published through the broker so API server instances
+	// can push them to connected users; the admin process has no subscribers
+	hub := realtime.NewHub(realtime.NewBrokerFromEnv(repo.RealtimeEvent))
+	svc := service.New(repo, ossClient, hub)
 	server := adminhandler.NewAdminServer(repo, svc)
 
 	// Public routes
@@ -77,11 +82,41 @@ func main() {
 	adminGroup.GET("/users", server.ListUsers)
 	adminGroup.GET("/users/:id", server.GetUser)
 	adminGroup.PATCH("/users/:id/auth", server.ReviewUserAuth)
+	adminGroup.POST("/users/:id/olive-branches", server.GrantOliveBranches)
 
 	adminGroup.GET("/feedbacks", server.ListFeedbacks)
 	adminGroup.GET("/feedbacks/:id", server.GetFeedback)
 	adminGroup.PATCH("/feedbacks/:id", server.ReplyFeedback)
 
+	adminGroup.GET("/refunds", server.ListRefunds)
+	adminGroup.PATCH("/refunds/:id", server.ReviewRefund)
+	adminGroup.POST("/orders/:id/refund", server.RefundOrder)
+
+	adminGroup.GET("/message-outbox", server.ListMessageOutbox)
+	adminGroup.POST("/message-outbox/:id/replay", server.ReplayMessageOutbox)
+
+	adminGroup.GET("/image-audits", server.ListImageAudits)
+	adminGroup.PATCH("/image-audits/:id", server.ReviewImageAudit)
+
+	adminGroup.GET("/email-templates", server.ListEmailTemplates)
+	adminGroup.POST("/email-templates", server.CreateEmailTemplate)
+	adminGroup.GET("/email-templates/:id", server.GetEmailTemplate)
+	adminGroup.PUT("/email-templates/:id", server.UpdateEmailTemplate)
+	adminGroup.DELETE("/email-templates/:id", server.DeleteEmailTemplate)
+	adminGroup.POST("/email-templates/:id/preview", server.PreviewEmailTemplate)
+
+	adminGroup.GET("/skill-categories", server.ListSkillCategories)
+	adminGroup.POST("/skill-categories", server.CreateSkillCategory)
+	adminGroup.PUT("/skill-categories/:id", server.UpdateSkillCategory)
+	adminGroup.GET("/skill-tags", server.ListSkillTags)
+	adminGroup.POST("/skill-tags", server.CreateSkillTag)
+	adminGroup.POST("/skill-tags/relink", server.RelinkSkillTags)
+	adminGroup.GET("/skill-tags/:id", server.GetSkillTag)
+	adminGroup.PUT("/skill-tags/:id", server.UpdateSkillTag)
+	adminGroup.POST("/skill-tags/:id/merge", server.MergeSkillTag)
+
+	adminGroup.POST("/search/rebuild", server.RebuildSearchIndex)
+
 	port := os.Getenv("ADMIN_PORT")
 	if port == "" {
 		port = "8081"
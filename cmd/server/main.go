@@ -12,9 +12,11 @@ import (
 	"github.com/trv3wood/kuaizu-server/api"
 	"github.com/trv3wood/kuaizu-server/cmd"
 	"github.com/trv3wood/kuaizu-server/internal/db"
+	"github.com/trv3wood/kuaizu-server/internal/email"
 	"github.com/trv3wood/kuaizu-server/internal/handler"
 	"github.com/trv3wood/kuaizu-server/internal/middleware"
 	"github.com/trv3wood/kuaizu-server/internal/oss"
+	"github.com/trv3wood/kuaizu-server/internal/realtime"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 	"github.com/trv3wood/kuaizu-server/internal/service"
 )
@@ -63,8 +65,39 @@ func main() {
 
 	// Initialize repository, service, and handler
 	repo := repository.New(pool)
-	svc := service.New(repo, ossClient)
-	server := handler.NewServer(repo, svc)
+
+	// Realtime hub pushes new notifications to connected users; the broker
+	// fans events out across server instances and the admin process
+	hub := realtime.NewHub(realtime.NewBrokerFromEnv(repo.RealtimeEvent))
+	if err := hub.Start(ctx); err != nil {
+		log.Fatalf("Failed to start realtime hub: %v", err)
+	}
+
+	svc := service.New(repo, ossClient, hub)
+	server := handler.NewServer(repo, svc, hub)
+
+	// Start email task worker (promotion emails are queued in email_task)
+	emailWorker, err := email.NewWorkerFromEnv(ctx, repo.EmailProvider, repo.EmailTask, repo.EmailPromotion, repo.Project, repo.User, repo.EmailTemplate)
+	if err != nil {
+		log.Printf("Warning: email worker disabled: %v", err)
+	} else {
+		go emailWorker.Run(ctx)
+	}
+
+	// Start unpaid order expiry scheduler
+	go service.NewOrderExpiryScheduler(repo, svc.Payment).Run(ctx)
+
+	// Start refund reconcile scheduler (refunds whose outcome is unknown)
+	go service.NewRefundReconcileScheduler(svc.Refund).Run(ctx)
+
+	// Start expired project promotion scheduler
+	go service.NewProjectPromotionScheduler(repo).Run(ctx)
+
+	// Start subscribe message dispatcher (notifications are queued in message_outbox)
+	go service.NewMessageDispatcher(repo.MessageOutbox, svc.Message).Run(ctx)
+
+	// Start pending olive branch expiry scheduler
+	go service.NewOliveBranchExpiryScheduler(repo, hub).Run(ctx)
 
 	// Register API routes with /api/v2 prefix
 	apiGroup := e.Group("/api/v2")
@@ -77,11 +110,13 @@ func main() {
 
 		// Public endpoints that don't require authentication
 		publicEndpoints := []string{
-			"/api/v2/auth/login/wechat",    // WeChat login
-			"/api/v2/auth/register/phone",  // WeChat phone registration
-			"/api/v2/dictionaries/schools", // School list
-			"/api/v2/dictionaries/majors",  // Major list
-			"/api/v2/email/unsubscribe",    // Email unsubscribe
+			"/api/v2/auth/login/wechat",             // WeChat login
+			"/api/v2/auth/register/phone",           // WeChat phone registration
+			"/api/v2/dictionaries/schools",          // School list
+			"/api/v2/dictionaries/majors",           // Major list
+			"/api/v2/dictionaries/skill-tags",       // Skill tag autocomplete
+			"/api/v2/dictionaries/skill-categories", // Skill categories with tags
+			"/api/v2/email/unsubscribe",             // Email unsubscribe
 		}
 
 		// Check exact matches
@@ -101,6 +136,10 @@ func main() {
 			if path == "/api/v2/talent-profiles" {
 				return true
 			}
+			// /api/v2/projects/:id/members - roster (public)
+			if path == "/api/v2/projects/:id/members" {
+				return true
+			}
 		}
 
 		return false
@@ -109,8 +148,13 @@ func main() {
 
 	api.RegisterHandlers(apiGroup, server)
 
-	// WeChat Pay callback (no auth required)
+	// WeChat Pay callbacks (no auth required)
 	e.POST("/api/v2/payment/wechat/notify", server.WechatPayCallback)
+	e.POST("/api/v2/payment/wechat/refund-notify", server.WechatRefundCallback)
+
+	// WeChat message push, e.g. media check results (no auth required)
+	e.GET("/api/v2/wechat/message", server.WechatMessagePush)
+	e.POST("/api/v2/wechat/message", server.WechatMessagePush)
 
 	// Health check endpoint
 	e.GET("/health", func(c echo.Context) error {
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	adminvo "github.com/trv3wood/kuaizu-server/internal/admin/vo"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/response"
+	"github.com/trv3wood/kuaizu-server/internal/service"
+)
+
+// ListEmailTemplates handles GET /admin/email-templates
+func (s *AdminServer) ListEmailTemplates(ctx echo.Context) error {
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	size, _ := strconv.Atoi(ctx.QueryParam("size"))
+
+	params := repository.EmailTemplateListParams{
+		Page: page,
+		Size: size,
+	}
+
+	if v := ctx.QueryParam("isActive"); v != "" {
+		isActive, err := strconv.ParseBool(v)
+		if err != nil {
+			return response.BadRequest(ctx, "invalid isActive")
+		}
+		params.IsActive = &isActive
+	}
+
+	if v := ctx.QueryParam("keyword"); v != "" {
+		params.Keyword = &v
+	}
+
+	result, err := s.svc.EmailTemplate.ListTemplates(ctx.Request().Context(), params)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]adminvo.AdminEmailTemplateVO, len(result.List))
+	for i := range result.List {
+		list[i] = *adminvo.NewAdminEmailTemplateVO(&result.List[i])
+	}
+
+	return response.Success(ctx, map[string]interface{}{
+		"list":  list,
+		"total": result.Total,
+		"page":  result.Page,
+		"size":  result.Size,
+	})
+}
+
+// GetEmailTemplate handles GET /admin/email-templates/:id
+func (s *AdminServer) GetEmailTemplate(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid template id")
+	}
+
+	t, err := s.svc.EmailTemplate.GetTemplate(ctx.Request().Context(), id)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminEmailTemplateVO(t))
+}
+
+type emailTemplateRequest struct {
+	TemplateCode string  `json:"templateCode"`
+	TemplateName string  `json:"templateName"`
+	Subject      string  `json:"subject"`
+	HTMLContent  string  `json:"htmlContent"`
+	TextContent  *string `json:"textContent"`
+	Description  *string `json:"description"`
+	IsActive     *bool   `json:"isActive"`
+}
+
+func (r emailTemplateRequest) toInput() service.EmailTemplateInput {
+	return service.EmailTemplateInput{
+		TemplateCode: r.TemplateCode,
+		TemplateName: r.TemplateName,
+		Subject:      r.Subject,
+		HTMLContent:  r.HTMLContent,
+		TextContent:  r.TextContent,
+		Description:  r.Description,
+		IsActive:     r.IsActive,
+	}
+}
+
+// CreateEmailTemplate handles POST /admin/email-templates
+func (s *AdminServer) CreateEmailTemplate(ctx echo.Context) error {
+	var req emailTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	t, err := s.svc.EmailTemplate.CreateTemplate(ctx.Request().Context(), req.toInput())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminEmailTemplateVO(t))
+}
+
+// UpdateEmailTemplate handles PUT /admin/email-templates/:id
+func (s *AdminServer) UpdateEmailTemplate(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid template id")
+	}
+
+	var req emailTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	t, err := s.svc.EmailTemplate.UpdateTemplate(ctx.Request().Context(), id, req.toInput())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminEmailTemplateVO(t))
+}
+
+// DeleteEmailTemplate handles DELETE /admin/email-templates/:id
+func (s *AdminServer) DeleteEmailTemplate(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid template id")
+	}
+
+	if err := s.svc.EmailTemplate.DeleteTemplate(ctx.Request().Context(), id); err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.SuccessMessage(ctx, "操作成功")
+}
+
+type previewEmailTemplateRequest struct {
+	Vars map[string]interface{} `json:"vars"`
+}
+
+// PreviewEmailTemplate handles POST /admin/email-templates/:id/preview
+func (s *AdminServer) PreviewEmailTemplate(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid template id")
+	}
+
+	var req previewEmailTemplateRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	rendered, err := s.svc.EmailTemplate.PreviewTemplate(ctx.Request().Context(), id, req.Vars)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, map[string]interface{}{
+		"subject":     rendered.Subject,
+		"htmlContent": rendered.HTML,
+		"textContent": rendered.Text,
+	})
+}
@@ -80,7 +80,8 @@ func (s *AdminServer) ReplyFeedback(ctx echo.Context) error {
 		return response.BadRequest(ctx, "adminReply is required")
 	}
 
-	if err := s.svc.Feedback.ReplyFeedback(ctx.Request().Context(), id, req.AdminReply); err != nil {
+	adminID, _ := ctx.Get("adminID").(int)
+	if err := s.svc.Feedback.ReplyFeedback(ctx.Request().Context(), adminID, id, req.AdminReply); err != nil {
 		return mapServiceError(ctx, err)
 	}
 
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	adminvo "github.com/trv3wood/kuaizu-server/internal/admin/vo"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/response"
+)
+
+// ListImageAudits handles GET /admin/image-audits
+// 默认返回待人工复审的图片
+func (s *AdminServer) ListImageAudits(ctx echo.Context) error {
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	size, _ := strconv.Atoi(ctx.QueryParam("size"))
+
+	status := models.ImageAuditStatusFlagged
+	if v := ctx.QueryParam("status"); v != "" {
+		var err error
+		status, err = strconv.Atoi(v)
+		if err != nil {
+			return response.BadRequest(ctx, "invalid status")
+		}
+	}
+
+	params := repository.ImageAuditListParams{
+		Page:   page,
+		Size:   size,
+		Status: &status,
+	}
+
+	if v := ctx.QueryParam("bizType"); v != "" {
+		params.BizType = &v
+	}
+
+	result, err := s.svc.ImageAudit.ListAudits(ctx.Request().Context(), params)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]adminvo.AdminImageAuditVO, len(result.List))
+	for i := range result.List {
+		list[i] = *adminvo.NewAdminImageAuditVO(&result.List[i])
+	}
+
+	return response.Success(ctx, map[string]interface{}{
+		"list":  list,
+		"total": result.Total,
+		"page":  result.Page,
+		"size":  result.Size,
+	})
+}
+
+type reviewImageAuditRequest struct {
+	Approve *bool `json:"approve"`
+}
+
+// ReviewImageAudit handles PATCH /admin/image-audits/:id
+func (s *AdminServer) ReviewImageAudit(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.BadRequest(ctx, "invalid image audit id")
+	}
+
+	var req reviewImageAuditRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	if req.Approve == nil {
+		return response.BadRequest(ctx, "approve is required")
+	}
+
+	audit, err := s.svc.ImageAudit.Review(ctx.Request().Context(), id, *req.Approve)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminImageAuditVO(audit))
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	adminvo "github.com/trv3wood/kuaizu-server/internal/admin/vo"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/response"
+)
+
+// ListMessageOutbox handles GET /admin/message-outbox
+// 默认返回发送失败的订阅消息
+func (s *AdminServer) ListMessageOutbox(ctx echo.Context) error {
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	size, _ := strconv.Atoi(ctx.QueryParam("size"))
+
+	status := models.MessageOutboxStatusFailed
+	if v := ctx.QueryParam("status"); v != "" {
+		var err error
+		status, err = strconv.Atoi(v)
+		if err != nil {
+			return response.BadRequest(ctx, "invalid status")
+		}
+	}
+
+	params := repository.MessageOutboxListParams{
+		Page:   page,
+		Size:   size,
+		Status: &status,
+	}
+
+	if v := ctx.QueryParam("userId"); v != "" {
+		userID, err := strconv.Atoi(v)
+		if err != nil {
+			return response.BadRequest(ctx, "invalid userId")
+		}
+		params.UserID = &userID
+	}
+
+	if v := ctx.QueryParam("bizKey"); v != "" {
+		params.BizKey = &v
+	}
+
+	result, err := s.svc.Message.ListOutbox(ctx.Request().Context(), params)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]adminvo.AdminMessageOutboxVO, len(result.List))
+	for i := range result.List {
+		list[i] = *adminvo.NewAdminMessageOutboxVO(&result.List[i])
+	}
+
+	return response.Success(ctx, map[string]interface{}{
+		"list":  list,
+		"total": result.Total,
+		"page":  result.Page,
+		"size":  result.Size,
+	})
+}
+
+// ReplayMessageOutbox handles POST /admin/message-outbox/:id/replay
+func (s *AdminServer) ReplayMessageOutbox(ctx echo.Context) error {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return response.BadRequest(ctx, "invalid message id")
+	}
+
+	msg, err := s.svc.Message.ReplayOutbox(ctx.Request().Context(), id)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminMessageOutboxVO(msg))
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	adminvo "github.com/trv3wood/kuaizu-server/internal/admin/vo"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/response"
+)
+
+// ListRefunds handles GET /admin/refunds
+func (s *AdminServer) ListRefunds(ctx echo.Context) error {
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	size, _ := strconv.Atoi(ctx.QueryParam("size"))
+
+	params := repository.OrderRefundListParams{
+		Page: page,
+		Size: size,
+	}
+
+	if v := ctx.QueryParam("status"); v != "" {
+		status, err := strconv.Atoi(v)
+		if err != nil {
+			return response.BadRequest(ctx, "invalid status")
+		}
+		params.Status = &status
+	}
+
+	if v := ctx.QueryParam("userId"); v != "" {
+		userID, err := strconv.Atoi(v)
+		if err != nil {
+			return response.BadRequest(ctx, "invalid userId")
+		}
+		params.UserID = &userID
+	}
+
+	result, err := s.svc.Refund.ListRefunds(ctx.Request().Context(), params)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]adminvo.AdminRefundVO, len(result.List))
+	for i := range result.List {
+		list[i] = *adminvo.NewAdminRefundVO(&result.List[i])
+	}
+
+	return response.Success(ctx, map[string]interface{}{
+		"list":  list,
+		"total": result.Total,
+		"page":  result.Page,
+		"size":  result.Size,
+	})
+}
+
+type reviewRefundRequest struct {
+	Approve *bool  `json:"approve"`
+	Note    string `json:"note"`
+}
+
+// ReviewRefund handles PATCH /admin/refunds/:id
+func (s *AdminServer) ReviewRefund(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid refund id")
+	}
+
+	var req reviewRefundRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	if req.Approve == nil {
+		return response.BadRequest(ctx, "approve is required")
+	}
+
+	refund, err := s.svc.Refund.ReviewRefund(ctx.Request().Context(), id, *req.Approve, strings.TrimSpace(req.Note))
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminRefundVO(refund))
+}
+
+type refundOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+// RefundOrder handles POST /admin/orders/:id/refund
+func (s *AdminServer) RefundOrder(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid order id")
+	}
+
+	var req refundOrderRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	refund, err := s.svc.Refund.AdminRefund(ctx.Request().Context(), id, strings.TrimSpace(req.Reason))
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminRefundVO(refund))
+}
@@ -0,0 +1,19 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/trv3wood/kuaizu-server/internal/response"
+)
+
+// RebuildSearchIndex handles POST /admin/search/rebuild
+func (s *AdminServer) RebuildSearchIndex(ctx echo.Context) error {
+	result, err := s.svc.Search.Rebuild(ctx.Request().Context())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, map[string]interface{}{
+		"projects": result.Projects,
+		"talents":  result.Talents,
+	})
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	adminvo "github.com/trv3wood/kuaizu-server/internal/admin/vo"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/response"
+	"github.com/trv3wood/kuaizu-server/internal/service"
+)
+
+// ListSkillCategories handles GET /admin/skill-categories
+func (s *AdminServer) ListSkillCategories(ctx echo.Context) error {
+	categories, err := s.svc.SkillTag.ListCategories(ctx.Request().Context())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]adminvo.AdminSkillCategoryVO, len(categories))
+	for i := range categories {
+		list[i] = *adminvo.NewAdminSkillCategoryVO(&categories[i])
+	}
+
+	return response.Success(ctx, list)
+}
+
+type skillCategoryRequest struct {
+	Name      string `json:"name"`
+	SortOrder int    `json:"sortOrder"`
+}
+
+func (r skillCategoryRequest) toInput() service.SkillCategoryInput {
+	return service.SkillCategoryInput{Name: r.Name, SortOrder: r.SortOrder}
+}
+
+// CreateSkillCategory handles POST /admin/skill-categories
+func (s *AdminServer) CreateSkillCategory(ctx echo.Context) error {
+	var req skillCategoryRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	c, err := s.svc.SkillTag.CreateCategory(ctx.Request().Context(), req.toInput())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminSkillCategoryVO(c))
+}
+
+// UpdateSkillCategory handles PUT /admin/skill-categories/:id
+func (s *AdminServer) UpdateSkillCategory(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid category id")
+	}
+
+	var req skillCategoryRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	c, err := s.svc.SkillTag.UpdateCategory(ctx.Request().Context(), id, req.toInput())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminSkillCategoryVO(c))
+}
+
+// ListSkillTags handles GET /admin/skill-tags
+func (s *AdminServer) ListSkillTags(ctx echo.Context) error {
+	page, _ := strconv.Atoi(ctx.QueryParam("page"))
+	size, _ := strconv.Atoi(ctx.QueryParam("size"))
+
+	params := repository.SkillTagListParams{
+		Page: page,
+		Size: size,
+	}
+
+	if v := ctx.QueryParam("categoryId"); v != "" {
+		categoryID, err := strconv.Atoi(v)
+		if err != nil {
+			return response.BadRequest(ctx, "invalid categoryId")
+		}
+		params.CategoryID = &categoryID
+	}
+
+	if v := ctx.QueryParam("status"); v != "" {
+		status, err := strconv.Atoi(v)
+		if err != nil {
+			return response.BadRequest(ctx, "invalid status")
+		}
+		params.Status = &status
+	}
+
+	if v := ctx.QueryParam("keyword"); v != "" {
+		params.Keyword = &v
+	}
+
+	result, err := s.svc.SkillTag.ListTags(ctx.Request().Context(), params)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]adminvo.AdminSkillTagVO, len(result.List))
+	for i := range result.List {
+		list[i] = *adminvo.NewAdminSkillTagVO(&result.List[i])
+	}
+
+	return response.Success(ctx, map[string]interface{}{
+		"list":  list,
+		"total": result.Total,
+		"page":  result.Page,
+		"size":  result.Size,
+	})
+}
+
+// GetSkillTag handles GET /admin/skill-tags/:id
+func (s *AdminServer) GetSkillTag(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid tag id")
+	}
+
+	t, err := s.svc.SkillTag.GetTag(ctx.Request().Context(), id)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminSkillTagVO(t))
+}
+
+type skillTagRequest struct {
+	CategoryID int      `json:"categoryId"`
+	Name       string   `json:"name"`
+	Status     *int     `json:"status"`
+	Synonyms   []string `json:"synonyms"`
+}
+
+func (r skillTagRequest) toInput() service.SkillTagInput {
+	return service.SkillTagInput{
+		CategoryID: r.CategoryID,
+		Name:       r.Name,
+		Status:     r.Status,
+		Synonyms:   r.Synonyms,
+	}
+}
+
+// CreateSkillTag handles POST /admin/skill-tags
+func (s *AdminServer) CreateSkillTag(ctx echo.Context) error {
+	var req skillTagRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	t, err := s.svc.SkillTag.CreateTag(ctx.Request().Context(), req.toInput())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminSkillTagVO(t))
+}
+
+// UpdateSkillTag handles PUT /admin/skill-tags/:id
+func (s *AdminServer) UpdateSkillTag(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid tag id")
+	}
+
+	var req skillTagRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	t, err := s.svc.SkillTag.UpdateTag(ctx.Request().Context(), id, req.toInput())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminSkillTagVO(t))
+}
+
+type mergeSkillTagRequest struct {
+	TargetID int `json:"targetId"`
+}
+
+// MergeSkillTag handles POST /admin/skill-tags/:id/merge
+func (s *AdminServer) MergeSkillTag(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid tag id")
+	}
+
+	var req mergeSkillTagRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+	if req.TargetID <= 0 {
+		return response.BadRequest(ctx, "invalid targetId")
+	}
+
+	t, err := s.svc.SkillTag.MergeTags(ctx.Request().Context(), id, req.TargetID)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminSkillTagVO(t))
+}
+
+// RelinkSkillTags handles POST /admin/skill-tags/relink
+func (s *AdminServer) RelinkSkillTags(ctx echo.Context) error {
+	result, err := s.svc.SkillTag.Relink(ctx.Request().Context())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, map[string]interface{}{
+		"projects": result.Projects,
+		"talents":  result.Talents,
+	})
+}
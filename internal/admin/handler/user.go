@@ -2,6 +2,7 @@ package handler
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	adminvo "github.com/trv3wood/kuaizu-server/internal/admin/vo"
@@ -104,3 +105,28 @@ func (s *AdminServer) ReviewUserAuth(ctx echo.Context) error {
 
 	return response.SuccessMessage(ctx, "操作成功")
 }
+
+type grantOliveBranchRequest struct {
+	Amount int    `json:"amount"`
+	Remark string `json:"remark"`
+}
+
+// GrantOliveBranches handles POST /admin/users/:id/olive-branches
+func (s *AdminServer) GrantOliveBranches(ctx echo.Context) error {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		return response.BadRequest(ctx, "invalid user id")
+	}
+
+	var req grantOliveBranchRequest
+	if err := ctx.Bind(&req); err != nil {
+		return response.BadRequest(ctx, "invalid request body")
+	}
+
+	user, err := s.svc.OliveBranch.AdminGrant(ctx.Request().Context(), id, req.Amount, strings.TrimSpace(req.Remark))
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return response.Success(ctx, adminvo.NewAdminUserVO(user))
+}
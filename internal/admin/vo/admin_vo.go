@@ -33,38 +33,137 @@ type AdminProjectVO struct {
 
 // AdminUserVO is the admin-facing user response model.
 type AdminUserVO struct {
-	ID             int        `json:"id"`
-	OpenID         string     `json:"openId"`
-	Nickname       *string    `json:"nickname"`
-	Phone          *string    `json:"phone"`
-	Email          *string    `json:"email"`
-	SchoolID       *int       `json:"schoolId"`
-	MajorID        *int       `json:"majorId"`
-	Grade          *int       `json:"grade"`
-	LastActiveDate *time.Time `json:"lastActiveDate"`
-	AuthStatus     *int       `json:"authStatus"`
-	AuthImgUrl     *string    `json:"authImgUrl"`
-	EmailOptOut    *bool      `json:"emailOptOut"`
-	CreatedAt      *time.Time `json:"createdAt"`
-	SchoolName     *string    `json:"schoolName"`
-	SchoolCode     *string    `json:"schoolCode"`
-	MajorName      *string    `json:"majorName"`
-	ClassID        *int       `json:"classId"`
+	ID                  int        `json:"id"`
+	OpenID              string     `json:"openId"`
+	Nickname            *string    `json:"nickname"`
+	Phone               *string    `json:"phone"`
+	Email               *string    `json:"email"`
+	SchoolID            *int       `json:"schoolId"`
+	MajorID             *int       `json:"majorId"`
+	Grade               *int       `json:"grade"`
+	OliveBranchCount    *int       `json:"oliveBranchCount"`
+	FreeBranchUsedToday *int       `json:"freeBranchUsedToday"`
+	LastActiveDate      *time.Time `json:"lastActiveDate"`
+	AuthStatus          *int       `json:"authStatus"`
+	AuthImgUrl          *string    `json:"authImgUrl"`
+	EmailOptOut         *bool      `json:"emailOptOut"`
+	CreatedAt           *time.Time `json:"createdAt"`
+	SchoolName          *string    `json:"schoolName"`
+	SchoolCode          *string    `json:"schoolCode"`
+	MajorName           *string    `json:"majorName"`
+	ClassID             *int       `json:"classId"`
 }
 
 // AdminFeedbackVO is the admin-facing feedback response model.
 type AdminFeedbackVO struct {
-	ID           int       `json:"id"`
+	ID           int                      `json:"id"`
+	UserID       int                      `json:"userId"`
+	Content      string                   `json:"content"`
+	Images       []string                 `json:"images"`
+	Status       int                      `json:"status"`
+	Messages     []AdminFeedbackMessageVO `json:"messages,omitempty"`
+	CreatedAt    time.Time                `json:"createdAt"`
+	UpdatedAt    time.Time                `json:"updatedAt"`
+	UserNickname *string                  `json:"userNickname"`
+}
+
+// AdminFeedbackMessageVO is the admin-facing feedback message response model.
+type AdminFeedbackMessageVO struct {
+	ID         int       `json:"id"`
+	SenderType string    `json:"senderType"`
+	SenderID   *int      `json:"senderId"`
+	Content    string    `json:"content"`
+	Images     []string  `json:"images"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// AdminImageAuditVO is the admin-facing image audit response model.
+type AdminImageAuditVO struct {
+	ID           int64     `json:"id"`
 	UserID       int       `json:"userId"`
-	Content      string    `json:"content"`
-	ContactImage *string   `json:"contactImage"`
+	BizType      string    `json:"bizType"`
+	ImageURL     string    `json:"imageUrl"`
 	Status       int       `json:"status"`
-	AdminReply   *string   `json:"adminReply"`
+	Source       *string   `json:"source"`
+	Suggest      *string   `json:"suggest"`
+	Label        *int      `json:"label"`
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
 	UserNickname *string   `json:"userNickname"`
 }
 
+// AdminMessageOutboxVO is the admin-facing subscribe message outbox response model.
+type AdminMessageOutboxVO struct {
+	ID           int64      `json:"id"`
+	UserID       int        `json:"userId"`
+	BizKey       string     `json:"bizKey"`
+	Payload      string     `json:"payload"`
+	Status       int        `json:"status"`
+	RetryCount   int        `json:"retryCount"`
+	ErrorMsg     *string    `json:"errorMsg"`
+	NextRetryAt  *time.Time `json:"nextRetryAt"`
+	SentAt       *time.Time `json:"sentAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	UserNickname *string    `json:"userNickname"`
+}
+
+// AdminRefundVO is the admin-facing order refund response model.
+type AdminRefundVO struct {
+	ID           int        `json:"id"`
+	OrderID      int        `json:"orderId"`
+	UserID       int        `json:"userId"`
+	OutRefundNo  string     `json:"outRefundNo"`
+	RefundID     *string    `json:"refundId"`
+	Amount       float64    `json:"amount"`
+	Reason       *string    `json:"reason"`
+	Status       int        `json:"status"`
+	Source       string     `json:"source"`
+	Note         *string    `json:"note"`
+	SuccessTime  *time.Time `json:"successTime"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	ProductName  *string    `json:"productName"`
+	UserNickname *string    `json:"userNickname"`
+}
+
+// AdminEmailTemplateVO is the admin-facing email template response model.
+type AdminEmailTemplateVO struct {
+	ID           int       `json:"id"`
+	TemplateCode string    `json:"templateCode"`
+	TemplateName string    `json:"templateName"`
+	Subject      string    `json:"subject"`
+	HTMLContent  *string   `json:"htmlContent"`
+	TextContent  *string   `json:"textContent"`
+	Description  *string   `json:"description"`
+	IsActive     bool      `json:"isActive"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// AdminSkillCategoryVO is the admin-facing skill category response model.
+type AdminSkillCategoryVO struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	SortOrder int       `json:"sortOrder"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// AdminSkillTagVO is the admin-facing skill tag response model.
+type AdminSkillTagVO struct {
+	ID           int       `json:"id"`
+	CategoryID   int       `json:"categoryId"`
+	CategoryName *string   `json:"categoryName"`
+	Name         string    `json:"name"`
+	Status       int       `json:"status"`
+	Synonyms     []string  `json:"synonyms"`
+	ProjectCount int       `json:"projectCount"`
+	TalentCount  int       `json:"talentCount"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
 // NewAdminProjectVO converts a Project model to AdminProjectVO.
 func NewAdminProjectVO(p *models.Project) *AdminProjectVO {
 	if p == nil {
@@ -103,22 +202,24 @@ func NewAdminUserVO(u *models.User) *AdminUserVO {
 	}
 
 	vo := AdminUserVO{
-		ID:             u.ID,
-		OpenID:         u.OpenID,
-		Nickname:       u.Nickname,
-		Phone:          u.Phone,
-		Email:          u.Email,
-		SchoolID:       u.SchoolID,
-		MajorID:        u.MajorID,
-		Grade:          u.Grade,
-		LastActiveDate: u.LastActiveDate,
-		AuthImgUrl:     ossFullURLPtr(u.AuthImgUrl),
-		EmailOptOut:    u.EmailOptOut,
-		CreatedAt:      u.CreatedAt,
-		SchoolName:     u.SchoolName,
-		SchoolCode:     u.SchoolCode,
-		MajorName:      u.MajorName,
-		ClassID:        u.ClassID,
+		ID:                  u.ID,
+		OpenID:              u.OpenID,
+		Nickname:            u.Nickname,
+		Phone:               u.Phone,
+		Email:               u.Email,
+		SchoolID:            u.SchoolID,
+		MajorID:             u.MajorID,
+		Grade:               u.Grade,
+		OliveBranchCount:    u.OliveBranchCount,
+		FreeBranchUsedToday: u.FreeBranchUsedToday,
+		LastActiveDate:      u.LastActiveDate,
+		AuthImgUrl:          ossFullURLPtr(u.AuthImgUrl),
+		EmailOptOut:         u.EmailOptOut,
+		CreatedAt:           u.CreatedAt,
+		SchoolName:          u.SchoolName,
+		SchoolCode:          u.SchoolCode,
+		MajorName:           u.MajorName,
+		ClassID:             u.ClassID,
 	}
 	if u.AuthImgUrl != nil && u.AuthStatus != nil && *u.AuthStatus == 0 {
 		vo.AuthStatus = intPtr(3) //  提交了审核材料且未认证，将状态映射为 3-审核中，方便管理员优先处理
@@ -134,20 +235,161 @@ func NewAdminFeedbackVO(f *models.Feedback) *AdminFeedbackVO {
 		return nil
 	}
 
-	return &AdminFeedbackVO{
+	vo := &AdminFeedbackVO{
 		ID:           f.ID,
 		UserID:       f.UserID,
 		Content:      f.Content,
-		ContactImage: ossFullURLPtr(f.ContactImage),
+		Images:       ossFullURLs(models.SplitImageKeys(f.ContactImage)),
 		Status:       f.Status,
-		AdminReply:   f.AdminReply,
 		CreatedAt:    f.CreatedAt,
 		UpdatedAt:    f.UpdatedAt,
 		UserNickname: f.UserNickname,
 	}
+	for _, m := range f.Messages {
+		vo.Messages = append(vo.Messages, AdminFeedbackMessageVO{
+			ID:         m.ID,
+			SenderType: m.SenderType,
+			SenderID:   m.SenderID,
+			Content:    m.Content,
+			Images:     ossFullURLs(models.SplitImageKeys(m.Images)),
+			CreatedAt:  m.CreatedAt,
+		})
+	}
+	return vo
+}
+
+// NewAdminImageAuditVO converts an ImageAudit model to AdminImageAuditVO.
+func NewAdminImageAuditVO(a *models.ImageAudit) *AdminImageAuditVO {
+	if a == nil {
+		return nil
+	}
+
+	return &AdminImageAuditVO{
+		ID:           a.ID,
+		UserID:       a.UserID,
+		BizType:      a.BizType,
+		ImageURL:     oss.FullURL(a.ImageKey),
+		Status:       a.Status,
+		Source:       a.Source,
+		Suggest:      a.Suggest,
+		Label:        a.Label,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+		UserNickname: a.UserNickname,
+	}
+}
+
+// NewAdminMessageOutboxVO converts a MessageOutbox model to AdminMessageOutboxVO.
+func NewAdminMessageOutboxVO(m *models.MessageOutbox) *AdminMessageOutboxVO {
+	if m == nil {
+		return nil
+	}
+
+	return &AdminMessageOutboxVO{
+		ID:           m.ID,
+		UserID:       m.UserID,
+		BizKey:       m.BizKey,
+		Payload:      m.Payload,
+		Status:       m.Status,
+		RetryCount:   m.RetryCount,
+		ErrorMsg:     m.ErrorMsg,
+		NextRetryAt:  m.NextRetryAt,
+		SentAt:       m.SentAt,
+		CreatedAt:    m.CreatedAt,
+		UpdatedAt:    m.UpdatedAt,
+		UserNickname: m.UserNickname,
+	}
+}
+
+// NewAdminRefundVO converts an OrderRefund model to AdminRefundVO.
+func NewAdminRefundVO(r *models.OrderRefund) *AdminRefundVO {
+	if r == nil {
+		return nil
+	}
+
+	return &AdminRefundVO{
+		ID:           r.ID,
+		OrderID:      r.OrderID,
+		UserID:       r.UserID,
+		OutRefundNo:  r.OutRefundNo,
+		RefundID:     r.RefundID,
+		Amount:       r.Amount,
+		Reason:       r.Reason,
+		Status:       r.Status,
+		Source:       r.Source,
+		Note:         r.Note,
+		SuccessTime:  r.SuccessTime,
+		CreatedAt:    r.CreatedAt,
+		UpdatedAt:    r.UpdatedAt,
+		ProductName:  r.ProductName,
+		UserNickname: r.UserNickname,
+	}
+}
+
+// NewAdminEmailTemplateVO converts an EmailTemplate model to AdminEmailTemplateVO.
+func NewAdminEmailTemplateVO(t *models.EmailTemplate) *AdminEmailTemplateVO {
+	if t == nil {
+		return nil
+	}
+
+	return &AdminEmailTemplateVO{
+		ID:           t.ID,
+		TemplateCode: t.TemplateCode,
+		TemplateName: t.TemplateName,
+		Subject:      t.Subject,
+		HTMLContent:  t.HTMLContent,
+		TextContent:  t.TextContent,
+		Description:  t.Description,
+		IsActive:     t.IsActive,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
+}
+
+// NewAdminSkillCategoryVO converts a SkillCategory model to AdminSkillCategoryVO.
+func NewAdminSkillCategoryVO(c *models.SkillCategory) *AdminSkillCategoryVO {
+	if c == nil {
+		return nil
+	}
+
+	return &AdminSkillCategoryVO{
+		ID:        c.ID,
+		Name:      c.Name,
+		SortOrder: c.SortOrder,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// NewAdminSkillTagVO converts a SkillTag model to AdminSkillTagVO.
+func NewAdminSkillTagVO(t *models.SkillTag) *AdminSkillTagVO {
+	if t == nil {
+		return nil
+	}
+
+	return &AdminSkillTagVO{
+		ID:           t.ID,
+		CategoryID:   t.CategoryID,
+		CategoryName: t.CategoryName,
+		Name:         t.Name,
+		Status:       t.Status,
+		Synonyms:     t.Synonyms,
+		ProjectCount: t.ProjectCount,
+		TalentCount:  t.TalentCount,
+		CreatedAt:    t.CreatedAt,
+		UpdatedAt:    t.UpdatedAt,
+	}
 }
 
 // ossFullURLPtr resolves a nullable relative OSS path to a full URL pointer.
+func ossFullURLs(keys []string) []string {
+	urls := make([]string, len(keys))
+	for i, key := range keys {
+		urls[i] = oss.FullURL(key)
+	}
+	return urls
+}
+
 func ossFullURLPtr(rel *string) *string {
 	if rel == nil {
 		return nil
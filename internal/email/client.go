@@ -11,6 +11,8 @@ import (
 // Client 邮件客户端接口
 type Client interface {
 	Send(to, subject, htmlBody string) error
+	// SendWithText 发送带纯文本备选正文的邮件，textBody 为空时等同于 Send
+	SendWithText(to, subject, htmlBody, textBody string) error
 }
 
 // SMTPClient SMTP邮件客户端
@@ -85,16 +87,15 @@ func NewSMTPClientFromEnv() (*SMTPClient, error) {
 
 // Send 发送邮件
 func (c *SMTPClient) Send(to, subject, htmlBody string) error {
-	// 构建邮件
-	msg := mail.NewMsg()
-	if err := msg.FromFormat(c.fromName, c.user); err != nil {
-		return fmt.Errorf("set from: %w", err)
-	}
-	if err := msg.To(to); err != nil {
-		return fmt.Errorf("set to: %w", err)
+	return c.SendWithText(to, subject, htmlBody, "")
+}
+
+// SendWithText 发送邮件，同时附带纯文本正文（multipart/alternative）
+func (c *SMTPClient) SendWithText(to, subject, htmlBody, textBody string) error {
+	msg, err := buildMessage(c.fromName, c.user, to, subject, htmlBody, textBody)
+	if err != nil {
+		return err
 	}
-	msg.Subject(subject)
-	msg.SetBodyString(mail.TypeTextHTML, htmlBody)
 
 	// 根据端口选择TLS策略
 	tlsPolicy := mail.TLSMandatory
@@ -125,3 +126,22 @@ func (c *SMTPClient) Send(to, subject, htmlBody string) error {
 
 	return nil
 }
+
+// buildMessage 构建邮件，textBody 非空时生成 multipart/alternative
+func buildMessage(fromName, from, to, subject, htmlBody, textBody string) (*mail.Msg, error) {
+	msg := mail.NewMsg()
+	if err := msg.FromFormat(fromName, from); err != nil {
+		return nil, fmt.Errorf("set from: %w", err)
+	}
+	if err := msg.To(to); err != nil {
+		return nil, fmt.Errorf("set to: %w", err)
+	}
+	msg.Subject(subject)
+	if textBody != "" {
+		msg.SetBodyString(mail.TypeTextPlain, textBody)
+		msg.AddAlternativeString(mail.TypeTextHTML, htmlBody)
+	} else {
+		msg.SetBodyString(mail.TypeTextHTML, htmlBody)
+	}
+	return msg, nil
+}
@@ -0,0 +1,131 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// providerConfig email_provider_config.config_json 的内容
+type providerConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	User     string `json:"user"`
+	Password string `json:"password"`
+	FromName string `json:"fromName"`
+	Path     string `json:"path"` // file 服务商的 mbox 文件路径
+
+	RateLimitPerMinute int `json:"rateLimitPerMinute"` // 每分钟最多发送数，0 表示不限制
+}
+
+// newProviderFromConfig 根据数据库配置创建服务商
+func newProviderFromConfig(cfg models.EmailProviderConfig) (*Provider, error) {
+	var pc providerConfig
+	if err := json.Unmarshal([]byte(cfg.ConfigJSON), &pc); err != nil {
+		return nil, fmt.Errorf("parse config_json: %w", err)
+	}
+	if pc.FromName == "" {
+		pc.FromName = "快组校园团队"
+	}
+
+	var client Client
+	switch cfg.ProviderType {
+	case models.EmailProviderSMTP, models.EmailProviderAliyun:
+		if pc.Host == "" || pc.User == "" || pc.Password == "" {
+			return nil, fmt.Errorf("host, user and password are required")
+		}
+		if pc.Port == 0 {
+			pc.Port = 465
+		}
+		client = NewSMTPClient(SMTPConfig{
+			Host:     pc.Host,
+			Port:     pc.Port,
+			User:     pc.User,
+			Password: pc.Password,
+			FromName: pc.FromName,
+		})
+	case models.EmailProviderFile:
+		if pc.Path == "" {
+			return nil, fmt.Errorf("path is required")
+		}
+		if pc.User == "" {
+			pc.User = "noreply@localhost"
+		}
+		client = NewFileClient(pc.Path, pc.FromName, pc.User)
+	default:
+		return nil, fmt.Errorf("unsupported provider type %q", cfg.ProviderType)
+	}
+
+	return NewProvider(cfg.ID, cfg.ConfigName, client, pc.RateLimitPerMinute), nil
+}
+
+// FileClient 把邮件追加写入本地 mbox 文件，不真正投递，用于测试与本地开发
+type FileClient struct {
+	path     string
+	fromName string
+	from     string
+
+	mu sync.Mutex
+}
+
+// NewFileClient 创建 mbox 文件客户端
+func NewFileClient(path, fromName, from string) *FileClient {
+	return &FileClient{path: path, fromName: fromName, from: from}
+}
+
+// Send 写入一封 HTML 邮件
+func (c *FileClient) Send(to, subject, htmlBody string) error {
+	return c.SendWithText(to, subject, htmlBody, "")
+}
+
+// SendWithText 写入一封邮件，textBody 非空时附带纯文本正文
+func (c *FileClient) SendWithText(to, subject, htmlBody, textBody string) error {
+	msg, err := buildMessage(c.fromName, c.from, to, subject, htmlBody, textBody)
+	if err != nil {
+		return err
+	}
+
+	var raw bytes.Buffer
+	if _, err := msg.WriteTo(&raw); err != nil {
+		return fmt.Errorf("render message: %w", err)
+	}
+
+	// mboxrd 格式：分隔行 + 正文中以 "From " 开头的行加 ">" 转义
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From %s %s\n", c.from, time.Now().UTC().Format(time.ANSIC))
+	scanner := bufio.NewScanner(&raw)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			buf.WriteByte('>')
+		}
+		buf.WriteString(line)
+		buf.WriteByte('\n')
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("render message: %w", err)
+	}
+	buf.WriteByte('\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open mbox: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("write mbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+const (
+	providerReloadInterval  = 5 * time.Minute // 重新读取 email_provider_config 的间隔
+	providerFailureCooldown = time.Minute     // 服务商发送失败后暂停使用的时间
+	envProviderID           = 0               // 环境变量 SMTP 服务商的 ID
+)
+
+// ErrRateLimited 服务商已达到发送频率上限
+var ErrRateLimited = errors.New("email provider rate limited")
+
+// ErrNoProvider 没有可用的邮件服务商
+var ErrNoProvider = errors.New("no email provider configured")
+
+// Delivery 一次成功发送的服务商信息
+type Delivery struct {
+	ProviderID   int
+	ProviderName string
+}
+
+// Provider 注册表中的一个邮件服务商
+type Provider struct {
+	ID   int
+	Name string
+
+	client    Client
+	rateLimit int // 每分钟最多发送数，0 表示不限制
+	window    time.Time
+	sentInWin int
+	coolUntil time.Time
+}
+
+// NewProvider 创建服务商，rateLimitPerMinute 为 0 表示不限制
+func NewProvider(id int, name string, client Client, rateLimitPerMinute int) *Provider {
+	return &Provider{ID: id, Name: name, client: client, rateLimit: rateLimitPerMinute}
+}
+
+// reserve 占用一次发送额度，超过每分钟上限时返回 false
+func (p *Provider) reserve(now time.Time) bool {
+	if p.rateLimit <= 0 {
+		return true
+	}
+	if now.Sub(p.window) >= time.Minute {
+		p.window = now
+		p.sentInWin = 0
+	}
+	if p.sentInWin >= p.rateLimit {
+		return false
+	}
+	p.sentInWin++
+	return true
+}
+
+// Registry 邮件服务商注册表
+// 按优先级依次尝试服务商：发送失败或达到频率上限时切换到下一个，
+// 失败的服务商会暂停一段时间，所有服务商都不可用时才返回错误。
+type Registry struct {
+	providerRepo repository.EmailProviderConfigRepo
+
+	mu        sync.Mutex
+	providers []*Provider
+	loadedAt  time.Time
+}
+
+// NewRegistry 使用给定的服务商创建注册表，顺序即优先级
+func NewRegistry(providers ...*Provider) *Registry {
+	return &Registry{providers: providers, loadedAt: time.Now()}
+}
+
+// NewRegistryFromDB 从 email_provider_config 创建注册表，并定期重新加载。
+// 没有启用的配置时回退到环境变量中的 SMTP 配置。
+func NewRegistryFromDB(ctx context.Context, providerRepo repository.EmailProviderConfigRepo) (*Registry, error) {
+	r := &Registry{providerRepo: providerRepo}
+	if err := r.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload 重新读取服务商配置；保留已有服务商的限流与暂停状态
+// 没有配置仓库时只使用环境变量中的 SMTP 配置。
+func (r *Registry) Reload(ctx context.Context) error {
+	var configs []models.EmailProviderConfig
+	if r.providerRepo != nil {
+		var err error
+		configs, err = r.providerRepo.ListActive(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	var providers []*Provider
+	for _, cfg := range configs {
+		p, err := newProviderFromConfig(cfg)
+		if err != nil {
+			log.Printf("[email.Registry] skip provider %d (%s): %v", cfg.ID, cfg.ConfigName, err)
+			continue
+		}
+		providers = append(providers, p)
+	}
+
+	if len(providers) == 0 {
+		client, err := NewSMTPClientFromEnv()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrNoProvider, err)
+		}
+		providers = append(providers, NewProvider(envProviderID, "env", client, 0))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := make(map[int]*Provider, len(r.providers))
+	for _, p := range r.providers {
+		previous[p.ID] = p
+	}
+	for _, p := range providers {
+		if old, ok := previous[p.ID]; ok {
+			p.window, p.sentInWin = old.window, old.sentInWin
+			p.coolUntil = old.coolUntil
+		}
+	}
+
+	r.providers = providers
+	r.loadedAt = time.Now()
+	return nil
+}
+
+// Send 发送 HTML 邮件
+func (r *Registry) Send(to, subject, htmlBody string) error {
+	_, err := r.Deliver(to, subject, htmlBody, "")
+	return err
+}
+
+// SendWithText 发送带纯文本备选正文的邮件
+func (r *Registry) SendWithText(to, subject, htmlBody, textBody string) error {
+	_, err := r.Deliver(to, subject, htmlBody, textBody)
+	return err
+}
+
+// Deliver 按优先级发送邮件，返回实际发送的服务商
+func (r *Registry) Deliver(to, subject, htmlBody, textBody string) (*Delivery, error) {
+	r.reloadIfStale()
+
+	// 正在暂停的服务商放到最后，其他服务商都失败时仍会尝试
+	now := time.Now()
+	r.mu.Lock()
+	ordered := make([]*Provider, 0, len(r.providers))
+	var cooling []*Provider
+	for _, p := range r.providers {
+		if now.Before(p.coolUntil) {
+			cooling = append(cooling, p)
+		} else {
+			ordered = append(ordered, p)
+		}
+	}
+	ordered = append(ordered, cooling...)
+	r.mu.Unlock()
+
+	if len(ordered) == 0 {
+		return nil, ErrNoProvider
+	}
+
+	var errs []error
+	for _, p := range ordered {
+		r.mu.Lock()
+		allowed := p.reserve(time.Now())
+		r.mu.Unlock()
+		if !allowed {
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name, ErrRateLimited))
+			continue
+		}
+
+		err := p.client.SendWithText(to, subject, htmlBody, textBody)
+
+		r.mu.Lock()
+		if err != nil {
+			p.coolUntil = time.Now().Add(providerFailureCooldown)
+		} else {
+			p.coolUntil = time.Time{}
+		}
+		r.mu.Unlock()
+
+		if err == nil {
+			return &Delivery{ProviderID: p.ID, ProviderName: p.Name}, nil
+		}
+
+		log.Printf("[email.Registry] provider %s failed, trying next: %v", p.Name, err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// reloadIfStale 超过重新加载间隔时刷新配置，失败时继续使用旧配置
+func (r *Registry) reloadIfStale() {
+	if r.providerRepo == nil {
+		return
+	}
+
+	r.mu.Lock()
+	stale := time.Since(r.loadedAt) >= providerReloadInterval
+	if stale {
+		// 先推后时间，避免并发发送同时触发重新加载
+		r.loadedAt = time.Now()
+	}
+	r.mu.Unlock()
+
+	if !stale {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := r.Reload(ctx); err != nil {
+		log.Printf("[email.Registry] reload providers: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// fakeProviderRepo 内存版 EmailProviderConfigRepo
+type fakeProviderRepo struct {
+	configs []models.EmailProviderConfig
+}
+
+func (r *fakeProviderRepo) ListActive(ctx context.Context) ([]models.EmailProviderConfig, error) {
+	return r.configs, nil
+}
+
+// TestRegistry_FailsOverToNextProvider 测试首选服务商失败后切换到下一个，并暂停失败的服务商
+func TestRegistry_FailsOverToNextProvider(t *testing.T) {
+	primary := &fakeClient{failFor: map[string]bool{"a@example.com": true}}
+	backup := &fakeClient{}
+	registry := NewRegistry(
+		NewProvider(1, "primary", primary, 0),
+		NewProvider(2, "backup", backup, 0),
+	)
+
+	delivery, err := registry.Deliver("a@example.com", "subject", "<p>hi</p>", "hi")
+	if err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if delivery.ProviderID != 2 {
+		t.Errorf("expected backup provider, got %d", delivery.ProviderID)
+	}
+
+	// 首选服务商暂停期间，下一封直接使用备用服务商
+	if _, err := registry.Deliver("b@example.com", "subject", "<p>hi</p>", ""); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if len(primary.sent) != 0 {
+		t.Errorf("expected cooling provider to be skipped, got %v", primary.sent)
+	}
+	if len(backup.sent) != 2 {
+		t.Errorf("expected 2 emails via backup, got %d", len(backup.sent))
+	}
+}
+
+// TestRegistry_RateLimitFailover 测试达到频率上限后切换服务商，全部受限时返回 ErrRateLimited
+func TestRegistry_RateLimitFailover(t *testing.T) {
+	primary := &fakeClient{}
+	backup := &fakeClient{}
+	registry := NewRegistry(
+		NewProvider(1, "primary", primary, 1),
+		NewProvider(2, "backup", backup, 1),
+	)
+
+	for _, want := range []int{1, 2} {
+		delivery, err := registry.Deliver("a@example.com", "subject", "<p>hi</p>", "")
+		if err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+		if delivery.ProviderID != want {
+			t.Errorf("expected provider %d, got %d", want, delivery.ProviderID)
+		}
+	}
+
+	if _, err := registry.Deliver("a@example.com", "subject", "<p>hi</p>", ""); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("expected ErrRateLimited, got %v", err)
+	}
+}
+
+// TestRegistry_LoadsFileProviderFromDB 测试从配置创建 mbox 文件服务商并跳过无效配置
+func TestRegistry_LoadsFileProviderFromDB(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.mbox")
+	repo := &fakeProviderRepo{configs: []models.EmailProviderConfig{
+		{ID: 1, ProviderType: models.EmailProviderSendGrid, ConfigName: "sendgrid", ConfigJSON: `{}`},
+		{ID: 2, ProviderType: models.EmailProviderFile, ConfigName: "local", ConfigJSON: `{"path":"` + path + `"}`},
+	}}
+
+	registry, err := NewRegistryFromDB(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("load registry: %v", err)
+	}
+
+	delivery, err := registry.Deliver("a@example.com", "测试主题", "<p>From here</p>", "From here")
+	if err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if delivery.ProviderID != 2 || delivery.ProviderName != "local" {
+		t.Errorf("unexpected delivery: %+v", delivery)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read mbox: %v", err)
+	}
+	mbox := string(data)
+	if !strings.HasPrefix(mbox, "From noreply@localhost ") {
+		t.Errorf("expected mbox separator line, got %q", strings.SplitN(mbox, "\n", 2)[0])
+	}
+	if !strings.Contains(mbox, "a@example.com") {
+		t.Error("expected recipient in mbox")
+	}
+	if !strings.Contains(mbox, "multipart/alternative") {
+		t.Error("expected multipart message with text part")
+	}
+}
+
+// TestWorker_RecordsProvider 测试任务记录实际发送的服务商
+func TestWorker_RecordsProvider(t *testing.T) {
+	taskRepo := newFakeTaskRepo(newTestTask(1, "a@example.com"))
+	promotionRepo := &fakePromotionRepo{promotion: &models.EmailPromotion{ID: 1, Status: models.EmailPromotionStatusSending}}
+	registry := NewRegistry(
+		NewProvider(3, "primary", &fakeClient{failFor: map[string]bool{"a@example.com": true}}, 0),
+		NewProvider(4, "backup", &fakeClient{}, 0),
+	)
+
+	newTestWorker(registry, taskRepo, promotionRepo).processBatch(context.Background())
+
+	task := taskRepo.tasks[1]
+	if task.Status != models.EmailTaskStatusSuccess {
+		t.Fatalf("expected task success, got %d", task.Status)
+	}
+	if task.ProviderID == nil || *task.ProviderID != 4 {
+		t.Errorf("expected provider 4 recorded, got %v", task.ProviderID)
+	}
+}
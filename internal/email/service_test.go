@@ -45,11 +45,8 @@ func TestBatchEmailSending_Success(t *testing.T) {
 		{ID: 3, Email: testEmail, Nickname: "用户3"},
 	}
 
-	// 创建邮件服务
-	service := &Service{
-		client:           client,
-		templateRenderer: NewTemplateRenderer("https://kuaizu.xyz"),
-	}
+	// 创建模板渲染器
+	renderer := NewTemplateRenderer("https://kuaizu.xyz")
 
 	// 模拟批量发送逻辑（第4步）
 	sentCount := 0
@@ -61,14 +58,14 @@ func TestBatchEmailSending_Success(t *testing.T) {
 
 		// 渲染邮件
 		nickname := r.Nickname
-		subject, body, err := service.templateRenderer.RenderProjectPromotion(project, &nickname, unsubscribeToken)
+		subject, body, err := renderer.RenderProjectPromotion(project, &nickname, unsubscribeToken)
 		if err != nil {
 			t.Errorf("Failed to render email for %s: %v", r.Email, err)
 			continue
 		}
 
 		// 发送邮件
-		if err := service.client.Send(r.Email, subject, body); err == nil {
+		if err := client.Send(r.Email, subject, body); err == nil {
 			sentCount++
 			t.Logf("✓ Email sent successfully to %s (nickname: %s)", r.Email, r.Nickname)
 		} else {
@@ -125,10 +122,7 @@ func TestBatchEmailSending_RateLimiting(t *testing.T) {
 		{ID: 3, Email: testEmail, Nickname: "用户3"},
 	}
 
-	service := &Service{
-		client:           client,
-		templateRenderer: NewTemplateRenderer("https://kuaizu.xyz"),
-	}
+	renderer := NewTemplateRenderer("https://kuaizu.xyz")
 
 	// 模拟批量发送逻辑，测量时间
 	startTime := time.Now()
@@ -138,12 +132,12 @@ func TestBatchEmailSending_RateLimiting(t *testing.T) {
 	for _, r := range recipients {
 		unsubscribeToken := generateUnsubscribeTokenForEmail(r.ID)
 		nickname := r.Nickname
-		subject, body, err := service.templateRenderer.RenderProjectPromotion(project, &nickname, unsubscribeToken)
+		subject, body, err := renderer.RenderProjectPromotion(project, &nickname, unsubscribeToken)
 		if err != nil {
 			continue
 		}
 
-		if err := service.client.Send(r.Email, subject, body); err == nil {
+		if err := client.Send(r.Email, subject, body); err == nil {
 			sentCount++
 			t.Logf("✓ Email sent to %s", r.Email)
 		}
@@ -252,10 +246,7 @@ func TestBatchEmailSending_LargeRecipientList(t *testing.T) {
 		}
 	}
 
-	service := &Service{
-		client:           client,
-		templateRenderer: NewTemplateRenderer("https://kuaizu.xyz"),
-	}
+	renderer := NewTemplateRenderer("https://kuaizu.xyz")
 
 	// 模拟批量发送逻辑（使用较长的延迟以避免触发限制）
 	startTime := time.Now()
@@ -265,14 +256,14 @@ func TestBatchEmailSending_LargeRecipientList(t *testing.T) {
 	for i, r := range recipients {
 		unsubscribeToken := generateUnsubscribeTokenForEmail(r.ID)
 		nickname := r.Nickname
-		subject, body, err := service.templateRenderer.RenderProjectPromotion(project, &nickname, unsubscribeToken)
+		subject, body, err := renderer.RenderProjectPromotion(project, &nickname, unsubscribeToken)
 		if err != nil {
 			t.Logf("Failed to render email %d: %v", i+1, err)
 			failedCount++
 			continue
 		}
 
-		if err := service.client.Send(r.Email, subject, body); err == nil {
+		if err := client.Send(r.Email, subject, body); err == nil {
 			sentCount++
 			t.Logf("✓ Email %d/%d sent successfully", i+1, recipientCount)
 		} else {
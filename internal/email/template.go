@@ -2,12 +2,26 @@ package email
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"html/template"
+	htmltemplate "html/template"
+	"sync"
+	texttemplate "text/template"
+	"time"
 
 	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
 )
 
+// templateCacheTTL 模板缓存有效期。
+// 管理后台与小程序服务是两个进程，后台修改模板后只能让本进程缓存失效，
+// 其他进程依赖 TTL 在此时间内读到新模板。
+const templateCacheTTL = 5 * time.Minute
+
+// ErrTemplateNotFound 模板不存在或已禁用，且没有内置模板可用
+var ErrTemplateNotFound = errors.New("email template not found")
+
 // ProjectPromotionData 项目推广邮件数据
 type ProjectPromotionData struct {
 	Nickname       string
@@ -19,21 +33,217 @@ type ProjectPromotionData struct {
 	UnsubscribeURL string
 }
 
+// RenderedEmail 渲染后的邮件内容
+type RenderedEmail struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// compiledTemplate 已解析的模板
+type compiledTemplate struct {
+	subject  *texttemplate.Template
+	html     *htmltemplate.Template
+	text     *texttemplate.Template // 可为空
+	loadedAt time.Time
+}
+
+// builtinTemplate 内置模板，数据库中没有对应的启用模板时使用
+type builtinTemplate struct {
+	subject string
+	html    string
+	text    string
+}
+
+var builtinTemplates = map[string]builtinTemplate{
+	models.EmailTemplateProjectPromotion: {
+		subject: "【快组校园】有一个项目可能适合你：{{.ProjectName}}",
+		html:    projectPromotionTemplate,
+		text:    projectPromotionTextTemplate,
+	},
+}
+
 // TemplateRenderer 邮件模板渲染器
+// 优先使用 email_template 表中启用的模板，缺失时回退到内置模板。
 type TemplateRenderer struct {
-	baseURL string
+	baseURL      string
+	templateRepo repository.EmailTemplateRepo
+
+	mu    sync.RWMutex
+	cache map[string]*compiledTemplate
 }
 
-// NewTemplateRenderer 创建模板渲染器
+// NewTemplateRenderer 创建只使用内置模板的渲染器
 func NewTemplateRenderer(baseURL string) *TemplateRenderer {
-	return &TemplateRenderer{baseURL: baseURL}
+	return NewTemplateRendererWithRepo(baseURL, nil)
 }
 
-// RenderProjectPromotion 渲染项目推广邮件
-func (r *TemplateRenderer) RenderProjectPromotion(project *models.Project, nickname *string, unsubscribeToken string) (string, string, error) {
-	// 邮件主题
-	subject := fmt.Sprintf("【快组校园】有一个项目可能适合你：%s", project.Name)
+// NewTemplateRendererWithRepo 创建从数据库加载模板的渲染器
+func NewTemplateRendererWithRepo(baseURL string, templateRepo repository.EmailTemplateRepo) *TemplateRenderer {
+	return &TemplateRenderer{
+		baseURL:      baseURL,
+		templateRepo: templateRepo,
+		cache:        make(map[string]*compiledTemplate),
+	}
+}
+
+// NewTemplateRendererFromEnv 从环境变量读取站点地址创建渲染器
+func NewTemplateRendererFromEnv(templateRepo repository.EmailTemplateRepo) *TemplateRenderer {
+	return NewTemplateRendererWithRepo(baseURLFromEnv(), templateRepo)
+}
+
+// Render 按模板编码渲染邮件
+func (r *TemplateRenderer) Render(ctx context.Context, code string, data interface{}) (*RenderedEmail, error) {
+	tmpl, err := r.load(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.execute(data)
+}
+
+// Preview 渲染一个（可能尚未保存的）模板，不读写缓存
+func (r *TemplateRenderer) Preview(t *models.EmailTemplate, data interface{}) (*RenderedEmail, error) {
+	tmpl, err := compileTemplate(t)
+	if err != nil {
+		return nil, err
+	}
+	return tmpl.execute(data)
+}
 
+// Validate 检查模板语法
+func (r *TemplateRenderer) Validate(t *models.EmailTemplate) error {
+	_, err := compileTemplate(t)
+	return err
+}
+
+// Invalidate 使指定模板的缓存失效
+func (r *TemplateRenderer) Invalidate(code string) {
+	r.mu.Lock()
+	delete(r.cache, code)
+	r.mu.Unlock()
+}
+
+// InvalidateAll 清空模板缓存
+func (r *TemplateRenderer) InvalidateAll() {
+	r.mu.Lock()
+	r.cache = make(map[string]*compiledTemplate)
+	r.mu.Unlock()
+}
+
+// load 读取缓存，过期或缺失时从数据库（或内置模板）重新加载
+func (r *TemplateRenderer) load(ctx context.Context, code string) (*compiledTemplate, error) {
+	r.mu.RLock()
+	cached, ok := r.cache[code]
+	r.mu.RUnlock()
+	if ok && time.Since(cached.loadedAt) < templateCacheTTL {
+		return cached, nil
+	}
+
+	var tmpl *compiledTemplate
+	if r.templateRepo != nil {
+		t, err := r.templateRepo.GetByCode(ctx, code)
+		if err != nil {
+			return nil, fmt.Errorf("load template %s: %w", code, err)
+		}
+		if t != nil && t.IsActive {
+			if tmpl, err = compileTemplate(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if tmpl == nil {
+		b, ok := builtinTemplates[code]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, code)
+		}
+		var err error
+		if tmpl, err = compileTemplate(&models.EmailTemplate{
+			TemplateCode: code,
+			Subject:      b.subject,
+			HTMLContent:  &b.html,
+			TextContent:  &b.text,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	tmpl.loadedAt = time.Now()
+	r.mu.Lock()
+	r.cache[code] = tmpl
+	r.mu.Unlock()
+
+	return tmpl, nil
+}
+
+// compileTemplate 解析模板的主题、HTML 与纯文本部分
+func compileTemplate(t *models.EmailTemplate) (*compiledTemplate, error) {
+	if t.HTMLContent == nil || *t.HTMLContent == "" {
+		return nil, fmt.Errorf("template %s: html content is empty", t.TemplateCode)
+	}
+
+	subject, err := texttemplate.New("subject").Parse(t.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("parse subject: %w", err)
+	}
+
+	html, err := htmltemplate.New("html").Parse(*t.HTMLContent)
+	if err != nil {
+		return nil, fmt.Errorf("parse html: %w", err)
+	}
+
+	tmpl := &compiledTemplate{subject: subject, html: html}
+	if t.TextContent != nil && *t.TextContent != "" {
+		if tmpl.text, err = texttemplate.New("text").Parse(*t.TextContent); err != nil {
+			return nil, fmt.Errorf("parse text: %w", err)
+		}
+	}
+
+	return tmpl, nil
+}
+
+// execute 使用数据渲染各部分
+func (t *compiledTemplate) execute(data interface{}) (*RenderedEmail, error) {
+	var buf bytes.Buffer
+	result := &RenderedEmail{}
+
+	if err := t.subject.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("execute subject: %w", err)
+	}
+	result.Subject = buf.String()
+
+	buf.Reset()
+	if err := t.html.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("execute html: %w", err)
+	}
+	result.HTML = buf.String()
+
+	if t.text != nil {
+		buf.Reset()
+		if err := t.text.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("execute text: %w", err)
+		}
+		result.Text = buf.String()
+	}
+
+	return result, nil
+}
+
+// ProjectPromotionSampleData 模板预览用的示例数据
+func (r *TemplateRenderer) ProjectPromotionSampleData() ProjectPromotionData {
+	return ProjectPromotionData{
+		Nickname:       "同学",
+		ProjectName:    "示例项目",
+		ProjectDesc:    "这是一段示例项目介绍。",
+		SchoolName:     "示例大学",
+		MemberCount:    3,
+		ProjectURL:     fmt.Sprintf("%s/projects/%d", r.baseURL, 1),
+		UnsubscribeURL: fmt.Sprintf("%s/email/unsubscribe?token=%s", r.baseURL, "preview"),
+	}
+}
+
+// RenderProjectPromotionEmail 渲染项目推广邮件（HTML 与纯文本）
+func (r *TemplateRenderer) RenderProjectPromotionEmail(ctx context.Context, project *models.Project, nickname *string, unsubscribeToken string) (*RenderedEmail, error) {
 	// 准备数据
 	data := ProjectPromotionData{
 		Nickname:       "同学",
@@ -58,28 +268,33 @@ func (r *TemplateRenderer) RenderProjectPromotion(project *models.Project, nickn
 		data.MemberCount = *project.MemberCount
 	}
 
-	// 渲染模板
-	body, err := r.renderTemplate(projectPromotionTemplate, data)
+	return r.Render(ctx, models.EmailTemplateProjectPromotion, data)
+}
+
+// RenderProjectPromotion 渲染项目推广邮件，返回主题与 HTML 正文
+func (r *TemplateRenderer) RenderProjectPromotion(project *models.Project, nickname *string, unsubscribeToken string) (string, string, error) {
+	rendered, err := r.RenderProjectPromotionEmail(context.Background(), project, nickname, unsubscribeToken)
 	if err != nil {
 		return "", "", err
 	}
-
-	return subject, body, nil
+	return rendered.Subject, rendered.HTML, nil
 }
 
-func (r *TemplateRenderer) renderTemplate(tmplStr string, data interface{}) (string, error) {
-	tmpl, err := template.New("email").Parse(tmplStr)
-	if err != nil {
-		return "", fmt.Errorf("parse template: %w", err)
-	}
+// 项目推广邮件纯文本模板
+const projectPromotionTextTemplate = `Hi {{.Nickname}}，
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", fmt.Errorf("execute template: %w", err)
-	}
+平台上有一个项目正在招募队员，快来看看是否适合你：
 
-	return buf.String(), nil
-}
+{{.ProjectName}}
+{{if .ProjectDesc}}{{.ProjectDesc}}
+{{end}}{{if .SchoolName}}学校：{{.SchoolName}}
+{{end}}{{if .MemberCount}}需要 {{.MemberCount}} 人
+{{end}}
+查看详情：{{.ProjectURL}}
+
+此邮件由快组校园平台发送
+如不想收到此类邮件，请访问以下链接退订：{{.UnsubscribeURL}}
+`
 
 // 项目推广邮件模板
 const projectPromotionTemplate = `<!DOCTYPE html>
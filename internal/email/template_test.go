@@ -0,0 +1,122 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// fakeTemplateRepo 只实现渲染器用到的 GetByCode，并记录查询次数
+type fakeTemplateRepo struct {
+	repository.EmailTemplateRepo
+	templates map[string]*models.EmailTemplate
+	lookups   int
+}
+
+func (r *fakeTemplateRepo) GetByCode(ctx context.Context, code string) (*models.EmailTemplate, error) {
+	r.lookups++
+	return r.templates[code], nil
+}
+
+func strPtr(s string) *string { return &s }
+
+// TestTemplateRenderer_UsesDatabaseTemplate 测试优先使用数据库模板并缓存
+func TestTemplateRenderer_UsesDatabaseTemplate(t *testing.T) {
+	repo := &fakeTemplateRepo{templates: map[string]*models.EmailTemplate{
+		models.EmailTemplateProjectPromotion: {
+			TemplateCode: models.EmailTemplateProjectPromotion,
+			Subject:      "新项目：{{.ProjectName}}",
+			HTMLContent:  strPtr("<p>{{.Nickname}}，看看 {{.ProjectName}}</p>"),
+			TextContent:  strPtr("{{.Nickname}}，看看 {{.ProjectName}}"),
+			IsActive:     true,
+		},
+	}}
+	renderer := NewTemplateRendererWithRepo("https://kuaizu.xyz", repo)
+	project := &models.Project{ID: 1, Name: "<A&B>"}
+
+	rendered, err := renderer.RenderProjectPromotionEmail(context.Background(), project, strPtr("小明"), "token")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if rendered.Subject != "新项目：<A&B>" {
+		t.Errorf("unexpected subject: %q", rendered.Subject)
+	}
+	if rendered.HTML != "<p>小明，看看 &lt;A&amp;B&gt;</p>" {
+		t.Errorf("expected escaped html, got %q", rendered.HTML)
+	}
+	if rendered.Text != "小明，看看 <A&B>" {
+		t.Errorf("unexpected text: %q", rendered.Text)
+	}
+
+	// 第二次渲染命中缓存
+	if _, err := renderer.RenderProjectPromotionEmail(context.Background(), project, nil, "token"); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if repo.lookups != 1 {
+		t.Errorf("expected 1 repository lookup, got %d", repo.lookups)
+	}
+
+	// 失效后重新加载
+	renderer.Invalidate(models.EmailTemplateProjectPromotion)
+	if _, err := renderer.RenderProjectPromotionEmail(context.Background(), project, nil, "token"); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if repo.lookups != 2 {
+		t.Errorf("expected reload after invalidate, got %d lookups", repo.lookups)
+	}
+}
+
+// TestTemplateRenderer_FallsBackToBuiltin 测试模板禁用时回退到内置模板
+func TestTemplateRenderer_FallsBackToBuiltin(t *testing.T) {
+	repo := &fakeTemplateRepo{templates: map[string]*models.EmailTemplate{
+		models.EmailTemplateProjectPromotion: {
+			TemplateCode: models.EmailTemplateProjectPromotion,
+			Subject:      "禁用的模板",
+			HTMLContent:  strPtr("<p>disabled</p>"),
+			IsActive:     false,
+		},
+	}}
+	renderer := NewTemplateRendererWithRepo("https://kuaizu.xyz", repo)
+
+	rendered, err := renderer.RenderProjectPromotionEmail(context.Background(), &models.Project{ID: 7, Name: "测试项目"}, nil, "token")
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(rendered.Subject, "测试项目") {
+		t.Errorf("expected builtin subject, got %q", rendered.Subject)
+	}
+	if !strings.Contains(rendered.HTML, "https://kuaizu.xyz/projects/7") {
+		t.Error("expected builtin html with project url")
+	}
+	if !strings.Contains(rendered.Text, "https://kuaizu.xyz/email/unsubscribe?token=token") {
+		t.Error("expected builtin text with unsubscribe url")
+	}
+}
+
+// TestTemplateRenderer_UnknownCode 测试未知模板编码返回 ErrTemplateNotFound
+func TestTemplateRenderer_UnknownCode(t *testing.T) {
+	renderer := NewTemplateRendererWithRepo("https://kuaizu.xyz", &fakeTemplateRepo{})
+
+	if _, err := renderer.Render(context.Background(), "missing", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("expected ErrTemplateNotFound, got %v", err)
+	}
+}
+
+// TestTemplateRenderer_Validate 测试模板语法校验
+func TestTemplateRenderer_Validate(t *testing.T) {
+	renderer := NewTemplateRenderer("https://kuaizu.xyz")
+
+	bad := &models.EmailTemplate{Subject: "{{.ProjectName", HTMLContent: strPtr("<p></p>")}
+	if err := renderer.Validate(bad); err == nil {
+		t.Error("expected subject parse error")
+	}
+
+	empty := &models.EmailTemplate{Subject: "ok"}
+	if err := renderer.Validate(empty); err == nil {
+		t.Error("expected error for empty html")
+	}
+}
@@ -0,0 +1,368 @@
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+const (
+	workerPollInterval = 5 * time.Second        // 轮询待发送任务的间隔
+	workerBatchSize    = 20                     // 每次领取的任务数
+	workerSendInterval = 100 * time.Millisecond // 每封邮件之间的延迟，避免触发反垃圾机制
+	workerTaskLease    = 10 * time.Minute       // 领取任务后的租约，超时未完成的任务会被重新领取
+
+	maxTaskRetries   = 5                // 最大重试次数，超过后任务标记为失败
+	retryBaseBackoff = time.Minute      // 首次重试的等待时间，之后指数增长
+	retryMaxBackoff  = 30 * time.Minute // 重试等待时间上限
+	rateLimitDelay   = time.Minute      // 服务商都达到频率上限时，任务推迟到下一个频率窗口，不计入重试次数
+
+	maxTaskErrorLen = 500 // email_task.error_msg 列长度
+)
+
+// Worker 邮件任务发送器
+// 把待发送的推广展开为 email_task，再从 email_task 表领取到期任务逐条发送，失败按指数退避重试，
+// 并把发送结果汇总回 email_promotion。推广与任务状态全部落库，进程重启后可继续发送。
+type Worker struct {
+	client           Client
+	templateRenderer *TemplateRenderer
+	taskRepo         repository.EmailTaskRepo
+	promotionRepo    repository.EmailPromotionRepo
+	projectRepo      repository.ProjectRepo
+	userRepo         repository.UserRepo
+}
+
+// NewWorker 创建邮件任务发送器
+func NewWorker(
+	client Client,
+	baseURL string,
+	taskRepo repository.EmailTaskRepo,
+	promotionRepo repository.EmailPromotionRepo,
+	projectRepo repository.ProjectRepo,
+	userRepo repository.UserRepo,
+	templateRepo repository.EmailTemplateRepo,
+) *Worker {
+	return &Worker{
+		client:           client,
+		templateRenderer: NewTemplateRendererWithRepo(baseURL, templateRepo),
+		taskRepo:         taskRepo,
+		promotionRepo:    promotionRepo,
+		projectRepo:      projectRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// NewWorkerFromEnv 使用 email_provider_config 中的服务商创建邮件任务发送器
+func NewWorkerFromEnv(
+	ctx context.Context,
+	providerRepo repository.EmailProviderConfigRepo,
+	taskRepo repository.EmailTaskRepo,
+	promotionRepo repository.EmailPromotionRepo,
+	projectRepo repository.ProjectRepo,
+	userRepo repository.UserRepo,
+	templateRepo repository.EmailTemplateRepo,
+) (*Worker, error) {
+	registry, err := NewRegistryFromDB(ctx, providerRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWorker(registry, baseURLFromEnv(), taskRepo, promotionRepo, projectRepo, userRepo, templateRepo), nil
+}
+
+// Run 持续处理邮件任务，直到 ctx 被取消
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(workerPollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.enqueuePending(ctx)
+
+		// 领满一批说明可能还有积压，立即继续
+		for w.processBatch(ctx) == workerBatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// enqueuePending 把待发送的推广展开为邮件任务
+func (w *Worker) enqueuePending(ctx context.Context) {
+	promotions, err := w.promotionRepo.ListPending(ctx, workerBatchSize)
+	if err != nil {
+		log.Printf("[email.Worker] list pending promotions: %v", err)
+		return
+	}
+
+	for i := range promotions {
+		w.enqueuePromotion(ctx, &promotions[i])
+	}
+}
+
+// enqueuePromotion 为推广的每个收件人写入一条邮件任务
+// 状态切换与任务写入在同一事务中完成；查询失败时推广保持待发送，下一轮继续展开
+func (w *Worker) enqueuePromotion(ctx context.Context, promotion *models.EmailPromotion) {
+	project, err := w.projectRepo.GetByID(ctx, promotion.ProjectID)
+	if err != nil {
+		log.Printf("[email.Worker] get project for promotion %d: %v", promotion.ID, err)
+		return
+	}
+	if project == nil {
+		w.failPromotion(ctx, promotion, "项目不存在")
+		return
+	}
+
+	recipients, err := w.userRepo.FindEmailRecipients(ctx, promotion.CreatorID, promotion.MaxRecipients)
+	if err != nil {
+		log.Printf("[email.Worker] find recipients for promotion %d: %v", promotion.ID, err)
+		return
+	}
+
+	tasks := make([]models.EmailTask, 0, len(recipients))
+	for _, r := range recipients {
+		vars, err := json.Marshal(promotionTemplateVars{
+			UserID:    r.ID,
+			Nickname:  r.Nickname,
+			ProjectID: project.ID,
+		})
+		if err != nil {
+			continue
+		}
+		varsStr := string(vars)
+		tasks = append(tasks, models.EmailTask{
+			PromotionID:    promotion.ID,
+			RecipientEmail: r.Email,
+			TemplateCode:   models.EmailTemplateProjectPromotion,
+			TemplateVars:   &varsStr,
+			Status:         models.EmailTaskStatusPending,
+		})
+	}
+
+	// 没有收件人时直接完成
+	now := time.Now()
+	promotion.StartedAt = &now
+	promotion.Status = models.EmailPromotionStatusSending
+	if len(tasks) == 0 {
+		promotion.Status = models.EmailPromotionStatusCompleted
+		promotion.CompletedAt = &now
+	}
+
+	if _, err := w.taskRepo.CreateForPromotion(ctx, promotion, tasks); err != nil {
+		log.Printf("[email.Worker] create tasks for promotion %d: %v", promotion.ID, err)
+	}
+}
+
+// failPromotion 将推广标记为失败
+func (w *Worker) failPromotion(ctx context.Context, promotion *models.EmailPromotion, errMsg string) {
+	promotion.Status = models.EmailPromotionStatusFailed
+	promotion.ErrorMessage = &errMsg
+	if err := w.promotionRepo.Update(ctx, promotion); err != nil {
+		log.Printf("[email.Worker] update promotion %d: %v", promotion.ID, err)
+	}
+}
+
+// processBatch 领取并发送一批任务，返回领取到的任务数
+func (w *Worker) processBatch(ctx context.Context) int {
+	tasks, err := w.taskRepo.ClaimDue(ctx, workerBatchSize, time.Now().Add(workerTaskLease))
+	if err != nil {
+		log.Printf("[email.Worker] claim tasks: %v", err)
+		return 0
+	}
+
+	projects := make(map[int]*models.Project)
+	promotionIDs := make(map[int]bool)
+	for i := range tasks {
+		w.sendTask(ctx, &tasks[i], projects)
+		promotionIDs[tasks[i].PromotionID] = true
+		time.Sleep(workerSendInterval)
+	}
+
+	for promotionID := range promotionIDs {
+		w.rollupPromotion(ctx, promotionID)
+	}
+
+	return len(tasks)
+}
+
+// sendTask 渲染并发送单个任务，按结果更新任务状态
+func (w *Worker) sendTask(ctx context.Context, task *models.EmailTask, projects map[int]*models.Project) {
+	var vars promotionTemplateVars
+	if task.TemplateVars != nil {
+		if err := json.Unmarshal([]byte(*task.TemplateVars), &vars); err != nil {
+			w.failTask(ctx, task, fmt.Sprintf("invalid template vars: %v", err))
+			return
+		}
+	}
+
+	project, ok := projects[vars.ProjectID]
+	if !ok {
+		p, err := w.projectRepo.GetByID(ctx, vars.ProjectID)
+		if err != nil {
+			w.retryTask(ctx, task, fmt.Sprintf("get project: %v", err))
+			return
+		}
+		projects[vars.ProjectID] = p
+		project = p
+	}
+	if project == nil {
+		w.failTask(ctx, task, "项目不存在")
+		return
+	}
+
+	// 退订 token 在发送时生成，保证时间戳新鲜
+	rendered, err := w.templateRenderer.RenderProjectPromotionEmail(ctx, project, vars.Nickname, generateUnsubscribeTokenForEmail(vars.UserID))
+	if err != nil {
+		w.failTask(ctx, task, fmt.Sprintf("render template: %v", err))
+		return
+	}
+
+	providerID, err := w.deliver(task.RecipientEmail, rendered)
+	if errors.Is(err, ErrRateLimited) {
+		w.deferTask(ctx, task, err.Error())
+		return
+	}
+	if err != nil {
+		w.retryTask(ctx, task, err.Error())
+		return
+	}
+
+	held, err := w.taskRepo.MarkSuccess(ctx, task.ID, claimToken(task), time.Now(), providerID)
+	if err != nil {
+		log.Printf("[email.Worker] mark task %d success: %v", task.ID, err)
+		return
+	}
+	if !held {
+		log.Printf("[email.Worker] task %d was reclaimed before its success was recorded", task.ID)
+	}
+}
+
+// deliver 发送邮件；客户端为服务商注册表时返回实际发送的服务商ID
+func (w *Worker) deliver(to string, rendered *RenderedEmail) (*int, error) {
+	if registry, ok := w.client.(*Registry); ok {
+		delivery, err := registry.Deliver(to, rendered.Subject, rendered.HTML, rendered.Text)
+		if err != nil {
+			return nil, err
+		}
+		return &delivery.ProviderID, nil
+	}
+
+	return nil, w.client.SendWithText(to, rendered.Subject, rendered.HTML, rendered.Text)
+}
+
+// retryTask 记录一次失败；未超过最大重试次数时按指数退避重新排队
+func (w *Worker) retryTask(ctx context.Context, task *models.EmailTask, errMsg string) {
+	retryCount := task.RetryCount + 1
+	if retryCount > maxTaskRetries {
+		task.RetryCount = retryCount
+		w.failTask(ctx, task, errMsg)
+		return
+	}
+
+	nextRetryAt := time.Now().Add(retryBackoff(retryCount))
+	held, err := w.taskRepo.MarkRetry(ctx, task.ID, claimToken(task), retryCount, truncateTaskError(errMsg), nextRetryAt)
+	if err != nil {
+		log.Printf("[email.Worker] mark task %d retry: %v", task.ID, err)
+		return
+	}
+	if !held {
+		log.Printf("[email.Worker] task %d was reclaimed before its retry was recorded", task.ID)
+	}
+}
+
+// deferTask 服务商限流时把任务推迟到下一个频率窗口，重试次数保持不变
+func (w *Worker) deferTask(ctx context.Context, task *models.EmailTask, errMsg string) {
+	nextRetryAt := time.Now().Add(rateLimitDelay)
+	held, err := w.taskRepo.MarkRetry(ctx, task.ID, claimToken(task), task.RetryCount, truncateTaskError(errMsg), nextRetryAt)
+	if err != nil {
+		log.Printf("[email.Worker] mark task %d deferred: %v", task.ID, err)
+		return
+	}
+	if !held {
+		log.Printf("[email.Worker] task %d was reclaimed before its deferral was recorded", task.ID)
+	}
+}
+
+// failTask 将任务标记为最终失败
+func (w *Worker) failTask(ctx context.Context, task *models.EmailTask, errMsg string) {
+	held, err := w.taskRepo.MarkFailed(ctx, task.ID, claimToken(task), task.RetryCount, truncateTaskError(errMsg))
+	if err != nil {
+		log.Printf("[email.Worker] mark task %d failed: %v", task.ID, err)
+		return
+	}
+	if !held {
+		log.Printf("[email.Worker] task %d was reclaimed before its failure was recorded", task.ID)
+	}
+}
+
+// claimToken 返回领取任务时分配的令牌
+func claimToken(task *models.EmailTask) string {
+	if task.ClaimToken == nil {
+		return ""
+	}
+	return *task.ClaimToken
+}
+
+// rollupPromotion 把任务发送结果汇总到推广记录
+func (w *Worker) rollupPromotion(ctx context.Context, promotionID int) {
+	stats, err := w.taskRepo.GetStatsByPromotionID(ctx, promotionID)
+	if err != nil {
+		log.Printf("[email.Worker] get stats for promotion %d: %v", promotionID, err)
+		return
+	}
+
+	promotion, err := w.promotionRepo.GetByID(ctx, promotionID)
+	if err != nil || promotion == nil {
+		log.Printf("[email.Worker] get promotion %d: %v", promotionID, err)
+		return
+	}
+
+	promotion.TotalSent = stats.Success
+	if stats.Unfinished() == 0 {
+		completedAt := time.Now()
+		promotion.CompletedAt = &completedAt
+		promotion.Status = models.EmailPromotionStatusCompleted
+		if stats.Success == 0 && stats.Failed > 0 {
+			errMsg := "全部邮件发送失败"
+			promotion.Status = models.EmailPromotionStatusFailed
+			promotion.ErrorMessage = &errMsg
+		}
+	}
+
+	if err := w.promotionRepo.Update(ctx, promotion); err != nil {
+		log.Printf("[email.Worker] update promotion %d: %v", promotionID, err)
+	}
+}
+
+// retryBackoff 返回第 n 次重试前的等待时间
+func retryBackoff(n int) time.Duration {
+	backoff := retryBaseBackoff
+	for i := 1; i < n; i++ {
+		backoff *= 2
+		if backoff >= retryMaxBackoff {
+			return retryMaxBackoff
+		}
+	}
+	return backoff
+}
+
+// truncateTaskError 截断错误信息以适配列长度
+func truncateTaskError(msg string) string {
+	r := []rune(msg)
+	if len(r) > maxTaskErrorLen {
+		return string(r[:maxTaskErrorLen])
+	}
+	return msg
+}
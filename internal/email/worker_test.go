@@ -0,0 +1,362 @@
+package email
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// fakeTaskRepo 内存版 EmailTaskRepo
+type fakeTaskRepo struct {
+	tasks  map[int64]*models.EmailTask
+	claims int
+}
+
+func newFakeTaskRepo(tasks ...models.EmailTask) *fakeTaskRepo {
+	r := &fakeTaskRepo{tasks: make(map[int64]*models.EmailTask)}
+	for i := range tasks {
+		t := tasks[i]
+		r.tasks[t.ID] = &t
+	}
+	return r
+}
+
+func (r *fakeTaskRepo) CreateForPromotion(ctx context.Context, promotion *models.EmailPromotion, tasks []models.EmailTask) (bool, error) {
+	for i := range tasks {
+		t := tasks[i]
+		t.ID = int64(len(r.tasks) + 1)
+		r.tasks[t.ID] = &t
+	}
+	return true, nil
+}
+
+func (r *fakeTaskRepo) ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]models.EmailTask, error) {
+	var claimed []models.EmailTask
+	r.claims++
+	token := fmt.Sprintf("claim-%d", r.claims)
+	for id := int64(1); id <= int64(len(r.tasks)) && len(claimed) < limit; id++ {
+		t := r.tasks[id]
+		due := t.NextRetryAt == nil || !t.NextRetryAt.After(time.Now())
+		if (t.Status == models.EmailTaskStatusPending || t.Status == models.EmailTaskStatusRetrying) && due {
+			t.Status = models.EmailTaskStatusSending
+			t.NextRetryAt = &leaseUntil
+			t.ClaimToken = &token
+			claimed = append(claimed, *t)
+		}
+	}
+	return claimed, nil
+}
+
+// held 模拟 WHERE status = sending AND claim_token = ? 的校验
+func (r *fakeTaskRepo) held(id int64, claimToken string) bool {
+	t := r.tasks[id]
+	return t.Status == models.EmailTaskStatusSending && t.ClaimToken != nil && *t.ClaimToken == claimToken
+}
+
+func (r *fakeTaskRepo) MarkSuccess(ctx context.Context, id int64, claimToken string, sendTime time.Time, providerID *int) (bool, error) {
+	if !r.held(id, claimToken) {
+		return false, nil
+	}
+	r.tasks[id].Status = models.EmailTaskStatusSuccess
+	r.tasks[id].SendTime = &sendTime
+	r.tasks[id].ProviderID = providerID
+	return true, nil
+}
+
+func (r *fakeTaskRepo) MarkRetry(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string, nextRetryAt time.Time) (bool, error) {
+	if !r.held(id, claimToken) {
+		return false, nil
+	}
+	t := r.tasks[id]
+	t.Status = models.EmailTaskStatusRetrying
+	t.RetryCount = retryCount
+	t.ErrorMsg = &errMsg
+	t.NextRetryAt = &nextRetryAt
+	return true, nil
+}
+
+func (r *fakeTaskRepo) MarkFailed(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string) (bool, error) {
+	if !r.held(id, claimToken) {
+		return false, nil
+	}
+	t := r.tasks[id]
+	t.Status = models.EmailTaskStatusFailed
+	t.RetryCount = retryCount
+	t.ErrorMsg = &errMsg
+	return true, nil
+}
+
+func (r *fakeTaskRepo) GetStatsByPromotionID(ctx context.Context, promotionID int) (*models.EmailTaskStats, error) {
+	var stats models.EmailTaskStats
+	for _, t := range r.tasks {
+		if t.PromotionID != promotionID {
+			continue
+		}
+		switch t.Status {
+		case models.EmailTaskStatusPending:
+			stats.Pending++
+		case models.EmailTaskStatusSending:
+			stats.Sending++
+		case models.EmailTaskStatusSuccess:
+			stats.Success++
+		case models.EmailTaskStatusFailed:
+			stats.Failed++
+		case models.EmailTaskStatusRetrying:
+			stats.Retrying++
+		}
+	}
+	return &stats, nil
+}
+
+// fakePromotionRepo 只实现 Worker 用到的方法
+type fakePromotionRepo struct {
+	repository.EmailPromotionRepo
+	promotion *models.EmailPromotion
+}
+
+func (r *fakePromotionRepo) GetByID(ctx context.Context, id int) (*models.EmailPromotion, error) {
+	p := *r.promotion
+	return &p, nil
+}
+
+func (r *fakePromotionRepo) ListPending(ctx context.Context, limit int) ([]models.EmailPromotion, error) {
+	if r.promotion.Status != models.EmailPromotionStatusPending {
+		return nil, nil
+	}
+	return []models.EmailPromotion{*r.promotion}, nil
+}
+
+func (r *fakePromotionRepo) Update(ctx context.Context, promotion *models.EmailPromotion) error {
+	p := *promotion
+	r.promotion = &p
+	return nil
+}
+
+// fakeProjectRepo 只实现 Worker 用到的方法
+type fakeProjectRepo struct {
+	repository.ProjectRepo
+}
+
+func (r *fakeProjectRepo) GetByID(ctx context.Context, id int) (*models.Project, error) {
+	return &models.Project{ID: id, Name: "测试项目"}, nil
+}
+
+// fakeUserRepo 只实现 Worker 用到的方法
+type fakeUserRepo struct {
+	repository.UserRepo
+	recipients []*repository.EmailRecipient
+}
+
+func (r *fakeUserRepo) FindEmailRecipients(ctx context.Context, excludeUserID int, limit int) ([]*repository.EmailRecipient, error) {
+	if len(r.recipients) > limit {
+		return r.recipients[:limit], nil
+	}
+	return r.recipients, nil
+}
+
+// fakeClient 记录收件人，可指定发送失败的地址
+type fakeClient struct {
+	sent    []string
+	failFor map[string]bool
+}
+
+func (c *fakeClient) Send(to, subject, htmlBody string) error {
+	if c.failFor[to] {
+		return errors.New("smtp unavailable")
+	}
+	c.sent = append(c.sent, to)
+	return nil
+}
+
+func (c *fakeClient) SendWithText(to, subject, htmlBody, textBody string) error {
+	return c.Send(to, subject, htmlBody)
+}
+
+func newTestTask(id int64, email string) models.EmailTask {
+	vars := `{"userId":1,"projectId":200}`
+	return models.EmailTask{
+		ID:             id,
+		PromotionID:    1,
+		RecipientEmail: email,
+		TemplateCode:   models.EmailTemplateProjectPromotion,
+		TemplateVars:   &vars,
+		Status:         models.EmailTaskStatusPending,
+	}
+}
+
+func newTestWorker(client Client, taskRepo *fakeTaskRepo, promotionRepo *fakePromotionRepo) *Worker {
+	return NewWorker(client, "https://kuaizu.xyz", taskRepo, promotionRepo, &fakeProjectRepo{}, &fakeUserRepo{}, nil)
+}
+
+// TestWorker_EnqueuesPendingPromotion 测试待发送推广被展开为邮件任务
+func TestWorker_EnqueuesPendingPromotion(t *testing.T) {
+	taskRepo := newFakeTaskRepo()
+	promotionRepo := &fakePromotionRepo{promotion: &models.EmailPromotion{ID: 1, ProjectID: 200, CreatorID: 9, MaxRecipients: 2, Status: models.EmailPromotionStatusPending}}
+	w := newTestWorker(&fakeClient{}, taskRepo, promotionRepo)
+	w.userRepo = &fakeUserRepo{recipients: []*repository.EmailRecipient{
+		{ID: 1, Email: "a@example.com"},
+		{ID: 2, Email: "b@example.com"},
+		{ID: 3, Email: "c@example.com"},
+	}}
+
+	w.enqueuePending(context.Background())
+
+	if len(taskRepo.tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d", len(taskRepo.tasks))
+	}
+	for _, task := range taskRepo.tasks {
+		if task.PromotionID != 1 || task.Status != models.EmailTaskStatusPending {
+			t.Errorf("unexpected task %+v", task)
+		}
+	}
+}
+
+// TestWorker_SendsAndRollsUp 测试全部发送成功后汇总推广状态
+func TestWorker_SendsAndRollsUp(t *testing.T) {
+	taskRepo := newFakeTaskRepo(newTestTask(1, "a@example.com"), newTestTask(2, "b@example.com"))
+	promotionRepo := &fakePromotionRepo{promotion: &models.EmailPromotion{ID: 1, Status: models.EmailPromotionStatusSending}}
+	client := &fakeClient{}
+
+	n := newTestWorker(client, taskRepo, promotionRepo).processBatch(context.Background())
+
+	if n != 2 {
+		t.Fatalf("expected 2 claimed tasks, got %d", n)
+	}
+	if len(client.sent) != 2 {
+		t.Errorf("expected 2 emails sent, got %d", len(client.sent))
+	}
+	if promotionRepo.promotion.TotalSent != 2 {
+		t.Errorf("expected total_sent 2, got %d", promotionRepo.promotion.TotalSent)
+	}
+	if promotionRepo.promotion.Status != models.EmailPromotionStatusCompleted {
+		t.Errorf("expected promotion completed, got %d", promotionRepo.promotion.Status)
+	}
+	if promotionRepo.promotion.CompletedAt == nil {
+		t.Error("expected completed_at to be set")
+	}
+}
+
+// TestWorker_RetryWithBackoff 测试发送失败后进入重试并保持推广为发送中
+func TestWorker_RetryWithBackoff(t *testing.T) {
+	taskRepo := newFakeTaskRepo(newTestTask(1, "a@example.com"), newTestTask(2, "bad@example.com"))
+	promotionRepo := &fakePromotionRepo{promotion: &models.EmailPromotion{ID: 1, Status: models.EmailPromotionStatusSending}}
+	client := &fakeClient{failFor: map[string]bool{"bad@example.com": true}}
+
+	before := time.Now()
+	newTestWorker(client, taskRepo, promotionRepo).processBatch(context.Background())
+
+	failed := taskRepo.tasks[2]
+	if failed.Status != models.EmailTaskStatusRetrying {
+		t.Fatalf("expected task to be retrying, got %d", failed.Status)
+	}
+	if failed.RetryCount != 1 {
+		t.Errorf("expected retry_count 1, got %d", failed.RetryCount)
+	}
+	if failed.NextRetryAt == nil || failed.NextRetryAt.Before(before.Add(retryBaseBackoff)) {
+		t.Errorf("expected next retry after base backoff, got %v", failed.NextRetryAt)
+	}
+	if promotionRepo.promotion.Status != models.EmailPromotionStatusSending {
+		t.Errorf("expected promotion still sending, got %d", promotionRepo.promotion.Status)
+	}
+	if promotionRepo.promotion.TotalSent != 1 {
+		t.Errorf("expected total_sent 1, got %d", promotionRepo.promotion.TotalSent)
+	}
+}
+
+// TestWorker_GivesUpAfterMaxRetries 测试超过最大重试次数后任务失败，推广标记失败
+func TestWorker_GivesUpAfterMaxRetries(t *testing.T) {
+	task := newTestTask(1, "bad@example.com")
+	task.Status = models.EmailTaskStatusRetrying
+	task.RetryCount = maxTaskRetries
+	taskRepo := newFakeTaskRepo(task)
+	promotionRepo := &fakePromotionRepo{promotion: &models.EmailPromotion{ID: 1, Status: models.EmailPromotionStatusSending}}
+	client := &fakeClient{failFor: map[string]bool{"bad@example.com": true}}
+
+	newTestWorker(client, taskRepo, promotionRepo).processBatch(context.Background())
+
+	if taskRepo.tasks[1].Status != models.EmailTaskStatusFailed {
+		t.Fatalf("expected task failed, got %d", taskRepo.tasks[1].Status)
+	}
+	if promotionRepo.promotion.Status != models.EmailPromotionStatusFailed {
+		t.Errorf("expected promotion failed, got %d", promotionRepo.promotion.Status)
+	}
+}
+
+// TestWorker_StaleClaimDiscarded 测试租约过期被重新领取后，原发送者的结果不会覆盖新租约
+func TestWorker_StaleClaimDiscarded(t *testing.T) {
+	taskRepo := newFakeTaskRepo(newTestTask(1, "a@example.com"))
+	promotionRepo := &fakePromotionRepo{promotion: &models.EmailPromotion{ID: 1, Status: models.EmailPromotionStatusSending}}
+	w := newTestWorker(&fakeClient{}, taskRepo, promotionRepo)
+
+	stale, _ := taskRepo.ClaimDue(context.Background(), 1, time.Now().Add(-time.Minute))
+	taskRepo.tasks[1].Status = models.EmailTaskStatusPending
+	taskRepo.ClaimDue(context.Background(), 1, time.Now().Add(workerTaskLease))
+
+	w.retryTask(context.Background(), &stale[0], "smtp unavailable")
+
+	task := taskRepo.tasks[1]
+	if task.Status != models.EmailTaskStatusSending || *task.ClaimToken != "claim-2" {
+		t.Errorf("expected task to stay claimed by the new lease, got status %d token %v", task.Status, *task.ClaimToken)
+	}
+	if task.RetryCount != 0 {
+		t.Errorf("expected retry count untouched, got %d", task.RetryCount)
+	}
+}
+
+// TestWorker_RateLimitedTasksDeferred 测试服务商限流时任务推迟到下一个频率窗口，不计入重试次数也不会失败
+func TestWorker_RateLimitedTasksDeferred(t *testing.T) {
+	var tasks []models.EmailTask
+	for i := int64(1); i <= 3; i++ {
+		task := newTestTask(i, fmt.Sprintf("user%d@example.com", i))
+		task.Status = models.EmailTaskStatusRetrying
+		task.RetryCount = maxTaskRetries
+		tasks = append(tasks, task)
+	}
+	taskRepo := newFakeTaskRepo(tasks...)
+	promotionRepo := &fakePromotionRepo{promotion: &models.EmailPromotion{ID: 1, Status: models.EmailPromotionStatusSending}}
+	client := &fakeClient{}
+	registry := NewRegistry(NewProvider(1, "smtp", client, 1))
+
+	before := time.Now()
+	newTestWorker(registry, taskRepo, promotionRepo).processBatch(context.Background())
+
+	if len(client.sent) != 1 {
+		t.Fatalf("expected 1 email sent within the rate limit, got %d", len(client.sent))
+	}
+	for id := int64(2); id <= 3; id++ {
+		task := taskRepo.tasks[id]
+		if task.Status != models.EmailTaskStatusRetrying {
+			t.Errorf("expected throttled task %d to be retrying, got %d", id, task.Status)
+		}
+		if task.RetryCount != maxTaskRetries {
+			t.Errorf("expected throttled task %d to keep retry_count %d, got %d", id, maxTaskRetries, task.RetryCount)
+		}
+		if task.NextRetryAt == nil || task.NextRetryAt.Before(before.Add(rateLimitDelay)) {
+			t.Errorf("expected throttled task %d to wait for the next rate window, got %v", id, task.NextRetryAt)
+		}
+	}
+	if promotionRepo.promotion.Status != models.EmailPromotionStatusSending {
+		t.Errorf("expected promotion still sending, got %d", promotionRepo.promotion.Status)
+	}
+}
+
+// TestRetryBackoff 测试指数退避与上限
+func TestRetryBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		3:  4 * time.Minute,
+		5:  16 * time.Minute,
+		10: retryMaxBackoff,
+	}
+	for n, want := range cases {
+		if got := retryBackoff(n); got != want {
+			t.Errorf("retryBackoff(%d) = %v, want %v", n, got, want)
+		}
+	}
+}
@@ -6,8 +6,8 @@ import (
 
 // UploadFile handles POST /commons/uploads
 // 根据 form 字段 `type` 区分上传用途：
-//   - avatar:     上传用户头像，同时更新 user.avatar_url
-//   - background: 上传用户封面图，同时更新 user.cover_image
+//   - avatar:     上传用户头像，审核通过后更新 user.avatar_url
+//   - background: 上传用户封面图，审核通过后更新 user.cover_image
 //   - (其他/空):  仅上传，返回 URL，不更新数据库
 func (s *Server) UploadFile(ctx echo.Context) error {
 	file, header, err := ctx.Request().FormFile("file")
@@ -25,7 +25,7 @@ func (s *Server) UploadFile(ctx echo.Context) error {
 		if err != nil {
 			return mapServiceError(ctx, err)
 		}
-		return Success(ctx, map[string]string{"url": result.URL})
+		return Success(ctx, map[string]interface{}{"url": result.URL, "auditStatus": result.AuditStatus})
 
 	case "background":
 		userID := GetUserID(ctx)
@@ -33,7 +33,7 @@ func (s *Server) UploadFile(ctx echo.Context) error {
 		if err != nil {
 			return mapServiceError(ctx, err)
 		}
-		return Success(ctx, map[string]string{"url": result.URL})
+		return Success(ctx, map[string]interface{}{"url": result.URL, "auditStatus": result.AuditStatus})
 
 	default:
 		return BadRequest(ctx, "无效的文件类型")
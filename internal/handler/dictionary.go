@@ -40,3 +40,41 @@ func (s *Server) ListMajors(ctx echo.Context, params api.ListMajorsParams) error
 
 	return Success(ctx, classVOs)
 }
+
+// ListSkillTags handles GET /dictionaries/skill-tags
+func (s *Server) ListSkillTags(ctx echo.Context, params api.ListSkillTagsParams) error {
+	keyword, size := "", 0
+	if params.Keyword != nil {
+		keyword = *params.Keyword
+	}
+	if params.Size != nil {
+		size = *params.Size
+	}
+
+	tags, err := s.svc.SkillTag.Suggest(ctx.Request().Context(), keyword, params.CategoryId, size)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	tagVOs := make([]api.SkillTagVO, len(tags))
+	for i := range tags {
+		tagVOs[i] = *tags[i].ToVO()
+	}
+
+	return Success(ctx, tagVOs)
+}
+
+// ListSkillCategories handles GET /dictionaries/skill-categories
+func (s *Server) ListSkillCategories(ctx echo.Context) error {
+	categories, err := s.svc.SkillTag.ListCategoryTree(ctx.Request().Context())
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	categoryVOs := make([]api.SkillCategoryVO, len(categories))
+	for i := range categories {
+		categoryVOs[i] = *categories[i].ToVO()
+	}
+
+	return Success(ctx, categoryVOs)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/trv3wood/kuaizu-server/api"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// FavoriteProject handles POST /projects/{id}/favorite
+func (s *Server) FavoriteProject(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	if err := s.svc.Favorite.AddProject(ctx.Request().Context(), userID, id); err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return SuccessMessage(ctx, "已收藏")
+}
+
+// UnfavoriteProject handles DELETE /projects/{id}/favorite
+func (s *Server) UnfavoriteProject(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	if err := s.svc.Favorite.Remove(ctx.Request().Context(), userID, models.FavoriteTargetProject, id); err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return SuccessMessage(ctx, "已取消收藏")
+}
+
+// FavoriteTalentProfile handles POST /talent-profiles/{id}/favorite
+func (s *Server) FavoriteTalentProfile(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	if err := s.svc.Favorite.AddTalent(ctx.Request().Context(), userID, id); err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return SuccessMessage(ctx, "已收藏")
+}
+
+// UnfavoriteTalentProfile handles DELETE /talent-profiles/{id}/favorite
+func (s *Server) UnfavoriteTalentProfile(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	if err := s.svc.Favorite.Remove(ctx.Request().Context(), userID, models.FavoriteTargetTalent, id); err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return SuccessMessage(ctx, "已取消收藏")
+}
+
+// ListFavoriteProjects handles GET /users/me/favorite-projects
+func (s *Server) ListFavoriteProjects(ctx echo.Context, params api.ListFavoriteProjectsParams) error {
+	userID := GetUserID(ctx)
+
+	page, size := 1, 10
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if params.Size != nil {
+		size = *params.Size
+	}
+
+	result, err := s.svc.Favorite.ListProjects(ctx.Request().Context(), userID, page, size)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	s.svc.SkillTag.AttachProjects(ctx.Request().Context(), result.List)
+	list := make([]api.ProjectVO, len(result.List))
+	for i, p := range result.List {
+		list[i] = *p.ToVO()
+	}
+
+	pageInfo := api.PageInfo{
+		Page:       &result.Page,
+		Size:       &result.Size,
+		Total:      &result.Total,
+		TotalPages: &result.TotalPages,
+	}
+
+	return Success(ctx, api.ProjectPageResponse{
+		List:     &list,
+		PageInfo: &pageInfo,
+	})
+}
+
+// ListFavoriteTalents handles GET /users/me/favorite-talents
+func (s *Server) ListFavoriteTalents(ctx echo.Context, params api.ListFavoriteTalentsParams) error {
+	userID := GetUserID(ctx)
+
+	page, size := 1, 10
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if params.Size != nil {
+		size = *params.Size
+	}
+
+	result, err := s.svc.Favorite.ListTalents(ctx.Request().Context(), userID, page, size)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	s.svc.SkillTag.AttachTalents(ctx.Request().Context(), result.List)
+	list := make([]api.TalentProfileVO, len(result.List))
+	for i, p := range result.List {
+		list[i] = *p.ToVO()
+	}
+
+	pageInfo := api.PageInfo{
+		Page:       &result.Page,
+		Size:       &result.Size,
+		Total:      &result.Total,
+		TotalPages: &result.TotalPages,
+	}
+
+	return Success(ctx, api.TalentProfilePageResponse{
+		List:     &list,
+		PageInfo: &pageInfo,
+	})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"mime/multipart"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/trv3wood/kuaizu-server/api"
+	"github.com/trv3wood/kuaizu-server/internal/service"
+)
+
+// maxFeedbackContentLength 反馈及追问内容的最大字数
+const maxFeedbackContentLength = 1000
+
+// readFeedbackForm 读取 multipart 表单中的反馈内容与截图
+func readFeedbackForm(ctx echo.Context) (string, []*multipart.FileHeader, string) {
+	form, err := ctx.MultipartForm()
+	if err != nil {
+		return "", nil, "请求参数错误"
+	}
+
+	content := strings.TrimSpace(ctx.FormValue("content"))
+	if content == "" {
+		return "", nil, "反馈内容不能为空"
+	}
+	if utf8.RuneCountInString(content) > maxFeedbackContentLength {
+		return "", nil, "反馈内容不能超过1000字"
+	}
+
+	return content, form.File["images"], ""
+}
+
+// SubmitFeedback handles POST /feedbacks
+func (s *Server) SubmitFeedback(ctx echo.Context) error {
+	content, images, msg := readFeedbackForm(ctx)
+	if msg != "" {
+		return BadRequest(ctx, msg)
+	}
+
+	fb, err := s.svc.Feedback.SubmitFeedback(ctx.Request().Context(), service.FeedbackInput{
+		UserID:  GetUserID(ctx),
+		Content: content,
+		Images:  images,
+	})
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return Success(ctx, fb.ToVO())
+}
+
+// ListMyFeedbacks handles GET /feedbacks
+func (s *Server) ListMyFeedbacks(ctx echo.Context, params api.ListMyFeedbacksParams) error {
+	page, size := 1, 10
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if params.Size != nil {
+		size = *params.Size
+	}
+
+	result, err := s.svc.Feedback.ListMyFeedbacks(ctx.Request().Context(), GetUserID(ctx), page, size)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]api.FeedbackVO, len(result.List))
+	for i := range result.List {
+		list[i] = *result.List[i].ToVO()
+	}
+
+	return Success(ctx, api.FeedbackPageResponse{
+		List: &list,
+		PageInfo: &api.PageInfo{
+			Page:       &result.Page,
+			Size:       &result.Size,
+			Total:      &result.Total,
+			TotalPages: &result.TotalPages,
+		},
+	})
+}
+
+// GetMyFeedback handles GET /feedbacks/{id}
+func (s *Server) GetMyFeedback(ctx echo.Context, id int) error {
+	fb, err := s.svc.Feedback.GetMyFeedback(ctx.Request().Context(), GetUserID(ctx), id)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return Success(ctx, fb.ToVO())
+}
+
+// AddFeedbackMessage handles POST /feedbacks/{id}/messages
+func (s *Server) AddFeedbackMessage(ctx echo.Context, id int) error {
+	content, images, msg := readFeedbackForm(ctx)
+	if msg != "" {
+		return BadRequest(ctx, msg)
+	}
+
+	m, err := s.svc.Feedback.AddUserMessage(ctx.Request().Context(), id, service.FeedbackInput{
+		UserID:  GetUserID(ctx),
+		Content: content,
+		Images:  images,
+	})
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return Success(ctx, m.ToVO())
+}
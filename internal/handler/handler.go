@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"strings"
+
 	"github.com/trv3wood/kuaizu-server/api"
+	"github.com/trv3wood/kuaizu-server/internal/realtime"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 	"github.com/trv3wood/kuaizu-server/internal/service"
 )
@@ -10,14 +13,15 @@ import (
 type Server struct {
 	repo *repository.Repository
 	svc  *service.Services
+	hub  *realtime.Hub
 }
 
 // Ensure Server implements the generated ServerInterface
 var _ api.ServerInterface = (*Server)(nil)
 
 // NewServer creates a new Server instance
-func NewServer(repo *repository.Repository, svc *service.Services) *Server {
-	return &Server{repo: repo, svc: svc}
+func NewServer(repo *repository.Repository, svc *service.Services, hub *realtime.Hub) *Server {
+	return &Server{repo: repo, svc: svc, hub: hub}
 }
 
 // GetUserID extracts user ID from context (set by auth middleware)
@@ -30,6 +34,12 @@ func GetUserID(ctx interface{ Get(string) interface{} }) int {
 	return userID
 }
 
+// OptionalUserID returns the user ID on public endpoints, 0 when the request is anonymous
+func OptionalUserID(ctx interface{ Get(string) interface{} }) int {
+	userID, _ := ctx.Get("userID").(int)
+	return userID
+}
+
 // GetOpenID extracts OpenID from context (set by auth middleware)
 func GetOpenID(ctx interface{ Get(string) interface{} }) string {
 	openID, ok := ctx.Get("openID").(string)
@@ -38,3 +48,15 @@ func GetOpenID(ctx interface{ Get(string) interface{} }) string {
 	}
 	return openID
 }
+
+// trimmedOrNil trims an optional string field and treats a blank value as absent
+func trimmedOrNil(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := strings.TrimSpace(*s)
+	if v == "" {
+		return nil
+	}
+	return &v
+}
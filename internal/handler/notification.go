@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/trv3wood/kuaizu-server/api"
+	"github.com/trv3wood/kuaizu-server/internal/realtime"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// sseHeartbeatInterval 事件流心跳间隔，避免连接被代理或客户端判定为空闲而断开
+const sseHeartbeatInterval = 25 * time.Second
+
+// ListMyNotifications handles GET /notifications
+func (s *Server) ListMyNotifications(ctx echo.Context, params api.ListMyNotificationsParams) error {
+	userID := GetUserID(ctx)
+
+	listParams := repository.NotificationListParams{
+		UserID: userID,
+		Page:   1,
+		Size:   10,
+	}
+	if params.Page != nil {
+		listParams.Page = *params.Page
+	}
+	if params.Size != nil {
+		listParams.Size = *params.Size
+	}
+	if params.UnreadOnly != nil {
+		listParams.UnreadOnly = *params.UnreadOnly
+	}
+
+	result, err := s.svc.Notification.ListNotifications(ctx.Request().Context(), listParams)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]api.NotificationVO, len(result.List))
+	for i := range result.List {
+		list[i] = *result.List[i].ToVO()
+	}
+
+	return Success(ctx, api.NotificationPageResponse{
+		List: &list,
+		PageInfo: &api.PageInfo{
+			Page:       &result.Page,
+			Size:       &result.Size,
+			Total:      &result.Total,
+			TotalPages: &result.TotalPages,
+		},
+	})
+}
+
+// GetUnreadNotificationCount handles GET /notifications/unread-count
+func (s *Server) GetUnreadNotificationCount(ctx echo.Context) error {
+	userID := GetUserID(ctx)
+
+	count, err := s.svc.Notification.CountUnread(ctx.Request().Context(), userID)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return Success(ctx, api.NotificationCountVO{Count: count})
+}
+
+// MarkNotificationRead handles POST /notifications/{id}/read
+func (s *Server) MarkNotificationRead(ctx echo.Context, id int64) error {
+	userID := GetUserID(ctx)
+
+	n, err := s.svc.Notification.MarkRead(ctx.Request().Context(), userID, id)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return Success(ctx, n.ToVO())
+}
+
+// MarkAllNotificationsRead handles POST /notifications/read-all
+func (s *Server) MarkAllNotificationsRead(ctx echo.Context) error {
+	userID := GetUserID(ctx)
+
+	count, err := s.svc.Notification.MarkAllRead(ctx.Request().Context(), userID)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return Success(ctx, api.NotificationCountVO{Count: count})
+}
+
+// StreamNotifications handles GET /notifications/stream
+func (s *Server) StreamNotifications(ctx echo.Context) error {
+	userID := GetUserID(ctx)
+
+	client := s.hub.Connect(userID)
+	defer s.hub.Disconnect(client)
+
+	w := ctx.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := realtime.WriteSSEComment(w, "connected"); err != nil {
+		return nil
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		var err error
+		select {
+		case <-ctx.Request().Context().Done():
+			return nil
+		case msg := <-client.Events():
+			err = realtime.WriteSSE(w, msg)
+		case <-heartbeat.C:
+			err = realtime.WriteSSEComment(w, "ping")
+		}
+		if err != nil {
+			// 客户端已断开
+			return nil
+		}
+		w.Flush()
+	}
+}
@@ -147,3 +147,36 @@ func (s *Server) GetMySentOliveBranches(ctx echo.Context, params api.GetMySentOl
 		PageInfo: &pageInfo,
 	})
 }
+
+// GetMyOliveBranchLedger handles GET /users/me/olive-branch-ledger
+func (s *Server) GetMyOliveBranchLedger(ctx echo.Context, params api.GetMyOliveBranchLedgerParams) error {
+	userID := GetUserID(ctx)
+
+	page, size := 1, 10
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if params.Size != nil {
+		size = *params.Size
+	}
+
+	result, err := s.svc.OliveBranch.ListLedger(ctx.Request().Context(), userID, page, size)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]api.OliveBranchLedgerVO, len(result.List))
+	for i := range result.List {
+		list[i] = *result.List[i].ToVO()
+	}
+
+	return Success(ctx, api.OliveBranchLedgerPageResponse{
+		List: &list,
+		PageInfo: &api.PageInfo{
+			Page:       &result.Page,
+			Size:       &result.Size,
+			Total:      &result.Total,
+			TotalPages: &result.TotalPages,
+		},
+	})
+}
@@ -1,6 +1,9 @@
 package handler
 
 import (
+	"strings"
+	"unicode/utf8"
+
 	"github.com/labstack/echo/v4"
 	"github.com/trv3wood/kuaizu-server/api"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
@@ -131,3 +134,28 @@ func (s *Server) CancelOrder(ctx echo.Context, id int) error {
 
 	return Success(ctx, order.ToVO())
 }
+
+// RequestRefund handles POST /orders/{id}/refund
+func (s *Server) RequestRefund(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	var req api.RequestRefundDTO
+	if err := ctx.Bind(&req); err != nil {
+		return BadRequest(ctx, "请求参数错误")
+	}
+
+	reason := ""
+	if req.Reason != nil {
+		reason = strings.TrimSpace(*req.Reason)
+	}
+	if utf8.RuneCountInString(reason) > 200 {
+		return BadRequest(ctx, "退款原因不能超过200字")
+	}
+
+	refund, err := s.svc.Refund.RequestRefund(ctx.Request().Context(), userID, id, reason)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return Success(ctx, refund.ToVO())
+}
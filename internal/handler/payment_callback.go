@@ -84,7 +84,8 @@ func (s *Server) WechatPayCallback(ctx echo.Context) error {
 		return ctx.JSON(http.StatusOK, successResponse())
 	}
 
-	if order.Status == models.OrderStatusPaid {
+	// 已支付、已退款或已取消的订单视为已处理
+	if order.Status != models.OrderStatusPending {
 		return ctx.JSON(http.StatusOK, successResponse())
 	}
 
@@ -96,3 +97,27 @@ func (s *Server) WechatPayCallback(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, successResponse())
 }
+
+// WechatRefundCallback handles POST /payment/wechat/refund-notify
+func (s *Server) WechatRefundCallback(ctx echo.Context) error {
+	payConfig, err := wechat.DefaultPayConfig()
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, failResponse("支付配置错误"))
+	}
+
+	payClient, err := wechat.NewPayClient(payConfig)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, failResponse("支付配置错误"))
+	}
+
+	notification, err := payClient.ParseRefundNotification(ctx.Request().Context(), ctx.Request())
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, failResponse("验签失败: "+err.Error()))
+	}
+
+	if err := s.svc.Refund.HandleRefundNotification(ctx.Request().Context(), notification); err != nil {
+		return ctx.JSON(http.StatusInternalServerError, failResponse("处理退款通知失败"))
+	}
+
+	return ctx.JSON(http.StatusOK, successResponse())
+}
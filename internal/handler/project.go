@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"unicode/utf8"
+
 	"github.com/labstack/echo/v4"
 	"github.com/trv3wood/kuaizu-server/api"
+	"github.com/trv3wood/kuaizu-server/internal/models"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 	"github.com/trv3wood/kuaizu-server/internal/service"
 )
@@ -10,10 +13,13 @@ import (
 // ListProjects handles GET /projects
 func (s *Server) ListProjects(ctx echo.Context, params api.ListProjectsParams) error {
 	listParams := repository.ListParams{
-		Page:     1,
-		Size:     10,
-		Keyword:  params.Keyword,
-		SchoolID: params.SchoolId,
+		Page:       1,
+		Size:       10,
+		Keyword:    params.Keyword,
+		SchoolID:   params.SchoolId,
+		Education:  params.Education,
+		Skill:      params.Skill,
+		SkillTagID: params.SkillTagId,
 	}
 
 	if params.Page != nil {
@@ -40,6 +46,8 @@ func (s *Server) ListProjects(ctx echo.Context, params api.ListProjectsParams) e
 		return mapServiceError(ctx, err)
 	}
 
+	s.svc.SkillTag.AttachProjects(ctx.Request().Context(), result.List)
+	s.svc.Favorite.MarkProjects(ctx.Request().Context(), OptionalUserID(ctx), result.List)
 	list := make([]api.ProjectVO, len(result.List))
 	for i, p := range result.List {
 		list[i] = *p.ToVO()
@@ -51,6 +59,9 @@ func (s *Server) ListProjects(ctx echo.Context, params api.ListProjectsParams) e
 		Total:      &result.Total,
 		TotalPages: &result.TotalPages,
 	}
+	if result.Truncated {
+		pageInfo.Truncated = &result.Truncated
+	}
 
 	return Success(ctx, api.ProjectPageResponse{
 		List:     &list,
@@ -83,10 +94,19 @@ func (s *Server) CreateProject(ctx echo.Context) error {
 	if err != nil {
 		return mapServiceError(ctx, err)
 	}
+	s.attachProject(ctx, project)
 
 	return Success(ctx, project.ToVO())
 }
 
+// attachProject 回填单个项目的技能标签和当前用户的收藏状态
+func (s *Server) attachProject(ctx echo.Context, p *models.Project) {
+	list := []models.Project{*p}
+	s.svc.SkillTag.AttachProjects(ctx.Request().Context(), list)
+	s.svc.Favorite.MarkProjects(ctx.Request().Context(), OptionalUserID(ctx), list)
+	p.SkillTags, p.Favorited = list[0].SkillTags, list[0].Favorited
+}
+
 // ListMyProjects handles GET /projects/my
 func (s *Server) ListMyProjects(ctx echo.Context, params api.ListMyProjectsParams) error {
 	userID := GetUserID(ctx)
@@ -112,6 +132,8 @@ func (s *Server) ListMyProjects(ctx echo.Context, params api.ListMyProjectsParam
 		return mapServiceError(ctx, err)
 	}
 
+	s.svc.SkillTag.AttachProjects(ctx.Request().Context(), result.List)
+	s.svc.Favorite.MarkProjects(ctx.Request().Context(), userID, result.List)
 	list := make([]api.ProjectVO, len(result.List))
 	for i, p := range result.List {
 		list[i] = *p.ToVO()
@@ -136,6 +158,7 @@ func (s *Server) GetProject(ctx echo.Context, id int) error {
 	if err != nil {
 		return mapServiceError(ctx, err)
 	}
+	s.attachProject(ctx, project)
 
 	return Success(ctx, project.ToDetailVO())
 }
@@ -163,6 +186,7 @@ func (s *Server) UpdateProject(ctx echo.Context, id int) error {
 	if err != nil {
 		return mapServiceError(ctx, err)
 	}
+	s.attachProject(ctx, project)
 
 	return Success(ctx, project.ToVO())
 }
@@ -178,6 +202,39 @@ func (s *Server) DeleteProject(ctx echo.Context, id int) error {
 	return SuccessMessage(ctx, "项目已删除")
 }
 
+// CloseProject handles POST /projects/{id}/close
+func (s *Server) CloseProject(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	project, err := s.svc.Project.CloseProject(ctx.Request().Context(), id, userID)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+	s.attachProject(ctx, project)
+
+	return Success(ctx, project.ToDetailVO())
+}
+
+// PromoteProject handles POST /projects/{id}/promotion
+func (s *Server) PromoteProject(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	var req api.PromoteProjectDTO
+	if err := ctx.Bind(&req); err != nil {
+		return BadRequest(ctx, "请求参数错误")
+	}
+	if req.OrderId <= 0 {
+		return BadRequest(ctx, "订单ID无效")
+	}
+
+	project, err := s.svc.Project.PromoteProject(ctx.Request().Context(), userID, id, req.OrderId)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return Success(ctx, project.ToDetailVO())
+}
+
 // ListProjectApplications handles GET /projects/{id}/applications
 func (s *Server) ListProjectApplications(ctx echo.Context, id int, params api.ListProjectApplicationsParams) error {
 	userID := GetUserID(ctx)
@@ -268,9 +325,22 @@ func (s *Server) ListMyApplications(ctx echo.Context, params api.ListMyApplicati
 func (s *Server) ApplyToProject(ctx echo.Context, id int) error {
 	userID := GetUserID(ctx)
 
+	var req api.ApplyToProjectJSONRequestBody
+	if err := ctx.Bind(&req); err != nil {
+		return BadRequest(ctx, "请求参数错误")
+	}
+
 	input := service.ApplyToProjectInput{
-		ProjectID: id,
-		UserID:    userID,
+		ProjectID:   id,
+		UserID:      userID,
+		ApplyReason: trimmedOrNil(req.ApplyReason),
+		Contact:     trimmedOrNil(req.Contact),
+	}
+	if input.ApplyReason != nil && utf8.RuneCountInString(*input.ApplyReason) > 500 {
+		return BadRequest(ctx, "申请理由不能超过500字")
+	}
+	if input.Contact != nil && utf8.RuneCountInString(*input.Contact) > 100 {
+		return BadRequest(ctx, "联系方式不能超过100字")
 	}
 
 	application, err := s.svc.Project.ApplyToProject(ctx.Request().Context(), input)
@@ -290,9 +360,70 @@ func (s *Server) ReviewApplication(ctx echo.Context, id int) error {
 		return InvalidParams(ctx, err)
 	}
 
-	if err := s.svc.Project.ReviewApplication(ctx.Request().Context(), id, userID, req.Status); err != nil {
+	replyMsg := trimmedOrNil(req.ReplyMsg)
+	if replyMsg != nil && utf8.RuneCountInString(*replyMsg) > 500 {
+		return BadRequest(ctx, "回复不能超过500字")
+	}
+
+	if err := s.svc.Project.ReviewApplication(ctx.Request().Context(), id, userID, req.Status, replyMsg); err != nil {
 		return mapServiceError(ctx, err)
 	}
 
 	return Success(ctx, nil)
 }
+
+// BatchReviewApplications handles POST /projects/{id}/applications/batch-review
+func (s *Server) BatchReviewApplications(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	var req api.BatchReviewApplicationsDTO
+	if err := ctx.Bind(&req); err != nil {
+		return InvalidParams(ctx, err)
+	}
+
+	if len(req.ApplicationIds) == 0 {
+		return BadRequest(ctx, "请选择要审核的申请")
+	}
+	replyMsg := trimmedOrNil(req.ReplyMsg)
+	if replyMsg != nil && utf8.RuneCountInString(*replyMsg) > 500 {
+		return BadRequest(ctx, "回复不能超过500字")
+	}
+
+	result, err := s.svc.Project.BatchReviewApplications(ctx.Request().Context(), id, userID, req.ApplicationIds, req.Status, replyMsg)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return Success(ctx, api.BatchReviewResultVO{
+		ReviewedIds: result.ReviewedIDs,
+		SkippedIds:  result.SkippedIDs,
+	})
+}
+
+// WithdrawApplication handles POST /project-applications/{id}/withdraw
+func (s *Server) WithdrawApplication(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	if err := s.svc.Project.WithdrawApplication(ctx.Request().Context(), id, userID); err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	return SuccessMessage(ctx, "申请已撤回")
+}
+
+// ListApplicationHistory handles GET /project-applications/{id}/history
+func (s *Server) ListApplicationHistory(ctx echo.Context, id int) error {
+	userID := GetUserID(ctx)
+
+	attempts, err := s.svc.Project.ListApplicationHistory(ctx.Request().Context(), id, userID)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]api.ProjectApplicationVO, len(attempts))
+	for i := range attempts {
+		list[i] = *attempts[i].ToVO()
+	}
+
+	return Success(ctx, list)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/trv3wood/kuaizu-server/api"
+)
+
+// ListProjectMembers handles GET /projects/{id}/members
+func (s *Server) ListProjectMembers(ctx echo.Context, id int) error {
+	members, err := s.svc.ProjectMember.ListMembers(ctx.Request().Context(), id)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]api.ProjectMemberVO, len(members))
+	for i := range members {
+		list[i] = *members[i].ToVO()
+	}
+
+	return Success(ctx, list)
+}
+
+// RemoveProjectMember handles DELETE /projects/{id}/members/{userId}
+func (s *Server) RemoveProjectMember(ctx echo.Context, id int, userId int) error {
+	operatorID := GetUserID(ctx)
+
+	if err := s.svc.ProjectMember.RemoveMember(ctx.Request().Context(), id, operatorID, userId); err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	if operatorID == userId {
+		return SuccessMessage(ctx, "已退出项目")
+	}
+	return SuccessMessage(ctx, "已移出成员")
+}
+
+// ListMyTeams handles GET /users/me/teams
+func (s *Server) ListMyTeams(ctx echo.Context, params api.ListMyTeamsParams) error {
+	userID := GetUserID(ctx)
+
+	page, size := 1, 10
+	if params.Page != nil {
+		page = *params.Page
+	}
+	if params.Size != nil {
+		size = *params.Size
+	}
+
+	result, err := s.svc.ProjectMember.ListMyTeams(ctx.Request().Context(), userID, page, size)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	list := make([]api.TeamVO, len(result.List))
+	for i := range result.List {
+		list[i] = *result.List[i].ToTeamVO()
+	}
+
+	return Success(ctx, api.TeamPageResponse{
+		List: &list,
+		PageInfo: &api.PageInfo{
+			Page:       &result.Page,
+			Size:       &result.Size,
+			Total:      &result.Total,
+			TotalPages: &result.TotalPages,
+		},
+	})
+}
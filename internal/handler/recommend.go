@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/trv3wood/kuaizu-server/api"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// ListRecommendedTalents handles GET /projects/{id}/recommended-talents
+func (s *Server) ListRecommendedTalents(ctx echo.Context, id int, params api.ListRecommendedTalentsParams) error {
+	userID := GetUserID(ctx)
+
+	size := 0
+	if params.Size != nil {
+		size = *params.Size
+	}
+
+	results, err := s.svc.Recommend.RecommendTalents(ctx.Request().Context(), id, userID, size)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	talents := make([]models.TalentProfile, len(results))
+	for i := range results {
+		talents[i] = results[i].Talent
+	}
+	s.svc.Favorite.MarkTalents(ctx.Request().Context(), userID, talents)
+
+	list := make([]api.RecommendedTalentVO, len(results))
+	for i := range results {
+		list[i] = api.RecommendedTalentVO{
+			Talent:  *talents[i].ToVO(),
+			Score:   results[i].Score,
+			Reasons: results[i].Reasons,
+		}
+	}
+
+	return Success(ctx, list)
+}
+
+// ListRecommendedProjects handles GET /users/me/recommended-projects
+func (s *Server) ListRecommendedProjects(ctx echo.Context, params api.ListRecommendedProjectsParams) error {
+	userID := GetUserID(ctx)
+
+	size := 0
+	if params.Size != nil {
+		size = *params.Size
+	}
+
+	results, err := s.svc.Recommend.RecommendProjects(ctx.Request().Context(), userID, size)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+
+	projects := make([]models.Project, len(results))
+	for i := range results {
+		projects[i] = results[i].Project
+	}
+	s.svc.Favorite.MarkProjects(ctx.Request().Context(), userID, projects)
+
+	list := make([]api.RecommendedProjectVO, len(results))
+	for i := range results {
+		list[i] = api.RecommendedProjectVO{
+			Project: *projects[i].ToVO(),
+			Score:   results[i].Score,
+			Reasons: results[i].Reasons,
+		}
+	}
+
+	return Success(ctx, list)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/trv3wood/kuaizu-server/api"
 	"github.com/trv3wood/kuaizu-server/internal/models"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/service"
 )
 
 // ListTalentProfiles handles GET /talent-profiles
@@ -23,12 +24,23 @@ func (s *Server) ListTalentProfiles(ctx echo.Context, params api.ListTalentProfi
 
 	status := int(api.TalentStatus(1)) // 仅展示已发布的
 	listParams := repository.TalentProfileListParams{
-		Page:     page,
-		Size:     size,
-		SchoolID: params.SchoolId,
-		MajorID:  params.MajorId,
-		Keyword:  params.Keyword,
-		Status:   &status,
+		Page:       page,
+		Size:       size,
+		SchoolID:   params.SchoolId,
+		MajorID:    params.MajorId,
+		Keyword:    params.Keyword,
+		Skill:      params.Skill,
+		SkillTagID: params.SkillTagId,
+		Status:     &status,
+	}
+
+	// 有关键词或技能时从搜索索引取命中的档案，按相关度排序
+	matches, err := s.svc.Search.Match(ctx.Request().Context(), models.SearchDocTalent, params.Keyword, params.Skill)
+	if err != nil {
+		return mapServiceError(ctx, err)
+	}
+	if matches != nil {
+		listParams.IDs, listParams.Keyword, listParams.Skill = matches.IDs, nil, nil
 	}
 
 	profiles, total, err := s.repo.TalentProfile.List(ctx.Request().Context(), listParams)
@@ -36,9 +48,15 @@ func (s *Server) ListTalentProfiles(ctx echo.Context, params api.ListTalentProfi
 		return InternalError(ctx, "获取人才列表失败")
 	}
 
+	s.svc.SkillTag.AttachTalents(ctx.Request().Context(), profiles)
+	s.svc.Favorite.MarkTalents(ctx.Request().Context(), OptionalUserID(ctx), profiles)
+
 	// Convert to VOs
 	var profileVOs []api.TalentProfileVO
 	for _, p := range profiles {
+		if matches != nil {
+			matches.HighlightTalent(&p)
+		}
 		profileVOs = append(profileVOs, *p.ToVO())
 	}
 
@@ -53,6 +71,9 @@ func (s *Server) ListTalentProfiles(ctx echo.Context, params api.ListTalentProfi
 			TotalPages: &totalPages,
 		},
 	}
+	if matches != nil && matches.Truncated {
+		response.PageInfo.Truncated = &matches.Truncated
+	}
 
 	return Success(ctx, response)
 }
@@ -75,7 +96,12 @@ func (s *Server) UpsertTalentProfile(ctx echo.Context) error {
 		auditTexts = append(auditTexts, *req.ProjectExperience)
 	}
 	if len(auditTexts) > 0 {
-		if err := s.svc.ContentAudit.CheckText(ctx.Request().Context(), auditTexts...); err != nil {
+		if err := s.svc.ContentAudit.CheckText(ctx.Request().Context(), service.TextAuditInput{
+			UserID:  userID,
+			Scene:   models.ContentAuditSceneProfile,
+			BizType: models.ContentAuditBizTalentProfile,
+			Texts:   auditTexts,
+		}); err != nil {
 			return BadRequest(ctx, "内容包含违规信息，请修改后重试")
 		}
 	}
@@ -88,6 +114,12 @@ func (s *Server) UpsertTalentProfile(ctx echo.Context) error {
 		skillSummary = &s
 	}
 
+	if req.Education != nil {
+		if err := service.IsValidStatus("talent_profile.education", *req.Education); err != nil {
+			return mapServiceError(ctx, err)
+		}
+	}
+
 	// Default status to 1 (active) if not provided
 	status := 1
 	if req.Status != nil {
@@ -100,22 +132,27 @@ func (s *Server) UpsertTalentProfile(ctx echo.Context) error {
 		SkillSummary:      skillSummary,
 		ProjectExperience: req.ProjectExperience,
 		MBTI:              req.Mbti,
+		Education:         req.Education,
 		Status:            &status,
 	}
 
-	if err := s.repo.TalentProfile.Upsert(ctx.Request().Context(), profile); err != nil {
-		return InternalError(ctx, "保存人才档案失败")
-	}
-
-	// Fetch the updated profile to return
-	updated, err := s.repo.TalentProfile.GetByUserID(ctx.Request().Context(), userID)
-	if err != nil || updated == nil {
-		return InternalError(ctx, "获取人才档案失败")
+	updated, err := s.svc.TalentProfile.SaveProfile(ctx.Request().Context(), profile)
+	if err != nil {
+		return mapServiceError(ctx, err)
 	}
+	s.attachTalent(ctx, updated)
 
 	return Success(ctx, updated.ToDetailVO())
 }
 
+// attachTalent 回填单个人才档案的技能标签和当前用户的收藏状态
+func (s *Server) attachTalent(ctx echo.Context, p *models.TalentProfile) {
+	list := []models.TalentProfile{*p}
+	s.svc.SkillTag.AttachTalents(ctx.Request().Context(), list)
+	s.svc.Favorite.MarkTalents(ctx.Request().Context(), OptionalUserID(ctx), list)
+	p.SkillTags, p.Favorited = list[0].SkillTags, list[0].Favorited
+}
+
 // GetTalentProfile handles GET /talent-profiles/{id}
 func (s *Server) GetTalentProfile(ctx echo.Context, id int, params api.GetTalentProfileParams) error {
 	profile, err := s.repo.TalentProfile.GetByID(ctx.Request().Context(), id)
@@ -147,6 +184,7 @@ func (s *Server) GetTalentProfile(ctx echo.Context, id int, params api.GetTalent
 	if profile == nil {
 		return NotFound(ctx, "人才档案不存在")
 	}
+	s.attachTalent(ctx, profile)
 
 	return Success(ctx, profile.ToDetailVO())
 }
@@ -162,6 +200,7 @@ func (s *Server) GetMyTalentProfile(ctx echo.Context) error {
 	if profile == nil {
 		return NotFound(ctx, "人才档案不存在")
 	}
+	s.attachTalent(ctx, profile)
 
 	return Success(ctx, profile.ToDetailVO())
 }
@@ -170,8 +209,8 @@ func (s *Server) GetMyTalentProfile(ctx echo.Context) error {
 func (s *Server) DeleteMyTalentProfile(ctx echo.Context) error {
 	userID := GetUserID(ctx)
 
-	if err := s.repo.TalentProfile.DeleteByUserID(ctx.Request().Context(), userID); err != nil {
-		return InternalError(ctx, "删除人才档案失败")
+	if err := s.svc.TalentProfile.DeleteProfile(ctx.Request().Context(), userID); err != nil {
+		return mapServiceError(ctx, err)
 	}
 
 	return Success(ctx, nil)
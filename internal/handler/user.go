@@ -3,6 +3,8 @@ package handler
 import (
 	"github.com/labstack/echo/v4"
 	"github.com/trv3wood/kuaizu-server/api"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/service"
 )
 
 // GetCurrentUser handles GET /users/me
@@ -42,7 +44,13 @@ func (s *Server) UpdateCurrentUser(ctx echo.Context) error {
 	// Update fields if provided
 	if req.Nickname != nil {
 		// 文字内容审核
-		if err := s.svc.ContentAudit.CheckText(ctx.Request().Context(), *req.Nickname); err != nil {
+		if err := s.svc.ContentAudit.CheckText(ctx.Request().Context(), service.TextAuditInput{
+			UserID:  userID,
+			Scene:   models.ContentAuditSceneProfile,
+			BizType: models.ContentAuditBizUser,
+			BizID:   &userID,
+			Texts:   []string{*req.Nickname},
+		}); err != nil {
 			return BadRequest(ctx, "昵称包含违规信息，请修改后重试")
 		}
 		user.Nickname = req.Nickname
@@ -69,6 +77,13 @@ func (s *Server) UpdateCurrentUser(ctx echo.Context) error {
 		return InternalError(ctx, "更新用户信息失败")
 	}
 
+	// 昵称是人才档案搜索文档的标题，修改后重建索引
+	if req.Nickname != nil {
+		if profile, err := s.repo.TalentProfile.GetByUserID(ctx.Request().Context(), userID); err == nil {
+			s.svc.Search.IndexTalent(ctx.Request().Context(), profile)
+		}
+	}
+
 	// Reload user with joined data
 	user, err = s.repo.User.GetByID(ctx.Request().Context(), userID)
 	if err != nil {
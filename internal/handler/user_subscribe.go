@@ -40,9 +40,15 @@ func (s *Server) GetMsgTemplates(ctx echo.Context, params api.GetMsgTemplatesPar
 		return InternalError(ctx, "获取模板失败")
 	}
 
+	counts, err := s.svc.Message.GetSubscribeCounts(ctx.Request().Context(), GetUserID(ctx), bizKeys)
+	if err != nil {
+		return InternalError(ctx, "获取订阅次数失败")
+	}
+
 	data := make([]api.MsgTemplateVO, len(configs))
 	for i, c := range configs {
 		data[i] = *c.ToVO()
+		data[i].RemainingCount = counts[c.BizKey]
 	}
 
 	return Success(ctx, data)
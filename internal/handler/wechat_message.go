@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/trv3wood/kuaizu-server/internal/wechat"
+)
+
+// WechatMessagePush handles GET/POST /wechat/message
+// 小程序消息推送（JSON 明文模式）：
+//   - GET:  服务器地址验证，签名正确时原样返回 echostr
+//   - POST: 事件推送，目前处理 wxa_media_check（图片审核结果），同一 trace_id 重复推送只生效一次
+func (s *Server) WechatMessagePush(ctx echo.Context) error {
+	if !wechat.VerifyPushSignature(
+		os.Getenv("WECHAT_MSG_TOKEN"),
+		ctx.QueryParam("signature"),
+		ctx.QueryParam("timestamp"),
+		ctx.QueryParam("nonce"),
+	) {
+		return ctx.String(http.StatusForbidden, "invalid signature")
+	}
+	if !wechat.PushTimestampFresh(ctx.QueryParam("timestamp"), time.Now()) {
+		return ctx.String(http.StatusForbidden, "stale timestamp")
+	}
+
+	if ctx.Request().Method == http.MethodGet {
+		return ctx.String(http.StatusOK, ctx.QueryParam("echostr"))
+	}
+
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		return ctx.String(http.StatusBadRequest, "read body failed")
+	}
+
+	var msg wechat.PushMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return ctx.String(http.StatusBadRequest, "invalid body")
+	}
+
+	switch msg.Event {
+	case wechat.EventMediaCheck:
+		var event wechat.MediaCheckEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return ctx.String(http.StatusBadRequest, "invalid body")
+		}
+		if err := s.svc.ImageAudit.HandleMediaCheckResult(
+			ctx.Request().Context(), event.TraceID, event.Result.Suggest, event.Result.Label,
+		); err != nil {
+			// 返回非 success 让微信重试
+			return ctx.String(http.StatusInternalServerError, "fail")
+		}
+	default:
+		log.Printf("[WechatMessagePush] ignore event %q", msg.Event)
+	}
+
+	return ctx.String(http.StatusOK, "success")
+}
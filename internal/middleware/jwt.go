@@ -29,8 +29,13 @@ func JWTAuth(config *JWTConfig) echo.MiddlewareFunc {
 
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			// Skip authentication if skipper returns true
+			// Skip authentication if skipper returns true. A valid token on a
+			// public endpoint still identifies the user, e.g. for favorited flags.
 			if config.Skipper != nil && config.Skipper(c) {
+				if claims := optionalClaims(config.JWTConfig, c); claims != nil {
+					c.Set("userID", claims.UserID)
+					c.Set("openID", claims.OpenID)
+				}
 				return next(c)
 			}
 
@@ -70,3 +75,17 @@ func JWTAuth(config *JWTConfig) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// optionalClaims parses the Bearer token if present, returning nil when it is
+// missing or invalid
+func optionalClaims(config *auth.Config, c echo.Context) *auth.Claims {
+	parts := strings.SplitN(c.Request().Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		return nil
+	}
+	claims, err := auth.ParseToken(config, parts[1])
+	if err != nil {
+		return nil
+	}
+	return claims
+}
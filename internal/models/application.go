@@ -8,17 +8,57 @@ import (
 
 // ProjectApplication represents a project application in the database
 type ProjectApplication struct {
-	ID        int       `db:"id"`
-	ProjectID int       `db:"project_id"`
-	UserID    int       `db:"user_id"`
-	Status    int       `db:"status"` // 0-待审核, 1-已通过, 2-已拒绝
-	AppliedAt time.Time `db:"applied_at"`
-	UpdatedAt time.Time `db:"updated_at"`
+	ID          int       `db:"id"`
+	ProjectID   int       `db:"project_id"`
+	UserID      int       `db:"user_id"`
+	ApplyReason *string   `db:"apply_reason"` // 申请理由/留言
+	Contact     *string   `db:"contact"`      // 联系方式，见 ContactVisibleTo
+	Status      int       `db:"status"`       // 0-待审核, 1-已通过, 2-已拒绝, 3-已撤回
+	ReplyMsg    *string   `db:"reply_msg"`    // 队长回复
+	AppliedAt   time.Time `db:"applied_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
 
 	// Joined fields
-	ProjectName   *string        `db:"project_name"`
-	Applicant     *User          `db:"-"`
-	TalentProfile *TalentProfile `db:"-"`
+	ProjectName      *string                `db:"project_name"`
+	ProjectCreatorID int                    `db:"project_creator_id"`
+	Applicant        *User                  `db:"-"`
+	TalentProfile    *TalentProfile         `db:"-"`
+	StatusLogs       []ApplicationStatusLog `db:"-"`
+}
+
+// ApplicationStatusLog 申请状态变更记录，提交、审核、撤回各写入一条
+type ApplicationStatusLog struct {
+	ID            int       `db:"id"`
+	ApplicationID int       `db:"application_id"`
+	Status        int       `db:"status"`
+	OperatorID    *int      `db:"operator_id"` // 提交和撤回为申请人，审核为队长
+	Remark        *string   `db:"remark"`      // 审核时为队长回复
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// ToVO converts ApplicationStatusLog to API ApplicationStatusLogVO
+func (l *ApplicationStatusLog) ToVO() *api.ApplicationStatusLogVO {
+	return &api.ApplicationStatusLogVO{
+		Status:     api.ApplicationStatus(l.Status),
+		OperatorId: l.OperatorID,
+		Remark:     l.Remark,
+		CreatedAt:  l.CreatedAt,
+	}
+}
+
+// ContactVisibleTo 联系方式只对队长可见，申请人在申请通过后可见
+func (a *ProjectApplication) ContactVisibleTo(viewerID int) bool {
+	if viewerID == a.ProjectCreatorID {
+		return true
+	}
+	return viewerID == a.UserID && a.Status == ApplicationStatusApproved
+}
+
+// RedactContact 对无权查看的用户隐藏联系方式
+func (a *ProjectApplication) RedactContact(viewerID int) {
+	if !a.ContactVisibleTo(viewerID) {
+		a.Contact = nil
+	}
 }
 
 // ToVO converts ProjectApplication to API ProjectApplicationVO
@@ -28,7 +68,10 @@ func (a *ProjectApplication) ToVO() *api.ProjectApplicationVO {
 		Id:          &a.ID,
 		ProjectId:   &a.ProjectID,
 		ProjectName: a.ProjectName,
+		ApplyReason: a.ApplyReason,
+		Contact:     a.Contact,
 		Status:      (*api.ApplicationStatus)(&a.Status),
+		ReplyMsg:    a.ReplyMsg,
 		AppliedAt:   &a.AppliedAt,
 	}
 
@@ -40,5 +83,13 @@ func (a *ProjectApplication) ToVO() *api.ProjectApplicationVO {
 		vo.TalentProfile = a.TalentProfile.ToVO()
 	}
 
+	if a.StatusLogs != nil {
+		logs := make([]api.ApplicationStatusLogVO, len(a.StatusLogs))
+		for i := range a.StatusLogs {
+			logs[i] = *a.StatusLogs[i].ToVO()
+		}
+		vo.StatusLogs = &logs
+	}
+
 	return vo
 }
@@ -14,12 +14,96 @@ const (
 	EmailPromotionStatusFailed    EmailPromotionStatus = 3 // 失败
 )
 
+// Email Task Status
+const (
+	EmailTaskStatusPending  EmailTaskStatus = 0 // 待发送
+	EmailTaskStatusSending  EmailTaskStatus = 1 // 发送中
+	EmailTaskStatusSuccess  EmailTaskStatus = 2 // 成功
+	EmailTaskStatusFailed   EmailTaskStatus = 3 // 失败
+	EmailTaskStatusRetrying EmailTaskStatus = 4 // 重试中
+)
+
+// Email Template Code
+const (
+	EmailTemplateProjectPromotion = "project_promotion" // 项目推广邮件
+)
+
+// Email Provider Type
+const (
+	EmailProviderSMTP     = "smtp"     // 通用 SMTP
+	EmailProviderAliyun   = "aliyun"   // 阿里云邮件推送（SMTP 接入）
+	EmailProviderSendGrid = "sendgrid" // SendGrid（暂未接入）
+	EmailProviderFile     = "file"     // 本地 mbox 文件，用于测试环境
+)
+
+// Content Audit Suggest
+const (
+	ContentAuditSuggestPass   = "pass"   // 通过
+	ContentAuditSuggestReview = "review" // 需人工复审
+	ContentAuditSuggestRisky  = "risky"  // 违规
+)
+
+// Content Audit Source
+const (
+	ContentAuditSourceWechat = "wechat" // 微信内容安全接口
+	ContentAuditSourceLocal  = "local"  // 本地关键词/正则规则
+)
+
+// Content Audit Scene
+const (
+	ContentAuditSceneProfile   = 1 // 资料
+	ContentAuditSceneComment   = 2 // 评论
+	ContentAuditSceneForum     = 3 // 论坛
+	ContentAuditSceneSocialLog = 4 // 社交日志
+)
+
+// Content Audit Business Type
+const (
+	ContentAuditBizProject       = "project"        // 项目
+	ContentAuditBizUser          = "user"           // 用户资料
+	ContentAuditBizTalentProfile = "talent_profile" // 人才档案
+	ContentAuditBizApplication   = "application"    // 项目申请（申请理由、联系方式、队长回复）
+)
+
+// Image Audit Status
+const (
+	ImageAuditStatusPending    = 0 // 待机审
+	ImageAuditStatusPassed     = 1 // 通过
+	ImageAuditStatusFlagged    = 2 // 待人工复审
+	ImageAuditStatusRejected   = 3 // 驳回
+	ImageAuditStatusSuperseded = 4 // 已被新图片替代
+)
+
+// Image Audit Business Type
+const (
+	ImageAuditBizAvatar = "avatar" // 用户头像
+	ImageAuditBizCover  = "cover"  // 用户封面图
+)
+
+// Image Audit Source
+const (
+	ImageAuditSourceWechat = "wechat" // 微信多媒体内容安全接口
+	ImageAuditSourceAdmin  = "admin"  // 管理员人工审核
+)
+
+// Favorite Target Types
+const (
+	FavoriteTargetProject = "project" // 项目
+	FavoriteTargetTalent  = "talent"  // 人才档案
+)
+
 // Feedback Status
 const (
 	FeedbackStatusPending = 0 // 待处理
 	FeedbackStatusDone    = 1 // 已处理
 )
 
+// Feedback Message Sender
+const (
+	FeedbackSenderUser  = "user"  // 用户
+	FeedbackSenderAdmin = "admin" // 管理员
+)
+
 // Olive Branch Status
 const (
 	OliveBranchStatusPending  = 0 // 待处理
@@ -34,6 +118,16 @@ const (
 	OliveBranchCostPaid = 2 // 付费额度
 )
 
+// Olive Branch Ledger Entry Type
+const (
+	OliveBranchLedgerPurchase    = "purchase"     // 购买橄榄枝
+	OliveBranchLedgerOrderRefund = "order_refund" // 订单退款收回（退款失败时恢复）
+	OliveBranchLedgerFreeUse     = "free_use"     // 使用每日免费额度
+	OliveBranchLedgerSend        = "send"         // 使用付费余额发送
+	OliveBranchLedgerRefund      = "refund"       // 退还橄榄枝额度
+	OliveBranchLedgerAdminGrant  = "admin_grant"  // 管理员调整
+)
+
 // Olive Branch Type
 const (
 	OliveBranchTypeTalent = 1 // 人才互联
@@ -45,6 +139,22 @@ const (
 	OrderStatusPaid      = 1 // 已支付
 	OrderStatusCancelled = 2 // 已取消
 	OrderStatusRefunded  = 3 // 已退款
+	OrderStatusRefunding = 4 // 退款中
+)
+
+// Order Refund Status
+const (
+	RefundStatusPending    = 0 // 待审核（用户申请）
+	RefundStatusProcessing = 1 // 退款中
+	RefundStatusSuccess    = 2 // 退款成功
+	RefundStatusFailed     = 3 // 退款失败，权益已恢复
+	RefundStatusRejected   = 4 // 已驳回
+)
+
+// Order Refund Source
+const (
+	RefundSourceUser  = "user"  // 用户申请
+	RefundSourceAdmin = "admin" // 管理员发起
 )
 
 // Product Type
@@ -53,6 +163,19 @@ const (
 	ProductTypeBenefit  = 2 // 服务权益
 )
 
+// Benefit Kind (product.config_json 中的 kind)
+const (
+	BenefitKindEmailPromotion = "email_promotion" // 邮件推广，数量为收件人数
+	BenefitKindProjectTop     = "project_top"     // 项目置顶，数量为天数
+	BenefitKindOliveBranch    = "olive_branch"    // 橄榄枝
+)
+
+// User Entitlement Status
+const (
+	EntitlementStatusActive  = 1 // 有效
+	EntitlementStatusRevoked = 2 // 已撤销（退款）
+)
+
 // Project Direction
 const (
 	ProjectDirectionLaunch      = 1 // 落地
@@ -88,11 +211,38 @@ const (
 	EducationPostgraduate  = 3 // 研究生
 )
 
+// Skill Tag Status
+const (
+	SkillTagStatusDisabled = 0 // 停用，不参与联想和抽取
+	SkillTagStatusActive   = 1 // 启用
+)
+
 // Project Application Status
 const (
-	ApplicationStatusPending  = 0 // 待审核
-	ApplicationStatusApproved = 1 // 已通过
-	ApplicationStatusRejected = 2 // 已拒绝
+	ApplicationStatusPending   = 0 // 待审核
+	ApplicationStatusApproved  = 1 // 已通过
+	ApplicationStatusRejected  = 2 // 已拒绝
+	ApplicationStatusWithdrawn = 3 // 已撤回
+)
+
+// Project Member Role
+const (
+	ProjectMemberRoleLeader = "leader" // 队长
+	ProjectMemberRoleMember = "member" // 队员
+)
+
+// Project Member Source
+const (
+	ProjectMemberSourceCreator     = "creator"      // 创建项目
+	ProjectMemberSourceApplication = "application"  // 申请通过
+	ProjectMemberSourceOliveBranch = "olive_branch" // 接受橄榄枝邀请
+)
+
+// Project Member Status
+const (
+	ProjectMemberStatusActive  = 1 // 在队
+	ProjectMemberStatusLeft    = 2 // 已退出
+	ProjectMemberStatusRemoved = 3 // 被队长移出
 )
 
 // Talent Profile Status
@@ -108,6 +258,34 @@ const (
 	UserAuthStatusFailed = 2 // 认证失败
 )
 
+// Message Outbox Status
+const (
+	MessageOutboxStatusPending  = 0 // 待发送
+	MessageOutboxStatusSending  = 1 // 发送中
+	MessageOutboxStatusSent     = 2 // 已发送
+	MessageOutboxStatusFailed   = 3 // 失败
+	MessageOutboxStatusRetrying = 4 // 重试中
+	MessageOutboxStatusSkipped  = 5 // 已跳过（用户没有剩余的订阅授权）
+)
+
+// Notification Types
+// related_id 含义：申请类为申请ID，橄榄枝类为橄榄枝ID，项目审核与成员变动为项目ID，反馈回复为反馈ID，
+// 收藏的项目关闭为项目ID，收藏的人才下线为人才档案ID
+const (
+	NotificationTypeApplicationReceived = "application_received"  // 收到项目申请
+	NotificationTypeApplicationReviewed = "application_reviewed"  // 项目申请审核结果
+	NotificationTypeOliveBranchReceived = "olive_branch_received" // 收到橄榄枝邀请
+	NotificationTypeOliveBranchHandled  = "olive_branch_handled"  // 橄榄枝被接受/拒绝
+	NotificationTypeOliveBranchExpired  = "olive_branch_expired"  // 橄榄枝超时未处理
+	NotificationTypeProjectAudit        = "project_audit"         // 项目审核结果
+	NotificationTypeCertification       = "certification"         // 身份认证结果
+	NotificationTypeFeedbackReply       = "feedback_reply"        // 反馈回复
+	NotificationTypeMemberLeft          = "member_left"           // 队员退出项目
+	NotificationTypeMemberRemoved       = "member_removed"        // 被移出项目
+	NotificationTypeFavoriteClosed      = "favorite_closed"       // 收藏的项目已关闭
+	NotificationTypeFavoriteOffline     = "favorite_offline"      // 收藏的人才已下线
+)
+
 // Message Business Keys (Subscription Messages)
 const (
 	MsgBizKeyCardReceived       = "MSG_CARD_RECEIVED"        // 收到名片通知
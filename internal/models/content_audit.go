@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ContentAuditLog 内容审核记录，每次审核调用一条
+type ContentAuditLog struct {
+	ID        int64     `db:"id"`
+	UserID    int       `db:"user_id"`  // 提交内容的用户
+	BizType   string    `db:"biz_type"` // 业务类型：project/user/talent_profile
+	BizID     *int      `db:"biz_id"`   // 业务ID，创建前审核时为空，创建后回填
+	Scene     int       `db:"scene"`    // 审核场景：1-资料 2-评论 3-论坛 4-社交日志
+	Content   string    `db:"content"`  // 审核的文本
+	Source    string    `db:"source"`   // 审核来源：wechat/local
+	Suggest   string    `db:"suggest"`  // 审核建议：pass/review/risky
+	Label     int       `db:"label"`    // 命中标签，100 为正常
+	Keyword   *string   `db:"keyword"`  // 命中的关键词
+	TraceID   *string   `db:"trace_id"` // 微信返回的 trace_id
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// EmailProviderConfig 邮件服务商配置
+type EmailProviderConfig struct {
+	ID           int       `db:"id"`
+	ProviderType string    `db:"provider_type"` // 服务商类型：aliyun/smtp/sendgrid/file
+	ConfigName   string    `db:"config_name"`   // 配置名称
+	ConfigJSON   string    `db:"config_json"`   // 配置参数JSON
+	IsDefault    bool      `db:"is_default"`    // 是否默认
+	IsActive     bool      `db:"is_active"`     // 是否启用
+	Priority     int       `db:"priority"`      // 优先级（数字越小优先级越高）
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// EmailTaskStatus 邮件发送任务状态
+type EmailTaskStatus int
+
+// EmailTask 邮件发送任务（每个收件人一条）
+type EmailTask struct {
+	ID             int64           `db:"id"`
+	PromotionID    int             `db:"promotion_id"`    // 关联的推广记录ID
+	RecipientEmail string          `db:"recipient_email"` // 收件人邮箱
+	TemplateCode   string          `db:"template_code"`   // 使用的模板编码
+	TemplateVars   *string         `db:"template_vars"`   // 模板变量JSON
+	Status         EmailTaskStatus `db:"status"`          // 任务状态
+	RetryCount     int             `db:"retry_count"`     // 重试次数
+	ErrorMsg       *string         `db:"error_msg"`       // 错误信息
+	SendTime       *time.Time      `db:"send_time"`       // 实际发送时间
+	NextRetryAt    *time.Time      `db:"next_retry_at"`   // 下次可执行时间（重试退避 / 发送租约）
+	ClaimToken     *string         `db:"claim_token"`     // 领取令牌，写回结果时校验
+	ProviderID     *int            `db:"provider_id"`     // 实际发送的服务商配置ID，0 表示环境变量中的 SMTP
+	CreateTime     time.Time       `db:"create_time"`
+}
+
+// EmailTaskStats 某次推广下各状态的任务数量
+type EmailTaskStats struct {
+	Pending  int `db:"pending"`
+	Sending  int `db:"sending"`
+	Success  int `db:"success"`
+	Failed   int `db:"failed"`
+	Retrying int `db:"retrying"`
+}
+
+// Unfinished 返回尚未结束（待发送/发送中/重试中）的任务数量
+func (s *EmailTaskStats) Unfinished() int {
+	return s.Pending + s.Sending + s.Retrying
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// EmailTemplate 邮件模板
+type EmailTemplate struct {
+	ID           int       `db:"id"`
+	TemplateCode string    `db:"template_code"` // 模板编码（唯一）
+	TemplateName string    `db:"template_name"` // 模板名称
+	Subject      string    `db:"subject"`       // 邮件主题（支持模板变量）
+	HTMLContent  *string   `db:"html_content"`  // HTML模板内容
+	TextContent  *string   `db:"text_content"`  // 纯文本模板内容（备用）
+	Description  *string   `db:"description"`   // 模板描述
+	IsActive     bool      `db:"is_active"`     // 是否启用
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// Favorite represents a project or talent profile saved by a user
+type Favorite struct {
+	ID         int       `db:"id"`
+	UserID     int       `db:"user_id"`
+	TargetType string    `db:"target_type"` // project-项目 talent-人才档案
+	TargetID   int       `db:"target_id"`
+	CreatedAt  time.Time `db:"created_at"`
+}
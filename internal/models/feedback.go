@@ -1,18 +1,95 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/api"
+	"github.com/trv3wood/kuaizu-server/internal/oss"
+)
 
 // Feedback represents a user feedback in the database
 type Feedback struct {
 	ID           int       `db:"id"`
 	UserID       int       `db:"user_id"`
 	Content      string    `db:"content"`
-	ContactImage *string   `db:"contact_image"`
-	Status       int       `db:"status"` // 0=pending, 1=handled
-	AdminReply   *string   `db:"admin_reply"`
+	ContactImage *string   `db:"contact_image"` // 截图 OSS key，多张以逗号分隔
+	Status       int       `db:"status"`        // 0=pending, 1=handled
 	CreatedAt    time.Time `db:"created_at"`
 	UpdatedAt    time.Time `db:"updated_at"`
 
 	// Joined fields
-	UserNickname *string `db:"nickname"`
+	UserNickname *string           `db:"nickname"`
+	Messages     []FeedbackMessage `db:"-"`
+}
+
+// FeedbackMessage 反馈下用户与管理员的往来消息
+type FeedbackMessage struct {
+	ID         int       `db:"id"`
+	FeedbackID int       `db:"feedback_id"`
+	SenderType string    `db:"sender_type"` // user-用户 admin-管理员
+	SenderID   *int      `db:"sender_id"`   // 用户ID或管理员ID
+	Content    string    `db:"content"`
+	Images     *string   `db:"images"` // 截图 OSS key，多张以逗号分隔
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// JoinImageKeys 把多张截图的 OSS key 合并为一列存储，没有截图时为 nil
+func JoinImageKeys(keys []string) *string {
+	if len(keys) == 0 {
+		return nil
+	}
+	v := strings.Join(keys, ",")
+	return &v
+}
+
+// SplitImageKeys 拆分合并存储的截图 OSS key
+func SplitImageKeys(v *string) []string {
+	if v == nil || *v == "" {
+		return []string{}
+	}
+	return strings.Split(*v, ",")
+}
+
+// imageURLs 把合并存储的截图 OSS key 转为完整 URL
+func imageURLs(v *string) []string {
+	keys := SplitImageKeys(v)
+	urls := make([]string, len(keys))
+	for i, key := range keys {
+		urls[i] = oss.FullURL(key)
+	}
+	return urls
+}
+
+// ToVO converts Feedback to API FeedbackVO
+func (f *Feedback) ToVO() *api.FeedbackVO {
+	vo := &api.FeedbackVO{
+		Id:        f.ID,
+		Content:   f.Content,
+		Images:    imageURLs(f.ContactImage),
+		Status:    api.FeedbackVOStatus(f.Status),
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+
+	if f.Messages != nil {
+		messages := make([]api.FeedbackMessageVO, len(f.Messages))
+		for i := range f.Messages {
+			messages[i] = *f.Messages[i].ToVO()
+		}
+		vo.Messages = &messages
+	}
+
+	return vo
+}
+
+// ToVO converts FeedbackMessage to API FeedbackMessageVO
+func (m *FeedbackMessage) ToVO() *api.FeedbackMessageVO {
+	return &api.FeedbackMessageVO{
+		Id:         m.ID,
+		SenderType: api.FeedbackSenderType(m.SenderType),
+		Content:    m.Content,
+		Images:     imageURLs(m.Images),
+		CreatedAt:  m.CreatedAt,
+	}
 }
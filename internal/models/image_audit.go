@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ImageAudit 图片审核记录
+// 新上传的头像/封面先以待审核状态保存，审核通过后才写入用户资料。
+type ImageAudit struct {
+	ID        int64     `db:"id"`
+	UserID    int       `db:"user_id"`
+	BizType   string    `db:"biz_type"`  // 图片用途：avatar/cover
+	ImageKey  string    `db:"image_key"` // OSS 相对路径
+	Status    int       `db:"status"`    // 0-待机审 1-通过 2-待人工复审 3-驳回 4-已被新图片替代
+	Source    *string   `db:"source"`    // 审核来源：wechat/admin
+	TraceID   *string   `db:"trace_id"`  // 微信 media_check_async 的 trace_id
+	Suggest   *string   `db:"suggest"`   // 审核建议：pass/review/risky
+	Label     *int      `db:"label"`     // 命中标签
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+
+	// Joined fields
+	UserNickname *string `db:"user_nickname"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// MessageOutbox 订阅消息发件箱
+// 与业务变更在同一事务中写入，由后台分发器按顺序投递，失败按指数退避重试。
+type MessageOutbox struct {
+	ID          int64      `db:"id"`
+	UserID      int        `db:"user_id"`
+	BizKey      string     `db:"biz_key"`       // 消息业务键，见 MsgBizKey*
+	Payload     string     `db:"payload"`       // 业务数据 JSON，如 {"project_name": "..."}
+	Status      int        `db:"status"`        // 0-待发送 1-发送中 2-已发送 3-失败 4-重试中
+	RetryCount  int        `db:"retry_count"`   // 已重试次数
+	ErrorMsg    *string    `db:"error_msg"`     // 最近一次失败原因
+	NextRetryAt *time.Time `db:"next_retry_at"` // 下次发送时间；发送中时为租约到期时间
+	ClaimToken  *string    `db:"claim_token"`   // 领取令牌，写回结果时校验
+	SentAt      *time.Time `db:"sent_at"`       // 发送成功时间
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+
+	// Joined fields
+	UserNickname *string `db:"user_nickname"`
+}
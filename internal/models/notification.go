@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/api"
+)
+
+// Notification 站内通知
+// 每个业务事件都写入一条，与微信订阅消息并行，用户拒绝订阅或授权次数用完时仍可在消息中心查看。
+type Notification struct {
+	ID        int64      `db:"id"`
+	UserID    int        `db:"user_id"`
+	Type      string     `db:"type"`       // 通知类型，见 NotificationType*
+	Title     string     `db:"title"`      // 标题
+	Content   string     `db:"content"`    // 正文
+	RelatedID *int       `db:"related_id"` // 关联业务ID，含义随通知类型而定
+	IsRead    bool       `db:"is_read"`
+	ReadAt    *time.Time `db:"read_at"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// ToVO converts Notification to API NotificationVO
+func (n *Notification) ToVO() *api.NotificationVO {
+	return &api.NotificationVO{
+		Id:        n.ID,
+		Type:      n.Type,
+		Title:     n.Title,
+		Content:   n.Content,
+		RelatedId: n.RelatedID,
+		IsRead:    n.IsRead,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+}
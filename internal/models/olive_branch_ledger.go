@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/api"
+)
+
+// OliveBranchLedger 橄榄枝额度流水
+// 只追加不修改，每次免费额度或付费余额发生变动都记录一条。
+type OliveBranchLedger struct {
+	ID            int64     `db:"id"`
+	UserID        int       `db:"user_id"`
+	EntryType     string    `db:"entry_type"`      // 流水类型，见 OliveBranchLedger*
+	CostType      int       `db:"cost_type"`       // 1-免费额度, 2-付费额度
+	Amount        int       `db:"amount"`          // 变动数量，增加为正、扣减为负
+	OliveBranchID *int      `db:"olive_branch_id"` // 关联橄榄枝ID
+	OrderID       *int      `db:"order_id"`        // 关联订单ID
+	Remark        *string   `db:"remark"`          // 备注
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// ToVO converts OliveBranchLedger to API OliveBranchLedgerVO
+func (l *OliveBranchLedger) ToVO() *api.OliveBranchLedgerVO {
+	return &api.OliveBranchLedgerVO{
+		Id:            &l.ID,
+		EntryType:     &l.EntryType,
+		CostType:      &l.CostType,
+		Amount:        &l.Amount,
+		OliveBranchId: l.OliveBranchID,
+		OrderId:       l.OrderID,
+		Remark:        l.Remark,
+		CreatedAt:     &l.CreatedAt,
+	}
+}
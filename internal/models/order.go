@@ -14,7 +14,7 @@ type Order struct {
 	Price      float64    `db:"price"`        // 下单时的单价快照
 	Quantity   int        `db:"quantity"`     // 购买数量
 	ActualPaid float64    `db:"actual_paid"`  // 实付金额
-	Status     int        `db:"status"`       // 0-待支付, 1-已支付, 2-已取消, 3-已退款
+	Status     int        `db:"status"`       // 0-待支付, 1-已支付, 2-已取消, 3-已退款, 4-退款中
 	WxPayNo    *string    `db:"wx_pay_no"`    // 微信支付订单号
 	OutTradeNo *string    `db:"out_trade_no"` // 商户单号
 	PayTime    *time.Time `db:"pay_time"`     // 支付时间
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/api"
+)
+
+// OrderRefund 订单退款记录
+// 用户申请的退款先进入待审核，管理员通过后才调用微信退款；管理员也可以直接发起退款。
+type OrderRefund struct {
+	ID          int        `db:"id"`
+	OrderID     int        `db:"order_id"`
+	UserID      int        `db:"user_id"`
+	OutRefundNo string     `db:"out_refund_no"` // 商户退款单号
+	RefundID    *string    `db:"refund_id"`     // 微信退款单号
+	Amount      float64    `db:"amount"`        // 退款金额
+	Reason      *string    `db:"reason"`        // 退款原因
+	Status      int        `db:"status"`        // 0-待审核 1-退款中 2-退款成功 3-退款失败 4-已驳回
+	Source      string     `db:"source"`        // 发起方：user/admin
+	Note        *string    `db:"note"`          // 驳回原因或失败原因
+	SuccessTime *time.Time `db:"success_time"`  // 退款到账时间
+	CreatedAt   time.Time  `db:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at"`
+
+	// Joined fields
+	ProductName  *string `db:"product_name"`
+	UserNickname *string `db:"user_nickname"`
+}
+
+// ToVO converts OrderRefund to API OrderRefundVO
+func (r *OrderRefund) ToVO() *api.OrderRefundVO {
+	status := api.RefundStatus(r.Status)
+
+	return &api.OrderRefundVO{
+		Id:          &r.ID,
+		OrderId:     &r.OrderID,
+		Amount:      &r.Amount,
+		Reason:      r.Reason,
+		Status:      &status,
+		Note:        r.Note,
+		SuccessTime: r.SuccessTime,
+		CreatedAt:   &r.CreatedAt,
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/trv3wood/kuaizu-server/api"
@@ -13,10 +15,57 @@ type Product struct {
 	Type        int       `db:"type"` // 类型: 1-虚拟币, 2-服务权益
 	Description *string   `db:"description"`
 	Price       float64   `db:"price"`
+	ConfigJSON  *string   `db:"config_json"` // 权益配置，见 BenefitConfig
 	CreatedAt   time.Time `db:"created_at"`
 	UpdatedAt   time.Time `db:"updated_at"`
 }
 
+// BenefitConfig 服务权益商品的配置，对应 product.config_json
+// 每购买一份发放的权益数量由 Kind 对应的字段决定，例如
+// {"kind":"email_promotion","recipients":50} 表示每份可向 50 人发送推广邮件。
+type BenefitConfig struct {
+	Kind          string `json:"kind"`
+	Recipients    int    `json:"recipients,omitempty"`    // email_promotion: 每份的收件人数
+	Days          int    `json:"days,omitempty"`          // project_top: 每份的置顶天数
+	OliveBranches int    `json:"oliveBranches,omitempty"` // olive_branch: 每份的橄榄枝数量
+}
+
+// PerUnit 返回每份商品发放的权益数量
+func (c *BenefitConfig) PerUnit() int {
+	switch c.Kind {
+	case BenefitKindEmailPromotion:
+		return c.Recipients
+	case BenefitKindProjectTop:
+		return c.Days
+	case BenefitKindOliveBranch:
+		return c.OliveBranches
+	}
+	return 0
+}
+
+// BenefitConfig 解析商品的权益配置
+// 虚拟币商品没有配置时按每份一个橄榄枝处理。
+func (p *Product) BenefitConfig() (*BenefitConfig, error) {
+	if p.ConfigJSON == nil || *p.ConfigJSON == "" {
+		if p.Type == ProductTypeCurrency {
+			return &BenefitConfig{Kind: BenefitKindOliveBranch, OliveBranches: 1}, nil
+		}
+		return nil, fmt.Errorf("product %d has no benefit config", p.ID)
+	}
+
+	var cfg BenefitConfig
+	if err := json.Unmarshal([]byte(*p.ConfigJSON), &cfg); err != nil {
+		return nil, fmt.Errorf("parse product %d config: %w", p.ID, err)
+	}
+	if p.Type == ProductTypeCurrency && cfg.Kind == "" {
+		cfg.Kind = BenefitKindOliveBranch
+	}
+	if cfg.PerUnit() <= 0 {
+		return nil, fmt.Errorf("product %d has invalid benefit config %q", p.ID, *p.ConfigJSON)
+	}
+	return &cfg, nil
+}
+
 // ToVO converts Product to API ProductVO
 func (p *Product) ToVO() *api.ProductVO {
 	return &api.ProductVO{
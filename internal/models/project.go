@@ -26,8 +26,13 @@ type Project struct {
 	SkillRequirement     *string    `db:"skill_requirement"`
 
 	// Joined fields
-	SchoolName *string `db:"school_name"`
-	Creator    *User   `db:"-"`
+	SchoolName         *string `db:"school_name"`
+	Creator            *User   `db:"-"`
+	CurrentMemberCount *int    `db:"-"` // 当前在队队员人数，不含队长
+
+	SkillTags  []SkillTag        `db:"-"` // 从技能要求中识别出的技能标签
+	Highlights map[string]string `db:"-"` // 搜索命中的高亮片段
+	Favorited  bool              `db:"-"` // 当前用户是否已收藏
 }
 
 // ToVO converts Project to API ProjectVO
@@ -45,6 +50,9 @@ func (p *Project) ToVO() *api.ProjectVO {
 		Status:          &status,
 		PromotionStatus: &p.PromotionStatus,
 		IsCrossSchool:   p.IsCrossSchool,
+		SkillTags:       skillTagsVO(p.SkillTags),
+		Favorited:       &p.Favorited,
+		Highlights:      highlightsVO(p.Highlights),
 	}
 }
 
@@ -68,6 +76,9 @@ func (p *Project) ToDetailVO() *api.ProjectDetailVO {
 		EducationRequirement: p.EducationRequirement,
 		SkillRequirement:     p.SkillRequirement,
 		PromotionExpireTime:  p.PromotionExpireTime,
+		CurrentMemberCount:   p.CurrentMemberCount,
+		SkillTags:            skillTagsVO(p.SkillTags),
+		Favorited:            &p.Favorited,
 	}
 
 	if p.Creator != nil {
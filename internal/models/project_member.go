@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/api"
+)
+
+// ProjectMember 项目成员
+// 创建项目时写入队长，申请通过或接受橄榄枝邀请时写入队员；退出或被移出后保留记录。
+type ProjectMember struct {
+	ID        int        `db:"id"`
+	ProjectID int        `db:"project_id"`
+	UserID    int        `db:"user_id"`
+	Role      string     `db:"role"`   // leader-队长 member-队员
+	Source    string     `db:"source"` // 加入方式，见 ProjectMemberSource*
+	Status    int        `db:"status"` // 1-在队 2-已退出 3-被移出
+	JoinedAt  time.Time  `db:"joined_at"`
+	LeftAt    *time.Time `db:"left_at"`
+
+	// Joined fields
+	Nickname   *string  `db:"nickname"`
+	AvatarUrl  *string  `db:"avatar_url"`
+	SchoolName *string  `db:"school_name"`
+	MajorName  *string  `db:"major_name"`
+	Project    *Project `db:"-"`
+}
+
+// ToVO converts ProjectMember to API ProjectMemberVO
+func (m *ProjectMember) ToVO() *api.ProjectMemberVO {
+	return &api.ProjectMemberVO{
+		UserId:     m.UserID,
+		Nickname:   m.Nickname,
+		AvatarUrl:  ptrFullURL(m.AvatarUrl),
+		SchoolName: m.SchoolName,
+		MajorName:  m.MajorName,
+		Role:       api.ProjectMemberRole(m.Role),
+		JoinedAt:   m.JoinedAt,
+	}
+}
+
+// ToTeamVO converts ProjectMember with its project to API TeamVO
+func (m *ProjectMember) ToTeamVO() *api.TeamVO {
+	vo := &api.TeamVO{
+		Role:     api.ProjectMemberRole(m.Role),
+		JoinedAt: m.JoinedAt,
+	}
+	if m.Project != nil {
+		vo.Project = *m.Project.ToVO()
+	}
+	return vo
+}
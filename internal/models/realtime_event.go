@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// RealtimeEvent 实时事件日志
+// 多实例部署时各实例通过轮询该表互相转发推送事件，仅短期保留。
+type RealtimeEvent struct {
+	ID        int64     `db:"id"`
+	UserID    int       `db:"user_id"`
+	Type      string    `db:"type"` // 事件类型，如 notification
+	Data      string    `db:"data"` // 事件数据 JSON
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/api"
+)
+
+// 搜索文档类型
+const (
+	SearchDocProject = "project"
+	SearchDocTalent  = "talent"
+)
+
+// SearchDocument 全文搜索文档
+// 项目和人才档案写入时同步生成，检索只负责文本相关度，学校、状态等条件仍在业务表上过滤。
+type SearchDocument struct {
+	ID        int64     `db:"id"`
+	DocType   string    `db:"doc_type"` // project / talent
+	RefID     int       `db:"ref_id"`   // 项目ID或人才档案ID
+	Title     string    `db:"title"`    // 项目名称或昵称
+	Content   string    `db:"content"`  // 项目详情，或自我评价与项目经历
+	Skills    string    `db:"skills"`   // 技能，空格分隔
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// SearchHit 一条搜索命中
+type SearchHit struct {
+	RefID int     `db:"ref_id"`
+	Score float64 `db:"score"`
+}
+
+// highlightsVO converts search highlights to the API type, nil when empty.
+func highlightsVO(h map[string]string) *api.SearchHighlights {
+	if len(h) == 0 {
+		return nil
+	}
+	vo := api.SearchHighlights(h)
+	return &vo
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/api"
+)
+
+// SkillCategory represents a skill tag category in the database
+type SkillCategory struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	SortOrder int       `db:"sort_order"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+
+	Tags []SkillTag `db:"-"`
+}
+
+// ToVO converts SkillCategory to API SkillCategoryVO
+func (c *SkillCategory) ToVO() *api.SkillCategoryVO {
+	tags := make([]api.SkillTagVO, len(c.Tags))
+	for i := range c.Tags {
+		tags[i] = *c.Tags[i].ToVO()
+	}
+
+	return &api.SkillCategoryVO{
+		Id:   &c.ID,
+		Name: &c.Name,
+		Tags: &tags,
+	}
+}
+
+// SkillTag represents a skill tag in the managed dictionary
+type SkillTag struct {
+	ID         int       `db:"id"`
+	CategoryID int       `db:"category_id"`
+	Name       string    `db:"name"`
+	Status     int       `db:"status"` // 0-停用, 1-启用
+	CreatedAt  time.Time `db:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at"`
+
+	// Joined fields
+	CategoryName *string `db:"category_name"`
+	ProjectCount int     `db:"project_count"` // 关联的项目数
+	TalentCount  int     `db:"talent_count"`  // 关联的人才档案数
+
+	Synonyms []string `db:"-"`
+}
+
+// ToVO converts SkillTag to API SkillTagVO
+func (t *SkillTag) ToVO() *api.SkillTagVO {
+	return &api.SkillTagVO{
+		Id:           &t.ID,
+		Name:         &t.Name,
+		CategoryId:   &t.CategoryID,
+		CategoryName: t.CategoryName,
+	}
+}
+
+// SkillTagTerm is a name or synonym that identifies an active skill tag in free text
+type SkillTagTerm struct {
+	TagID int    `db:"tag_id"`
+	Term  string `db:"term"`
+}
+
+// SkillText is the free skill text of a project or talent profile, used when
+// re-extracting skill tag links
+type SkillText struct {
+	RefID int     `db:"ref_id"`
+	Text  *string `db:"text"`
+}
+
+// skillTagsVO converts linked skill tags to the API list, nil when not loaded
+func skillTagsVO(tags []SkillTag) *[]api.SkillTagVO {
+	if tags == nil {
+		return nil
+	}
+	vos := make([]api.SkillTagVO, len(tags))
+	for i := range tags {
+		vos[i] = *tags[i].ToVO()
+	}
+	return &vos
+}
@@ -16,7 +16,7 @@ type SubscribeConfig struct {
 	ID             int             `db:"id"`
 	UserID         int             `db:"user_id"`
 	BizKey         string          `db:"biz_key"`
-	SubscribeCount *int            `db:"subscribe_count"`
+	SubscribeCount int             `db:"subscribe_count"` // 剩余一次性订阅授权次数
 	Status         SubscribeStatus `db:"status"`
 	CreatedAt      *time.Time      `db:"created_at"`
 	UpdatedAt      *time.Time      `db:"updated_at"`
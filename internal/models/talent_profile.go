@@ -15,7 +15,8 @@ type TalentProfile struct {
 	SkillSummary      *string    `db:"skill_summary"` // JSON array stored as string
 	ProjectExperience *string    `db:"project_experience"`
 	MBTI              *string    `db:"mbti"`
-	Status            *int       `db:"status"` // 0: 下架, 1: 上架
+	Education         *int       `db:"education"` // 学历:1-大专,2-本科,3-研究生
+	Status            *int       `db:"status"`    // 0: 下架, 1: 上架
 	CreatedAt         *time.Time `db:"created_at"`
 	UpdatedAt         *time.Time `db:"updated_at"`
 
@@ -30,6 +31,18 @@ type TalentProfile struct {
 	// Populated after follow-up queries
 	SchoolName *string `db:"-"`
 	MajorName  *string `db:"-"`
+
+	SkillTags  []SkillTag        `db:"-"` // 从技能中识别出的技能标签
+	Highlights map[string]string `db:"-"` // 搜索命中的高亮片段
+	Favorited  bool              `db:"-"` // 当前用户是否已收藏
+}
+
+// Skills returns the skill tags of the profile.
+func (t *TalentProfile) Skills() []string {
+	if skills := t.parseSkills(); skills != nil {
+		return *skills
+	}
+	return nil
 }
 
 // parseSkills parses the skill_summary JSON string into a string slice
@@ -54,8 +67,12 @@ func (t *TalentProfile) ToVO() *api.TalentProfileVO {
 		MajorName:  t.MajorName,
 		Mbti:       t.MBTI,
 		Skills:     t.parseSkills(),
+		SkillTags:  skillTagsVO(t.SkillTags),
+		Favorited:  &t.Favorited,
+		Education:  t.Education,
 		Status:     (*api.TalentStatus)(t.Status),
 		AvatarUrl:  ptrFullURL(t.AvatarUrl),
+		Highlights: highlightsVO(t.Highlights),
 	}
 }
 
@@ -69,6 +86,9 @@ func (t *TalentProfile) ToDetailVO() *api.TalentProfileDetailVO {
 		MajorName:         t.MajorName,
 		Mbti:              t.MBTI,
 		Skills:            t.parseSkills(),
+		SkillTags:         skillTagsVO(t.SkillTags),
+		Favorited:         &t.Favorited,
+		Education:         t.Education,
 		SelfEvaluation:    t.SelfEvaluation,
 		ProjectExperience: t.ProjectExperience,
 		Status:            (*api.TalentStatus)(t.Status),
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// UserEntitlement 用户权益台账
+// 服务权益商品支付成功后按商品配置发放一条记录，使用时累加 used，退款时撤销。
+type UserEntitlement struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	OrderID   int       `db:"order_id"`
+	Kind      string    `db:"kind"`   // 权益类型，见 BenefitKind*
+	Total     int       `db:"total"`  // 发放数量
+	Used      int       `db:"used"`   // 已使用数量
+	Status    int       `db:"status"` // 1-有效 2-已撤销
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// Remaining 返回剩余可用数量
+func (e *UserEntitlement) Remaining() int {
+	if e.Status != EntitlementStatusActive {
+		return 0
+	}
+	return e.Total - e.Used
+}
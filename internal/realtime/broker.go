@@ -0,0 +1,139 @@
+package realtime
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+const (
+	dbBrokerPollInterval = time.Second      // 轮询事件日志的间隔
+	dbBrokerBatchSize    = 200              // 每次读取的事件数
+	dbBrokerRetention    = 10 * time.Minute // 事件日志保留时长
+	dbBrokerCleanupEvery = 600              // 每轮询多少次清理一次过期日志
+)
+
+// NewBrokerFromEnv 根据 REALTIME_BROKER 创建 Broker：
+// local 仅在本进程内分发；mysql（默认）通过 realtime_event 表在实例间分发，
+// 管理后台等其他进程产生的事件也能推送到 API 服务实例上的连接。
+func NewBrokerFromEnv(repo repository.RealtimeEventRepo) Broker {
+	switch v := os.Getenv("REALTIME_BROKER"); v {
+	case "local":
+		return NewLocalBroker()
+	case "", "mysql":
+		return NewDBBroker(repo)
+	default:
+		log.Printf("[NewBrokerFromEnv] unknown REALTIME_BROKER %q, using mysql", v)
+		return NewDBBroker(repo)
+	}
+}
+
+// LocalBroker 进程内 Broker，Publish 同步回调所有订阅者
+type LocalBroker struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]func(Message)
+}
+
+// NewLocalBroker creates a LocalBroker.
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{subscribers: make(map[int]func(Message))}
+}
+
+// Publish 把消息交给所有订阅者
+func (b *LocalBroker) Publish(_ context.Context, msg Message) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, deliver := range b.subscribers {
+		deliver(msg)
+	}
+	return nil
+}
+
+// Subscribe 注册订阅者，ctx 取消后移除
+func (b *LocalBroker) Subscribe(ctx context.Context, deliver func(Message)) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = deliver
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers, id)
+		b.mu.Unlock()
+	}()
+	return nil
+}
+
+// DBBroker 基于 realtime_event 表的 Broker
+// Publish 追加事件日志，每个订阅者从订阅时刻起按 id 顺序轮询新事件，日志只保留很短时间。
+type DBBroker struct {
+	repo repository.RealtimeEventRepo
+}
+
+// NewDBBroker creates a DBBroker.
+func NewDBBroker(repo repository.RealtimeEventRepo) *DBBroker {
+	return &DBBroker{repo: repo}
+}
+
+// Publish 追加一条事件日志
+func (b *DBBroker) Publish(ctx context.Context, msg Message) error {
+	return b.repo.Create(ctx, &models.RealtimeEvent{
+		UserID: msg.UserID,
+		Type:   msg.Type,
+		Data:   string(msg.Data),
+	})
+}
+
+// Subscribe 从当前最新的事件之后开始轮询，ctx 取消后停止
+func (b *DBBroker) Subscribe(ctx context.Context, deliver func(Message)) error {
+	lastID, err := b.repo.MaxID(ctx)
+	if err != nil {
+		return err
+	}
+	go b.poll(ctx, lastID, deliver)
+	return nil
+}
+
+// poll 持续读取 lastID 之后的事件并回调
+func (b *DBBroker) poll(ctx context.Context, lastID int64, deliver func(Message)) {
+	ticker := time.NewTicker(dbBrokerPollInterval)
+	defer ticker.Stop()
+
+	for tick := 1; ; tick++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// 读满一批说明可能还有积压，立即继续
+		for {
+			events, err := b.repo.ListAfter(ctx, lastID, dbBrokerBatchSize)
+			if err != nil {
+				log.Printf("[DBBroker.poll] repository error: %v", err)
+				break
+			}
+			for _, e := range events {
+				deliver(Message{UserID: e.UserID, Type: e.Type, Data: []byte(e.Data)})
+				lastID = e.ID
+			}
+			if len(events) < dbBrokerBatchSize {
+				break
+			}
+		}
+
+		if tick%dbBrokerCleanupEvery == 0 {
+			if _, err := b.repo.DeleteBefore(ctx, time.Now().Add(-dbBrokerRetention)); err != nil {
+				log.Printf("[DBBroker.poll] repository error cleaning up events: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+)
+
+const (
+	// EventNotification 新的站内通知，数据为 api.NotificationVO
+	EventNotification = "notification"
+
+	clientBufferSize = 16 // 每个连接待写出的事件数，写不过来的慢连接丢弃新事件
+)
+
+// Event 推送给用户的实时事件
+type Event struct {
+	Type string
+	Data any
+}
+
+// Message Broker 中传递的事件，Data 为已序列化的 JSON
+type Message struct {
+	UserID int             `json:"userId"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// Broker 负责在服务实例之间分发事件
+// 单实例使用 LocalBroker；多实例部署时每条消息需投递到所有实例的订阅者。
+type Broker interface {
+	Publish(ctx context.Context, msg Message) error
+	// Subscribe 注册消息回调，ctx 取消后停止回调
+	Subscribe(ctx context.Context, deliver func(Message)) error
+}
+
+// Client 一个在线连接
+type Client struct {
+	UserID int
+	events chan Message
+}
+
+// Events 返回推送给该连接的事件
+func (c *Client) Events() <-chan Message {
+	return c.events
+}
+
+// Hub 实时事件中心
+// 业务通过 Publish 把事件交给 Broker，Hub 从 Broker 订阅消息并分发给本实例上该用户的所有连接。
+type Hub struct {
+	broker Broker
+
+	mu      sync.RWMutex
+	clients map[int]map[*Client]struct{}
+}
+
+// NewHub creates a Hub on top of the given broker.
+func NewHub(broker Broker) *Hub {
+	return &Hub{
+		broker:  broker,
+		clients: make(map[int]map[*Client]struct{}),
+	}
+}
+
+// Start 开始从 Broker 接收消息，ctx 取消后停止
+func (h *Hub) Start(ctx context.Context) error {
+	return h.broker.Subscribe(ctx, h.dispatch)
+}
+
+// Publish 向用户推送事件
+func (h *Hub) Publish(ctx context.Context, userID int, event Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return fmt.Errorf("marshal event data: %w", err)
+	}
+	return h.broker.Publish(ctx, Message{UserID: userID, Type: event.Type, Data: data})
+}
+
+// Connect 注册用户的一个连接
+func (h *Hub) Connect(userID int) *Client {
+	c := &Client{UserID: userID, events: make(chan Message, clientBufferSize)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*Client]struct{})
+	}
+	h.clients[userID][c] = struct{}{}
+	return c
+}
+
+// Disconnect 注销连接
+func (h *Hub) Disconnect(c *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients[c.UserID], c)
+	if len(h.clients[c.UserID]) == 0 {
+		delete(h.clients, c.UserID)
+	}
+}
+
+// dispatch 把消息分发给本实例上接收用户的所有连接
+func (h *Hub) dispatch(msg Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for c := range h.clients[msg.UserID] {
+		select {
+		case c.events <- msg:
+		default:
+			log.Printf("[Hub.dispatch] client buffer full, dropping %s event for user %d", msg.Type, msg.UserID)
+		}
+	}
+}
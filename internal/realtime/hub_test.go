@@ -0,0 +1,86 @@
+package realtime
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHub(t *testing.T) *Hub {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	h := NewHub(NewLocalBroker())
+	require.NoError(t, h.Start(ctx))
+	return h
+}
+
+func TestHub_DeliversToAllConnectionsOfUser(t *testing.T) {
+	h := newTestHub(t)
+	phone := h.Connect(10)
+	pad := h.Connect(10)
+	other := h.Connect(20)
+
+	require.NoError(t, h.Publish(context.Background(), 10, Event{Type: EventNotification, Data: map[string]int{"id": 1}}))
+
+	for _, c := range []*Client{phone, pad} {
+		select {
+		case msg := <-c.Events():
+			assert.Equal(t, 10, msg.UserID)
+			assert.Equal(t, EventNotification, msg.Type)
+			assert.JSONEq(t, `{"id":1}`, string(msg.Data))
+		default:
+			t.Fatal("expected an event")
+		}
+	}
+	assert.Empty(t, other.Events())
+}
+
+func TestHub_DisconnectStopsDelivery(t *testing.T) {
+	h := newTestHub(t)
+	c := h.Connect(10)
+	h.Disconnect(c)
+
+	require.NoError(t, h.Publish(context.Background(), 10, Event{Type: EventNotification, Data: 1}))
+
+	assert.Empty(t, c.Events())
+	assert.Empty(t, h.clients)
+}
+
+func TestHub_FullBufferDropsEvents(t *testing.T) {
+	h := newTestHub(t)
+	c := h.Connect(10)
+
+	for i := 0; i < clientBufferSize+5; i++ {
+		require.NoError(t, h.Publish(context.Background(), 10, Event{Type: EventNotification, Data: i}))
+	}
+
+	assert.Len(t, c.Events(), clientBufferSize)
+}
+
+func TestLocalBroker_UnsubscribeOnCancel(t *testing.T) {
+	b := NewLocalBroker()
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, b.Subscribe(ctx, func(Message) {}))
+	cancel()
+
+	assert.Eventually(t, func() bool {
+		b.mu.RLock()
+		defer b.mu.RUnlock()
+		return len(b.subscribers) == 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWriteSSE(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteSSE(&buf, Message{Type: EventNotification, Data: []byte("{\"a\":1,\n\"b\":2}")}))
+	assert.Equal(t, "event: notification\ndata: {\"a\":1,\ndata: \"b\":2}\n\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, WriteSSEComment(&buf, "ping"))
+	assert.Equal(t, ": ping\n\n", buf.String())
+}
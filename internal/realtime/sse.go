@@ -0,0 +1,25 @@
+package realtime
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteSSE 按 Server-Sent Events 格式写出一条消息
+func WriteSSE(w io.Writer, msg Message) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "event: %s\n", msg.Type)
+	for _, line := range strings.Split(string(msg.Data), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteSSEComment 写出一条注释行，用于保持连接
+func WriteSSEComment(w io.Writer, comment string) error {
+	_, err := fmt.Fprintf(w, ": %s\n\n", comment)
+	return err
+}
@@ -86,9 +86,9 @@ func (r *ApplicationRepository) List(ctx context.Context, params ApplicationList
 	offset := (params.Page - 1) * params.Size
 	query := fmt.Sprintf(`
 		SELECT
-			pa.id, pa.project_id, pa.user_id,
-			pa.status, pa.applied_at, pa.updated_at,
-			p.name AS project_name
+			pa.id, pa.project_id, pa.user_id, pa.apply_reason, pa.contact,
+			pa.status, pa.reply_msg, pa.applied_at, pa.updated_at,
+			p.name AS project_name, COALESCE(p.creator_id, 0) AS project_creator_id
 		FROM project_application pa
 		LEFT JOIN project p ON pa.project_id = p.id
 		WHERE %s
@@ -194,9 +194,9 @@ func (r *ApplicationRepository) List(ctx context.Context, params ApplicationList
 func (r *ApplicationRepository) Create(ctx context.Context, app *models.ProjectApplication) error {
 	query := `
 		INSERT INTO project_application (
-			project_id, user_id, status
+			project_id, user_id, apply_reason, contact, status
 		) VALUES (
-			:project_id, :user_id, :status
+			:project_id, :user_id, :apply_reason, :contact, :status
 		)
 	`
 
@@ -214,13 +214,39 @@ func (r *ApplicationRepository) Create(ctx context.Context, app *models.ProjectA
 	return nil
 }
 
+// CreateTx creates a new application within a transaction
+func (r *ApplicationRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, app *models.ProjectApplication) error {
+	query := `
+		INSERT INTO project_application (
+			project_id, user_id, apply_reason, contact, status
+		) VALUES (
+			:project_id, :user_id, :apply_reason, :contact, :status
+		)
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, app)
+	if err != nil {
+		return fmt.Errorf("create application: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	app.ID = int(id)
+
+	return nil
+}
+
 // GetByID retrieves an application by ID
 func (r *ApplicationRepository) GetByID(ctx context.Context, id int) (*models.ProjectApplication, error) {
 	query := `
 		SELECT
-			pa.id, pa.project_id, pa.user_id,
-			pa.status, pa.applied_at, pa.updated_at
+			pa.id, pa.project_id, pa.user_id, pa.apply_reason, pa.contact,
+			pa.status, pa.reply_msg, pa.applied_at, pa.updated_at,
+			p.name AS project_name, COALESCE(p.creator_id, 0) AS project_creator_id
 		FROM project_application pa
+		LEFT JOIN project p ON pa.project_id = p.id
 		WHERE pa.id = ?
 	`
 
@@ -235,21 +261,77 @@ func (r *ApplicationRepository) GetByID(ctx context.Context, id int) (*models.Pr
 	return &app, nil
 }
 
-// CheckDuplicate checks if a user has already applied to a project
-func (r *ApplicationRepository) CheckDuplicate(ctx context.Context, projectID, userID int) (bool, error) {
-	query := `SELECT EXISTS(SELECT 1 FROM project_application WHERE project_id = ? AND user_id = ?)`
-	var exists bool
-	if err := r.db.QueryRowxContext(ctx, query, projectID, userID).Scan(&exists); err != nil {
-		return false, fmt.Errorf("check duplicate application: %w", err)
+// GetLatest retrieves the most recent application of a user to a project, or nil
+func (r *ApplicationRepository) GetLatest(ctx context.Context, projectID, userID int) (*models.ProjectApplication, error) {
+	query := `
+		SELECT id, project_id, user_id, apply_reason, contact, status, reply_msg, applied_at, updated_at
+		FROM project_application
+		WHERE project_id = ? AND user_id = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	var app models.ProjectApplication
+	if err := r.db.GetContext(ctx, &app, query, projectID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query latest application: %w", err)
 	}
-	return exists, nil
+
+	return &app, nil
+}
+
+// GetLatestTx retrieves the most recent application of a user to a project
+// within a transaction, locking it until the transaction ends, or nil
+func (r *ApplicationRepository) GetLatestTx(ctx context.Context, tx *sqlx.Tx, projectID, userID int) (*models.ProjectApplication, error) {
+	query := `
+		SELECT id, project_id, user_id, apply_reason, contact, status, reply_msg, applied_at, updated_at
+		FROM project_application
+		WHERE project_id = ? AND user_id = ?
+		ORDER BY id DESC
+		LIMIT 1
+		FOR UPDATE
+	`
+
+	var app models.ProjectApplication
+	if err := tx.GetContext(ctx, &app, query, projectID, userID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("lock latest application: %w", err)
+	}
+
+	return &app, nil
 }
 
-// UpdateStatus updates the status and reply message of an application
-func (r *ApplicationRepository) UpdateStatus(ctx context.Context, id int, status int) error {
-	query := `UPDATE project_application SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+// ListAttempts retrieves every application of a user to a project, newest first
+func (r *ApplicationRepository) ListAttempts(ctx context.Context, projectID, userID int) ([]models.ProjectApplication, error) {
+	query := `
+		SELECT
+			pa.id, pa.project_id, pa.user_id, pa.apply_reason, pa.contact,
+			pa.status, pa.reply_msg, pa.applied_at, pa.updated_at,
+			p.name AS project_name, COALESCE(p.creator_id, 0) AS project_creator_id
+		FROM project_application pa
+		LEFT JOIN project p ON pa.project_id = p.id
+		WHERE pa.project_id = ? AND pa.user_id = ?
+		ORDER BY pa.id DESC
+	`
 
-	result, err := r.db.ExecContext(ctx, query, status, id)
+	var applications []models.ProjectApplication
+	if err := r.db.SelectContext(ctx, &applications, query, projectID, userID); err != nil {
+		return nil, fmt.Errorf("query application attempts: %w", err)
+	}
+
+	return applications, nil
+}
+
+// UpdateStatus updates the status and reply message of an application.
+// A nil replyMsg keeps the current reply.
+func (r *ApplicationRepository) UpdateStatus(ctx context.Context, id int, status int, replyMsg *string) error {
+	query := `UPDATE project_application SET status = ?, reply_msg = COALESCE(?, reply_msg), updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, status, replyMsg, id)
 	if err != nil {
 		return fmt.Errorf("update application status: %w", err)
 	}
@@ -261,3 +343,123 @@ func (r *ApplicationRepository) UpdateStatus(ctx context.Context, id int, status
 
 	return nil
 }
+
+// TransitionStatusTx moves an application from one status to another within a
+// transaction, and reports false when it is no longer in the from status.
+// A nil replyMsg keeps the current reply.
+func (r *ApplicationRepository) TransitionStatusTx(ctx context.Context, tx *sqlx.Tx, id, from, to int, replyMsg *string) (bool, error) {
+	query := `
+		UPDATE project_application
+		SET status = ?, reply_msg = COALESCE(?, reply_msg), updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := tx.ExecContext(ctx, query, to, replyMsg, id, from)
+	if err != nil {
+		return false, fmt.Errorf("transition application status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// LockPendingTx locks the pending applications of a project among ids within a
+// transaction; ids of other projects or no longer pending are left out
+func (r *ApplicationRepository) LockPendingTx(ctx context.Context, tx *sqlx.Tx, projectID int, ids []int) ([]models.ProjectApplication, error) {
+	query, args, err := sqlx.In(`
+		SELECT id, project_id, user_id, apply_reason, contact, status, reply_msg, applied_at, updated_at
+		FROM project_application
+		WHERE project_id = ? AND id IN (?) AND status = ?
+		ORDER BY id ASC
+		FOR UPDATE
+	`, projectID, ids, models.ApplicationStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("build pending applications IN query: %w", err)
+	}
+
+	var applications []models.ProjectApplication
+	if err := tx.SelectContext(ctx, &applications, tx.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("lock pending applications: %w", err)
+	}
+
+	return applications, nil
+}
+
+// UpdateStatusBatchTx sets the status and reply message of several applications
+// within a transaction. A nil replyMsg keeps the current replies.
+func (r *ApplicationRepository) UpdateStatusBatchTx(ctx context.Context, tx *sqlx.Tx, ids []int, status int, replyMsg *string) error {
+	query, args, err := sqlx.In(`
+		UPDATE project_application
+		SET status = ?, reply_msg = COALESCE(?, reply_msg), updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (?)
+	`, status, replyMsg, ids)
+	if err != nil {
+		return fmt.Errorf("build application status IN query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("update application status batch: %w", err)
+	}
+
+	return nil
+}
+
+// CreateLogTx records a status change of an application within a transaction
+func (r *ApplicationRepository) CreateLogTx(ctx context.Context, tx *sqlx.Tx, l *models.ApplicationStatusLog) error {
+	query := `
+		INSERT INTO project_application_log (application_id, status, operator_id, remark)
+		VALUES (:application_id, :status, :operator_id, :remark)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, l); err != nil {
+		return fmt.Errorf("create application log: %w", err)
+	}
+
+	return nil
+}
+
+// CreateLogsTx records status changes of several applications in one statement
+func (r *ApplicationRepository) CreateLogsTx(ctx context.Context, tx *sqlx.Tx, logs []*models.ApplicationStatusLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO project_application_log (application_id, status, operator_id, remark)
+		VALUES (:application_id, :status, :operator_id, :remark)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, logs); err != nil {
+		return fmt.Errorf("create application logs: %w", err)
+	}
+
+	return nil
+}
+
+// ListLogs retrieves the status changes of the given applications in order
+func (r *ApplicationRepository) ListLogs(ctx context.Context, applicationIDs []int) ([]models.ApplicationStatusLog, error) {
+	if len(applicationIDs) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id, application_id, status, operator_id, remark, created_at
+		FROM project_application_log
+		WHERE application_id IN (?)
+		ORDER BY id ASC
+	`, applicationIDs)
+	if err != nil {
+		return nil, fmt.Errorf("build application log IN query: %w", err)
+	}
+
+	var logs []models.ApplicationStatusLog
+	if err := r.db.SelectContext(ctx, &logs, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("query application logs: %w", err)
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// ContentAuditRepository handles content audit log database operations
+type ContentAuditRepository struct {
+	db *sqlx.DB
+}
+
+// NewContentAuditRepository creates a new ContentAuditRepository
+func NewContentAuditRepository(db *sqlx.DB) *ContentAuditRepository {
+	return &ContentAuditRepository{db: db}
+}
+
+// Create inserts a content audit log
+func (r *ContentAuditRepository) Create(ctx context.Context, log *models.ContentAuditLog) error {
+	query := `
+		INSERT INTO content_audit_log (
+			user_id, biz_type, biz_id, scene, content, source, suggest, label, keyword, trace_id
+		) VALUES (
+			:user_id, :biz_type, :biz_id, :scene, :content, :source, :suggest, :label, :keyword, :trace_id
+		)
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, log)
+	if err != nil {
+		return fmt.Errorf("create content audit log: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	log.ID = id
+	return nil
+}
+
+// AttachBizID sets the business ID on logs recorded before the business object existed
+func (r *ContentAuditRepository) AttachBizID(ctx context.Context, ids []int64, bizID int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(`UPDATE content_audit_log SET biz_id = ? WHERE id IN (?)`, bizID, ids)
+	if err != nil {
+		return fmt.Errorf("build attach query: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), args...); err != nil {
+		return fmt.Errorf("attach content audit biz id: %w", err)
+	}
+
+	return nil
+}
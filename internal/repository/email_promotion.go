@@ -19,8 +19,8 @@ func NewEmailPromotionRepository(db *sqlx.DB) *EmailPromotionRepository {
 	return &EmailPromotionRepository{db: db}
 }
 
-// Create creates a new email promotion record
-func (r *EmailPromotionRepository) Create(ctx context.Context, promotion *models.EmailPromotion) error {
+// CreateTx creates a new email promotion record within a transaction
+func (r *EmailPromotionRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, promotion *models.EmailPromotion) error {
 	query := `
 		INSERT INTO email_promotion (
 			order_id, project_id, creator_id, max_recipients, total_sent, status
@@ -29,7 +29,7 @@ func (r *EmailPromotionRepository) Create(ctx context.Context, promotion *models
 		)
 	`
 
-	result, err := r.db.NamedExecContext(ctx, query, promotion)
+	result, err := tx.NamedExecContext(ctx, query, promotion)
 	if err != nil {
 		return fmt.Errorf("create email promotion: %w", err)
 	}
@@ -67,8 +67,9 @@ func (r *EmailPromotionRepository) GetByID(ctx context.Context, id int) (*models
 	return &promotion, nil
 }
 
-// GetByOrderID retrieves an email promotion by order ID
-func (r *EmailPromotionRepository) GetByOrderID(ctx context.Context, orderID int) (*models.EmailPromotion, error) {
+// GetByOrderIDTx retrieves the email promotion of an order within a
+// transaction, locking it until the transaction ends, or nil
+func (r *EmailPromotionRepository) GetByOrderIDTx(ctx context.Context, tx *sqlx.Tx, orderID int) (*models.EmailPromotion, error) {
 	query := `
 		SELECT 
 			id, order_id, project_id, creator_id,
@@ -76,10 +77,11 @@ func (r *EmailPromotionRepository) GetByOrderID(ctx context.Context, orderID int
 			error_message, started_at, completed_at, created_at
 		FROM email_promotion
 		WHERE order_id = ?
+		FOR UPDATE
 	`
 
 	var promotion models.EmailPromotion
-	if err := r.db.QueryRowxContext(ctx, query, orderID).StructScan(&promotion); err != nil {
+	if err := tx.QueryRowxContext(ctx, query, orderID).StructScan(&promotion); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -109,6 +111,27 @@ func (r *EmailPromotionRepository) Update(ctx context.Context, promotion *models
 	return nil
 }
 
+// ListPending retrieves promotions that have not been expanded into email tasks yet, oldest first
+func (r *EmailPromotionRepository) ListPending(ctx context.Context, limit int) ([]models.EmailPromotion, error) {
+	query := `
+		SELECT
+			id, order_id, project_id, creator_id,
+			max_recipients, total_sent, status,
+			error_message, started_at, completed_at, created_at
+		FROM email_promotion
+		WHERE status = ?
+		ORDER BY id
+		LIMIT ?
+	`
+
+	var promotions []models.EmailPromotion
+	if err := r.db.SelectContext(ctx, &promotions, query, models.EmailPromotionStatusPending, limit); err != nil {
+		return nil, fmt.Errorf("query pending email promotions: %w", err)
+	}
+
+	return promotions, nil
+}
+
 // ListByCreatorID retrieves email promotions by creator ID with pagination
 func (r *EmailPromotionRepository) ListByCreatorID(ctx context.Context, creatorID int, page, size int) ([]models.EmailPromotion, int64, error) {
 	// Count total
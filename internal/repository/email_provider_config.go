@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// EmailProviderConfigRepository handles email provider config database operations
+type EmailProviderConfigRepository struct {
+	db *sqlx.DB
+}
+
+// NewEmailProviderConfigRepository creates a new EmailProviderConfigRepository
+func NewEmailProviderConfigRepository(db *sqlx.DB) *EmailProviderConfigRepository {
+	return &EmailProviderConfigRepository{db: db}
+}
+
+// ListActive returns active provider configs, default first, then by priority
+func (r *EmailProviderConfigRepository) ListActive(ctx context.Context) ([]models.EmailProviderConfig, error) {
+	query := `
+		SELECT
+			id, provider_type, config_name, config_json, is_default,
+			is_active, priority, created_at, updated_at
+		FROM email_provider_config
+		WHERE is_active = 1
+		ORDER BY is_default DESC, priority ASC, id ASC
+	`
+
+	var configs []models.EmailProviderConfig
+	if err := r.db.SelectContext(ctx, &configs, query); err != nil {
+		return nil, fmt.Errorf("list active email providers: %w", err)
+	}
+
+	return configs, nil
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// emailTaskInsertBatch limits the number of rows per multi-row INSERT
+const emailTaskInsertBatch = 500
+
+// EmailTaskRepository handles email task database operations
+type EmailTaskRepository struct {
+	db *sqlx.DB
+}
+
+// NewEmailTaskRepository creates a new EmailTaskRepository
+func NewEmailTaskRepository(db *sqlx.DB) *EmailTaskRepository {
+	return &EmailTaskRepository{db: db}
+}
+
+// CreateForPromotion moves a pending promotion to its started status and inserts
+// its email tasks in a single transaction. It returns false without writing
+// anything when the promotion is no longer pending (another worker expanded it).
+func (r *EmailTaskRepository) CreateForPromotion(ctx context.Context, promotion *models.EmailPromotion, tasks []models.EmailTask) (bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE email_promotion SET
+			status = ?,
+			started_at = ?,
+			completed_at = ?
+		WHERE id = ? AND status = ?
+	`, promotion.Status, promotion.StartedAt, promotion.CompletedAt, promotion.ID, models.EmailPromotionStatusPending)
+	if err != nil {
+		return false, fmt.Errorf("start email promotion: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	query := `
+		INSERT INTO email_task (
+			promotion_id, recipient_email, template_code, template_vars, status, retry_count
+		) VALUES (
+			:promotion_id, :recipient_email, :template_code, :template_vars, :status, :retry_count
+		)
+	`
+
+	for start := 0; start < len(tasks); start += emailTaskInsertBatch {
+		end := start + emailTaskInsertBatch
+		if end > len(tasks) {
+			end = len(tasks)
+		}
+		if _, err := tx.NamedExecContext(ctx, query, tasks[start:end]); err != nil {
+			return false, fmt.Errorf("create email tasks: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// ClaimDue locks up to limit due tasks and marks them as sending until leaseUntil.
+// Due tasks are pending/retrying tasks whose backoff has elapsed, plus sending
+// tasks whose lease expired (e.g. the worker crashed mid-send). Each claim gets a
+// new claim token; only its holder can record the outcome of the task.
+func (r *EmailTaskRepository) ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]models.EmailTask, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	query := `
+		SELECT
+			id, promotion_id, recipient_email, template_code, template_vars,
+			status, retry_count, error_msg, send_time, next_retry_at, create_time
+		FROM email_task
+		WHERE (status IN (?, ?) AND (next_retry_at IS NULL OR next_retry_at <= ?))
+		   OR (status = ? AND next_retry_at <= ?)
+		ORDER BY id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var tasks []models.EmailTask
+	if err := tx.SelectContext(ctx, &tasks, query,
+		models.EmailTaskStatusPending, models.EmailTaskStatusRetrying, now,
+		models.EmailTaskStatusSending, now,
+		limit,
+	); err != nil {
+		return nil, fmt.Errorf("query due email tasks: %w", err)
+	}
+
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, len(tasks))
+	for i, t := range tasks {
+		ids[i] = t.ID
+	}
+	claimToken := uuid.NewString()
+
+	updateQuery, args, err := sqlx.In(`
+		UPDATE email_task SET
+			status = ?,
+			next_retry_at = ?,
+			claim_token = ?
+		WHERE id IN (?)
+	`, models.EmailTaskStatusSending, leaseUntil, claimToken, ids)
+	if err != nil {
+		return nil, fmt.Errorf("build claim query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(updateQuery), args...); err != nil {
+		return nil, fmt.Errorf("claim email tasks: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	for i := range tasks {
+		tasks[i].Status = models.EmailTaskStatusSending
+		tasks[i].NextRetryAt = &leaseUntil
+		tasks[i].ClaimToken = &claimToken
+	}
+
+	return tasks, nil
+}
+
+// MarkSuccess marks a claimed task as successfully sent and records the provider that delivered it.
+// It returns false when the claim is no longer held (the lease expired and the task was reclaimed).
+func (r *EmailTaskRepository) MarkSuccess(ctx context.Context, id int64, claimToken string, sendTime time.Time, providerID *int) (bool, error) {
+	query := `
+		UPDATE email_task SET
+			status = ?,
+			error_msg = NULL,
+			send_time = ?,
+			next_retry_at = NULL,
+			provider_id = ?,
+			claim_token = NULL
+		WHERE id = ? AND status = ? AND claim_token = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.EmailTaskStatusSuccess, sendTime, providerID,
+		id, models.EmailTaskStatusSending, claimToken)
+	if err != nil {
+		return false, fmt.Errorf("mark email task success: %w", err)
+	}
+
+	return claimHeld(result)
+}
+
+// MarkRetry records a failed attempt of a claimed task and schedules the next one.
+// It returns false when the claim is no longer held.
+func (r *EmailTaskRepository) MarkRetry(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string, nextRetryAt time.Time) (bool, error) {
+	query := `
+		UPDATE email_task SET
+			status = ?,
+			retry_count = ?,
+			error_msg = ?,
+			next_retry_at = ?,
+			claim_token = NULL
+		WHERE id = ? AND status = ? AND claim_token = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.EmailTaskStatusRetrying, retryCount, errMsg, nextRetryAt,
+		id, models.EmailTaskStatusSending, claimToken)
+	if err != nil {
+		return false, fmt.Errorf("mark email task retry: %w", err)
+	}
+
+	return claimHeld(result)
+}
+
+// MarkFailed marks a claimed task as permanently failed.
+// It returns false when the claim is no longer held.
+func (r *EmailTaskRepository) MarkFailed(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string) (bool, error) {
+	query := `
+		UPDATE email_task SET
+			status = ?,
+			retry_count = ?,
+			error_msg = ?,
+			next_retry_at = NULL,
+			claim_token = NULL
+		WHERE id = ? AND status = ? AND claim_token = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.EmailTaskStatusFailed, retryCount, errMsg,
+		id, models.EmailTaskStatusSending, claimToken)
+	if err != nil {
+		return false, fmt.Errorf("mark email task failed: %w", err)
+	}
+
+	return claimHeld(result)
+}
+
+// claimHeld reports whether a guarded task update matched the claimed row
+func claimHeld(result sql.Result) (bool, error) {
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// GetStatsByPromotionID counts tasks of a promotion grouped by status
+func (r *EmailTaskRepository) GetStatsByPromotionID(ctx context.Context, promotionID int) (*models.EmailTaskStats, error) {
+	query := `
+		SELECT
+			COALESCE(SUM(status = ?), 0) AS pending,
+			COALESCE(SUM(status = ?), 0) AS sending,
+			COALESCE(SUM(status = ?), 0) AS success,
+			COALESCE(SUM(status = ?), 0) AS failed,
+			COALESCE(SUM(status = ?), 0) AS retrying
+		FROM email_task
+		WHERE promotion_id = ?
+	`
+
+	var stats models.EmailTaskStats
+	if err := r.db.QueryRowxContext(ctx, query,
+		models.EmailTaskStatusPending,
+		models.EmailTaskStatusSending,
+		models.EmailTaskStatusSuccess,
+		models.EmailTaskStatusFailed,
+		models.EmailTaskStatusRetrying,
+		promotionID,
+	).StructScan(&stats); err != nil {
+		return nil, fmt.Errorf("get email task stats: %w", err)
+	}
+
+	return &stats, nil
+}
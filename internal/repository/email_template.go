@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// EmailTemplateRepository handles email template database operations
+type EmailTemplateRepository struct {
+	db *sqlx.DB
+}
+
+// NewEmailTemplateRepository creates a new EmailTemplateRepository
+func NewEmailTemplateRepository(db *sqlx.DB) *EmailTemplateRepository {
+	return &EmailTemplateRepository{db: db}
+}
+
+// EmailTemplateListParams contains parameters for listing email templates
+type EmailTemplateListParams struct {
+	Page     int
+	Size     int
+	IsActive *bool
+	Keyword  *string // 匹配模板编码或名称
+}
+
+const emailTemplateColumns = `
+	id, template_code, template_name, subject, html_content, text_content,
+	description, is_active, created_at, updated_at
+`
+
+// List retrieves paginated email templates with optional filters
+func (r *EmailTemplateRepository) List(ctx context.Context, params EmailTemplateListParams) ([]models.EmailTemplate, int64, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+
+	if params.IsActive != nil {
+		conditions = append(conditions, "is_active = ?")
+		args = append(args, *params.IsActive)
+	}
+
+	if params.Keyword != nil && *params.Keyword != "" {
+		conditions = append(conditions, "(template_code LIKE ? OR template_name LIKE ?)")
+		like := "%" + *params.Keyword + "%"
+		args = append(args, like, like)
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	// Count total
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM email_template WHERE %s`, whereClause)
+	var total int64
+	if err := r.db.QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count email templates: %w", err)
+	}
+
+	// Query with pagination
+	offset := (params.Page - 1) * params.Size
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM email_template
+		WHERE %s
+		ORDER BY updated_at DESC
+		LIMIT ? OFFSET ?
+	`, emailTemplateColumns, whereClause)
+	args = append(args, params.Size, offset)
+
+	var templates []models.EmailTemplate
+	if err := r.db.SelectContext(ctx, &templates, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("query email templates: %w", err)
+	}
+
+	return templates, total, nil
+}
+
+// GetByID retrieves an email template by ID
+func (r *EmailTemplateRepository) GetByID(ctx context.Context, id int) (*models.EmailTemplate, error) {
+	query := `SELECT ` + emailTemplateColumns + ` FROM email_template WHERE id = ?`
+
+	var t models.EmailTemplate
+	if err := r.db.QueryRowxContext(ctx, query, id).StructScan(&t); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get email template by id: %w", err)
+	}
+
+	return &t, nil
+}
+
+// GetByCode retrieves an email template by its unique code
+func (r *EmailTemplateRepository) GetByCode(ctx context.Context, code string) (*models.EmailTemplate, error) {
+	query := `SELECT ` + emailTemplateColumns + ` FROM email_template WHERE template_code = ?`
+
+	var t models.EmailTemplate
+	if err := r.db.QueryRowxContext(ctx, query, code).StructScan(&t); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get email template by code: %w", err)
+	}
+
+	return &t, nil
+}
+
+// Create creates a new email template
+func (r *EmailTemplateRepository) Create(ctx context.Context, t *models.EmailTemplate) error {
+	query := `
+		INSERT INTO email_template (
+			template_code, template_name, subject, html_content, text_content, description, is_active
+		) VALUES (
+			:template_code, :template_name, :subject, :html_content, :text_content, :description, :is_active
+		)
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, t)
+	if err != nil {
+		return fmt.Errorf("create email template: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	t.ID = int(id)
+	return nil
+}
+
+// Update updates an email template
+func (r *EmailTemplateRepository) Update(ctx context.Context, t *models.EmailTemplate) error {
+	query := `
+		UPDATE email_template SET
+			template_name = :template_name,
+			subject = :subject,
+			html_content = :html_content,
+			text_content = :text_content,
+			description = :description,
+			is_active = :is_active
+		WHERE id = :id
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, t); err != nil {
+		return fmt.Errorf("update email template: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes an email template
+func (r *EmailTemplateRepository) Delete(ctx context.Context, id int) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM email_template WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete email template: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// FavoriteRepository handles favorite database operations
+type FavoriteRepository struct {
+	db *sqlx.DB
+}
+
+// NewFavoriteRepository creates a new FavoriteRepository
+func NewFavoriteRepository(db *sqlx.DB) *FavoriteRepository {
+	return &FavoriteRepository{db: db}
+}
+
+// Add saves the target for the user, doing nothing if it is already saved
+func (r *FavoriteRepository) Add(ctx context.Context, userID int, targetType string, targetID int) error {
+	query := `INSERT IGNORE INTO favorite (user_id, target_type, target_id, created_at) VALUES (?, ?, ?, NOW())`
+	if _, err := r.db.ExecContext(ctx, query, userID, targetType, targetID); err != nil {
+		return fmt.Errorf("add favorite: %w", err)
+	}
+	return nil
+}
+
+// Remove deletes the user's favorite of the target
+func (r *FavoriteRepository) Remove(ctx context.Context, userID int, targetType string, targetID int) error {
+	query := `DELETE FROM favorite WHERE user_id = ? AND target_type = ? AND target_id = ?`
+	if _, err := r.db.ExecContext(ctx, query, userID, targetType, targetID); err != nil {
+		return fmt.Errorf("remove favorite: %w", err)
+	}
+	return nil
+}
+
+// DeleteByTarget removes every favorite of a deleted target
+func (r *FavoriteRepository) DeleteByTarget(ctx context.Context, targetType string, targetID int) error {
+	query := `DELETE FROM favorite WHERE target_type = ? AND target_id = ?`
+	if _, err := r.db.ExecContext(ctx, query, targetType, targetID); err != nil {
+		return fmt.Errorf("delete favorites by target: %w", err)
+	}
+	return nil
+}
+
+// DeleteByTargetTx removes every favorite of a deleted target within a transaction
+func (r *FavoriteRepository) DeleteByTargetTx(ctx context.Context, tx *sqlx.Tx, targetType string, targetID int) error {
+	query := `DELETE FROM favorite WHERE target_type = ? AND target_id = ?`
+	if _, err := tx.ExecContext(ctx, query, targetType, targetID); err != nil {
+		return fmt.Errorf("delete favorites by target: %w", err)
+	}
+	return nil
+}
+
+// ListFavorited returns which of the targets the user has saved
+func (r *FavoriteRepository) ListFavorited(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int]bool, error) {
+	result := make(map[int]bool)
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders, args := intPlaceholders(targetIDs)
+	query := `SELECT target_id FROM favorite WHERE user_id = ? AND target_type = ? AND target_id IN (` + placeholders + `)`
+	var ids []int
+	if err := r.db.SelectContext(ctx, &ids, query, append([]interface{}{userID, targetType}, args...)...); err != nil {
+		return nil, fmt.Errorf("list favorited: %w", err)
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
+
+// ListUserIDs returns the users who saved the target
+func (r *FavoriteRepository) ListUserIDs(ctx context.Context, targetType string, targetID int) ([]int, error) {
+	query := `SELECT user_id FROM favorite WHERE target_type = ? AND target_id = ? ORDER BY id`
+	var ids []int
+	if err := r.db.SelectContext(ctx, &ids, query, targetType, targetID); err != nil {
+		return nil, fmt.Errorf("list favorite users: %w", err)
+	}
+	return ids, nil
+}
@@ -57,7 +57,7 @@ func (r *FeedbackRepository) List(ctx context.Context, params FeedbackListParams
 	query := fmt.Sprintf(`
 		SELECT
 			f.id, f.user_id, f.content, f.contact_image,
-			f.status, f.admin_reply, f.created_at, f.updated_at,
+			f.status, f.created_at, f.updated_at,
 			u.nickname
 		FROM feedback f
 		LEFT JOIN `+"`user`"+` u ON f.user_id = u.id
@@ -80,7 +80,7 @@ func (r *FeedbackRepository) GetByID(ctx context.Context, id int) (*models.Feedb
 	query := `
 		SELECT
 			f.id, f.user_id, f.content, f.contact_image,
-			f.status, f.admin_reply, f.created_at, f.updated_at,
+			f.status, f.created_at, f.updated_at,
 			u.nickname
 		FROM feedback f
 		LEFT JOIN ` + "`user`" + ` u ON f.user_id = u.id
@@ -98,18 +98,65 @@ func (r *FeedbackRepository) GetByID(ctx context.Context, id int) (*models.Feedb
 	return &f, nil
 }
 
-// Reply sets admin reply and marks feedback as handled
-func (r *FeedbackRepository) Reply(ctx context.Context, id int, reply string) error {
-	query := `UPDATE feedback SET admin_reply = ?, status = 1, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+// Create creates a new feedback
+func (r *FeedbackRepository) Create(ctx context.Context, f *models.Feedback) error {
+	query := `
+		INSERT INTO feedback (user_id, content, contact_image, status)
+		VALUES (:user_id, :content, :contact_image, :status)
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, f)
+	if err != nil {
+		return fmt.Errorf("create feedback: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	f.ID = int(id)
+
+	return nil
+}
+
+// ListMessages retrieves the messages of a feedback in order
+func (r *FeedbackRepository) ListMessages(ctx context.Context, feedbackID int) ([]models.FeedbackMessage, error) {
+	query := `
+		SELECT id, feedback_id, sender_type, sender_id, content, images, created_at
+		FROM feedback_message
+		WHERE feedback_id = ?
+		ORDER BY id ASC
+	`
+
+	var messages []models.FeedbackMessage
+	if err := r.db.SelectContext(ctx, &messages, query, feedbackID); err != nil {
+		return nil, fmt.Errorf("query feedback messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// AddMessageTx appends a message to a feedback and sets the feedback status within a transaction
+func (r *FeedbackRepository) AddMessageTx(ctx context.Context, tx *sqlx.Tx, m *models.FeedbackMessage, status int) error {
+	query := `
+		INSERT INTO feedback_message (feedback_id, sender_type, sender_id, content, images)
+		VALUES (:feedback_id, :sender_type, :sender_id, :content, :images)
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, m)
+	if err != nil {
+		return fmt.Errorf("create feedback message: %w", err)
+	}
 
-	result, err := r.db.ExecContext(ctx, query, reply, id)
+	id, err := result.LastInsertId()
 	if err != nil {
-		return fmt.Errorf("reply feedback: %w", err)
+		return fmt.Errorf("get last insert id: %w", err)
 	}
+	m.ID = int(id)
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("feedback not found")
+	updateQuery := `UPDATE feedback SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+	if _, err := tx.ExecContext(ctx, updateQuery, status, m.FeedbackID); err != nil {
+		return fmt.Errorf("update feedback status: %w", err)
 	}
 
 	return nil
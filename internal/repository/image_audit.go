@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// ImageAuditRepository handles image audit database operations
+type ImageAuditRepository struct {
+	db *sqlx.DB
+}
+
+// NewImageAuditRepository creates a new ImageAuditRepository
+func NewImageAuditRepository(db *sqlx.DB) *ImageAuditRepository {
+	return &ImageAuditRepository{db: db}
+}
+
+// ImageAuditListParams contains parameters for listing image audits
+type ImageAuditListParams struct {
+	Page    int
+	Size    int
+	Status  *int
+	BizType *string
+}
+
+const imageAuditColumns = `
+	a.id, a.user_id, a.biz_type, a.image_key, a.status, a.source,
+	a.trace_id, a.suggest, a.label, a.created_at, a.updated_at
+`
+
+// List retrieves paginated image audits with optional filters
+func (r *ImageAuditRepository) List(ctx context.Context, params ImageAuditListParams) ([]models.ImageAudit, int64, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+
+	if params.Status != nil {
+		conditions = append(conditions, "a.status = ?")
+		args = append(args, *params.Status)
+	}
+
+	if params.BizType != nil {
+		conditions = append(conditions, "a.biz_type = ?")
+		args = append(args, *params.BizType)
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	// Count total
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM image_audit a WHERE %s`, whereClause)
+	var total int64
+	if err := r.db.QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count image audits: %w", err)
+	}
+
+	// Query with pagination
+	offset := (params.Page - 1) * params.Size
+	query := fmt.Sprintf(`
+		SELECT %s, u.nickname AS user_nickname
+		FROM image_audit a
+		LEFT JOIN `+"`user`"+` u ON a.user_id = u.id
+		WHERE %s
+		ORDER BY a.created_at ASC
+		LIMIT ? OFFSET ?
+	`, imageAuditColumns, whereClause)
+	args = append(args, params.Size, offset)
+
+	var audits []models.ImageAudit
+	if err := r.db.SelectContext(ctx, &audits, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("query image audits: %w", err)
+	}
+
+	return audits, total, nil
+}
+
+// GetByID retrieves an image audit by ID
+func (r *ImageAuditRepository) GetByID(ctx context.Context, id int64) (*models.ImageAudit, error) {
+	query := `
+		SELECT ` + imageAuditColumns + `, u.nickname AS user_nickname
+		FROM image_audit a
+		LEFT JOIN ` + "`user`" + ` u ON a.user_id = u.id
+		WHERE a.id = ?
+	`
+
+	var audit models.ImageAudit
+	if err := r.db.QueryRowxContext(ctx, query, id).StructScan(&audit); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get image audit by id: %w", err)
+	}
+
+	return &audit, nil
+}
+
+// GetByTraceID retrieves an image audit by the WeChat trace_id
+func (r *ImageAuditRepository) GetByTraceID(ctx context.Context, traceID string) (*models.ImageAudit, error) {
+	query := `
+		SELECT ` + imageAuditColumns + `, NULL AS user_nickname
+		FROM image_audit a
+		WHERE a.trace_id = ?
+	`
+
+	var audit models.ImageAudit
+	if err := r.db.QueryRowxContext(ctx, query, traceID).StructScan(&audit); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get image audit by trace id: %w", err)
+	}
+
+	return &audit, nil
+}
+
+// ListPendingByUser returns the user's unresolved (pending or flagged) audits for a biz type
+func (r *ImageAuditRepository) ListPendingByUser(ctx context.Context, userID int, bizType string) ([]models.ImageAudit, error) {
+	query := `
+		SELECT ` + imageAuditColumns + `, NULL AS user_nickname
+		FROM image_audit a
+		WHERE a.user_id = ? AND a.biz_type = ? AND a.status IN (?, ?)
+	`
+
+	var audits []models.ImageAudit
+	if err := r.db.SelectContext(ctx, &audits, query,
+		userID, bizType, models.ImageAuditStatusPending, models.ImageAuditStatusFlagged,
+	); err != nil {
+		return nil, fmt.Errorf("list pending image audits: %w", err)
+	}
+
+	return audits, nil
+}
+
+// Create inserts an image audit record
+func (r *ImageAuditRepository) Create(ctx context.Context, audit *models.ImageAudit) error {
+	query := `
+		INSERT INTO image_audit (user_id, biz_type, image_key, status)
+		VALUES (:user_id, :biz_type, :image_key, :status)
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, audit)
+	if err != nil {
+		return fmt.Errorf("create image audit: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	audit.ID = id
+	return nil
+}
+
+// SetTraceID records the trace_id returned when the image was submitted for checking
+func (r *ImageAuditRepository) SetTraceID(ctx context.Context, id int64, traceID string) error {
+	query := `UPDATE image_audit SET trace_id = ?, source = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, traceID, models.ImageAuditSourceWechat, id); err != nil {
+		return fmt.Errorf("set image audit trace id: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateResult records an audit verdict. Only unresolved records are updated;
+// the returned bool reports whether this call changed the record.
+func (r *ImageAuditRepository) UpdateResult(ctx context.Context, audit *models.ImageAudit) (bool, error) {
+	return updateImageAuditResult(ctx, r.db, audit)
+}
+
+// UpdateResultTx records an audit verdict within a transaction, see UpdateResult
+func (r *ImageAuditRepository) UpdateResultTx(ctx context.Context, tx *sqlx.Tx, audit *models.ImageAudit) (bool, error) {
+	return updateImageAuditResult(ctx, tx, audit)
+}
+
+func updateImageAuditResult(ctx context.Context, db sqlx.ExtContext, audit *models.ImageAudit) (bool, error) {
+	query := `
+		UPDATE image_audit SET
+			status = :status,
+			source = :source,
+			suggest = :suggest,
+			label = :label,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = :id AND status IN (0, 2)
+	`
+
+	result, err := sqlx.NamedExecContext(ctx, db, query, audit)
+	if err != nil {
+		return false, fmt.Errorf("update image audit result: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
@@ -15,8 +15,37 @@ type OrderRepo interface {
 	Create(ctx context.Context, order *models.Order) (*models.Order, error)
 	ListByUserID(ctx context.Context, params OrderListParams) ([]*models.Order, int64, error)
 	UpdatePaymentStatus(ctx context.Context, id int, status int, wxPayNo string, payTime time.Time) error
-	UpdatePaymentStatusTx(ctx context.Context, tx *sqlx.Tx, id int, status int, wxPayNo string, payTime time.Time) error
+	MarkPaidTx(ctx context.Context, tx *sqlx.Tx, id int, wxPayNo string, payTime time.Time) (bool, error)
 	UpdateStatus(ctx context.Context, id int, status int) error
+	SetOutTradeNo(ctx context.Context, id int, outTradeNo string) error
+	ListExpiredPending(ctx context.Context, before time.Time, limit int) ([]*models.Order, error)
+	CancelIfPending(ctx context.Context, id int) (bool, error)
+	TransitionStatusTx(ctx context.Context, tx *sqlx.Tx, id int, from, to int) (bool, error)
+	LockStatusTx(ctx context.Context, tx *sqlx.Tx, id int) (int, error)
+}
+
+// OrderRefundRepo defines the interface for order refund repository operations.
+type OrderRefundRepo interface {
+	List(ctx context.Context, params OrderRefundListParams) ([]models.OrderRefund, int64, error)
+	GetByID(ctx context.Context, id int) (*models.OrderRefund, error)
+	GetByOutRefundNo(ctx context.Context, outRefundNo string) (*models.OrderRefund, error)
+	GetActiveByOrderID(ctx context.Context, orderID int) (*models.OrderRefund, error)
+	ListStaleProcessing(ctx context.Context, before time.Time, limit int) ([]models.OrderRefund, error)
+	CreateTx(ctx context.Context, tx *sqlx.Tx, refund *models.OrderRefund) error
+	UpdateStatus(ctx context.Context, id int, from, to int, note *string) (bool, error)
+	UpdateStatusTx(ctx context.Context, tx *sqlx.Tx, id int, from, to int, note *string) (bool, error)
+	SetRefundID(ctx context.Context, id int, refundID string) error
+	MarkSuccessTx(ctx context.Context, tx *sqlx.Tx, id int, refundID string, successTime time.Time) (bool, error)
+}
+
+// UserEntitlementRepo defines the interface for user entitlement ledger operations.
+type UserEntitlementRepo interface {
+	CreateTx(ctx context.Context, tx *sqlx.Tx, entitlement *models.UserEntitlement) error
+	GetByOrderID(ctx context.Context, orderID int) (*models.UserEntitlement, error)
+	GetByOrderIDTx(ctx context.Context, tx *sqlx.Tx, orderID int) (*models.UserEntitlement, error)
+	ConsumeTx(ctx context.Context, tx *sqlx.Tx, id int, amount int) (bool, error)
+	RevokeTx(ctx context.Context, tx *sqlx.Tx, orderID int) (bool, error)
+	RestoreTx(ctx context.Context, tx *sqlx.Tx, orderID int) error
 }
 
 // ProjectRepo defines the interface for project repository operations used by services.
@@ -24,11 +53,17 @@ type ProjectRepo interface {
 	GetByID(ctx context.Context, id int) (*models.Project, error)
 	List(ctx context.Context, params ListParams) ([]models.Project, int64, error)
 	Create(ctx context.Context, p *models.Project) error
+	CreateTx(ctx context.Context, tx *sqlx.Tx, p *models.Project) error
+	LockMemberCountTx(ctx context.Context, tx *sqlx.Tx, id int) (int, error)
 	Update(ctx context.Context, p *models.Project) error
 	Delete(ctx context.Context, id int) error
 	IsOwner(ctx context.Context, projectID, userID int) (bool, error)
 	UpdateStatus(ctx context.Context, id int, status int) error
+	UpdateStatusTx(ctx context.Context, tx *sqlx.Tx, id int, status int) error
 	IncrementViewCount(ctx context.Context, id int) error
+	ActivatePromotionTx(ctx context.Context, tx *sqlx.Tx, id int, days int, now time.Time) error
+	FinishExpiredPromotions(ctx context.Context, now time.Time) (int64, error)
+	ListRecommendCandidates(ctx context.Context, params ProjectCandidateParams) ([]models.Project, error)
 }
 
 // ProductRepo defines the interface for product repository operations used by services.
@@ -39,14 +74,58 @@ type ProductRepo interface {
 
 // EmailPromotionRepo defines the interface for email promotion repository operations.
 type EmailPromotionRepo interface {
-	Create(ctx context.Context, promotion *models.EmailPromotion) error
+	CreateTx(ctx context.Context, tx *sqlx.Tx, promotion *models.EmailPromotion) error
 	GetByID(ctx context.Context, id int) (*models.EmailPromotion, error)
-	GetByOrderID(ctx context.Context, orderID int) (*models.EmailPromotion, error)
+	GetByOrderIDTx(ctx context.Context, tx *sqlx.Tx, orderID int) (*models.EmailPromotion, error)
 	Update(ctx context.Context, promotion *models.EmailPromotion) error
+	ListPending(ctx context.Context, limit int) ([]models.EmailPromotion, error)
 	ListByCreatorID(ctx context.Context, creatorID int, page, size int) ([]models.EmailPromotion, int64, error)
 	ListByProjectID(ctx context.Context, projectID int) ([]models.EmailPromotion, error)
 }
 
+// EmailTaskRepo defines the interface for email task repository operations.
+type EmailTaskRepo interface {
+	CreateForPromotion(ctx context.Context, promotion *models.EmailPromotion, tasks []models.EmailTask) (bool, error)
+	ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]models.EmailTask, error)
+	MarkSuccess(ctx context.Context, id int64, claimToken string, sendTime time.Time, providerID *int) (bool, error)
+	MarkRetry(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string, nextRetryAt time.Time) (bool, error)
+	MarkFailed(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string) (bool, error)
+	GetStatsByPromotionID(ctx context.Context, promotionID int) (*models.EmailTaskStats, error)
+}
+
+// EmailProviderConfigRepo defines the interface for email provider config repository operations.
+type EmailProviderConfigRepo interface {
+	ListActive(ctx context.Context) ([]models.EmailProviderConfig, error)
+}
+
+// ContentAuditRepo defines the interface for content audit log repository operations.
+type ContentAuditRepo interface {
+	Create(ctx context.Context, log *models.ContentAuditLog) error
+	AttachBizID(ctx context.Context, ids []int64, bizID int) error
+}
+
+// ImageAuditRepo defines the interface for image audit repository operations.
+type ImageAuditRepo interface {
+	List(ctx context.Context, params ImageAuditListParams) ([]models.ImageAudit, int64, error)
+	GetByID(ctx context.Context, id int64) (*models.ImageAudit, error)
+	GetByTraceID(ctx context.Context, traceID string) (*models.ImageAudit, error)
+	ListPendingByUser(ctx context.Context, userID int, bizType string) ([]models.ImageAudit, error)
+	Create(ctx context.Context, audit *models.ImageAudit) error
+	SetTraceID(ctx context.Context, id int64, traceID string) error
+	UpdateResult(ctx context.Context, audit *models.ImageAudit) (bool, error)
+	UpdateResultTx(ctx context.Context, tx *sqlx.Tx, audit *models.ImageAudit) (bool, error)
+}
+
+// EmailTemplateRepo defines the interface for email template repository operations.
+type EmailTemplateRepo interface {
+	List(ctx context.Context, params EmailTemplateListParams) ([]models.EmailTemplate, int64, error)
+	GetByID(ctx context.Context, id int) (*models.EmailTemplate, error)
+	GetByCode(ctx context.Context, code string) (*models.EmailTemplate, error)
+	Create(ctx context.Context, t *models.EmailTemplate) error
+	Update(ctx context.Context, t *models.EmailTemplate) error
+	Delete(ctx context.Context, id int) error
+}
+
 // UserRepo defines the interface for user repository operations used by services.
 type UserRepo interface {
 	GetByID(ctx context.Context, id int) (*models.User, error)
@@ -58,13 +137,16 @@ type UserRepo interface {
 	UpdateQuota(ctx context.Context, user *models.User) error
 	AddOliveBranchCount(ctx context.Context, userID int, count int) error
 	AddOliveBranchCountTx(ctx context.Context, tx *sqlx.Tx, userID int, count int) error
+	DeductOliveBranchCountTx(ctx context.Context, tx *sqlx.Tx, userID int, count int) (bool, error)
+	UseFreeBranchTx(ctx context.Context, tx *sqlx.Tx, userID int, dailyLimit int, today time.Time) (bool, error)
 	UpdateAuthStatus(ctx context.Context, userID int, authStatus int) error
+	UpdateAuthStatusTx(ctx context.Context, tx *sqlx.Tx, userID int, authStatus int) error
 	ListUsers(ctx context.Context, params UserListParams) ([]models.User, int64, error)
 	FindEmailRecipients(ctx context.Context, excludeUserID int, limit int) ([]*EmailRecipient, error)
 	SetEmailOptOut(ctx context.Context, userID int, optOut bool) error
 	UpdateAuthImgUrl(ctx context.Context, userID int, authImgUrl string) error
-	UpdateAvatarUrl(ctx context.Context, userID int, avatarUrl string) error
-	UpdateCoverImage(ctx context.Context, userID int, coverImage string) error
+	UpdateAvatarUrlTx(ctx context.Context, tx *sqlx.Tx, userID int, avatarUrl string) error
+	UpdateCoverImageTx(ctx context.Context, tx *sqlx.Tx, userID int, coverImage string) error
 	GetEduCertInfoByID(ctx context.Context, userID int) (CertInfo, error)
 }
 
@@ -72,9 +154,31 @@ type UserRepo interface {
 type ApplicationRepo interface {
 	List(ctx context.Context, params ApplicationListParams) ([]models.ProjectApplication, int64, error)
 	Create(ctx context.Context, app *models.ProjectApplication) error
+	CreateTx(ctx context.Context, tx *sqlx.Tx, app *models.ProjectApplication) error
 	GetByID(ctx context.Context, id int) (*models.ProjectApplication, error)
-	CheckDuplicate(ctx context.Context, projectID, userID int) (bool, error)
-	UpdateStatus(ctx context.Context, id int, status int) error
+	GetLatest(ctx context.Context, projectID, userID int) (*models.ProjectApplication, error)
+	GetLatestTx(ctx context.Context, tx *sqlx.Tx, projectID, userID int) (*models.ProjectApplication, error)
+	ListAttempts(ctx context.Context, projectID, userID int) ([]models.ProjectApplication, error)
+	UpdateStatus(ctx context.Context, id int, status int, replyMsg *string) error
+	TransitionStatusTx(ctx context.Context, tx *sqlx.Tx, id, from, to int, replyMsg *string) (bool, error)
+	LockPendingTx(ctx context.Context, tx *sqlx.Tx, projectID int, ids []int) ([]models.ProjectApplication, error)
+	UpdateStatusBatchTx(ctx context.Context, tx *sqlx.Tx, ids []int, status int, replyMsg *string) error
+	CreateLogTx(ctx context.Context, tx *sqlx.Tx, l *models.ApplicationStatusLog) error
+	CreateLogsTx(ctx context.Context, tx *sqlx.Tx, logs []*models.ApplicationStatusLog) error
+	ListLogs(ctx context.Context, applicationIDs []int) ([]models.ApplicationStatusLog, error)
+}
+
+// ProjectMemberRepo defines the interface for project member repository operations.
+type ProjectMemberRepo interface {
+	AddTx(ctx context.Context, tx *sqlx.Tx, m *models.ProjectMember) error
+	GetActive(ctx context.Context, projectID, userID int) (*models.ProjectMember, error)
+	CountMembers(ctx context.Context, projectID int) (int, error)
+	CountMembersTx(ctx context.Context, tx *sqlx.Tx, projectID int) (int, error)
+	ListActiveUserIDsTx(ctx context.Context, tx *sqlx.Tx, projectID int, userIDs []int) ([]int, error)
+	ListActiveByProject(ctx context.Context, projectID int) ([]models.ProjectMember, error)
+	ListTeamsByUser(ctx context.Context, params TeamListParams) ([]models.ProjectMember, int64, error)
+	DeactivateTx(ctx context.Context, tx *sqlx.Tx, projectID, userID, status int) (bool, error)
+	CountDirectionsByUsers(ctx context.Context, userIDs []int) (map[int]map[int]int, error)
 }
 
 // OliveBranchRepo defines the interface for olive branch repository operations.
@@ -82,9 +186,19 @@ type OliveBranchRepo interface {
 	ListByReceiverID(ctx context.Context, params OliveBranchListParams) ([]models.OliveBranch, int64, error)
 	GetByID(ctx context.Context, id int) (*models.OliveBranch, error)
 	Create(ctx context.Context, ob *models.OliveBranch) error
+	CreateTx(ctx context.Context, tx *sqlx.Tx, ob *models.OliveBranch) error
 	UpdateStatus(ctx context.Context, id int, status int) error
 	ListBySenderID(ctx context.Context, params OliveBranchListParams) ([]models.OliveBranch, int64, error)
 	ExistsPending(ctx context.Context, senderID, receiverID, relatedProjectID int) (bool, error)
+	ExistsPendingTx(ctx context.Context, tx *sqlx.Tx, senderID, receiverID, relatedProjectID int) (bool, error)
+	TransitionStatusTx(ctx context.Context, tx *sqlx.Tx, id int, from, to int) (bool, error)
+	ListExpiredPending(ctx context.Context, before time.Time, limit int) ([]models.OliveBranch, error)
+}
+
+// OliveBranchLedgerRepo defines the interface for olive branch ledger operations.
+type OliveBranchLedgerRepo interface {
+	CreateTx(ctx context.Context, tx *sqlx.Tx, entry *models.OliveBranchLedger) error
+	ListByUserID(ctx context.Context, params OliveBranchLedgerListParams) ([]models.OliveBranchLedger, int64, error)
 }
 
 // SchoolRepo defines the interface for school repository operations.
@@ -103,8 +217,12 @@ type TalentProfileRepo interface {
 	List(ctx context.Context, params TalentProfileListParams) ([]models.TalentProfile, int64, error)
 	GetByID(ctx context.Context, id int) (*models.TalentProfile, error)
 	GetByUserID(ctx context.Context, userID int) (*models.TalentProfile, error)
+	GetByUserIDTx(ctx context.Context, tx *sqlx.Tx, userID int) (*models.TalentProfile, error)
 	Upsert(ctx context.Context, p *models.TalentProfile) error
+	UpsertTx(ctx context.Context, tx *sqlx.Tx, p *models.TalentProfile) error
 	DeleteByUserID(ctx context.Context, userID int) error
+	DeleteByUserIDTx(ctx context.Context, tx *sqlx.Tx, userID int) error
+	ListRecommendCandidates(ctx context.Context, params TalentCandidateParams) ([]models.TalentProfile, error)
 }
 
 // AdminUserRepo defines the interface for admin user repository operations.
@@ -117,7 +235,9 @@ type AdminUserRepo interface {
 type FeedbackRepo interface {
 	List(ctx context.Context, params FeedbackListParams) ([]models.Feedback, int64, error)
 	GetByID(ctx context.Context, id int) (*models.Feedback, error)
-	Reply(ctx context.Context, id int, reply string) error
+	Create(ctx context.Context, f *models.Feedback) error
+	ListMessages(ctx context.Context, feedbackID int) ([]models.FeedbackMessage, error)
+	AddMessageTx(ctx context.Context, tx *sqlx.Tx, m *models.FeedbackMessage, status int) error
 }
 
 // SubscribeConfigRepo defines the interface for subscribe config repository operations.
@@ -126,10 +246,90 @@ type SubscribeConfigRepo interface {
 	ListByUserID(ctx context.Context, userID int) ([]models.SubscribeConfig, error)
 	Upsert(ctx context.Context, config *models.SubscribeConfig) error
 	UpdateStatus(ctx context.Context, userID int, bizKey string, status models.SubscribeStatus) error
-	DecrementCount(ctx context.Context, userID int, bizKey string) error
+	UpsertStatus(ctx context.Context, userID int, bizKey string, status models.SubscribeStatus) error
+	DecrementCount(ctx context.Context, userID int, bizKey string) (bool, error)
+	RestoreCount(ctx context.Context, userID int, bizKey string) error
 	IncrementCount(ctx context.Context, userID int, bizKey string, count int) error
 }
 
+// MessageOutboxRepo defines the interface for message outbox repository operations.
+type MessageOutboxRepo interface {
+	CreateTx(ctx context.Context, tx *sqlx.Tx, msg *models.MessageOutbox) error
+	CreateBatchTx(ctx context.Context, tx *sqlx.Tx, msgs []*models.MessageOutbox) error
+	ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]models.MessageOutbox, error)
+	MarkSent(ctx context.Context, id int64, claimToken string, sentAt time.Time) (bool, error)
+	MarkRetry(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string, nextRetryAt time.Time) (bool, error)
+	MarkFailed(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string) (bool, error)
+	MarkSkipped(ctx context.Context, id int64, claimToken string, reason string) (bool, error)
+	Replay(ctx context.Context, id int64) (bool, error)
+	List(ctx context.Context, params MessageOutboxListParams) ([]models.MessageOutbox, int64, error)
+	GetByID(ctx context.Context, id int64) (*models.MessageOutbox, error)
+}
+
+// NotificationRepo defines the interface for in-app notification repository operations.
+type NotificationRepo interface {
+	CreateTx(ctx context.Context, tx *sqlx.Tx, n *models.Notification) error
+	CreateBatchTx(ctx context.Context, tx *sqlx.Tx, ns []*models.Notification) error
+	ListByUserID(ctx context.Context, params NotificationListParams) ([]models.Notification, int64, error)
+	GetByID(ctx context.Context, id int64) (*models.Notification, error)
+	CountUnread(ctx context.Context, userID int) (int64, error)
+	MarkRead(ctx context.Context, userID int, id int64, readAt time.Time) error
+	MarkAllRead(ctx context.Context, userID int, readAt time.Time) (int64, error)
+}
+
+// RealtimeEventRepo defines the interface for the realtime event log shared by server instances.
+type RealtimeEventRepo interface {
+	Create(ctx context.Context, e *models.RealtimeEvent) error
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]models.RealtimeEvent, error)
+	MaxID(ctx context.Context) (int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SearchDocumentRepo defines the interface for full-text search document operations.
+type SearchDocumentRepo interface {
+	Upsert(ctx context.Context, d *models.SearchDocument) error
+	Delete(ctx context.Context, docType string, refID int) error
+	Search(ctx context.Context, params SearchDocumentParams) ([]models.SearchHit, error)
+	ListProjectSources(ctx context.Context, afterID, limit int) ([]models.Project, error)
+	ListTalentSources(ctx context.Context, afterID, limit int) ([]models.TalentProfile, error)
+}
+
+// SkillTagRepo defines the interface for skill tag dictionary and link operations.
+type SkillTagRepo interface {
+	ListCategories(ctx context.Context) ([]models.SkillCategory, error)
+	GetCategoryByID(ctx context.Context, id int) (*models.SkillCategory, error)
+	GetCategoryByName(ctx context.Context, name string) (*models.SkillCategory, error)
+	CreateCategory(ctx context.Context, c *models.SkillCategory) error
+	UpdateCategory(ctx context.Context, c *models.SkillCategory) error
+	List(ctx context.Context, params SkillTagListParams) ([]models.SkillTag, int64, error)
+	ListActive(ctx context.Context) ([]models.SkillTag, error)
+	Suggest(ctx context.Context, params SkillTagSuggestParams) ([]models.SkillTag, error)
+	GetByID(ctx context.Context, id int) (*models.SkillTag, error)
+	FindByTerm(ctx context.Context, term string) (*models.SkillTag, error)
+	CreateTx(ctx context.Context, tx *sqlx.Tx, t *models.SkillTag) error
+	UpdateTx(ctx context.Context, tx *sqlx.Tx, t *models.SkillTag) error
+	ReplaceSynonymsTx(ctx context.Context, tx *sqlx.Tx, tagID int, synonyms []string) error
+	ListSynonyms(ctx context.Context, tagIDs []int) (map[int][]string, error)
+	ListTerms(ctx context.Context) ([]models.SkillTagTerm, error)
+	MergeTx(ctx context.Context, tx *sqlx.Tx, source *models.SkillTag, targetID int) error
+	SetProjectTags(ctx context.Context, projectID int, tagIDs []int) error
+	SetTalentTags(ctx context.Context, talentProfileID int, tagIDs []int) error
+	ListByProjects(ctx context.Context, projectIDs []int) (map[int][]models.SkillTag, error)
+	ListByTalents(ctx context.Context, talentProfileIDs []int) (map[int][]models.SkillTag, error)
+	ListProjectTexts(ctx context.Context, afterID, limit int) ([]models.SkillText, error)
+	ListTalentTexts(ctx context.Context, afterID, limit int) ([]models.SkillText, error)
+}
+
+// FavoriteRepo defines the interface for saved project and talent profile operations.
+type FavoriteRepo interface {
+	Add(ctx context.Context, userID int, targetType string, targetID int) error
+	Remove(ctx context.Context, userID int, targetType string, targetID int) error
+	DeleteByTarget(ctx context.Context, targetType string, targetID int) error
+	DeleteByTargetTx(ctx context.Context, tx *sqlx.Tx, targetType string, targetID int) error
+	ListFavorited(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int]bool, error)
+	ListUserIDs(ctx context.Context, targetType string, targetID int) ([]int, error)
+}
+
 // MsgTemplateConfigRepo defines the interface for fetching message template configurations.
 type MsgTemplateConfigRepo interface {
 	GetByBizKey(ctx context.Context, bizKey string) (*models.MsgTemplateConfig, error)
@@ -138,16 +338,31 @@ type MsgTemplateConfigRepo interface {
 
 // Compile-time interface satisfaction checks
 var _ OrderRepo = (*OrderRepository)(nil)
+var _ OrderRefundRepo = (*OrderRefundRepository)(nil)
+var _ UserEntitlementRepo = (*UserEntitlementRepository)(nil)
 var _ ProjectRepo = (*ProjectRepository)(nil)
 var _ ProductRepo = (*ProductRepository)(nil)
 var _ EmailPromotionRepo = (*EmailPromotionRepository)(nil)
+var _ EmailTaskRepo = (*EmailTaskRepository)(nil)
+var _ EmailTemplateRepo = (*EmailTemplateRepository)(nil)
+var _ EmailProviderConfigRepo = (*EmailProviderConfigRepository)(nil)
+var _ ContentAuditRepo = (*ContentAuditRepository)(nil)
+var _ ImageAuditRepo = (*ImageAuditRepository)(nil)
 var _ UserRepo = (*UserRepository)(nil)
 var _ ApplicationRepo = (*ApplicationRepository)(nil)
 var _ OliveBranchRepo = (*OliveBranchRepository)(nil)
+var _ OliveBranchLedgerRepo = (*OliveBranchLedgerRepository)(nil)
 var _ SchoolRepo = (*SchoolRepository)(nil)
 var _ MajorRepo = (*MajorRepository)(nil)
 var _ TalentProfileRepo = (*TalentProfileRepository)(nil)
 var _ AdminUserRepo = (*AdminUserRepository)(nil)
 var _ FeedbackRepo = (*FeedbackRepository)(nil)
 var _ SubscribeConfigRepo = (*SubscribeConfigRepository)(nil)
+var _ MessageOutboxRepo = (*MessageOutboxRepository)(nil)
 var _ MsgTemplateConfigRepo = (*MsgTemplateConfigRepository)(nil)
+var _ NotificationRepo = (*NotificationRepository)(nil)
+var _ RealtimeEventRepo = (*RealtimeEventRepository)(nil)
+var _ ProjectMemberRepo = (*ProjectMemberRepository)(nil)
+var _ SearchDocumentRepo = (*SearchDocumentRepository)(nil)
+var _ SkillTagRepo = (*SkillTagRepository)(nil)
+var _ FavoriteRepo = (*FavoriteRepository)(nil)
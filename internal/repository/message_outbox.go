@@ -0,0 +1,331 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// MessageOutboxRepository handles message outbox database operations
+type MessageOutboxRepository struct {
+	db *sqlx.DB
+}
+
+// NewMessageOutboxRepository creates a new MessageOutboxRepository
+func NewMessageOutboxRepository(db *sqlx.DB) *MessageOutboxRepository {
+	return &MessageOutboxRepository{db: db}
+}
+
+// MessageOutboxListParams contains parameters for listing outbox messages
+type MessageOutboxListParams struct {
+	Page   int
+	Size   int
+	Status *int
+	UserID *int
+	BizKey *string
+}
+
+const messageOutboxColumns = `
+	m.id, m.user_id, m.biz_key, m.payload, m.status, m.retry_count,
+	m.error_msg, m.next_retry_at, m.claim_token, m.sent_at, m.created_at, m.updated_at`
+
+// CreateTx inserts a pending message within the transaction of the business change
+func (r *MessageOutboxRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, msg *models.MessageOutbox) error {
+	query := `
+		INSERT INTO message_outbox (user_id, biz_key, payload, status, retry_count)
+		VALUES (:user_id, :biz_key, :payload, :status, :retry_count)
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, msg)
+	if err != nil {
+		return fmt.Errorf("create message outbox: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	msg.ID = id
+	return nil
+}
+
+// CreateBatchTx queues several messages in one statement within the transaction of the business change
+func (r *MessageOutboxRepository) CreateBatchTx(ctx context.Context, tx *sqlx.Tx, msgs []*models.MessageOutbox) error {
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO message_outbox (user_id, biz_key, payload, status, retry_count)
+		VALUES (:user_id, :biz_key, :payload, :status, :retry_count)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, msgs); err != nil {
+		return fmt.Errorf("create message outbox batch: %w", err)
+	}
+	return nil
+}
+
+// ClaimDue locks up to limit due messages and marks them as sending until leaseUntil.
+// Due messages are pending/retrying messages whose backoff has elapsed, plus sending
+// messages whose lease expired. Every claimed message gets a fresh claim token that
+// the Mark* methods check, so a dispatcher whose lease ran out cannot overwrite the result. A message is only claimed once every earlier message
+// of the same user has finished, so each user receives notifications in order.
+func (r *MessageOutboxRepository) ClaimDue(ctx context.Context, limit int, leaseUntil time.Time) ([]models.MessageOutbox, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	query := `
+		SELECT ` + messageOutboxColumns + `
+		FROM message_outbox m
+		WHERE ((m.status IN (?, ?) AND (m.next_retry_at IS NULL OR m.next_retry_at <= ?))
+		    OR (m.status = ? AND m.next_retry_at <= ?))
+		  AND NOT EXISTS (
+			SELECT 1 FROM message_outbox p
+			WHERE p.user_id = m.user_id AND p.id < m.id AND p.status IN (?, ?, ?)
+		  )
+		ORDER BY m.id
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var msgs []models.MessageOutbox
+	if err := tx.SelectContext(ctx, &msgs, query,
+		models.MessageOutboxStatusPending, models.MessageOutboxStatusRetrying, now,
+		models.MessageOutboxStatusSending, now,
+		models.MessageOutboxStatusPending, models.MessageOutboxStatusSending, models.MessageOutboxStatusRetrying,
+		limit,
+	); err != nil {
+		return nil, fmt.Errorf("query due outbox messages: %w", err)
+	}
+
+	if len(msgs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]int64, len(msgs))
+	for i, m := range msgs {
+		ids[i] = m.ID
+	}
+	claimToken := uuid.NewString()
+
+	updateQuery, args, err := sqlx.In(`
+		UPDATE message_outbox SET
+			status = ?,
+			next_retry_at = ?,
+			claim_token = ?
+		WHERE id IN (?)
+	`, models.MessageOutboxStatusSending, leaseUntil, claimToken, ids)
+	if err != nil {
+		return nil, fmt.Errorf("build claim query: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(updateQuery), args...); err != nil {
+		return nil, fmt.Errorf("claim outbox messages: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	for i := range msgs {
+		msgs[i].Status = models.MessageOutboxStatusSending
+		msgs[i].NextRetryAt = &leaseUntil
+		msgs[i].ClaimToken = &claimToken
+	}
+
+	return msgs, nil
+}
+
+// MarkSent marks a claimed message as delivered.
+// It returns false when the claim is no longer held (the lease expired and the message was reclaimed).
+func (r *MessageOutboxRepository) MarkSent(ctx context.Context, id int64, claimToken string, sentAt time.Time) (bool, error) {
+	query := `
+		UPDATE message_outbox SET
+			status = ?,
+			error_msg = NULL,
+			next_retry_at = NULL,
+			sent_at = ?,
+			claim_token = NULL
+		WHERE id = ? AND status = ? AND claim_token = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.MessageOutboxStatusSent, sentAt,
+		id, models.MessageOutboxStatusSending, claimToken)
+	if err != nil {
+		return false, fmt.Errorf("mark outbox message sent: %w", err)
+	}
+
+	return claimHeld(result)
+}
+
+// MarkRetry records a failed attempt of a claimed message and schedules the next one.
+// It returns false when the claim is no longer held.
+func (r *MessageOutboxRepository) MarkRetry(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string, nextRetryAt time.Time) (bool, error) {
+	query := `
+		UPDATE message_outbox SET
+			status = ?,
+			retry_count = ?,
+			error_msg = ?,
+			next_retry_at = ?,
+			claim_token = NULL
+		WHERE id = ? AND status = ? AND claim_token = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.MessageOutboxStatusRetrying, retryCount, errMsg, nextRetryAt,
+		id, models.MessageOutboxStatusSending, claimToken)
+	if err != nil {
+		return false, fmt.Errorf("mark outbox message retry: %w", err)
+	}
+
+	return claimHeld(result)
+}
+
+// MarkFailed marks a claimed message as permanently failed.
+// It returns false when the claim is no longer held.
+func (r *MessageOutboxRepository) MarkFailed(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string) (bool, error) {
+	query := `
+		UPDATE message_outbox SET
+			status = ?,
+			retry_count = ?,
+			error_msg = ?,
+			next_retry_at = NULL,
+			claim_token = NULL
+		WHERE id = ? AND status = ? AND claim_token = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.MessageOutboxStatusFailed, retryCount, errMsg,
+		id, models.MessageOutboxStatusSending, claimToken)
+	if err != nil {
+		return false, fmt.Errorf("mark outbox message failed: %w", err)
+	}
+
+	return claimHeld(result)
+}
+
+// MarkSkipped marks a claimed message as intentionally not sent, keeping the reason.
+// It returns false when the claim is no longer held.
+func (r *MessageOutboxRepository) MarkSkipped(ctx context.Context, id int64, claimToken string, reason string) (bool, error) {
+	query := `
+		UPDATE message_outbox SET
+			status = ?,
+			error_msg = ?,
+			next_retry_at = NULL,
+			claim_token = NULL
+		WHERE id = ? AND status = ? AND claim_token = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.MessageOutboxStatusSkipped, reason,
+		id, models.MessageOutboxStatusSending, claimToken)
+	if err != nil {
+		return false, fmt.Errorf("mark outbox message skipped: %w", err)
+	}
+
+	return claimHeld(result)
+}
+
+// Replay re-queues a failed message for immediate delivery.
+// It returns false when the message does not exist or is not failed.
+func (r *MessageOutboxRepository) Replay(ctx context.Context, id int64) (bool, error) {
+	query := `
+		UPDATE message_outbox SET
+			status = ?,
+			retry_count = 0,
+			error_msg = NULL,
+			next_retry_at = NULL,
+			claim_token = NULL
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.MessageOutboxStatusPending, id, models.MessageOutboxStatusFailed)
+	if err != nil {
+		return false, fmt.Errorf("replay outbox message: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// List retrieves paginated outbox messages with optional filters, newest first
+func (r *MessageOutboxRepository) List(ctx context.Context, params MessageOutboxListParams) ([]models.MessageOutbox, int64, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+
+	if params.Status != nil {
+		conditions = append(conditions, "m.status = ?")
+		args = append(args, *params.Status)
+	}
+
+	if params.UserID != nil {
+		conditions = append(conditions, "m.user_id = ?")
+		args = append(args, *params.UserID)
+	}
+
+	if params.BizKey != nil {
+		conditions = append(conditions, "m.biz_key = ?")
+		args = append(args, *params.BizKey)
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	// Count total
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM message_outbox m WHERE %s`, whereClause)
+	var total int64
+	if err := r.db.QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count outbox messages: %w", err)
+	}
+
+	// Query with pagination
+	offset := (params.Page - 1) * params.Size
+	query := fmt.Sprintf(`
+		SELECT `+messageOutboxColumns+`, u.nickname AS user_nickname
+		FROM message_outbox m
+		LEFT JOIN `+"`user`"+` u ON m.user_id = u.id
+		WHERE %s
+		ORDER BY m.id DESC
+		LIMIT ? OFFSET ?
+	`, whereClause)
+	args = append(args, params.Size, offset)
+
+	var msgs []models.MessageOutbox
+	if err := r.db.SelectContext(ctx, &msgs, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("query outbox messages: %w", err)
+	}
+
+	return msgs, total, nil
+}
+
+// GetByID retrieves an outbox message by ID
+func (r *MessageOutboxRepository) GetByID(ctx context.Context, id int64) (*models.MessageOutbox, error) {
+	query := `
+		SELECT ` + messageOutboxColumns + `, u.nickname AS user_nickname
+		FROM message_outbox m
+		LEFT JOIN ` + "`user`" + ` u ON m.user_id = u.id
+		WHERE m.id = ?
+	`
+
+	var msg models.MessageOutbox
+	if err := r.db.QueryRowxContext(ctx, query, id).StructScan(&msg); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query outbox message by id: %w", err)
+	}
+
+	return &msg, nil
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// NotificationRepository handles in-app notification database operations
+type NotificationRepository struct {
+	db *sqlx.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository
+func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// NotificationListParams contains parameters for listing notifications
+type NotificationListParams struct {
+	UserID     int
+	UnreadOnly bool
+	Page       int
+	Size       int
+}
+
+// CreateTx inserts a notification within the transaction of the business change
+func (r *NotificationRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, n *models.Notification) error {
+	query := `
+		INSERT INTO notification (user_id, type, title, content, related_id)
+		VALUES (:user_id, :type, :title, :content, :related_id)
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, n)
+	if err != nil {
+		return fmt.Errorf("create notification: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	n.ID = id
+	return nil
+}
+
+// CreateBatchTx inserts several notifications in one statement within the
+// transaction of the business change. The rows of one multi-row INSERT get
+// consecutive ids starting from the last insert id.
+func (r *NotificationRepository) CreateBatchTx(ctx context.Context, tx *sqlx.Tx, ns []*models.Notification) error {
+	if len(ns) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO notification (user_id, type, title, content, related_id)
+		VALUES (:user_id, :type, :title, :content, :related_id)
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, ns)
+	if err != nil {
+		return fmt.Errorf("create notifications: %w", err)
+	}
+
+	firstID, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	for i, n := range ns {
+		n.ID = firstID + int64(i)
+	}
+	return nil
+}
+
+// ListByUserID retrieves paginated notifications of a user, newest first
+func (r *NotificationRepository) ListByUserID(ctx context.Context, params NotificationListParams) ([]models.Notification, int64, error) {
+	whereClause := "user_id = ?"
+	args := []interface{}{params.UserID}
+	if params.UnreadOnly {
+		whereClause += " AND is_read = 0"
+	}
+
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM notification WHERE ` + whereClause
+	if err := r.db.QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count notifications: %w", err)
+	}
+
+	offset := (params.Page - 1) * params.Size
+	query := `
+		SELECT id, user_id, type, title, content, related_id, is_read, read_at, created_at
+		FROM notification
+		WHERE ` + whereClause + `
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+	args = append(args, params.Size, offset)
+
+	var notifications []models.Notification
+	if err := r.db.SelectContext(ctx, &notifications, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("query notifications: %w", err)
+	}
+
+	return notifications, total, nil
+}
+
+// GetByID retrieves a notification by ID
+func (r *NotificationRepository) GetByID(ctx context.Context, id int64) (*models.Notification, error) {
+	query := `
+		SELECT id, user_id, type, title, content, related_id, is_read, read_at, created_at
+		FROM notification
+		WHERE id = ?
+	`
+
+	var n models.Notification
+	if err := r.db.QueryRowxContext(ctx, query, id).StructScan(&n); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query notification by id: %w", err)
+	}
+
+	return &n, nil
+}
+
+// CountUnread counts the unread notifications of a user
+func (r *NotificationRepository) CountUnread(ctx context.Context, userID int) (int64, error) {
+	var count int64
+	query := `SELECT COUNT(*) FROM notification WHERE user_id = ? AND is_read = 0`
+	if err := r.db.QueryRowxContext(ctx, query, userID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks a notification of a user as read; already read notifications are left unchanged
+func (r *NotificationRepository) MarkRead(ctx context.Context, userID int, id int64, readAt time.Time) error {
+	query := `UPDATE notification SET is_read = 1, read_at = ? WHERE id = ? AND user_id = ? AND is_read = 0`
+
+	if _, err := r.db.ExecContext(ctx, query, readAt, id, userID); err != nil {
+		return fmt.Errorf("mark notification read: %w", err)
+	}
+
+	return nil
+}
+
+// MarkAllRead marks every unread notification of a user as read and returns how many were updated
+func (r *NotificationRepository) MarkAllRead(ctx context.Context, userID int, readAt time.Time) (int64, error) {
+	query := `UPDATE notification SET is_read = 1, read_at = ? WHERE user_id = ? AND is_read = 0`
+
+	result, err := r.db.ExecContext(ctx, query, readAt, userID)
+	if err != nil {
+		return 0, fmt.Errorf("mark all notifications read: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
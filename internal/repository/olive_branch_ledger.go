@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// OliveBranchLedgerRepository handles olive branch ledger database operations
+type OliveBranchLedgerRepository struct {
+	db *sqlx.DB
+}
+
+// NewOliveBranchLedgerRepository creates a new OliveBranchLedgerRepository
+func NewOliveBranchLedgerRepository(db *sqlx.DB) *OliveBranchLedgerRepository {
+	return &OliveBranchLedgerRepository{db: db}
+}
+
+// OliveBranchLedgerListParams contains parameters for listing ledger entries
+type OliveBranchLedgerListParams struct {
+	UserID int
+	Page   int
+	Size   int
+}
+
+// CreateTx appends a ledger entry within a transaction
+func (r *OliveBranchLedgerRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, entry *models.OliveBranchLedger) error {
+	query := `
+		INSERT INTO olive_branch_ledger (
+			user_id, entry_type, cost_type, amount,
+			olive_branch_id, order_id, remark
+		) VALUES (
+			:user_id, :entry_type, :cost_type, :amount,
+			:olive_branch_id, :order_id, :remark
+		)
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, entry)
+	if err != nil {
+		return fmt.Errorf("create olive branch ledger: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	entry.ID = id
+	return nil
+}
+
+// ListByUserID retrieves paginated ledger entries of a user, newest first
+func (r *OliveBranchLedgerRepository) ListByUserID(ctx context.Context, params OliveBranchLedgerListParams) ([]models.OliveBranchLedger, int64, error) {
+	var total int64
+	countQuery := `SELECT COUNT(*) FROM olive_branch_ledger WHERE user_id = ?`
+	if err := r.db.QueryRowxContext(ctx, countQuery, params.UserID).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count olive branch ledger: %w", err)
+	}
+
+	offset := (params.Page - 1) * params.Size
+	query := `
+		SELECT id, user_id, entry_type, cost_type, amount,
+		       olive_branch_id, order_id, remark, created_at
+		FROM olive_branch_ledger
+		WHERE user_id = ?
+		ORDER BY id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	var entries []models.OliveBranchLedger
+	if err := r.db.SelectContext(ctx, &entries, query, params.UserID, params.Size, offset); err != nil {
+		return nil, 0, fmt.Errorf("query olive branch ledger: %w", err)
+	}
+
+	return entries, total, nil
+}
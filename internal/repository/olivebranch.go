@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/trv3wood/kuaizu-server/internal/models"
@@ -164,6 +165,32 @@ func (r *OliveBranchRepository) Create(ctx context.Context, ob *models.OliveBran
 	return nil
 }
 
+// CreateTx creates a new olive branch record within a transaction
+func (r *OliveBranchRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, ob *models.OliveBranch) error {
+	query := `
+		INSERT INTO olive_branch_record (
+			sender_id, receiver_id, related_project_id,
+			type, cost_type, status
+		) VALUES (
+			:sender_id, :receiver_id, :related_project_id,
+			:type, :cost_type, :status
+		)
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, ob)
+	if err != nil {
+		return fmt.Errorf("create olive branch: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	ob.ID = int(id)
+
+	return nil
+}
+
 // ExistsPending checks if there is a pending (status=0) olive branch from sender to receiver.
 func (r *OliveBranchRepository) ExistsPending(ctx context.Context, senderID, receiverID, relatedProjectID int) (bool, error) {
 	var count int
@@ -174,6 +201,17 @@ func (r *OliveBranchRepository) ExistsPending(ctx context.Context, senderID, rec
 	return count > 0, nil
 }
 
+// ExistsPendingTx checks for a pending olive branch from sender to receiver
+// within a transaction, locking the matching records until it ends
+func (r *OliveBranchRepository) ExistsPendingTx(ctx context.Context, tx *sqlx.Tx, senderID, receiverID, relatedProjectID int) (bool, error) {
+	var ids []int
+	query := `SELECT id FROM olive_branch_record WHERE sender_id = ? AND receiver_id = ? AND related_project_id = ? AND status = 0 FOR UPDATE`
+	if err := tx.SelectContext(ctx, &ids, query, senderID, receiverID, relatedProjectID); err != nil {
+		return false, fmt.Errorf("lock pending olive branch: %w", err)
+	}
+	return len(ids) > 0, nil
+}
+
 // UpdateStatus updates the status of an olive branch
 func (r *OliveBranchRepository) UpdateStatus(ctx context.Context, id int, status int) error {
 	query := `UPDATE olive_branch_record SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
@@ -191,6 +229,43 @@ func (r *OliveBranchRepository) UpdateStatus(ctx context.Context, id int, status
 	return nil
 }
 
+// TransitionStatusTx moves an olive branch from one status to another within a transaction.
+// The returned bool reports whether the olive branch was in the expected status.
+func (r *OliveBranchRepository) TransitionStatusTx(ctx context.Context, tx *sqlx.Tx, id int, from, to int) (bool, error) {
+	query := `UPDATE olive_branch_record SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ? AND status = ?`
+
+	result, err := tx.ExecContext(ctx, query, to, id, from)
+	if err != nil {
+		return false, fmt.Errorf("transition olive branch status: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// ListExpiredPending retrieves pending olive branches sent before the given time
+func (r *OliveBranchRepository) ListExpiredPending(ctx context.Context, before time.Time, limit int) ([]models.OliveBranch, error) {
+	query := `
+		SELECT
+			ob.id, ob.sender_id, ob.receiver_id, ob.related_project_id,
+			ob.type, ob.cost_type, ob.status,
+			ob.created_at, ob.updated_at,
+			p.name AS project_name
+		FROM olive_branch_record ob
+		LEFT JOIN project p ON ob.related_project_id = p.id
+		WHERE ob.status = ? AND ob.created_at < ?
+		ORDER BY ob.created_at ASC
+		LIMIT ?
+	`
+
+	var records []models.OliveBranch
+	if err := r.db.SelectContext(ctx, &records, query, models.OliveBranchStatusPending, before, limit); err != nil {
+		return nil, fmt.Errorf("list expired pending olive branches: %w", err)
+	}
+
+	return records, nil
+}
+
 // ListBySenderID retrieves paginated olive branches sent by a user
 func (r *OliveBranchRepository) ListBySenderID(ctx context.Context, params OliveBranchListParams) ([]models.OliveBranch, int64, error) {
 	// Count total
@@ -51,7 +51,7 @@ func (r *OrderRepository) ListByUserID(ctx context.Context, params OrderListPara
 	query := fmt.Sprintf(`
 		SELECT
 			o.id, o.user_id, o.product_id, o.price, o.quantity, o.actual_paid, o.status,
-			o.wx_pay_no, o.pay_time, o.created_at, o.updated_at,
+			o.wx_pay_no, o.out_trade_no, o.pay_time, o.created_at, o.updated_at,
 			p.name as product_name
 		FROM `+"`order`"+` o
 		LEFT JOIN product p ON o.product_id = p.id
@@ -116,7 +116,7 @@ func (r *OrderRepository) GetByID(ctx context.Context, id int) (*models.Order, e
 	query := `
 		SELECT
 			o.id, o.user_id, o.product_id, o.price, o.quantity, o.actual_paid, o.status,
-			o.wx_pay_no, o.pay_time, o.created_at, o.updated_at,
+			o.wx_pay_no, o.out_trade_no, o.pay_time, o.created_at, o.updated_at,
 			p.name as product_name
 		FROM ` + "`order`" + ` o
 		LEFT JOIN product p ON o.product_id = p.id
@@ -153,23 +153,25 @@ func (r *OrderRepository) UpdatePaymentStatus(ctx context.Context, id int, statu
 	return nil
 }
 
-// UpdatePaymentStatusTx updates order payment status within a transaction
-func (r *OrderRepository) UpdatePaymentStatusTx(ctx context.Context, tx *sqlx.Tx, id int, status int, wxPayNo string, payTime time.Time) error {
+// MarkPaidTx records the payment of a pending order within a transaction.
+// The returned bool reports whether the order was still pending.
+func (r *OrderRepository) MarkPaidTx(ctx context.Context, tx *sqlx.Tx, id int, wxPayNo string, payTime time.Time) (bool, error) {
 	query := `
 		UPDATE ` + "`order`" + ` SET
 			status = ?,
 			wx_pay_no = ?,
 			pay_time = ?,
 			updated_at = NOW()
-		WHERE id = ?
+		WHERE id = ? AND status = ?
 	`
 
-	_, err := tx.ExecContext(ctx, query, status, wxPayNo, payTime, id)
+	result, err := tx.ExecContext(ctx, query, models.OrderStatusPaid, wxPayNo, payTime, id, models.OrderStatusPending)
 	if err != nil {
-		return fmt.Errorf("update payment status: %w", err)
+		return false, fmt.Errorf("mark order paid: %w", err)
 	}
 
-	return nil
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
 }
 
 // UpdateStatus updates only the order status
@@ -188,3 +190,90 @@ func (r *OrderRepository) UpdateStatus(ctx context.Context, id int, status int)
 
 	return nil
 }
+
+// SetOutTradeNo records the out_trade_no used for the WeChat Pay prepay order
+func (r *OrderRepository) SetOutTradeNo(ctx context.Context, id int, outTradeNo string) error {
+	query := `
+		UPDATE ` + "`order`" + ` SET
+			out_trade_no = ?,
+			updated_at = NOW()
+		WHERE id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, outTradeNo, id)
+	if err != nil {
+		return fmt.Errorf("set out trade no: %w", err)
+	}
+
+	return nil
+}
+
+// ListExpiredPending retrieves pending orders created before the given time
+func (r *OrderRepository) ListExpiredPending(ctx context.Context, before time.Time, limit int) ([]*models.Order, error) {
+	query := `
+		SELECT
+			o.id, o.user_id, o.product_id, o.price, o.quantity, o.actual_paid, o.status,
+			o.wx_pay_no, o.out_trade_no, o.pay_time, o.created_at, o.updated_at
+		FROM ` + "`order`" + ` o
+		WHERE o.status = ? AND o.created_at < ?
+		ORDER BY o.created_at ASC
+		LIMIT ?
+	`
+
+	var orders []*models.Order
+	if err := r.db.SelectContext(ctx, &orders, query, models.OrderStatusPending, before, limit); err != nil {
+		return nil, fmt.Errorf("list expired pending orders: %w", err)
+	}
+
+	return orders, nil
+}
+
+// CancelIfPending cancels the order only if it is still pending.
+// The returned bool reports whether the order was cancelled by this call.
+func (r *OrderRepository) CancelIfPending(ctx context.Context, id int) (bool, error) {
+	query := `
+		UPDATE ` + "`order`" + ` SET
+			status = ?,
+			updated_at = NOW()
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.OrderStatusCancelled, id, models.OrderStatusPending)
+	if err != nil {
+		return false, fmt.Errorf("cancel pending order: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// LockStatusTx locks the order row and returns its status, serializing refunds
+// started for the same order
+func (r *OrderRepository) LockStatusTx(ctx context.Context, tx *sqlx.Tx, id int) (int, error) {
+	query := `SELECT status FROM ` + "`order`" + ` WHERE id = ? FOR UPDATE`
+
+	var status int
+	if err := tx.GetContext(ctx, &status, query, id); err != nil {
+		return 0, fmt.Errorf("lock order: %w", err)
+	}
+	return status, nil
+}
+
+// TransitionStatusTx moves an order from one status to another within a transaction.
+// The returned bool reports whether the order was in the expected status.
+func (r *OrderRepository) TransitionStatusTx(ctx context.Context, tx *sqlx.Tx, id int, from, to int) (bool, error) {
+	query := `
+		UPDATE ` + "`order`" + ` SET
+			status = ?,
+			updated_at = NOW()
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := tx.ExecContext(ctx, query, to, id, from)
+	if err != nil {
+		return false, fmt.Errorf("transition order status: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
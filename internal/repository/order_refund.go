@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// OrderRefundRepository handles order refund database operations
+type OrderRefundRepository struct {
+	db *sqlx.DB
+}
+
+// NewOrderRefundRepository creates a new OrderRefundRepository
+func NewOrderRefundRepository(db *sqlx.DB) *OrderRefundRepository {
+	return &OrderRefundRepository{db: db}
+}
+
+// OrderRefundListParams contains parameters for listing refunds
+type OrderRefundListParams struct {
+	Page   int
+	Size   int
+	Status *int
+	UserID *int
+}
+
+const orderRefundSelect = `
+	SELECT
+		r.id, r.order_id, r.user_id, r.out_refund_no, r.refund_id, r.amount, r.reason,
+		r.status, r.source, r.note, r.success_time, r.created_at, r.updated_at,
+		p.name AS product_name, u.nickname AS user_nickname
+	FROM order_refund r
+	LEFT JOIN ` + "`order`" + ` o ON r.order_id = o.id
+	LEFT JOIN product p ON o.product_id = p.id
+	LEFT JOIN ` + "`user`" + ` u ON r.user_id = u.id
+`
+
+// List retrieves paginated refunds with optional filters
+func (r *OrderRefundRepository) List(ctx context.Context, params OrderRefundListParams) ([]models.OrderRefund, int64, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+
+	if params.Status != nil {
+		conditions = append(conditions, "r.status = ?")
+		args = append(args, *params.Status)
+	}
+
+	if params.UserID != nil {
+		conditions = append(conditions, "r.user_id = ?")
+		args = append(args, *params.UserID)
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	// Count total
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM order_refund r WHERE %s`, whereClause)
+	var total int64
+	if err := r.db.QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count order refunds: %w", err)
+	}
+
+	// Query with pagination
+	offset := (params.Page - 1) * params.Size
+	query := orderRefundSelect + fmt.Sprintf(`
+		WHERE %s
+		ORDER BY r.created_at DESC
+		LIMIT ? OFFSET ?
+	`, whereClause)
+	args = append(args, params.Size, offset)
+
+	var refunds []models.OrderRefund
+	if err := r.db.SelectContext(ctx, &refunds, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("query order refunds: %w", err)
+	}
+
+	return refunds, total, nil
+}
+
+// GetByID retrieves a refund by ID
+func (r *OrderRefundRepository) GetByID(ctx context.Context, id int) (*models.OrderRefund, error) {
+	return r.getOne(ctx, "r.id = ?", id)
+}
+
+// GetByOutRefundNo retrieves a refund by its out_refund_no
+func (r *OrderRefundRepository) GetByOutRefundNo(ctx context.Context, outRefundNo string) (*models.OrderRefund, error) {
+	return r.getOne(ctx, "r.out_refund_no = ?", outRefundNo)
+}
+
+// GetActiveByOrderID retrieves the pending or processing refund of an order
+func (r *OrderRefundRepository) GetActiveByOrderID(ctx context.Context, orderID int) (*models.OrderRefund, error) {
+	return r.getOne(ctx, "r.order_id = ? AND r.status IN (?, ?)",
+		orderID, models.RefundStatusPending, models.RefundStatusProcessing)
+}
+
+// ListStaleProcessing retrieves processing refunds that have not changed since before, oldest first
+func (r *OrderRefundRepository) ListStaleProcessing(ctx context.Context, before time.Time, limit int) ([]models.OrderRefund, error) {
+	query := orderRefundSelect + `
+		WHERE r.status = ? AND r.updated_at <= ?
+		ORDER BY r.id
+		LIMIT ?
+	`
+
+	var refunds []models.OrderRefund
+	if err := r.db.SelectContext(ctx, &refunds, query, models.RefundStatusProcessing, before, limit); err != nil {
+		return nil, fmt.Errorf("query stale processing refunds: %w", err)
+	}
+
+	return refunds, nil
+}
+
+func (r *OrderRefundRepository) getOne(ctx context.Context, where string, args ...interface{}) (*models.OrderRefund, error) {
+	query := orderRefundSelect + " WHERE " + where + " ORDER BY r.id DESC LIMIT 1"
+
+	var refund models.OrderRefund
+	if err := r.db.QueryRowxContext(ctx, query, args...).StructScan(&refund); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get order refund: %w", err)
+	}
+
+	return &refund, nil
+}
+
+// CreateTx inserts a refund record within a transaction
+func (r *OrderRefundRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, refund *models.OrderRefund) error {
+	return r.create(ctx, tx, refund)
+}
+
+func (r *OrderRefundRepository) create(ctx context.Context, ext sqlx.ExtContext, refund *models.OrderRefund) error {
+	query := `
+		INSERT INTO order_refund (order_id, user_id, out_refund_no, amount, reason, status, source)
+		VALUES (:order_id, :user_id, :out_refund_no, :amount, :reason, :status, :source)
+	`
+
+	result, err := sqlx.NamedExecContext(ctx, ext, query, refund)
+	if err != nil {
+		return fmt.Errorf("create order refund: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	refund.ID = int(id)
+	return nil
+}
+
+// UpdateStatus moves a refund from one status to another
+func (r *OrderRefundRepository) UpdateStatus(ctx context.Context, id int, from, to int, note *string) (bool, error) {
+	return r.updateStatus(ctx, r.db, id, from, to, note)
+}
+
+// UpdateStatusTx moves a refund from one status to another within a transaction.
+// The returned bool reports whether the refund was in the expected status.
+func (r *OrderRefundRepository) UpdateStatusTx(ctx context.Context, tx *sqlx.Tx, id int, from, to int, note *string) (bool, error) {
+	return r.updateStatus(ctx, tx, id, from, to, note)
+}
+
+func (r *OrderRefundRepository) updateStatus(ctx context.Context, ext sqlx.ExtContext, id int, from, to int, note *string) (bool, error) {
+	query := `
+		UPDATE order_refund SET
+			status = ?,
+			note = COALESCE(?, note),
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := ext.ExecContext(ctx, query, to, note, id, from)
+	if err != nil {
+		return false, fmt.Errorf("update order refund status: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// SetRefundID records the WeChat refund_id
+func (r *OrderRefundRepository) SetRefundID(ctx context.Context, id int, refundID string) error {
+	query := `UPDATE order_refund SET refund_id = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, refundID, id); err != nil {
+		return fmt.Errorf("set order refund id: %w", err)
+	}
+
+	return nil
+}
+
+// MarkSuccessTx marks a processing refund as successful within a transaction
+func (r *OrderRefundRepository) MarkSuccessTx(ctx context.Context, tx *sqlx.Tx, id int, refundID string, successTime time.Time) (bool, error) {
+	query := `
+		UPDATE order_refund SET
+			status = ?,
+			refund_id = COALESCE(NULLIF(?, ''), refund_id),
+			success_time = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		models.RefundStatusSuccess, refundID, successTime, id, models.RefundStatusProcessing)
+	if err != nil {
+		return false, fmt.Errorf("mark order refund success: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
@@ -23,21 +23,42 @@ func NewProjectRepository(db *sqlx.DB) *ProjectRepository {
 
 // ListParams contains parameters for listing projects
 type ListParams struct {
-	Page          int
-	Size          int
-	Keyword       *string
-	SchoolID      *int
-	Status        *int
-	Direction     *int
-	CreatorID     *int
-	IsCrossSchool *int
+	Page           int
+	Size           int
+	Keyword        *string
+	SchoolID       *int
+	Status         *int
+	Direction      *int
+	CreatorID      *int
+	IsCrossSchool  *int
+	Education      *int
+	Skill          *string
+	SkillTagID     *int
+	FavoriteUserID *int  // 只返回该用户收藏的项目，按收藏时间倒序
+	IDs            []int // 搜索命中的项目ID，非 nil 时只在其中筛选并按该顺序返回
+	RotationSeed   int64 // 推广中项目的轮换种子，同一种子下排序稳定
 }
 
 // List retrieves paginated projects with optional filters
 func (r *ProjectRepository) List(ctx context.Context, params ListParams) ([]models.Project, int64, error) {
+	if params.IDs != nil && len(params.IDs) == 0 {
+		return []models.Project{}, 0, nil
+	}
+
 	conditions := []string{"1=1"}
 	args := []interface{}{}
+	joins := ""
 
+	if params.FavoriteUserID != nil {
+		joins = "JOIN favorite fav ON fav.target_id = p.id"
+		conditions = append(conditions, "fav.target_type = ?", "fav.user_id = ?")
+		args = append(args, models.FavoriteTargetProject, *params.FavoriteUserID)
+	}
+	if params.IDs != nil {
+		placeholders, idArgs := intPlaceholders(params.IDs)
+		conditions = append(conditions, "p.id IN ("+placeholders+")")
+		args = append(args, idArgs...)
+	}
 	if params.Keyword != nil && *params.Keyword != "" {
 		conditions = append(conditions, "(p.name LIKE ? OR p.description LIKE ?)")
 		args = append(args, "%"+*params.Keyword+"%", "%"+*params.Keyword+"%")
@@ -62,16 +83,47 @@ func (r *ProjectRepository) List(ctx context.Context, params ListParams) ([]mode
 		conditions = append(conditions, "p.is_cross_school = ?")
 		args = append(args, *params.IsCrossSchool)
 	}
+	if params.Education != nil {
+		conditions = append(conditions, "p.education_requirement = ?")
+		args = append(args, *params.Education)
+	}
+	if params.Skill != nil && *params.Skill != "" {
+		conditions = append(conditions, "p.skill_requirement LIKE ?")
+		args = append(args, "%"+*params.Skill+"%")
+	}
+	if params.SkillTagID != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM project_skill_tag pst WHERE pst.project_id = p.id AND pst.tag_id = ?)")
+		args = append(args, *params.SkillTagID)
+	}
 
 	whereClause := strings.Join(conditions, " AND ")
 
 	// Count total
 	var total int64
-	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM project p WHERE %s`, whereClause)
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM project p %s WHERE %s`, joins, whereClause)
 	if err := r.db.QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("count projects: %w", err)
 	}
 
+	// 推广中的项目排在前面，彼此之间按种子打散轮换；其余按创建时间倒序。
+	// 搜索结果按命中顺序（相关度）排列，收藏列表按收藏时间倒序。
+	orderBy := `
+			(p.promotion_status = ? AND p.promotion_expire_time > ?) DESC,
+			CASE WHEN p.promotion_status = ? THEN CRC32(CONCAT(p.id, ':', ?)) END,
+			p.created_at DESC`
+	orderArgs := []interface{}{
+		models.ProjectPromotionActive, time.Now(),
+		models.ProjectPromotionActive, params.RotationSeed,
+	}
+	if params.IDs != nil {
+		placeholders, idArgs := intPlaceholders(params.IDs)
+		orderBy = "FIELD(p.id, " + placeholders + ")"
+		orderArgs = idArgs
+	} else if params.FavoriteUserID != nil {
+		orderBy = "fav.created_at DESC, fav.id DESC"
+		orderArgs = nil
+	}
+
 	// Query with pagination — column aliases match Project db tags
 	offset := (params.Page - 1) * params.Size
 	query := fmt.Sprintf(`
@@ -84,11 +136,12 @@ func (r *ProjectRepository) List(ctx context.Context, params ListParams) ([]mode
 			s.school_name
 		FROM project p
 		LEFT JOIN school s ON p.school_id = s.id
+		%s
 		WHERE %s
-		ORDER BY p.created_at DESC
+		ORDER BY %s
 		LIMIT ? OFFSET ?
-	`, whereClause)
-	args = append(args, params.Size, offset)
+	`, joins, whereClause, orderBy)
+	args = append(append(args, orderArgs...), params.Size, offset)
 
 	var projects []models.Project
 	if err := r.db.SelectContext(ctx, &projects, query, args...); err != nil {
@@ -98,6 +151,60 @@ func (r *ProjectRepository) List(ctx context.Context, params ListParams) ([]mode
 	return projects, total, nil
 }
 
+// ProjectCandidateParams contains parameters for listing projects to recommend to a user
+type ProjectCandidateParams struct {
+	UserID   int
+	SchoolID *int // 用户所在学校，不可跨校的项目只对同校用户开放
+	Limit    int
+}
+
+// ListRecommendCandidates returns approved, not yet full projects the user
+// neither created nor joined, newest first
+func (r *ProjectRepository) ListRecommendCandidates(ctx context.Context, params ProjectCandidateParams) ([]models.Project, error) {
+	conditions := []string{
+		"p.status = ?",
+		"p.creator_id <> ?",
+		"NOT EXISTS (SELECT 1 FROM project_member pm WHERE pm.project_id = p.id AND pm.user_id = ? AND pm.status = ?)",
+		`(p.member_count IS NULL OR p.member_count <= 0 OR p.member_count > (
+			SELECT COUNT(*) FROM project_member pm WHERE pm.project_id = p.id AND pm.role = ? AND pm.status = ?))`,
+	}
+	args := []interface{}{
+		models.ProjectStatusApproved,
+		params.UserID,
+		params.UserID, models.ProjectMemberStatusActive,
+		models.ProjectMemberRoleMember, models.ProjectMemberStatusActive,
+	}
+	if params.SchoolID != nil {
+		conditions = append(conditions, "(p.is_cross_school = ? OR p.school_id = ?)")
+		args = append(args, models.ProjectCrossSchoolYes, *params.SchoolID)
+	} else {
+		conditions = append(conditions, "p.is_cross_school = ?")
+		args = append(args, models.ProjectCrossSchoolYes)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			p.id, p.creator_id, p.name, p.description, p.school_id,
+			p.direction, p.member_count, p.status,
+			p.promotion_status, p.promotion_expire_time, p.view_count,
+			p.created_at, p.updated_at, p.is_cross_school,
+			p.education_requirement, p.skill_requirement,
+			s.school_name
+		FROM project p
+		LEFT JOIN school s ON p.school_id = s.id
+		WHERE %s
+		ORDER BY p.created_at DESC
+		LIMIT ?
+	`, strings.Join(conditions, " AND "))
+	args = append(args, params.Limit)
+
+	var projects []models.Project
+	if err := r.db.SelectContext(ctx, &projects, query, args...); err != nil {
+		return nil, fmt.Errorf("list recommend candidate projects: %w", err)
+	}
+	return projects, nil
+}
+
 // creatorRow holds the JOIN-ed creator columns for GetByID.
 // Column aliases (u_*) avoid conflicts with project columns of the same name.
 type creatorRow struct {
@@ -190,6 +297,45 @@ func (r *ProjectRepository) Create(ctx context.Context, p *models.Project) error
 	return nil
 }
 
+// CreateTx creates a new project within a transaction
+func (r *ProjectRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, p *models.Project) error {
+	query := `
+		INSERT INTO project (
+			creator_id, name, description, school_id, direction,
+			member_count, status, promotion_status, view_count,
+			is_cross_school, education_requirement, skill_requirement
+		) VALUES (
+			:creator_id, :name, :description, :school_id, :direction,
+			:member_count, :status, :promotion_status, :view_count,
+			:is_cross_school, :education_requirement, :skill_requirement
+		)
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, p)
+	if err != nil {
+		return fmt.Errorf("create project: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	p.ID = int(id)
+	return nil
+}
+
+// LockMemberCountTx locks the project row and returns its required member count
+// (0 when unset), serializing members joining the same project
+func (r *ProjectRepository) LockMemberCountTx(ctx context.Context, tx *sqlx.Tx, id int) (int, error) {
+	query := `SELECT COALESCE(member_count, 0) FROM project WHERE id = ? FOR UPDATE`
+
+	var memberCount int
+	if err := tx.GetContext(ctx, &memberCount, query, id); err != nil {
+		return 0, fmt.Errorf("lock project: %w", err)
+	}
+	return memberCount, nil
+}
+
 // Update updates a project
 func (r *ProjectRepository) Update(ctx context.Context, p *models.Project) error {
 	query := `
@@ -259,6 +405,22 @@ func (r *ProjectRepository) UpdateStatus(ctx context.Context, id int, status int
 	return nil
 }
 
+// UpdateStatusTx updates the status of a project within a transaction
+func (r *ProjectRepository) UpdateStatusTx(ctx context.Context, tx *sqlx.Tx, id int, status int) error {
+	query := `UPDATE project SET status = ?, updated_at = CURRENT_TIMESTAMP WHERE id = ?`
+
+	result, err := tx.ExecContext(ctx, query, status, id)
+	if err != nil {
+		return fmt.Errorf("update project status: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("project not found")
+	}
+	return nil
+}
+
 // IncrementViewCount increments the view count of a project
 func (r *ProjectRepository) IncrementViewCount(ctx context.Context, id int) error {
 	query := `UPDATE project SET view_count = view_count + 1 WHERE id = ?`
@@ -267,3 +429,52 @@ func (r *ProjectRepository) IncrementViewCount(ctx context.Context, id int) erro
 	}
 	return nil
 }
+
+// ActivatePromotionTx starts or extends the promotion of a project by the given days
+// within a transaction. An active promotion is extended from its current expire
+// time, otherwise from now.
+func (r *ProjectRepository) ActivatePromotionTx(ctx context.Context, tx *sqlx.Tx, id int, days int, now time.Time) error {
+	query := `
+		UPDATE project SET
+			promotion_expire_time = DATE_ADD(
+				IF(promotion_status = ? AND promotion_expire_time > ?, promotion_expire_time, ?),
+				INTERVAL ? DAY
+			),
+			promotion_status = ?,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE id = ?
+	`
+
+	result, err := tx.ExecContext(ctx, query,
+		models.ProjectPromotionActive, now, now, days,
+		models.ProjectPromotionActive, id,
+	)
+	if err != nil {
+		return fmt.Errorf("activate project promotion: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("project not found")
+	}
+	return nil
+}
+
+// FinishExpiredPromotions marks promotions that expired before now as finished
+func (r *ProjectRepository) FinishExpiredPromotions(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		UPDATE project SET promotion_status = ?, updated_at = CURRENT_TIMESTAMP
+		WHERE promotion_status = ? AND promotion_expire_time <= ?
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.ProjectPromotionFinished, models.ProjectPromotionActive, now)
+	if err != nil {
+		return 0, fmt.Errorf("finish expired promotions: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// ProjectMemberRepository handles project member database operations
+type ProjectMemberRepository struct {
+	db *sqlx.DB
+}
+
+// NewProjectMemberRepository creates a new ProjectMemberRepository
+func NewProjectMemberRepository(db *sqlx.DB) *ProjectMemberRepository {
+	return &ProjectMemberRepository{db: db}
+}
+
+// TeamListParams contains parameters for listing the teams of a user
+type TeamListParams struct {
+	UserID int
+	Page   int
+	Size   int
+}
+
+// AddTx adds a user to a project within a transaction. A user who left or was
+// removed before rejoins with the new role and join time.
+func (r *ProjectMemberRepository) AddTx(ctx context.Context, tx *sqlx.Tx, m *models.ProjectMember) error {
+	query := `
+		INSERT INTO project_member (project_id, user_id, role, source, status)
+		VALUES (:project_id, :user_id, :role, :source, :status)
+		ON DUPLICATE KEY UPDATE
+			role = VALUES(role),
+			source = VALUES(source),
+			status = VALUES(status),
+			joined_at = CURRENT_TIMESTAMP,
+			left_at = NULL
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, m); err != nil {
+		return fmt.Errorf("add project member: %w", err)
+	}
+	return nil
+}
+
+// GetActive returns the active membership of a user in a project, or nil
+func (r *ProjectMemberRepository) GetActive(ctx context.Context, projectID, userID int) (*models.ProjectMember, error) {
+	query := `
+		SELECT id, project_id, user_id, role, source, status, joined_at, left_at
+		FROM project_member
+		WHERE project_id = ? AND user_id = ? AND status = ?
+	`
+
+	var m models.ProjectMember
+	if err := r.db.GetContext(ctx, &m, query, projectID, userID, models.ProjectMemberStatusActive); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get project member: %w", err)
+	}
+	return &m, nil
+}
+
+// CountMembers returns the number of active members of a project, the leader excluded
+func (r *ProjectMemberRepository) CountMembers(ctx context.Context, projectID int) (int, error) {
+	return countMembers(ctx, r.db, projectID)
+}
+
+// CountMembersTx returns the number of active members of a project within a transaction, the leader excluded
+func (r *ProjectMemberRepository) CountMembersTx(ctx context.Context, tx *sqlx.Tx, projectID int) (int, error) {
+	return countMembers(ctx, tx, projectID)
+}
+
+func countMembers(ctx context.Context, q sqlx.QueryerContext, projectID int) (int, error) {
+	query := `SELECT COUNT(*) FROM project_member WHERE project_id = ? AND role = ? AND status = ?`
+
+	var count int
+	if err := sqlx.GetContext(ctx, q, &count, query, projectID, models.ProjectMemberRoleMember, models.ProjectMemberStatusActive); err != nil {
+		return 0, fmt.Errorf("count project members: %w", err)
+	}
+	return count, nil
+}
+
+// ListActiveUserIDsTx returns which of the users are already active in the
+// project, leader included, within a transaction
+func (r *ProjectMemberRepository) ListActiveUserIDsTx(ctx context.Context, tx *sqlx.Tx, projectID int, userIDs []int) ([]int, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders, args := intPlaceholders(userIDs)
+	query := `SELECT user_id FROM project_member WHERE project_id = ? AND status = ? AND user_id IN (` + placeholders + `)`
+	var ids []int
+	if err := tx.SelectContext(ctx, &ids, query, append([]interface{}{projectID, models.ProjectMemberStatusActive}, args...)...); err != nil {
+		return nil, fmt.Errorf("list active project members: %w", err)
+	}
+	return ids, nil
+}
+
+// ListActiveByProject returns the active members of a project, the leader first
+func (r *ProjectMemberRepository) ListActiveByProject(ctx context.Context, projectID int) ([]models.ProjectMember, error) {
+	query := `
+		SELECT
+			pm.id, pm.project_id, pm.user_id, pm.role, pm.source, pm.status, pm.joined_at, pm.left_at,
+			u.nickname, u.avatar_url, s.school_name, m.major_name
+		FROM project_member pm
+		JOIN ` + "`user`" + ` u ON pm.user_id = u.id
+		LEFT JOIN school s ON u.school_id = s.id
+		LEFT JOIN major m ON u.major_id = m.id
+		WHERE pm.project_id = ? AND pm.status = ?
+		ORDER BY pm.role = 'leader' DESC, pm.joined_at ASC, pm.id ASC
+	`
+
+	var members []models.ProjectMember
+	if err := r.db.SelectContext(ctx, &members, query, projectID, models.ProjectMemberStatusActive); err != nil {
+		return nil, fmt.Errorf("list project members: %w", err)
+	}
+	return members, nil
+}
+
+// directionCountRow holds the number of projects of one direction a user is active in.
+type directionCountRow struct {
+	UserID    int `db:"user_id"`
+	Direction int `db:"direction"`
+	Count     int `db:"cnt"`
+}
+
+// CountDirectionsByUsers returns, per user, how many projects of each
+// direction they are active members (leader included) of
+func (r *ProjectMemberRepository) CountDirectionsByUsers(ctx context.Context, userIDs []int) (map[int]map[int]int, error) {
+	counts := make(map[int]map[int]int)
+	if len(userIDs) == 0 {
+		return counts, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT pm.user_id, p.direction, COUNT(*) AS cnt
+		FROM project_member pm
+		JOIN project p ON pm.project_id = p.id
+		WHERE pm.user_id IN (?) AND pm.status = ? AND p.direction IS NOT NULL
+		GROUP BY pm.user_id, p.direction
+	`, userIDs, models.ProjectMemberStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("build count directions query: %w", err)
+	}
+
+	var rows []directionCountRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("count member directions: %w", err)
+	}
+	for _, row := range rows {
+		if counts[row.UserID] == nil {
+			counts[row.UserID] = make(map[int]int)
+		}
+		counts[row.UserID][row.Direction] = row.Count
+	}
+	return counts, nil
+}
+
+// teamRow holds a membership with the columns of its project.
+type teamRow struct {
+	models.ProjectMember
+	PName            string  `db:"p_name"`
+	PDescription     *string `db:"p_description"`
+	PSchoolID        *int    `db:"p_school_id"`
+	PSchoolName      *string `db:"p_school_name"`
+	PDirection       *int    `db:"p_direction"`
+	PMemberCount     *int    `db:"p_member_count"`
+	PStatus          int     `db:"p_status"`
+	PPromotionStatus int     `db:"p_promotion_status"`
+	PIsCrossSchool   *int    `db:"p_is_cross_school"`
+}
+
+// ListTeamsByUser returns paginated active memberships of a user with their projects, newest first
+func (r *ProjectMemberRepository) ListTeamsByUser(ctx context.Context, params TeamListParams) ([]models.ProjectMember, int64, error) {
+	countQuery := `SELECT COUNT(*) FROM project_member WHERE user_id = ? AND status = ?`
+	var total int64
+	if err := r.db.GetContext(ctx, &total, countQuery, params.UserID, models.ProjectMemberStatusActive); err != nil {
+		return nil, 0, fmt.Errorf("count teams: %w", err)
+	}
+
+	query := `
+		SELECT
+			pm.id, pm.project_id, pm.user_id, pm.role, pm.source, pm.status, pm.joined_at, pm.left_at,
+			p.name AS p_name, p.description AS p_description, p.school_id AS p_school_id,
+			s.school_name AS p_school_name, p.direction AS p_direction, p.member_count AS p_member_count,
+			p.status AS p_status, p.promotion_status AS p_promotion_status, p.is_cross_school AS p_is_cross_school
+		FROM project_member pm
+		JOIN project p ON pm.project_id = p.id
+		LEFT JOIN school s ON p.school_id = s.id
+		WHERE pm.user_id = ? AND pm.status = ?
+		ORDER BY pm.joined_at DESC, pm.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	offset := (params.Page - 1) * params.Size
+	var rows []teamRow
+	if err := r.db.SelectContext(ctx, &rows, query, params.UserID, models.ProjectMemberStatusActive, params.Size, offset); err != nil {
+		return nil, 0, fmt.Errorf("list teams: %w", err)
+	}
+
+	teams := make([]models.ProjectMember, len(rows))
+	for i, row := range rows {
+		teams[i] = row.ProjectMember
+		teams[i].Project = &models.Project{
+			ID:              row.ProjectID,
+			Name:            row.PName,
+			Description:     row.PDescription,
+			SchoolID:        row.PSchoolID,
+			SchoolName:      row.PSchoolName,
+			Direction:       row.PDirection,
+			MemberCount:     row.PMemberCount,
+			Status:          row.PStatus,
+			PromotionStatus: row.PPromotionStatus,
+			IsCrossSchool:   row.PIsCrossSchool,
+		}
+	}
+	return teams, total, nil
+}
+
+// DeactivateTx marks an active membership as left or removed within a transaction.
+// It reports false when the user is not an active member.
+func (r *ProjectMemberRepository) DeactivateTx(ctx context.Context, tx *sqlx.Tx, projectID, userID, status int) (bool, error) {
+	query := `
+		UPDATE project_member SET status = ?, left_at = CURRENT_TIMESTAMP
+		WHERE project_id = ? AND user_id = ? AND status = ?
+	`
+
+	result, err := tx.ExecContext(ctx, query, status, projectID, userID, models.ProjectMemberStatusActive)
+	if err != nil {
+		return false, fmt.Errorf("deactivate project member: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
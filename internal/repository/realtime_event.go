@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// RealtimeEventRepository handles realtime event log database operations
+type RealtimeEventRepository struct {
+	db *sqlx.DB
+}
+
+// NewRealtimeEventRepository creates a new RealtimeEventRepository
+func NewRealtimeEventRepository(db *sqlx.DB) *RealtimeEventRepository {
+	return &RealtimeEventRepository{db: db}
+}
+
+// Create appends an event to the log
+func (r *RealtimeEventRepository) Create(ctx context.Context, e *models.RealtimeEvent) error {
+	query := `
+		INSERT INTO realtime_event (user_id, type, data)
+		VALUES (:user_id, :type, :data)
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, e)
+	if err != nil {
+		return fmt.Errorf("create realtime event: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	e.ID = id
+	return nil
+}
+
+// ListAfter returns up to limit events with an id greater than afterID, oldest first
+func (r *RealtimeEventRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]models.RealtimeEvent, error) {
+	query := `
+		SELECT id, user_id, type, data, created_at
+		FROM realtime_event
+		WHERE id > ?
+		ORDER BY id ASC
+		LIMIT ?
+	`
+
+	var events []models.RealtimeEvent
+	if err := r.db.SelectContext(ctx, &events, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("list realtime events: %w", err)
+	}
+	return events, nil
+}
+
+// MaxID returns the id of the newest event, or 0 when the log is empty
+func (r *RealtimeEventRepository) MaxID(ctx context.Context) (int64, error) {
+	var id int64
+	if err := r.db.GetContext(ctx, &id, `SELECT COALESCE(MAX(id), 0) FROM realtime_event`); err != nil {
+		return 0, fmt.Errorf("get max realtime event id: %w", err)
+	}
+	return id, nil
+}
+
+// DeleteBefore removes events created before the given time
+func (r *RealtimeEventRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM realtime_event WHERE created_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete realtime events: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -1,26 +1,43 @@
 package repository
 
 import (
+	"strings"
+
 	"github.com/jmoiron/sqlx"
 )
 
 // Repository aggregates all sub-repositories
 type Repository struct {
-	db              *sqlx.DB
-	User            UserRepo
-	Project         ProjectRepo
-	Product         ProductRepo
-	Application     ApplicationRepo
-	OliveBranch     OliveBranchRepo
-	School          SchoolRepo
-	Major           MajorRepo
-	TalentProfile   TalentProfileRepo
-	Order           OrderRepo
-	EmailPromotion  EmailPromotionRepo
-	AdminUser       AdminUserRepo
-	Feedback        FeedbackRepo
-	MsgTemplate     MsgTemplateConfigRepo
-	SubscribeConfig SubscribeConfigRepo
+	db                *sqlx.DB
+	User              UserRepo
+	Project           ProjectRepo
+	Product           ProductRepo
+	Application       ApplicationRepo
+	ProjectMember     ProjectMemberRepo
+	OliveBranch       OliveBranchRepo
+	OliveBranchLedger OliveBranchLedgerRepo
+	School            SchoolRepo
+	Major             MajorRepo
+	TalentProfile     TalentProfileRepo
+	Order             OrderRepo
+	OrderRefund       OrderRefundRepo
+	Entitlement       UserEntitlementRepo
+	EmailPromotion    EmailPromotionRepo
+	EmailTask         EmailTaskRepo
+	EmailTemplate     EmailTemplateRepo
+	EmailProvider     EmailProviderConfigRepo
+	AdminUser         AdminUserRepo
+	Feedback          FeedbackRepo
+	Favorite          FavoriteRepo
+	MessageOutbox     MessageOutboxRepo
+	MsgTemplate       MsgTemplateConfigRepo
+	Notification      NotificationRepo
+	RealtimeEvent     RealtimeEventRepo
+	SearchDocument    SearchDocumentRepo
+	SkillTag          SkillTagRepo
+	SubscribeConfig   SubscribeConfigRepo
+	ContentAudit      ContentAuditRepo
+	ImageAudit        ImageAuditRepo
 }
 
 // DB returns the underlying database connection for transaction support
@@ -31,20 +48,45 @@ func (r *Repository) DB() *sqlx.DB {
 // New creates a new Repository with all sub-repositories
 func New(db *sqlx.DB) *Repository {
 	return &Repository{
-		db:              db,
-		User:            NewUserRepository(db),
-		Project:         NewProjectRepository(db),
-		Product:         NewProductRepository(db),
-		Application:     NewApplicationRepository(db),
-		OliveBranch:     NewOliveBranchRepository(db),
-		School:          NewSchoolRepository(db),
-		Major:           NewMajorRepository(db),
-		TalentProfile:   NewTalentProfileRepository(db),
-		Order:           NewOrderRepository(db),
-		EmailPromotion:  NewEmailPromotionRepository(db),
-		AdminUser:       NewAdminUserRepository(db),
-		Feedback:        NewFeedbackRepository(db),
-		MsgTemplate:     NewMsgTemplateConfigRepository(db),
-		SubscribeConfig: NewSubscribeConfigRepository(db),
+		db:                db,
+		User:              NewUserRepository(db),
+		Project:           NewProjectRepository(db),
+		Product:           NewProductRepository(db),
+		Application:       NewApplicationRepository(db),
+		ProjectMember:     NewProjectMemberRepository(db),
+		OliveBranch:       NewOliveBranchRepository(db),
+		OliveBranchLedger: NewOliveBranchLedgerRepository(db),
+		School:            NewSchoolRepository(db),
+		Major:             NewMajorRepository(db),
+		TalentProfile:     NewTalentProfileRepository(db),
+		Order:             NewOrderRepository(db),
+		OrderRefund:       NewOrderRefundRepository(db),
+		Entitlement:       NewUserEntitlementRepository(db),
+		EmailPromotion:    NewEmailPromotionRepository(db),
+		EmailTask:         NewEmailTaskRepository(db),
+		EmailTemplate:     NewEmailTemplateRepository(db),
+		EmailProvider:     NewEmailProviderConfigRepository(db),
+		AdminUser:         NewAdminUserRepository(db),
+		Feedback:          NewFeedbackRepository(db),
+		Favorite:          NewFavoriteRepository(db),
+		MessageOutbox:     NewMessageOutboxRepository(db),
+		MsgTemplate:       NewMsgTemplateConfigRepository(db),
+		Notification:      NewNotificationRepository(db),
+		RealtimeEvent:     NewRealtimeEventRepository(db),
+		SearchDocument:    NewSearchDocumentRepository(db),
+		SkillTag:          NewSkillTagRepository(db),
+		SubscribeConfig:   NewSubscribeConfigRepository(db),
+		ContentAudit:      NewContentAuditRepository(db),
+		ImageAudit:        NewImageAuditRepository(db),
+	}
+}
+
+// intPlaceholders returns "?, ?, ..." for the ids together with the matching
+// args, for queries that use the list more than once (e.g. IN and FIELD).
+func intPlaceholders(ids []int) (string, []interface{}) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
 	}
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "), args
 }
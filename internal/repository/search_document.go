@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// SearchDocumentRepository handles full-text search documents backed by
+// MySQL FULLTEXT indexes with the ngram parser.
+type SearchDocumentRepository struct {
+	db *sqlx.DB
+}
+
+// NewSearchDocumentRepository creates a new SearchDocumentRepository
+func NewSearchDocumentRepository(db *sqlx.DB) *SearchDocumentRepository {
+	return &SearchDocumentRepository{db: db}
+}
+
+// SearchDocumentParams contains parameters for a full-text search
+type SearchDocumentParams struct {
+	DocType string
+	Keyword string // 匹配标题、内容和技能，标题命中权重更高
+	Skill   string // 按短语匹配技能
+	Limit   int
+}
+
+// Upsert creates or replaces the document of a project or talent profile
+func (r *SearchDocumentRepository) Upsert(ctx context.Context, d *models.SearchDocument) error {
+	query := `
+		INSERT INTO search_document (doc_type, ref_id, title, content, skills)
+		VALUES (:doc_type, :ref_id, :title, :content, :skills)
+		ON DUPLICATE KEY UPDATE
+			title = VALUES(title),
+			content = VALUES(content),
+			skills = VALUES(skills),
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, d); err != nil {
+		return fmt.Errorf("upsert search document: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the document of a project or talent profile
+func (r *SearchDocumentRepository) Delete(ctx context.Context, docType string, refID int) error {
+	query := `DELETE FROM search_document WHERE doc_type = ? AND ref_id = ?`
+
+	if _, err := r.db.ExecContext(ctx, query, docType, refID); err != nil {
+		return fmt.Errorf("delete search document: %w", err)
+	}
+	return nil
+}
+
+// Search returns matching documents ordered by relevance
+func (r *SearchDocumentRepository) Search(ctx context.Context, params SearchDocumentParams) ([]models.SearchHit, error) {
+	conditions := []string{"doc_type = ?"}
+	args := []interface{}{params.DocType}
+	score := "0"
+	scoreArgs := []interface{}{}
+
+	if params.Keyword != "" {
+		conditions = append(conditions, "MATCH(title, content, skills) AGAINST(? IN NATURAL LANGUAGE MODE)")
+		args = append(args, params.Keyword)
+		score = "MATCH(title) AGAINST(? IN NATURAL LANGUAGE MODE) * 2 + MATCH(title, content, skills) AGAINST(? IN NATURAL LANGUAGE MODE)"
+		scoreArgs = append(scoreArgs, params.Keyword, params.Keyword)
+	}
+	if params.Skill != "" {
+		// 布尔模式下双引号内为短语，ngram 解析器按相邻的词元匹配
+		phrase := `"` + strings.ReplaceAll(params.Skill, `"`, " ") + `"`
+		conditions = append(conditions, "MATCH(skills) AGAINST(? IN BOOLEAN MODE)")
+		args = append(args, phrase)
+		if params.Keyword == "" {
+			score = "MATCH(skills) AGAINST(? IN BOOLEAN MODE)"
+			scoreArgs = append(scoreArgs, phrase)
+		}
+	}
+
+	query := fmt.Sprintf(`
+		SELECT ref_id, %s AS score
+		FROM search_document
+		WHERE %s
+		ORDER BY score DESC, ref_id DESC
+		LIMIT ?
+	`, score, strings.Join(conditions, " AND "))
+	args = append(append(scoreArgs, args...), params.Limit)
+
+	var hits []models.SearchHit
+	if err := r.db.SelectContext(ctx, &hits, query, args...); err != nil {
+		return nil, fmt.Errorf("search documents: %w", err)
+	}
+	return hits, nil
+}
+
+// ListProjectSources returns the indexed fields of projects with ID greater
+// than afterID, for rebuilding their documents
+func (r *SearchDocumentRepository) ListProjectSources(ctx context.Context, afterID, limit int) ([]models.Project, error) {
+	query := `SELECT id, name, description, skill_requirement FROM project WHERE id > ? ORDER BY id LIMIT ?`
+
+	var projects []models.Project
+	if err := r.db.SelectContext(ctx, &projects, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("query project search sources: %w", err)
+	}
+	return projects, nil
+}
+
+// ListTalentSources returns the indexed fields of talent profiles with ID
+// greater than afterID, for rebuilding their documents
+func (r *SearchDocumentRepository) ListTalentSources(ctx context.Context, afterID, limit int) ([]models.TalentProfile, error) {
+	query := `
+		SELECT tp.id, tp.user_id, tp.self_evaluation, tp.skill_summary, tp.project_experience, u.nickname
+		FROM talent_profile tp
+		LEFT JOIN user u ON tp.user_id = u.id
+		WHERE tp.id > ?
+		ORDER BY tp.id
+		LIMIT ?
+	`
+
+	var profiles []models.TalentProfile
+	if err := r.db.SelectContext(ctx, &profiles, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("query talent search sources: %w", err)
+	}
+	return profiles, nil
+}
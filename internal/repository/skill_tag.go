@@ -0,0 +1,471 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// SkillTagRepository handles skill category, skill tag, synonym and link database operations
+type SkillTagRepository struct {
+	db *sqlx.DB
+}
+
+// NewSkillTagRepository creates a new SkillTagRepository
+func NewSkillTagRepository(db *sqlx.DB) *SkillTagRepository {
+	return &SkillTagRepository{db: db}
+}
+
+// SkillTagListParams contains parameters for listing skill tags
+type SkillTagListParams struct {
+	Page       int
+	Size       int
+	CategoryID *int
+	Status     *int
+	Keyword    *string // 匹配标签名称或同义词
+}
+
+// SkillTagSuggestParams contains parameters for skill tag autocomplete
+type SkillTagSuggestParams struct {
+	Keyword    string
+	CategoryID *int
+	Limit      int
+}
+
+// skillTagColumns 标签列及分类名、关联数，配合 skill_tag t LEFT JOIN skill_category c 使用
+const skillTagColumns = `
+	t.id, t.category_id, t.name, t.status, t.created_at, t.updated_at,
+	c.name AS category_name,
+	(SELECT COUNT(*) FROM project_skill_tag pst WHERE pst.tag_id = t.id) AS project_count,
+	(SELECT COUNT(*) FROM talent_skill_tag tst WHERE tst.tag_id = t.id) AS talent_count
+`
+
+// synonymMatch 标签的任一同义词匹配 LIKE 参数
+const synonymMatch = `EXISTS (SELECT 1 FROM skill_tag_synonym sy WHERE sy.tag_id = t.id AND sy.synonym LIKE ?)`
+
+// ListCategories returns all skill categories in display order
+func (r *SkillTagRepository) ListCategories(ctx context.Context) ([]models.SkillCategory, error) {
+	query := `
+		SELECT id, name, sort_order, created_at, updated_at
+		FROM skill_category
+		ORDER BY sort_order, id
+	`
+
+	var categories []models.SkillCategory
+	if err := r.db.SelectContext(ctx, &categories, query); err != nil {
+		return nil, fmt.Errorf("query skill categories: %w", err)
+	}
+	return categories, nil
+}
+
+// GetCategoryByID retrieves a skill category by ID
+func (r *SkillTagRepository) GetCategoryByID(ctx context.Context, id int) (*models.SkillCategory, error) {
+	query := `SELECT id, name, sort_order, created_at, updated_at FROM skill_category WHERE id = ?`
+
+	var c models.SkillCategory
+	if err := r.db.QueryRowxContext(ctx, query, id).StructScan(&c); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get skill category by id: %w", err)
+	}
+	return &c, nil
+}
+
+// GetCategoryByName retrieves a skill category by its unique name
+func (r *SkillTagRepository) GetCategoryByName(ctx context.Context, name string) (*models.SkillCategory, error) {
+	query := `SELECT id, name, sort_order, created_at, updated_at FROM skill_category WHERE name = ?`
+
+	var c models.SkillCategory
+	if err := r.db.QueryRowxContext(ctx, query, name).StructScan(&c); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get skill category by name: %w", err)
+	}
+	return &c, nil
+}
+
+// CreateCategory creates a new skill category
+func (r *SkillTagRepository) CreateCategory(ctx context.Context, c *models.SkillCategory) error {
+	result, err := r.db.NamedExecContext(ctx,
+		`INSERT INTO skill_category (name, sort_order) VALUES (:name, :sort_order)`, c)
+	if err != nil {
+		return fmt.Errorf("create skill category: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	c.ID = int(id)
+	return nil
+}
+
+// UpdateCategory updates a skill category
+func (r *SkillTagRepository) UpdateCategory(ctx context.Context, c *models.SkillCategory) error {
+	query := `UPDATE skill_category SET name = :name, sort_order = :sort_order WHERE id = :id`
+	if _, err := r.db.NamedExecContext(ctx, query, c); err != nil {
+		return fmt.Errorf("update skill category: %w", err)
+	}
+	return nil
+}
+
+// List retrieves paginated skill tags with their usage counts
+func (r *SkillTagRepository) List(ctx context.Context, params SkillTagListParams) ([]models.SkillTag, int64, error) {
+	conditions := []string{"1=1"}
+	args := []interface{}{}
+
+	if params.CategoryID != nil {
+		conditions = append(conditions, "t.category_id = ?")
+		args = append(args, *params.CategoryID)
+	}
+	if params.Status != nil {
+		conditions = append(conditions, "t.status = ?")
+		args = append(args, *params.Status)
+	}
+	if params.Keyword != nil && *params.Keyword != "" {
+		conditions = append(conditions, "(t.name LIKE ? OR "+synonymMatch+")")
+		like := "%" + *params.Keyword + "%"
+		args = append(args, like, like)
+	}
+
+	whereClause := strings.Join(conditions, " AND ")
+
+	// Count total
+	var total int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM skill_tag t WHERE %s`, whereClause)
+	if err := r.db.QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count skill tags: %w", err)
+	}
+
+	offset := (params.Page - 1) * params.Size
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM skill_tag t
+		LEFT JOIN skill_category c ON t.category_id = c.id
+		WHERE %s
+		ORDER BY t.category_id, t.id
+		LIMIT ? OFFSET ?
+	`, skillTagColumns, whereClause)
+	args = append(args, params.Size, offset)
+
+	var tags []models.SkillTag
+	if err := r.db.SelectContext(ctx, &tags, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("query skill tags: %w", err)
+	}
+
+	return tags, total, nil
+}
+
+// ListActive returns all active skill tags ordered by category
+func (r *SkillTagRepository) ListActive(ctx context.Context) ([]models.SkillTag, error) {
+	query := `
+		SELECT t.id, t.category_id, t.name, t.status, t.created_at, t.updated_at, c.name AS category_name
+		FROM skill_tag t
+		LEFT JOIN skill_category c ON t.category_id = c.id
+		WHERE t.status = ?
+		ORDER BY t.category_id, t.id
+	`
+
+	var tags []models.SkillTag
+	if err := r.db.SelectContext(ctx, &tags, query, models.SkillTagStatusActive); err != nil {
+		return nil, fmt.Errorf("query active skill tags: %w", err)
+	}
+	return tags, nil
+}
+
+// Suggest returns active tags whose name or a synonym contains the keyword.
+// Prefix matches come first, then the most used tags.
+func (r *SkillTagRepository) Suggest(ctx context.Context, params SkillTagSuggestParams) ([]models.SkillTag, error) {
+	conditions := []string{"t.status = ?"}
+	args := []interface{}{models.SkillTagStatusActive}
+	orderBy := "project_count + talent_count DESC, t.id"
+	var orderArgs []interface{}
+
+	if params.CategoryID != nil {
+		conditions = append(conditions, "t.category_id = ?")
+		args = append(args, *params.CategoryID)
+	}
+	if params.Keyword != "" {
+		conditions = append(conditions, "(t.name LIKE ? OR "+synonymMatch+")")
+		like := "%" + params.Keyword + "%"
+		args = append(args, like, like)
+
+		orderBy = "(t.name LIKE ? OR " + synonymMatch + ") DESC, " + orderBy
+		prefix := params.Keyword + "%"
+		orderArgs = append(orderArgs, prefix, prefix)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM skill_tag t
+		LEFT JOIN skill_category c ON t.category_id = c.id
+		WHERE %s
+		ORDER BY %s
+		LIMIT ?
+	`, skillTagColumns, strings.Join(conditions, " AND "), orderBy)
+	args = append(append(args, orderArgs...), params.Limit)
+
+	var tags []models.SkillTag
+	if err := r.db.SelectContext(ctx, &tags, query, args...); err != nil {
+		return nil, fmt.Errorf("suggest skill tags: %w", err)
+	}
+	return tags, nil
+}
+
+// GetByID retrieves a skill tag by ID
+func (r *SkillTagRepository) GetByID(ctx context.Context, id int) (*models.SkillTag, error) {
+	query := `
+		SELECT ` + skillTagColumns + `
+		FROM skill_tag t
+		LEFT JOIN skill_category c ON t.category_id = c.id
+		WHERE t.id = ?
+	`
+
+	var t models.SkillTag
+	if err := r.db.QueryRowxContext(ctx, query, id).StructScan(&t); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get skill tag by id: %w", err)
+	}
+	return &t, nil
+}
+
+// FindByTerm retrieves the tag whose name or one of whose synonyms equals term
+func (r *SkillTagRepository) FindByTerm(ctx context.Context, term string) (*models.SkillTag, error) {
+	query := `
+		SELECT ` + skillTagColumns + `
+		FROM skill_tag t
+		LEFT JOIN skill_category c ON t.category_id = c.id
+		WHERE t.name = ? OR EXISTS (SELECT 1 FROM skill_tag_synonym sy WHERE sy.tag_id = t.id AND sy.synonym = ?)
+		LIMIT 1
+	`
+
+	var t models.SkillTag
+	if err := r.db.QueryRowxContext(ctx, query, term, term).StructScan(&t); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find skill tag by term: %w", err)
+	}
+	return &t, nil
+}
+
+// CreateTx creates a new skill tag within a transaction
+func (r *SkillTagRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, t *models.SkillTag) error {
+	result, err := tx.NamedExecContext(ctx,
+		`INSERT INTO skill_tag (category_id, name, status) VALUES (:category_id, :name, :status)`, t)
+	if err != nil {
+		return fmt.Errorf("create skill tag: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+	t.ID = int(id)
+	return nil
+}
+
+// UpdateTx updates a skill tag within a transaction
+func (r *SkillTagRepository) UpdateTx(ctx context.Context, tx *sqlx.Tx, t *models.SkillTag) error {
+	query := `UPDATE skill_tag SET category_id = :category_id, name = :name, status = :status WHERE id = :id`
+	if _, err := tx.NamedExecContext(ctx, query, t); err != nil {
+		return fmt.Errorf("update skill tag: %w", err)
+	}
+	return nil
+}
+
+// ReplaceSynonymsTx replaces all synonyms of a tag within a transaction
+func (r *SkillTagRepository) ReplaceSynonymsTx(ctx context.Context, tx *sqlx.Tx, tagID int, synonyms []string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM skill_tag_synonym WHERE tag_id = ?`, tagID); err != nil {
+		return fmt.Errorf("delete skill tag synonyms: %w", err)
+	}
+	if len(synonyms) == 0 {
+		return nil
+	}
+
+	values := strings.TrimSuffix(strings.Repeat("(?, ?), ", len(synonyms)), ", ")
+	args := make([]interface{}, 0, len(synonyms)*2)
+	for _, s := range synonyms {
+		args = append(args, tagID, s)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO skill_tag_synonym (tag_id, synonym) VALUES `+values, args...); err != nil {
+		return fmt.Errorf("insert skill tag synonyms: %w", err)
+	}
+	return nil
+}
+
+// ListSynonyms returns the synonyms of the given tags, keyed by tag ID
+func (r *SkillTagRepository) ListSynonyms(ctx context.Context, tagIDs []int) (map[int][]string, error) {
+	result := make(map[int][]string)
+	if len(tagIDs) == 0 {
+		return result, nil
+	}
+
+	query, args, err := sqlx.In(`SELECT tag_id, synonym AS term FROM skill_tag_synonym WHERE tag_id IN (?) ORDER BY id`, tagIDs)
+	if err != nil {
+		return nil, fmt.Errorf("build list synonyms query: %w", err)
+	}
+
+	var rows []models.SkillTagTerm
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("query skill tag synonyms: %w", err)
+	}
+	for _, row := range rows {
+		result[row.TagID] = append(result[row.TagID], row.Term)
+	}
+	return result, nil
+}
+
+// ListTerms returns the names and synonyms of all active tags
+func (r *SkillTagRepository) ListTerms(ctx context.Context) ([]models.SkillTagTerm, error) {
+	query := `
+		SELECT t.id AS tag_id, t.name AS term FROM skill_tag t WHERE t.status = ?
+		UNION ALL
+		SELECT sy.tag_id, sy.synonym FROM skill_tag_synonym sy
+		JOIN skill_tag t ON sy.tag_id = t.id
+		WHERE t.status = ?
+	`
+
+	var terms []models.SkillTagTerm
+	if err := r.db.SelectContext(ctx, &terms, query, models.SkillTagStatusActive, models.SkillTagStatusActive); err != nil {
+		return nil, fmt.Errorf("query skill tag terms: %w", err)
+	}
+	return terms, nil
+}
+
+// MergeTx moves the links and synonyms of source to target, keeps the source
+// name as a synonym of target and deletes source, within a transaction
+func (r *SkillTagRepository) MergeTx(ctx context.Context, tx *sqlx.Tx, source *models.SkillTag, targetID int) error {
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT IGNORE INTO project_skill_tag (project_id, tag_id) SELECT project_id, ? FROM project_skill_tag WHERE tag_id = ?`, []interface{}{targetID, source.ID}},
+		{`DELETE FROM project_skill_tag WHERE tag_id = ?`, []interface{}{source.ID}},
+		{`INSERT IGNORE INTO talent_skill_tag (talent_profile_id, tag_id) SELECT talent_profile_id, ? FROM talent_skill_tag WHERE tag_id = ?`, []interface{}{targetID, source.ID}},
+		{`DELETE FROM talent_skill_tag WHERE tag_id = ?`, []interface{}{source.ID}},
+		{`UPDATE skill_tag_synonym SET tag_id = ? WHERE tag_id = ?`, []interface{}{targetID, source.ID}},
+		{`DELETE FROM skill_tag WHERE id = ?`, []interface{}{source.ID}},
+		{`INSERT IGNORE INTO skill_tag_synonym (tag_id, synonym) VALUES (?, ?)`, []interface{}{targetID, source.Name}},
+	}
+	for _, st := range statements {
+		if _, err := tx.ExecContext(ctx, st.query, st.args...); err != nil {
+			return fmt.Errorf("merge skill tag %d into %d: %w", source.ID, targetID, err)
+		}
+	}
+	return nil
+}
+
+// SetProjectTags replaces the skill tags linked to a project
+func (r *SkillTagRepository) SetProjectTags(ctx context.Context, projectID int, tagIDs []int) error {
+	return r.setLinks(ctx, "project_skill_tag", "project_id", projectID, tagIDs)
+}
+
+// SetTalentTags replaces the skill tags linked to a talent profile
+func (r *SkillTagRepository) SetTalentTags(ctx context.Context, talentProfileID int, tagIDs []int) error {
+	return r.setLinks(ctx, "talent_skill_tag", "talent_profile_id", talentProfileID, tagIDs)
+}
+
+// setLinks 在事务中删除旧关联后写入新关联
+func (r *SkillTagRepository) setLinks(ctx context.Context, table, refColumn string, refID int, tagIDs []int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE %s = ?`, table, refColumn), refID); err != nil {
+		return fmt.Errorf("delete %s: %w", table, err)
+	}
+	if len(tagIDs) > 0 {
+		values := strings.TrimSuffix(strings.Repeat("(?, ?), ", len(tagIDs)), ", ")
+		args := make([]interface{}, 0, len(tagIDs)*2)
+		for _, id := range tagIDs {
+			args = append(args, refID, id)
+		}
+		query := fmt.Sprintf(`INSERT IGNORE INTO %s (%s, tag_id) VALUES %s`, table, refColumn, values)
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("insert %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// skillTagLinkRow holds a linked tag with the ID of the project or profile it belongs to.
+type skillTagLinkRow struct {
+	RefID int `db:"ref_id"`
+	models.SkillTag
+}
+
+// ListByProjects returns the active tags linked to each project
+func (r *SkillTagRepository) ListByProjects(ctx context.Context, projectIDs []int) (map[int][]models.SkillTag, error) {
+	return r.listLinks(ctx, "project_skill_tag", "project_id", projectIDs)
+}
+
+// ListByTalents returns the active tags linked to each talent profile
+func (r *SkillTagRepository) ListByTalents(ctx context.Context, talentProfileIDs []int) (map[int][]models.SkillTag, error) {
+	return r.listLinks(ctx, "talent_skill_tag", "talent_profile_id", talentProfileIDs)
+}
+
+func (r *SkillTagRepository) listLinks(ctx context.Context, table, refColumn string, refIDs []int) (map[int][]models.SkillTag, error) {
+	result := make(map[int][]models.SkillTag)
+	if len(refIDs) == 0 {
+		return result, nil
+	}
+
+	query, args, err := sqlx.In(fmt.Sprintf(`
+		SELECT l.%s AS ref_id,
+			t.id, t.category_id, t.name, t.status, t.created_at, t.updated_at, c.name AS category_name
+		FROM %s l
+		JOIN skill_tag t ON l.tag_id = t.id
+		LEFT JOIN skill_category c ON t.category_id = c.id
+		WHERE l.%s IN (?) AND t.status = ?
+		ORDER BY l.%s, t.category_id, t.id
+	`, refColumn, table, refColumn, refColumn), refIDs, models.SkillTagStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("build list %s query: %w", table, err)
+	}
+
+	var rows []skillTagLinkRow
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("query %s: %w", table, err)
+	}
+	for _, row := range rows {
+		result[row.RefID] = append(result[row.RefID], row.SkillTag)
+	}
+	return result, nil
+}
+
+// ListProjectTexts returns the skill requirement of projects with ID greater than afterID
+func (r *SkillTagRepository) ListProjectTexts(ctx context.Context, afterID, limit int) ([]models.SkillText, error) {
+	query := `SELECT id AS ref_id, skill_requirement AS text FROM project WHERE id > ? ORDER BY id LIMIT ?`
+
+	var texts []models.SkillText
+	if err := r.db.SelectContext(ctx, &texts, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("query project skill texts: %w", err)
+	}
+	return texts, nil
+}
+
+// ListTalentTexts returns the skill summary of talent profiles with ID greater than afterID
+func (r *SkillTagRepository) ListTalentTexts(ctx context.Context, afterID, limit int) ([]models.SkillText, error) {
+	query := `SELECT id AS ref_id, skill_summary AS text FROM talent_profile WHERE id > ? ORDER BY id LIMIT ?`
+
+	var texts []models.SkillText
+	if err := r.db.SelectContext(ctx, &texts, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("query talent skill texts: %w", err)
+	}
+	return texts, nil
+}
@@ -90,33 +90,78 @@ func (r *SubscribeConfigRepository) UpdateStatus(ctx context.Context, userID int
 	return nil
 }
 
-// DecrementCount decrements the subscribe_count by 1
-func (r *SubscribeConfigRepository) DecrementCount(ctx context.Context, userID int, bizKey string) error {
+// UpsertStatus records the latest authorization result of a subscription while
+// keeping its remaining count, creating the subscribe config if it does not exist yet
+func (r *SubscribeConfigRepository) UpsertStatus(ctx context.Context, userID int, bizKey string, status models.SubscribeStatus) error {
+	query := `
+		INSERT INTO subscribe (user_id, biz_key, subscribe_count, status)
+		VALUES (?, ?, 0, ?)
+		ON DUPLICATE KEY UPDATE
+			status = VALUES(status),
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, bizKey, status)
+	if err != nil {
+		return fmt.Errorf("upsert subscribe config status: %w", err)
+	}
+
+	return nil
+}
+
+// DecrementCount takes one one-time authorization. It returns false when none
+// is left, so concurrent senders can never use the same authorization twice.
+func (r *SubscribeConfigRepository) DecrementCount(ctx context.Context, userID int, bizKey string) (bool, error) {
 	query := `
 		UPDATE subscribe
-		SET subscribe_count = GREATEST(0, subscribe_count - 1),
+		SET subscribe_count = subscribe_count - 1,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE user_id = ? AND biz_key = ? AND subscribe_count > 0
 	`
 
-	_, err := r.db.ExecContext(ctx, query, userID, bizKey)
+	result, err := r.db.ExecContext(ctx, query, userID, bizKey)
 	if err != nil {
-		return fmt.Errorf("decrement subscribe count: %w", err)
+		return false, fmt.Errorf("decrement subscribe count: %w", err)
 	}
 
-	return nil
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
 }
 
-// IncrementCount increments the subscribe_count by specified amount
-func (r *SubscribeConfigRepository) IncrementCount(ctx context.Context, userID int, bizKey string, count int) error {
+// RestoreCount gives back an authorization taken by DecrementCount whose
+// message was not sent
+func (r *SubscribeConfigRepository) RestoreCount(ctx context.Context, userID int, bizKey string) error {
 	query := `
 		UPDATE subscribe
-		SET subscribe_count = subscribe_count + ?,
+		SET subscribe_count = subscribe_count + 1,
 		    updated_at = CURRENT_TIMESTAMP
 		WHERE user_id = ? AND biz_key = ?
 	`
 
-	_, err := r.db.ExecContext(ctx, query, count, userID, bizKey)
+	if _, err := r.db.ExecContext(ctx, query, userID, bizKey); err != nil {
+		return fmt.Errorf("restore subscribe count: %w", err)
+	}
+
+	return nil
+}
+
+// IncrementCount adds count one-time authorizations and marks the subscription
+// accepted, creating the subscribe config if it does not exist yet
+func (r *SubscribeConfigRepository) IncrementCount(ctx context.Context, userID int, bizKey string, count int) error {
+	query := `
+		INSERT INTO subscribe (user_id, biz_key, subscribe_count, status)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			subscribe_count = subscribe_count + VALUES(subscribe_count),
+			status = VALUES(status),
+			updated_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, bizKey, count, models.SubscribeStatusAccept)
 	if err != nil {
 		return fmt.Errorf("increment subscribe count: %w", err)
 	}
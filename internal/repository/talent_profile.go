@@ -23,12 +23,17 @@ func NewTalentProfileRepository(db *sqlx.DB) *TalentProfileRepository {
 
 // TalentProfileListParams contains parameters for listing talent profiles
 type TalentProfileListParams struct {
-	Page     int
-	Size     int
-	SchoolID *int
-	MajorID  *int
-	Keyword  *string
-	Status   *int
+	Page       int
+	Size       int
+	SchoolID   *int
+	MajorID    *int
+	Keyword    *string
+	Skill      *string
+	SkillTagID *int
+	Status     *int
+	IDs        []int // 搜索命中的档案ID，非 nil 时只在其中筛选并按该顺序返回
+
+	FavoriteUserID *int // 只返回该用户收藏的档案，按收藏时间倒序
 }
 
 // enrichSchoolMajor 为单条 TalentProfile 分别查 school/major 并回填名称
@@ -138,9 +143,26 @@ func (r *TalentProfileRepository) enrichSchoolMajorBatch(ctx context.Context, pr
 
 // List retrieves paginated talent profiles with optional filters
 func (r *TalentProfileRepository) List(ctx context.Context, params TalentProfileListParams) ([]models.TalentProfile, int64, error) {
+	if params.IDs != nil && len(params.IDs) == 0 {
+		return []models.TalentProfile{}, 0, nil
+	}
+
 	// Build WHERE clause - only show active profiles
 	conditions := []string{"tp.status = 1"}
 	args := []interface{}{}
+	joins := ""
+
+	if params.FavoriteUserID != nil {
+		joins = "JOIN favorite fav ON fav.target_id = tp.id"
+		conditions = append(conditions, "fav.target_type = ?", "fav.user_id = ?")
+		args = append(args, models.FavoriteTargetTalent, *params.FavoriteUserID)
+	}
+
+	if params.IDs != nil {
+		placeholders, idArgs := intPlaceholders(params.IDs)
+		conditions = append(conditions, "tp.id IN ("+placeholders+")")
+		args = append(args, idArgs...)
+	}
 
 	if params.SchoolID != nil {
 		conditions = append(conditions, "u.school_id = ?")
@@ -158,6 +180,15 @@ func (r *TalentProfileRepository) List(ctx context.Context, params TalentProfile
 		args = append(args, pattern, pattern, pattern)
 	}
 
+	if params.Skill != nil && *params.Skill != "" {
+		conditions = append(conditions, "tp.skill_summary LIKE ?")
+		args = append(args, "%"+*params.Skill+"%")
+	}
+	if params.SkillTagID != nil {
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM talent_skill_tag tst WHERE tst.talent_profile_id = tp.id AND tst.tag_id = ?)")
+		args = append(args, *params.SkillTagID)
+	}
+
 	if params.Status != nil {
 		conditions = append(conditions, "tp.status = ?")
 		args = append(args, *params.Status)
@@ -170,29 +201,42 @@ func (r *TalentProfileRepository) List(ctx context.Context, params TalentProfile
 		SELECT COUNT(*) 
 		FROM talent_profile tp
 		LEFT JOIN `+"`user`"+` u ON tp.user_id = u.id
+		%s
 		WHERE %s
-	`, whereClause)
+	`, joins, whereClause)
 	var total int64
 	if err := r.db.QueryRowxContext(ctx, countQuery, args...).Scan(&total); err != nil {
 		return nil, 0, fmt.Errorf("count talent profiles: %w", err)
 	}
 
+	// 搜索结果按命中顺序（相关度）排列，收藏列表按收藏时间倒序，否则按更新时间倒序
+	orderBy := "tp.updated_at DESC"
+	var orderArgs []interface{}
+	if params.IDs != nil {
+		var placeholders string
+		placeholders, orderArgs = intPlaceholders(params.IDs)
+		orderBy = "FIELD(tp.id, " + placeholders + ")"
+	} else if params.FavoriteUserID != nil {
+		orderBy = "fav.created_at DESC, fav.id DESC"
+	}
+
 	// Main query: talent_profile + user (2 tables), fetch school_id/major_id for follow-up
 	offset := (params.Page - 1) * params.Size
 	query := fmt.Sprintf(`
 		SELECT 
 			tp.id, tp.user_id, tp.self_evaluation, tp.skill_summary,
-			tp.project_experience, tp.mbti, tp.status,
+			tp.project_experience, tp.mbti, tp.education, tp.status,
 			tp.created_at, tp.updated_at,
 			u.nickname, u.phone, u.email, u.avatar_url,
 			u.school_id, u.major_id
 		FROM talent_profile tp
 		LEFT JOIN `+"`user`"+` u ON tp.user_id = u.id
+		%s
 		WHERE %s
-		ORDER BY tp.updated_at DESC
+		ORDER BY %s
 		LIMIT ? OFFSET ?
-	`, whereClause)
-	args = append(args, params.Size, offset)
+	`, joins, whereClause, orderBy)
+	args = append(append(args, orderArgs...), params.Size, offset)
 
 	var profiles []models.TalentProfile
 	if err := r.db.SelectContext(ctx, &profiles, query, args...); err != nil {
@@ -209,13 +253,59 @@ func (r *TalentProfileRepository) List(ctx context.Context, params TalentProfile
 	return profiles, total, nil
 }
 
+// TalentCandidateParams contains parameters for listing talents to recommend for a project
+type TalentCandidateParams struct {
+	ProjectID int
+	SchoolID  *int // 非 nil 时只取该校人才（项目不可跨校）
+	Limit     int
+}
+
+// ListRecommendCandidates returns published talent profiles of users who are
+// not active members (leader included) of the project, most recently updated first
+func (r *TalentProfileRepository) ListRecommendCandidates(ctx context.Context, params TalentCandidateParams) ([]models.TalentProfile, error) {
+	conditions := []string{
+		"tp.status = ?",
+		"NOT EXISTS (SELECT 1 FROM project_member pm WHERE pm.project_id = ? AND pm.user_id = tp.user_id AND pm.status = ?)",
+	}
+	args := []interface{}{models.TalentStatusOnline, params.ProjectID, models.ProjectMemberStatusActive}
+	if params.SchoolID != nil {
+		conditions = append(conditions, "u.school_id = ?")
+		args = append(args, *params.SchoolID)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			tp.id, tp.user_id, tp.self_evaluation, tp.skill_summary,
+			tp.project_experience, tp.mbti, tp.education, tp.status,
+			tp.created_at, tp.updated_at,
+			u.nickname, u.avatar_url,
+			u.school_id, u.major_id
+		FROM talent_profile tp
+		JOIN `+"`user`"+` u ON tp.user_id = u.id
+		WHERE %s
+		ORDER BY tp.updated_at DESC
+		LIMIT ?
+	`, strings.Join(conditions, " AND "))
+	args = append(args, params.Limit)
+
+	var profiles []models.TalentProfile
+	if err := r.db.SelectContext(ctx, &profiles, query, args...); err != nil {
+		return nil, fmt.Errorf("list recommend candidate talents: %w", err)
+	}
+
+	if err := r.enrichSchoolMajorBatch(ctx, profiles); err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
 // GetByID retrieves a talent profile by ID with user info
 func (r *TalentProfileRepository) GetByID(ctx context.Context, id int) (*models.TalentProfile, error) {
 	// talent_profile + user (2 tables)
 	query := `
 		SELECT 
 			tp.id, tp.user_id, tp.self_evaluation, tp.skill_summary,
-			tp.project_experience, tp.mbti, tp.status,
+			tp.project_experience, tp.mbti, tp.education, tp.status,
 			tp.created_at, tp.updated_at,
 			u.nickname, u.phone, u.email, u.avatar_url,
 			u.school_id, u.major_id
@@ -246,7 +336,7 @@ func (r *TalentProfileRepository) GetByUserID(ctx context.Context, userID int) (
 	query := `
 		SELECT 
 			tp.id, tp.user_id, tp.self_evaluation, tp.skill_summary,
-			tp.project_experience, tp.mbti, tp.status,
+			tp.project_experience, tp.mbti, tp.education, tp.status,
 			tp.created_at, tp.updated_at,
 			u.nickname, u.phone, u.email,
 			u.school_id, u.major_id
@@ -273,6 +363,32 @@ func (r *TalentProfileRepository) GetByUserID(ctx context.Context, userID int) (
 	return &p, nil
 }
 
+// GetByUserIDTx retrieves the talent profile of a user within a transaction,
+// locking it until the transaction ends, or nil. School and major names are
+// not filled in.
+func (r *TalentProfileRepository) GetByUserIDTx(ctx context.Context, tx *sqlx.Tx, userID int) (*models.TalentProfile, error) {
+	query := `
+		SELECT
+			tp.id, tp.user_id, tp.self_evaluation, tp.skill_summary,
+			tp.project_experience, tp.mbti, tp.education, tp.status,
+			tp.created_at, tp.updated_at,
+			u.nickname
+		FROM talent_profile tp
+		LEFT JOIN ` + "`user`" + ` u ON tp.user_id = u.id
+		WHERE tp.user_id = ?
+		FOR UPDATE OF tp
+	`
+
+	var p models.TalentProfile
+	if err := tx.QueryRowxContext(ctx, query, userID).StructScan(&p); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query talent profile by user id: %w", err)
+	}
+	return &p, nil
+}
+
 // Upsert creates or updates a talent profile for a user
 func (r *TalentProfileRepository) Upsert(ctx context.Context, p *models.TalentProfile) error {
 	// Check if profile exists
@@ -286,10 +402,10 @@ func (r *TalentProfileRepository) Upsert(ctx context.Context, p *models.TalentPr
 		query := `
 			INSERT INTO talent_profile (
 				user_id, self_evaluation, skill_summary, project_experience,
-				mbti, status
+				mbti, education, status
 			) VALUES (
 				:user_id, :self_evaluation, :skill_summary, :project_experience,
-				:mbti, :status
+				:mbti, :education, :status
 			)
 		`
 		result, err := r.db.NamedExecContext(ctx, query, p)
@@ -306,6 +422,7 @@ func (r *TalentProfileRepository) Upsert(ctx context.Context, p *models.TalentPr
 				skill_summary = :skill_summary,
 				project_experience = :project_experience,
 				mbti = :mbti,
+				education = :education,
 				status = :status,
 				updated_at = CURRENT_TIMESTAMP
 			WHERE user_id = :user_id
@@ -320,6 +437,51 @@ func (r *TalentProfileRepository) Upsert(ctx context.Context, p *models.TalentPr
 	return nil
 }
 
+// UpsertTx creates or updates a talent profile for a user within a transaction
+func (r *TalentProfileRepository) UpsertTx(ctx context.Context, tx *sqlx.Tx, p *models.TalentProfile) error {
+	var existingID int
+	err := tx.GetContext(ctx, &existingID, `SELECT id FROM talent_profile WHERE user_id = ? FOR UPDATE`, p.UserID)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("query talent profile id: %w", err)
+	}
+
+	if err == sql.ErrNoRows {
+		query := `
+			INSERT INTO talent_profile (
+				user_id, self_evaluation, skill_summary, project_experience,
+				mbti, education, status
+			) VALUES (
+				:user_id, :self_evaluation, :skill_summary, :project_experience,
+				:mbti, :education, :status
+			)
+		`
+		result, err := tx.NamedExecContext(ctx, query, p)
+		if err != nil {
+			return fmt.Errorf("insert talent profile: %w", err)
+		}
+		id, _ := result.LastInsertId()
+		p.ID = int(id)
+	} else {
+		query := `
+			UPDATE talent_profile SET
+				self_evaluation = :self_evaluation,
+				skill_summary = :skill_summary,
+				project_experience = :project_experience,
+				mbti = :mbti,
+				education = :education,
+				status = :status,
+				updated_at = CURRENT_TIMESTAMP
+			WHERE user_id = :user_id
+		`
+		if _, err := tx.NamedExecContext(ctx, query, p); err != nil {
+			return fmt.Errorf("update talent profile: %w", err)
+		}
+		p.ID = existingID
+	}
+
+	return nil
+}
+
 // DeleteByUserID deletes a talent profile by user ID
 func (r *TalentProfileRepository) DeleteByUserID(ctx context.Context, userID int) error {
 	query := `
@@ -331,3 +493,14 @@ func (r *TalentProfileRepository) DeleteByUserID(ctx context.Context, userID int
 	}
 	return nil
 }
+
+// DeleteByUserIDTx deletes a talent profile by user ID within a transaction
+func (r *TalentProfileRepository) DeleteByUserIDTx(ctx context.Context, tx *sqlx.Tx, userID int) error {
+	query := `
+		UPDATE talent_profile SET status = 0 WHERE user_id = ?
+	`
+	if _, err := tx.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("delete talent profile by user id: %w", err)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/trv3wood/kuaizu-server/internal/models"
@@ -173,6 +174,28 @@ func (r *UserRepository) UpdateQuota(ctx context.Context, user *models.User) err
 	return nil
 }
 
+// UseFreeBranchTx atomically consumes one of today's free olive branches within
+// a transaction. The daily counter restarts when last_active_date is before today.
+// It returns false without changing anything when today's quota is used up.
+func (r *UserRepository) UseFreeBranchTx(ctx context.Context, tx *sqlx.Tx, userID int, dailyLimit int, today time.Time) (bool, error) {
+	query := `
+		UPDATE ` + "`user`" + ` SET
+			free_branch_used_today = IF(last_active_date = ?, IFNULL(free_branch_used_today, 0), 0) + 1,
+			last_active_date = ?
+		WHERE id = ?
+		  AND (last_active_date IS NULL OR last_active_date < ? OR IFNULL(free_branch_used_today, 0) < ?)
+	`
+
+	day := today.Format("2006-01-02")
+	result, err := tx.ExecContext(ctx, query, day, day, userID, day, dailyLimit)
+	if err != nil {
+		return false, fmt.Errorf("use free olive branch: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
 // AddOliveBranchCount atomically adds count to user's olive_branch_count
 func (r *UserRepository) AddOliveBranchCount(ctx context.Context, userID int, count int) error {
 	query := `
@@ -205,6 +228,25 @@ func (r *UserRepository) AddOliveBranchCountTx(ctx context.Context, tx *sqlx.Tx,
 	return nil
 }
 
+// DeductOliveBranchCountTx atomically deducts count from user's olive_branch_count
+// within a transaction. It returns false without changing anything when the
+// balance is insufficient.
+func (r *UserRepository) DeductOliveBranchCountTx(ctx context.Context, tx *sqlx.Tx, userID int, count int) (bool, error) {
+	query := `
+		UPDATE ` + "`user`" + ` SET
+			olive_branch_count = olive_branch_count - ?
+		WHERE id = ? AND olive_branch_count >= ?
+	`
+
+	result, err := tx.ExecContext(ctx, query, count, userID, count)
+	if err != nil {
+		return false, fmt.Errorf("deduct olive branch count: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
 // UpdateAuthStatus updates user's certification auth status
 func (r *UserRepository) UpdateAuthStatus(ctx context.Context, userID int, authStatus int) error {
 	query := `UPDATE ` + "`user`" + ` SET auth_status = ? WHERE id = ?`
@@ -222,6 +264,23 @@ func (r *UserRepository) UpdateAuthStatus(ctx context.Context, userID int, authS
 	return nil
 }
 
+// UpdateAuthStatusTx updates user's authentication status within a transaction
+func (r *UserRepository) UpdateAuthStatusTx(ctx context.Context, tx *sqlx.Tx, userID int, authStatus int) error {
+	query := `UPDATE ` + "`user`" + ` SET auth_status = ? WHERE id = ?`
+
+	result, err := tx.ExecContext(ctx, query, authStatus, userID)
+	if err != nil {
+		return fmt.Errorf("update auth status: %w", err)
+	}
+
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
 // UserListParams contains parameters for listing users
 type UserListParams struct {
 	Page            int
@@ -356,22 +415,22 @@ func (r *UserRepository) UpdateAuthImgUrl(ctx context.Context, userID int, authI
 	return nil
 }
 
-// UpdateAvatarUrl updates user's avatar URL
-func (r *UserRepository) UpdateAvatarUrl(ctx context.Context, userID int, avatarUrl string) error {
+// UpdateAvatarUrlTx updates user's avatar URL within a transaction
+func (r *UserRepository) UpdateAvatarUrlTx(ctx context.Context, tx *sqlx.Tx, userID int, avatarUrl string) error {
 	query := `UPDATE ` + "`user`" + ` SET avatar_url = ? WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, avatarUrl, userID)
+	_, err := tx.ExecContext(ctx, query, avatarUrl, userID)
 	if err != nil {
 		return fmt.Errorf("update user avatar url: %w", err)
 	}
 	return nil
 }
 
-// UpdateCoverImage updates user's cover image URL
-func (r *UserRepository) UpdateCoverImage(ctx context.Context, userID int, coverImage string) error {
+// UpdateCoverImageTx updates user's cover image URL within a transaction
+func (r *UserRepository) UpdateCoverImageTx(ctx context.Context, tx *sqlx.Tx, userID int, coverImage string) error {
 	query := `UPDATE ` + "`user`" + ` SET cover_image = ? WHERE id = ?`
 
-	_, err := r.db.ExecContext(ctx, query, coverImage, userID)
+	_, err := tx.ExecContext(ctx, query, coverImage, userID)
 	if err != nil {
 		return fmt.Errorf("update user cover image: %w", err)
 	}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// UserEntitlementRepository handles user entitlement ledger operations
+type UserEntitlementRepository struct {
+	db *sqlx.DB
+}
+
+// NewUserEntitlementRepository creates a new UserEntitlementRepository
+func NewUserEntitlementRepository(db *sqlx.DB) *UserEntitlementRepository {
+	return &UserEntitlementRepository{db: db}
+}
+
+// CreateTx records a granted entitlement within a transaction
+func (r *UserEntitlementRepository) CreateTx(ctx context.Context, tx *sqlx.Tx, entitlement *models.UserEntitlement) error {
+	query := `
+		INSERT INTO user_entitlement (user_id, order_id, kind, total, used, status)
+		VALUES (:user_id, :order_id, :kind, :total, :used, :status)
+	`
+
+	result, err := tx.NamedExecContext(ctx, query, entitlement)
+	if err != nil {
+		return fmt.Errorf("create user entitlement: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("get last insert id: %w", err)
+	}
+
+	entitlement.ID = int(id)
+	return nil
+}
+
+// GetByOrderID retrieves the entitlement granted by an order
+func (r *UserEntitlementRepository) GetByOrderID(ctx context.Context, orderID int) (*models.UserEntitlement, error) {
+	query := `
+		SELECT id, user_id, order_id, kind, total, used, status, created_at, updated_at
+		FROM user_entitlement
+		WHERE order_id = ?
+	`
+
+	var entitlement models.UserEntitlement
+	if err := r.db.QueryRowxContext(ctx, query, orderID).StructScan(&entitlement); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get user entitlement by order id: %w", err)
+	}
+
+	return &entitlement, nil
+}
+
+// GetByOrderIDTx retrieves the entitlement granted by an order within a
+// transaction, locking it until the transaction ends, or nil
+func (r *UserEntitlementRepository) GetByOrderIDTx(ctx context.Context, tx *sqlx.Tx, orderID int) (*models.UserEntitlement, error) {
+	query := `
+		SELECT id, user_id, order_id, kind, total, used, status, created_at, updated_at
+		FROM user_entitlement
+		WHERE order_id = ?
+		FOR UPDATE
+	`
+
+	var entitlement models.UserEntitlement
+	if err := tx.QueryRowxContext(ctx, query, orderID).StructScan(&entitlement); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get user entitlement by order id: %w", err)
+	}
+
+	return &entitlement, nil
+}
+
+// ConsumeTx uses amount from an active entitlement within a transaction.
+// Returns false if the entitlement is revoked or has insufficient balance.
+func (r *UserEntitlementRepository) ConsumeTx(ctx context.Context, tx *sqlx.Tx, id int, amount int) (bool, error) {
+	query := `
+		UPDATE user_entitlement
+		SET used = used + ?
+		WHERE id = ? AND status = ? AND total - used >= ?
+	`
+
+	result, err := tx.ExecContext(ctx, query, amount, id, models.EntitlementStatusActive, amount)
+	if err != nil {
+		return false, fmt.Errorf("consume user entitlement: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RevokeTx revokes the unused entitlement of an order within a transaction.
+// Returns false if the entitlement does not exist, is already revoked or has been used.
+func (r *UserEntitlementRepository) RevokeTx(ctx context.Context, tx *sqlx.Tx, orderID int) (bool, error) {
+	query := `
+		UPDATE user_entitlement
+		SET status = ?
+		WHERE order_id = ? AND status = ? AND used = 0
+	`
+
+	result, err := tx.ExecContext(ctx, query, models.EntitlementStatusRevoked, orderID, models.EntitlementStatusActive)
+	if err != nil {
+		return false, fmt.Errorf("revoke user entitlement: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// RestoreTx reactivates a revoked entitlement within a transaction
+func (r *UserEntitlementRepository) RestoreTx(ctx context.Context, tx *sqlx.Tx, orderID int) error {
+	query := `
+		UPDATE user_entitlement
+		SET status = ?
+		WHERE order_id = ? AND status = ?
+	`
+
+	if _, err := tx.ExecContext(ctx, query, models.EntitlementStatusActive, orderID, models.EntitlementStatusRevoked); err != nil {
+		return fmt.Errorf("restore user entitlement: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// DBIndex 基于 search_document 表的索引
+// 表上建有 WITH PARSER ngram 的 FULLTEXT 索引，中文按相邻字符切分，检索结果由 MySQL 计算相关度。
+type DBIndex struct {
+	repo repository.SearchDocumentRepo
+}
+
+// NewDBIndex creates a DBIndex.
+func NewDBIndex(repo repository.SearchDocumentRepo) *DBIndex {
+	return &DBIndex{repo: repo}
+}
+
+// Upsert 写入或替换文档
+func (x *DBIndex) Upsert(ctx context.Context, doc Document) error {
+	return x.repo.Upsert(ctx, &models.SearchDocument{
+		DocType: doc.Type,
+		RefID:   doc.RefID,
+		Title:   doc.Title,
+		Content: doc.Content,
+		Skills:  strings.Join(doc.Skills, " "),
+	})
+}
+
+// Delete 删除文档
+func (x *DBIndex) Delete(ctx context.Context, docType string, refID int) error {
+	return x.repo.Delete(ctx, docType, refID)
+}
+
+// Search 按相关度检索
+func (x *DBIndex) Search(ctx context.Context, q Query) ([]Hit, error) {
+	rows, err := x.repo.Search(ctx, repository.SearchDocumentParams{
+		DocType: q.Type,
+		Keyword: strings.TrimSpace(q.Keyword),
+		Skill:   strings.TrimSpace(q.Skill),
+		Limit:   q.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]Hit, len(rows))
+	for i, r := range rows {
+		hits[i] = Hit{RefID: r.RefID, Score: r.Score}
+	}
+	return hits, nil
+}
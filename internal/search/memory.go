@@ -0,0 +1,143 @@
+package search
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// titleWeight 标题中词元的权重，与 DBIndex 的打分保持一致
+const titleWeight = 2
+
+// memoryDoc 已索引的文档
+type memoryDoc struct {
+	terms  map[string]float64 // 词元 -> 加权词频
+	length int                // 词元总数，用于长度归一化
+	skills string             // 小写后的技能文本，用于技能筛选
+}
+
+// MemoryIndex 进程内倒排索引，使用与 MySQL ngram 相同的分词方式，按 TF-IDF 打分
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]map[int]*memoryDoc          // 文档类型 -> 文档ID -> 文档
+	postings map[string]map[string]map[int]struct{} // 文档类型 -> 词元 -> 文档ID
+}
+
+// NewMemoryIndex creates an empty MemoryIndex.
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[string]map[int]*memoryDoc),
+		postings: make(map[string]map[string]map[int]struct{}),
+	}
+}
+
+// Upsert 写入或替换文档
+func (x *MemoryIndex) Upsert(_ context.Context, doc Document) error {
+	skills := strings.Join(doc.Skills, " ")
+	d := &memoryDoc{terms: make(map[string]float64), skills: strings.ToLower(skills)}
+	for _, t := range Tokenize(doc.Title) {
+		d.terms[t] += titleWeight
+		d.length++
+	}
+	for _, text := range []string{doc.Content, skills} {
+		for _, t := range Tokenize(text) {
+			d.terms[t]++
+			d.length++
+		}
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(doc.Type, doc.RefID)
+
+	if x.docs[doc.Type] == nil {
+		x.docs[doc.Type] = make(map[int]*memoryDoc)
+		x.postings[doc.Type] = make(map[string]map[int]struct{})
+	}
+	x.docs[doc.Type][doc.RefID] = d
+	postings := x.postings[doc.Type]
+	for t := range d.terms {
+		if postings[t] == nil {
+			postings[t] = make(map[int]struct{})
+		}
+		postings[t][doc.RefID] = struct{}{}
+	}
+	return nil
+}
+
+// Delete 删除文档
+func (x *MemoryIndex) Delete(_ context.Context, docType string, refID int) error {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.remove(docType, refID)
+	return nil
+}
+
+// remove 从倒排表中移除文档，调用方需持有写锁
+func (x *MemoryIndex) remove(docType string, refID int) {
+	d, ok := x.docs[docType][refID]
+	if !ok {
+		return
+	}
+	postings := x.postings[docType]
+	for t := range d.terms {
+		delete(postings[t], refID)
+		if len(postings[t]) == 0 {
+			delete(postings, t)
+		}
+	}
+	delete(x.docs[docType], refID)
+}
+
+// Search 关键词中任一词元命中即返回，按 TF-IDF 打分；指定技能时只保留技能包含该词的文档
+func (x *MemoryIndex) Search(_ context.Context, q Query) ([]Hit, error) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	docs := x.docs[q.Type]
+	skill := strings.ToLower(strings.TrimSpace(q.Skill))
+	scores := make(map[int]float64)
+
+	if terms := Tokenize(q.Keyword); len(terms) > 0 {
+		seen := make(map[string]bool, len(terms))
+		for _, t := range terms {
+			if seen[t] {
+				continue
+			}
+			seen[t] = true
+			ids := x.postings[q.Type][t]
+			if len(ids) == 0 {
+				continue
+			}
+			idf := math.Log(1 + float64(len(docs))/float64(len(ids)))
+			for id := range ids {
+				d := docs[id]
+				scores[id] += idf * d.terms[t] / math.Sqrt(float64(d.length))
+			}
+		}
+	} else if skill != "" {
+		for id := range docs {
+			scores[id] = 0
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		if skill != "" && !strings.Contains(docs[id].skills, skill) {
+			continue
+		}
+		hits = append(hits, Hit{RefID: id, Score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].RefID > hits[j].RefID
+	})
+	if q.Limit > 0 && len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits, nil
+}
@@ -0,0 +1,62 @@
+// Package search 项目与人才的全文搜索
+//
+// 索引只负责文本相关度：按关键词和技能找出命中的项目或人才档案并排序，
+// 学校、方向、状态等结构化条件仍由业务表查询过滤。
+package search
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// Document 一条待索引的文档
+type Document struct {
+	Type    string // models.SearchDocProject / models.SearchDocTalent
+	RefID   int
+	Title   string
+	Content string
+	Skills  []string
+}
+
+// Query 一次搜索
+type Query struct {
+	Type    string
+	Keyword string // 匹配标题、内容和技能，标题命中权重更高
+	Skill   string // 技能须包含该词
+	Limit   int
+}
+
+// Hit 一条命中，Score 越大越相关
+type Hit struct {
+	RefID int
+	Score float64
+}
+
+// Index 全文索引
+// 生产环境使用 DBIndex（MySQL FULLTEXT + ngram 分词）；MemoryIndex 为进程内倒排索引，供测试和本地开发使用。
+type Index interface {
+	// Upsert 写入或替换文档
+	Upsert(ctx context.Context, doc Document) error
+	// Delete 删除文档，文档不存在时不报错
+	Delete(ctx context.Context, docType string, refID int) error
+	// Search 按相关度从高到低返回最多 Limit 条命中
+	Search(ctx context.Context, q Query) ([]Hit, error)
+}
+
+// NewIndexFromEnv 根据 SEARCH_INDEX 创建 Index：
+// mysql（默认）使用 search_document 表上的 FULLTEXT 索引；
+// memory 使用进程内倒排索引，重启后为空，且不与其他进程共享。
+func NewIndexFromEnv(repo repository.SearchDocumentRepo) Index {
+	switch v := os.Getenv("SEARCH_INDEX"); v {
+	case "memory":
+		return NewMemoryIndex()
+	case "", "mysql":
+		return NewDBIndex(repo)
+	default:
+		log.Printf("[NewIndexFromEnv] unknown SEARCH_INDEX %q, using mysql", v)
+		return NewDBIndex(repo)
+	}
+}
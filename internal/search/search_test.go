@@ -0,0 +1,92 @@
+package search
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+func newTestIndex(t *testing.T) *MemoryIndex {
+	x := NewMemoryIndex()
+	docs := []Document{
+		{Type: models.SearchDocProject, RefID: 1, Title: "校园二手交易小程序", Content: "招募前端和后端开发，使用微信小程序", Skills: []string{"React", "Go"}},
+		{Type: models.SearchDocProject, RefID: 2, Title: "数学建模竞赛", Content: "需要擅长 Python 数据分析的同学，也欢迎前端同学做可视化", Skills: []string{"Python"}},
+		{Type: models.SearchDocProject, RefID: 3, Title: "考研互助小组", Content: "一起学习", Skills: nil},
+		{Type: models.SearchDocTalent, RefID: 1, Title: "小程序开发者", Content: "做过三个小程序", Skills: []string{"React"}},
+	}
+	for _, d := range docs {
+		require.NoError(t, x.Upsert(context.Background(), d))
+	}
+	return x
+}
+
+func hitIDs(hits []Hit) []int {
+	ids := make([]int, len(hits))
+	for i, h := range hits {
+		ids[i] = h.RefID
+	}
+	return ids
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"前端", "端开", "开发", "go"}, Tokenize("前端开发 Go"))
+	assert.Equal(t, []string{"c"}, Tokenize("C"))
+	assert.Empty(t, Tokenize("  ，。"))
+}
+
+func TestMemoryIndex_RanksTitleMatchesFirst(t *testing.T) {
+	x := newTestIndex(t)
+
+	hits, err := x.Search(context.Background(), Query{Type: models.SearchDocProject, Keyword: "小程序前端", Limit: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, hitIDs(hits))
+	assert.Greater(t, hits[0].Score, hits[1].Score)
+}
+
+func TestMemoryIndex_SkillFilter(t *testing.T) {
+	x := newTestIndex(t)
+
+	hits, err := x.Search(context.Background(), Query{Type: models.SearchDocProject, Keyword: "前端", Skill: "python", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []int{2}, hitIDs(hits))
+
+	// 只按技能筛选
+	hits, err = x.Search(context.Background(), Query{Type: models.SearchDocProject, Skill: "react", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, hitIDs(hits))
+}
+
+func TestMemoryIndex_UpsertReplacesAndDeleteRemoves(t *testing.T) {
+	x := newTestIndex(t)
+	ctx := context.Background()
+
+	require.NoError(t, x.Upsert(ctx, Document{Type: models.SearchDocProject, RefID: 3, Title: "考研互助小组", Content: "前端面试题整理"}))
+	hits, err := x.Search(ctx, Query{Type: models.SearchDocProject, Keyword: "面试", Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, hitIDs(hits))
+
+	require.NoError(t, x.Delete(ctx, models.SearchDocProject, 1))
+	hits, err = x.Search(ctx, Query{Type: models.SearchDocProject, Keyword: "小程序", Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, hits, "类型之间互不影响，已删除的项目不再命中")
+}
+
+func TestMemoryIndex_Limit(t *testing.T) {
+	x := newTestIndex(t)
+
+	hits, err := x.Search(context.Background(), Query{Type: models.SearchDocProject, Keyword: "前端", Limit: 1})
+
+	require.NoError(t, err)
+	assert.Len(t, hits, 1)
+}
+
+func TestHighlight(t *testing.T) {
+	assert.Equal(t, "使用<em>React</em>开发<em>小程序</em>", Highlight("使用React开发小程序", "小程序 react", 0))
+	assert.Equal(t, "", Highlight("考研互助", "前端", 0))
+	assert.Equal(t, "&lt;b&gt;<em>前端</em>", Highlight("<b>前端", "前端", 0))
+	assert.Equal(t, "…三<em>前端</em>四五六七…", Highlight("甲乙丙丁一二三前端四五六七八", "前端", 7))
+}
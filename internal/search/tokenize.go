@@ -0,0 +1,44 @@
+package search
+
+import "unicode"
+
+// ngramSize 与 MySQL 默认的 ngram_token_size 一致
+const ngramSize = 2
+
+// Tokenize 把文本切分为词元：按非字母数字断开，再把每段切成相邻两个字符的 ngram。
+// 中文无需词典即可检索任意词语，英文同样按 ngram 处理；不足两个字符的段落保留为单个词元。
+func Tokenize(text string) []string {
+	var tokens []string
+	for _, seg := range segments(text) {
+		if len(seg) < ngramSize {
+			tokens = append(tokens, string(seg))
+			continue
+		}
+		for i := 0; i+ngramSize <= len(seg); i++ {
+			tokens = append(tokens, string(seg[i:i+ngramSize]))
+		}
+	}
+	return tokens
+}
+
+// segments 返回小写化后的连续字母数字段落
+func segments(text string) [][]rune {
+	var (
+		segs [][]rune
+		cur  []rune
+	)
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			cur = append(cur, unicode.ToLower(r))
+			continue
+		}
+		if len(cur) > 0 {
+			segs = append(segs, cur)
+			cur = nil
+		}
+	}
+	if len(cur) > 0 {
+		segs = append(segs, cur)
+	}
+	return segs
+}
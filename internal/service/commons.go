@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
+	"github.com/trv3wood/kuaizu-server/internal/models"
 	"github.com/trv3wood/kuaizu-server/internal/oss"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 )
@@ -19,13 +20,14 @@ var allowedExts = map[string]bool{".jpg": true, ".jpeg": true, ".png": true}
 
 // CommonsService handles common utilities like file upload.
 type CommonsService struct {
-	ossClient *oss.Client
-	userRepo  repository.UserRepo
+	ossClient  *oss.Client
+	userRepo   repository.UserRepo
+	imageAudit *ImageAuditService
 }
 
 // NewCommonsService creates a new CommonsService.
-func NewCommonsService(ossClient *oss.Client, userRepo repository.UserRepo) *CommonsService {
-	return &CommonsService{ossClient: ossClient, userRepo: userRepo}
+func NewCommonsService(ossClient *oss.Client, userRepo repository.UserRepo, imageAudit *ImageAuditService) *CommonsService {
+	return &CommonsService{ossClient: ossClient, userRepo: userRepo, imageAudit: imageAudit}
 }
 
 // UploadFile validates and uploads a multipart file to OSS.
@@ -48,6 +50,40 @@ func (s *CommonsService) UploadFile(file multipart.File, header *multipart.FileH
 	return result, nil
 }
 
+// UploadImages uploads several image files and returns their keys. If any upload
+// fails, the images already uploaded are deleted.
+func (s *CommonsService) UploadImages(headers []*multipart.FileHeader) ([]string, error) {
+	keys := make([]string, 0, len(headers))
+	for _, header := range headers {
+		result, err := s.uploadFileHeader(header)
+		if err != nil {
+			s.DeleteFiles(keys)
+			return nil, err
+		}
+		keys = append(keys, result.Key)
+	}
+	return keys, nil
+}
+
+func (s *CommonsService) uploadFileHeader(header *multipart.FileHeader) (*oss.UploadResult, error) {
+	file, err := header.Open()
+	if err != nil {
+		log.Printf("[CommonsService.uploadFileHeader] open file error: %v", err)
+		return nil, ErrBadRequest("读取上传文件失败")
+	}
+	defer file.Close()
+	return s.UploadFile(file, header)
+}
+
+// DeleteFiles removes several files from OSS, logging failures.
+func (s *CommonsService) DeleteFiles(keys []string) {
+	for _, key := range keys {
+		if err := s.DeleteFile(key); err != nil {
+			log.Printf("[CommonsService.DeleteFiles] failed to delete %s: %v", key, err)
+		}
+	}
+}
+
 // DeleteFile removes a file from OSS by its key. Errors are logged but treated as
 // non-fatal so they do not roll back an otherwise successful operation.
 func (s *CommonsService) DeleteFile(key string) error {
@@ -89,62 +125,38 @@ func (s *CommonsService) SubmitCertification(ctx context.Context, userID int, fi
 	return result, nil
 }
 
-// UploadAvatar uploads a new avatar for the user, deletes the old one from OSS,
-// and persists the new URL to the database.
-func (s *CommonsService) UploadAvatar(ctx context.Context, userID int, file multipart.File, header *multipart.FileHeader) (*oss.UploadResult, error) {
-	// 1. 查询旧头像
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		log.Printf("[CommonsService.UploadAvatar] repository error getting user: %v", err)
-		return nil, ErrInternal("获取用户信息失败")
-	}
-
-	// 2. 上传新文件
-	result, err := s.UploadFile(file, header)
-	if err != nil {
-		return nil, err
-	}
-
-	// 3. 删除旧头像（忽略删除失败）
-	if user != nil && user.AvatarUrl != nil && *user.AvatarUrl != "" {
-		_ = s.DeleteFile(*user.AvatarUrl)
-	}
-
-	// 4. 更新数据库
-	if err := s.userRepo.UpdateAvatarUrl(ctx, userID, result.Key); err != nil {
-		log.Printf("[CommonsService.UploadAvatar] repository error updating avatar: %v", err)
-		return nil, ErrInternal("更新头像失败")
-	}
+// ImageUploadResult 头像/封面上传结果，图片审核通过后才会生效
+type ImageUploadResult struct {
+	*oss.UploadResult
+	AuditStatus int
+}
 
-	return result, nil
+// UploadAvatar uploads a new avatar for the user and submits it for image audit.
+// The avatar is written to the user's profile only after it passes the audit.
+func (s *CommonsService) UploadAvatar(ctx context.Context, userID int, file multipart.File, header *multipart.FileHeader) (*ImageUploadResult, error) {
+	return s.uploadAuditedImage(ctx, userID, models.ImageAuditBizAvatar, file, header)
 }
 
-// UploadCoverImage uploads a new cover image for the user, deletes the old one
-// from OSS, and persists the new URL to the database.
-func (s *CommonsService) UploadCoverImage(ctx context.Context, userID int, file multipart.File, header *multipart.FileHeader) (*oss.UploadResult, error) {
-	// 1. 查询旧封面图
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		log.Printf("[CommonsService.UploadCoverImage] repository error getting user: %v", err)
-		return nil, ErrInternal("获取用户信息失败")
-	}
+// UploadCoverImage uploads a new cover image for the user and submits it for
+// image audit. The cover is written to the user's profile only after it passes
+// the audit.
+func (s *CommonsService) UploadCoverImage(ctx context.Context, userID int, file multipart.File, header *multipart.FileHeader) (*ImageUploadResult, error) {
+	return s.uploadAuditedImage(ctx, userID, models.ImageAuditBizCover, file, header)
+}
 
-	// 2. 上传新文件
+func (s *CommonsService) uploadAuditedImage(ctx context.Context, userID int, bizType string, file multipart.File, header *multipart.FileHeader) (*ImageUploadResult, error) {
+	// 1. 上传新文件
 	result, err := s.UploadFile(file, header)
 	if err != nil {
 		return nil, err
 	}
 
-	// 3. 删除旧封面图（忽略删除失败）
-	if user != nil && user.CoverImage != nil && *user.CoverImage != "" {
-		_ = s.DeleteFile(*user.CoverImage)
-	}
-
-	// 4. 更新数据库
-	if err := s.userRepo.UpdateCoverImage(ctx, userID, result.Key); err != nil {
-		log.Printf("[CommonsService.UploadCoverImage] repository error updating cover image: %v", err)
-		return nil, ErrInternal("更新封面图失败")
+	// 2. 提交审核（旧图片在新图片审核通过后删除）
+	audit, err := s.imageAudit.Submit(ctx, userID, bizType, result.Key)
+	if err != nil {
+		_ = s.DeleteFile(result.Key)
+		return nil, err
 	}
 
-	return result, nil
+	return &ImageUploadResult{UploadResult: result, AuditStatus: audit.Status}, nil
 }
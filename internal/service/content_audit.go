@@ -1,25 +1,261 @@
 package service
 
-import "context"
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/wechat"
+)
+
+// msgSecCheckMaxRunes 单次 msgSecCheck 的文本长度上限，超出部分分段审核
+const msgSecCheckMaxRunes = 2500
+
+// TextAuditRequest 单次文本审核请求
+type TextAuditRequest struct {
+	OpenID  string
+	Scene   int
+	Content string
+}
+
+// TextVerdict 文本审核结论
+type TextVerdict struct {
+	Source  string // wechat/local
+	Suggest string // pass/review/risky
+	Label   int
+	Keyword string
+	TraceID string
+}
+
+// TextChecker 文本审核器
+type TextChecker interface {
+	CheckText(ctx context.Context, req TextAuditRequest) (*TextVerdict, error)
+}
+
+// wechatTextChecker 基于微信 msgSecCheck v2 的审核器
+type wechatTextChecker struct {
+	client *wechat.Client
+}
+
+// CheckText 调用 msgSecCheck，需要用户近两小时内访问过小程序的 openid
+func (c *wechatTextChecker) CheckText(ctx context.Context, req TextAuditRequest) (*TextVerdict, error) {
+	resp, err := c.client.MsgSecCheck(&wechat.MsgSecCheckRequest{
+		Content: req.Content,
+		Scene:   req.Scene,
+		OpenID:  req.OpenID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	suggest := resp.Result.Suggest
+	switch suggest {
+	case models.ContentAuditSuggestPass, models.ContentAuditSuggestReview, models.ContentAuditSuggestRisky:
+	default:
+		// 未知建议按人工复审处理
+		suggest = models.ContentAuditSuggestReview
+	}
+
+	return &TextVerdict{
+		Source:  models.ContentAuditSourceWechat,
+		Suggest: suggest,
+		Label:   resp.Result.Label,
+		Keyword: resp.Keyword(),
+		TraceID: resp.TraceID,
+	}, nil
+}
 
 // ContentAuditService 文字内容审核服务
-type ContentAuditService struct{}
+// 优先使用微信内容安全接口，接口不可用或用户没有 openid 时回退到本地规则。
+// 每次审核结论都会写入 content_audit_log。
+type ContentAuditService struct {
+	repo     *repository.Repository
+	primary  TextChecker // 可为空
+	fallback TextChecker
+}
 
 // NewContentAuditService creates a new ContentAuditService.
-func NewContentAuditService() *ContentAuditService {
-	return &ContentAuditService{}
-}
-
-// CheckText 校验文本内容是否合规。
-// 传入多段文本，任一违规则返回 error。
-// TODO: 接入微信 msgSecCheck 或第三方内容安全 API
-func (s *ContentAuditService) CheckText(ctx context.Context, texts ...string) error {
-	// 过滤空字符串
-	// for _, t := range texts {
-	// 	if t == "" {
-	// 		continue
-	// 	}
-	// 	// 调用内容安全 API ...
-	// }
+// 本地规则从 CONTENT_AUDIT_RULES_FILE 读取，未配置时使用内置规则。
+func NewContentAuditService(repo *repository.Repository) *ContentAuditService {
+	rules := defaultLocalRules
+	if path := os.Getenv("CONTENT_AUDIT_RULES_FILE"); path != "" {
+		loaded, err := LoadLocalRules(path)
+		if err != nil {
+			log.Printf("[ContentAuditService] load rules from %s failed, using defaults: %v", path, err)
+		} else {
+			rules = loaded
+		}
+	}
+
+	local, err := NewLocalRuleChecker(rules)
+	if err != nil {
+		log.Printf("[ContentAuditService] invalid local rules, using defaults: %v", err)
+		local, _ = NewLocalRuleChecker(defaultLocalRules)
+	}
+
+	return NewContentAuditServiceWithCheckers(repo, &wechatTextChecker{client: wechat.NewClient()}, local)
+}
+
+// NewContentAuditServiceWithCheckers creates a ContentAuditService with explicit checkers.
+func NewContentAuditServiceWithCheckers(repo *repository.Repository, primary, fallback TextChecker) *ContentAuditService {
+	return &ContentAuditService{repo: repo, primary: primary, fallback: fallback}
+}
+
+// TextAuditInput 文本审核输入
+type TextAuditInput struct {
+	UserID  int
+	Scene   int
+	BizType string
+	BizID   *int // 创建前审核时为空，之后通过 AttachBiz 回填
+	Texts   []string
+}
+
+// TextAuditResult 文本审核的综合结论
+type TextAuditResult struct {
+	Suggest string
+	Label   int
+	Keyword string
+	LogIDs  []int64
+}
+
+// IsRisky 内容违规
+func (r *TextAuditResult) IsRisky() bool {
+	return r.Suggest == models.ContentAuditSuggestRisky
+}
+
+// NeedsReview 内容需要人工复审
+func (r *TextAuditResult) NeedsReview() bool {
+	return r.Suggest == models.ContentAuditSuggestReview
+}
+
+// AuditText 审核文本并记录每次结论，返回最严重的结论。
+// 审核器全部不可用时按通过处理，不阻塞用户操作。
+func (s *ContentAuditService) AuditText(ctx context.Context, input TextAuditInput) *TextAuditResult {
+	result := &TextAuditResult{Suggest: models.ContentAuditSuggestPass, Label: 100}
+
+	var parts []string
+	for _, t := range input.Texts {
+		if t = strings.TrimSpace(t); t != "" {
+			parts = append(parts, t)
+		}
+	}
+	if len(parts) == 0 {
+		return result
+	}
+
+	var openID string
+	if s.primary != nil && input.UserID > 0 {
+		user, err := s.repo.User.GetByID(ctx, input.UserID)
+		if err != nil {
+			log.Printf("[ContentAuditService.AuditText] get user %d: %v", input.UserID, err)
+		} else if user != nil {
+			openID = user.OpenID
+		}
+	}
+
+	for _, chunk := range splitRunes(strings.Join(parts, "\n"), msgSecCheckMaxRunes) {
+		verdict := s.check(ctx, TextAuditRequest{OpenID: openID, Scene: input.Scene, Content: chunk})
+
+		entry := &models.ContentAuditLog{
+			UserID:  input.UserID,
+			BizType: input.BizType,
+			BizID:   input.BizID,
+			Scene:   input.Scene,
+			Content: chunk,
+			Source:  verdict.Source,
+			Suggest: verdict.Suggest,
+			Label:   verdict.Label,
+		}
+		if verdict.Keyword != "" {
+			entry.Keyword = &verdict.Keyword
+		}
+		if verdict.TraceID != "" {
+			entry.TraceID = &verdict.TraceID
+		}
+		if err := s.repo.ContentAudit.Create(ctx, entry); err != nil {
+			log.Printf("[ContentAuditService.AuditText] save audit log: %v", err)
+		} else {
+			result.LogIDs = append(result.LogIDs, entry.ID)
+		}
+
+		if suggestSeverity(verdict.Suggest) > suggestSeverity(result.Suggest) {
+			result.Suggest = verdict.Suggest
+			result.Label = verdict.Label
+			result.Keyword = verdict.Keyword
+		}
+	}
+
+	return result
+}
+
+// CheckText 审核文本，违规时返回 ErrBadRequest；需人工复审的内容放行并留档。
+// 用于没有人工审核队列的场景（昵称、人才档案等）。
+func (s *ContentAuditService) CheckText(ctx context.Context, input TextAuditInput) error {
+	if s.AuditText(ctx, input).IsRisky() {
+		return ErrBadRequest("内容包含违规信息，请修改后重试")
+	}
 	return nil
 }
+
+// AttachBiz 把创建前的审核记录关联到新建的业务对象
+func (s *ContentAuditService) AttachBiz(ctx context.Context, result *TextAuditResult, bizID int) {
+	if result == nil || len(result.LogIDs) == 0 {
+		return
+	}
+	if err := s.repo.ContentAudit.AttachBizID(ctx, result.LogIDs, bizID); err != nil {
+		log.Printf("[ContentAuditService.AttachBiz] %v", err)
+	}
+}
+
+// check 先用主审核器，失败或缺少 openid 时回退到本地规则
+func (s *ContentAuditService) check(ctx context.Context, req TextAuditRequest) *TextVerdict {
+	if s.primary != nil && req.OpenID != "" {
+		verdict, err := s.primary.CheckText(ctx, req)
+		if err == nil {
+			return verdict
+		}
+		log.Printf("[ContentAuditService.check] primary checker failed, falling back to local rules: %v", err)
+	}
+
+	if s.fallback != nil {
+		verdict, err := s.fallback.CheckText(ctx, req)
+		if err == nil {
+			return verdict
+		}
+		log.Printf("[ContentAuditService.check] fallback checker failed: %v", err)
+	}
+
+	return &TextVerdict{Source: models.ContentAuditSourceLocal, Suggest: models.ContentAuditSuggestPass, Label: 100}
+}
+
+// suggestSeverity 审核建议的严重程度，用于合并多段结论
+func suggestSeverity(suggest string) int {
+	switch suggest {
+	case models.ContentAuditSuggestRisky:
+		return 2
+	case models.ContentAuditSuggestReview:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// splitRunes 按字符数切分文本
+func splitRunes(s string, size int) []string {
+	r := []rune(s)
+	if len(r) <= size {
+		return []string{s}
+	}
+	var chunks []string
+	for start := 0; start < len(r); start += size {
+		end := start + size
+		if end > len(r) {
+			end = len(r)
+		}
+		chunks = append(chunks, string(r[start:end]))
+	}
+	return chunks
+}
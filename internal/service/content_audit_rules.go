@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+// LocalRule 本地审核规则，Keyword 与 Pattern 二选一
+type LocalRule struct {
+	Keyword string `json:"keyword"` // 包含即命中（忽略大小写）
+	Pattern string `json:"pattern"` // 正则表达式
+	Suggest string `json:"suggest"` // review/risky，默认 risky
+	Label   int    `json:"label"`   // 与微信标签保持一致，默认 21000（其他）
+}
+
+// defaultLocalRules 内置规则，可通过 CONTENT_AUDIT_RULES_FILE 覆盖
+var defaultLocalRules = []LocalRule{
+	{Keyword: "赌博", Label: 20006},
+	{Keyword: "博彩", Label: 20006},
+	{Keyword: "代开发票", Label: 20006},
+	{Keyword: "刷单", Label: 20008},
+	{Pattern: `(?i)(加|\+)\s*(微信|vx|wx|v信|qq)\s*[:：]?\s*[a-z0-9_-]{5,}`, Suggest: models.ContentAuditSuggestReview, Label: 20008},
+}
+
+// compiledRule 编译后的规则
+type compiledRule struct {
+	keyword string
+	re      *regexp.Regexp
+	suggest string
+	label   int
+}
+
+// LocalRuleChecker 基于关键词与正则的本地审核器
+type LocalRuleChecker struct {
+	rules []compiledRule
+}
+
+// NewLocalRuleChecker 编译规则并创建本地审核器
+func NewLocalRuleChecker(rules []LocalRule) (*LocalRuleChecker, error) {
+	c := &LocalRuleChecker{}
+	for i, r := range rules {
+		cr := compiledRule{suggest: r.Suggest, label: r.Label}
+		if cr.suggest == "" {
+			cr.suggest = models.ContentAuditSuggestRisky
+		}
+		if cr.suggest != models.ContentAuditSuggestRisky && cr.suggest != models.ContentAuditSuggestReview {
+			return nil, fmt.Errorf("rule %d: invalid suggest %q", i, r.Suggest)
+		}
+		if cr.label == 0 {
+			cr.label = 21000
+		}
+
+		switch {
+		case r.Pattern != "":
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", i, err)
+			}
+			cr.re = re
+		case r.Keyword != "":
+			cr.keyword = strings.ToLower(r.Keyword)
+		default:
+			return nil, fmt.Errorf("rule %d: keyword or pattern is required", i)
+		}
+
+		c.rules = append(c.rules, cr)
+	}
+	return c, nil
+}
+
+// LoadLocalRules 从 JSON 文件读取规则列表
+func LoadLocalRules(path string) ([]LocalRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rules file: %w", err)
+	}
+
+	var rules []LocalRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parse rules file: %w", err)
+	}
+	return rules, nil
+}
+
+// CheckText 返回命中的最严重规则
+func (c *LocalRuleChecker) CheckText(ctx context.Context, req TextAuditRequest) (*TextVerdict, error) {
+	verdict := &TextVerdict{
+		Source:  models.ContentAuditSourceLocal,
+		Suggest: models.ContentAuditSuggestPass,
+		Label:   100,
+	}
+
+	lower := strings.ToLower(req.Content)
+	for _, r := range c.rules {
+		var hit string
+		if r.re != nil {
+			hit = r.re.FindString(req.Content)
+		} else if strings.Contains(lower, r.keyword) {
+			hit = r.keyword
+		}
+		if hit == "" || suggestSeverity(r.suggest) <= suggestSeverity(verdict.Suggest) {
+			continue
+		}
+
+		verdict.Suggest = r.suggest
+		verdict.Label = r.label
+		verdict.Keyword = hit
+		if r.suggest == models.ContentAuditSuggestRisky {
+			break
+		}
+	}
+
+	return verdict, nil
+}
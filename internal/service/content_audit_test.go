@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// --- Mock Repositories using testify/mock ---
+
+type MockUserRepo struct {
+	repository.UserRepo
+	mock.Mock
+}
+
+func (m *MockUserRepo) GetByID(ctx context.Context, id int) (*models.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+type MockContentAuditRepo struct {
+	mock.Mock
+}
+
+func (m *MockContentAuditRepo) Create(ctx context.Context, log *models.ContentAuditLog) error {
+	args := m.Called(ctx, log)
+	log.ID = int64(len(m.Calls))
+	return args.Error(0)
+}
+
+func (m *MockContentAuditRepo) AttachBizID(ctx context.Context, ids []int64, bizID int) error {
+	args := m.Called(ctx, ids, bizID)
+	return args.Error(0)
+}
+
+type stubTextChecker struct {
+	verdict *TextVerdict
+	err     error
+	calls   int
+}
+
+func (c *stubTextChecker) CheckText(ctx context.Context, req TextAuditRequest) (*TextVerdict, error) {
+	c.calls++
+	return c.verdict, c.err
+}
+
+// --- Tests for LocalRuleChecker ---
+
+func TestLocalRuleChecker(t *testing.T) {
+	checker, err := NewLocalRuleChecker(defaultLocalRules)
+	require.NoError(t, err)
+
+	cases := []struct {
+		content string
+		suggest string
+	}{
+		{"招募前端开发同学，一起参加比赛", models.ContentAuditSuggestPass},
+		{"有意者加微信: abc12345", models.ContentAuditSuggestReview},
+		{"线上博彩项目招人", models.ContentAuditSuggestRisky},
+		{"加vx abc12345 了解博彩", models.ContentAuditSuggestRisky},
+	}
+	for _, c := range cases {
+		verdict, err := checker.CheckText(context.Background(), TextAuditRequest{Content: c.content})
+		require.NoError(t, err)
+		assert.Equal(t, c.suggest, verdict.Suggest, c.content)
+		assert.Equal(t, models.ContentAuditSourceLocal, verdict.Source)
+	}
+}
+
+func TestNewLocalRuleChecker_InvalidRule(t *testing.T) {
+	_, err := NewLocalRuleChecker([]LocalRule{{Pattern: "("}})
+	assert.Error(t, err)
+
+	_, err = NewLocalRuleChecker([]LocalRule{{Keyword: "x", Suggest: "block"}})
+	assert.Error(t, err)
+
+	_, err = NewLocalRuleChecker([]LocalRule{{Suggest: models.ContentAuditSuggestRisky}})
+	assert.Error(t, err)
+}
+
+// --- Tests for ContentAuditService ---
+
+func TestAuditText_FallsBackWhenPrimaryFails(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockAudit := new(MockContentAuditRepo)
+	mockUser.On("GetByID", mock.Anything, 1).Return(&models.User{ID: 1, OpenID: "openid-1"}, nil)
+	mockAudit.On("Create", mock.Anything, mock.MatchedBy(func(l *models.ContentAuditLog) bool {
+		return l.Source == models.ContentAuditSourceLocal && l.Suggest == models.ContentAuditSuggestRisky
+	})).Return(nil)
+
+	primary := &stubTextChecker{err: errors.New("wechat unavailable")}
+	local, err := NewLocalRuleChecker(defaultLocalRules)
+	require.NoError(t, err)
+
+	svc := NewContentAuditServiceWithCheckers(&repository.Repository{User: mockUser, ContentAudit: mockAudit}, primary, local)
+	result := svc.AuditText(context.Background(), TextAuditInput{
+		UserID:  1,
+		Scene:   models.ContentAuditSceneForum,
+		BizType: models.ContentAuditBizProject,
+		Texts:   []string{"项目名称", "线上博彩项目"},
+	})
+
+	assert.Equal(t, 1, primary.calls)
+	assert.True(t, result.IsRisky())
+	assert.Equal(t, "博彩", result.Keyword)
+	assert.Len(t, result.LogIDs, 1)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestAuditText_ReviewFromPrimary(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockAudit := new(MockContentAuditRepo)
+	mockUser.On("GetByID", mock.Anything, 1).Return(&models.User{ID: 1, OpenID: "openid-1"}, nil)
+	mockAudit.On("Create", mock.Anything, mock.MatchedBy(func(l *models.ContentAuditLog) bool {
+		return l.Source == models.ContentAuditSourceWechat && l.TraceID != nil && *l.TraceID == "trace-1"
+	})).Return(nil)
+	mockAudit.On("AttachBizID", mock.Anything, []int64{1}, 42).Return(nil)
+
+	primary := &stubTextChecker{verdict: &TextVerdict{
+		Source:  models.ContentAuditSourceWechat,
+		Suggest: models.ContentAuditSuggestReview,
+		Label:   20012,
+		TraceID: "trace-1",
+	}}
+	fallback := &stubTextChecker{}
+
+	svc := NewContentAuditServiceWithCheckers(&repository.Repository{User: mockUser, ContentAudit: mockAudit}, primary, fallback)
+	result := svc.AuditText(context.Background(), TextAuditInput{
+		UserID:  1,
+		Scene:   models.ContentAuditSceneForum,
+		BizType: models.ContentAuditBizProject,
+		Texts:   []string{"需要复审的内容"},
+	})
+	svc.AttachBiz(context.Background(), result, 42)
+
+	assert.True(t, result.NeedsReview())
+	assert.Equal(t, 20012, result.Label)
+	assert.Equal(t, 0, fallback.calls)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestAuditText_SkipsPrimaryWithoutOpenID(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockAudit := new(MockContentAuditRepo)
+	mockUser.On("GetByID", mock.Anything, 1).Return(&models.User{ID: 1}, nil)
+	mockAudit.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	primary := &stubTextChecker{}
+	local, err := NewLocalRuleChecker(defaultLocalRules)
+	require.NoError(t, err)
+
+	svc := NewContentAuditServiceWithCheckers(&repository.Repository{User: mockUser, ContentAudit: mockAudit}, primary, local)
+	err = svc.CheckText(context.Background(), TextAuditInput{
+		UserID:  1,
+		Scene:   models.ContentAuditSceneProfile,
+		BizType: models.ContentAuditBizUser,
+		Texts:   []string{"刷单兼职"},
+	})
+
+	assertServiceError(t, err, ErrCodeBadRequest, "内容包含违规信息，请修改后重试")
+	assert.Equal(t, 0, primary.calls)
+}
+
+func TestAuditText_EmptyTexts(t *testing.T) {
+	svc := NewContentAuditServiceWithCheckers(&repository.Repository{}, nil, nil)
+	result := svc.AuditText(context.Background(), TextAuditInput{Texts: []string{"", "  "}})
+
+	assert.Equal(t, models.ContentAuditSuggestPass, result.Suggest)
+	assert.Empty(t, result.LogIDs)
+}
@@ -2,9 +2,8 @@ package service
 
 import (
 	"context"
-	"log"
 
-	"github.com/trv3wood/kuaizu-server/internal/email"
+	"github.com/jmoiron/sqlx"
 	"github.com/trv3wood/kuaizu-server/internal/models"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 )
@@ -25,7 +24,9 @@ type TriggerPromotionResult struct {
 	MaxRecipients int
 }
 
-// TriggerPromotion validates ownership and creates a promotion, then starts async sending.
+// TriggerPromotion validates ownership and creates a pending promotion. The email
+// worker expands pending promotions into email tasks, so nothing is lost if the
+// process exits right after the promotion is stored.
 func (s *EmailPromotionService) TriggerPromotion(ctx context.Context, userID, orderID, projectID int) (*TriggerPromotionResult, error) {
 	// Validate order ownership
 	order, err := s.repo.Order.GetByID(ctx, orderID)
@@ -54,76 +55,57 @@ func (s *EmailPromotionService) TriggerPromotion(ctx context.Context, userID, or
 		return nil, ErrForbidden("只能推广自己创建的项目")
 	}
 
-	// Check duplication
-	existingPromotion, err := s.repo.EmailPromotion.GetByOrderID(ctx, orderID)
-	if err != nil {
-		return nil, ErrInternal("检查推广记录失败")
-	}
-	if existingPromotion != nil {
-		return nil, ErrBadRequest("此订单已触发过推广")
-	}
+	// Lock the entitlement so the duplicate check, the consume and the promotion
+	// record are committed together
+	var promotion *models.EmailPromotion
+	err = runInTx(ctx, s.repo, "EmailPromotionService.TriggerPromotion", "创建推广记录失败", func(tx *sqlx.Tx) error {
+		entitlement, err := s.repo.Entitlement.GetByOrderIDTx(ctx, tx, orderID)
+		if err != nil {
+			return err
+		}
+		if entitlement == nil || entitlement.Kind != models.BenefitKindEmailPromotion {
+			return ErrBadRequest("订单中没有邮件推广商品")
+		}
 
-	// Calculate max recipients from order items
-	maxRecipients, err := s.calculateMaxRecipients(ctx, order)
-	if err != nil {
-		return nil, err
-	}
+		existing, err := s.repo.EmailPromotion.GetByOrderIDTx(ctx, tx, orderID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return ErrBadRequest("此订单已触发过推广")
+		}
 
-	// Create promotion record
-	promotion := &models.EmailPromotion{
-		OrderID:       orderID,
-		ProjectID:     projectID,
-		CreatorID:     userID,
-		MaxRecipients: maxRecipients,
-		Status:        models.EmailPromotionStatusPending,
-	}
+		maxRecipients := entitlement.Remaining()
+		if maxRecipients <= 0 {
+			return ErrBadRequest("推广额度已用完")
+		}
+		consumed, err := s.repo.Entitlement.ConsumeTx(ctx, tx, entitlement.ID, maxRecipients)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return ErrBadRequest("推广额度已用完")
+		}
 
-	if err := s.repo.EmailPromotion.Create(ctx, promotion); err != nil {
-		log.Printf("Failed to create email promotion: %v", err)
-		return nil, ErrInternal("创建推广记录失败")
+		promotion = &models.EmailPromotion{
+			OrderID:       orderID,
+			ProjectID:     projectID,
+			CreatorID:     userID,
+			MaxRecipients: maxRecipients,
+			Status:        models.EmailPromotionStatusPending,
+		}
+		return s.repo.EmailPromotion.CreateTx(ctx, tx, promotion)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Start async email sending
-	s.startAsyncEmailSending(promotion)
-
 	return &TriggerPromotionResult{
 		Promotion:     promotion,
-		MaxRecipients: maxRecipients,
+		MaxRecipients: promotion.MaxRecipients,
 	}, nil
 }
 
-func (s *EmailPromotionService) calculateMaxRecipients(ctx context.Context, order *models.Order) (int, error) {
-	product, err := s.repo.Product.GetByID(ctx, order.ProductID)
-	if err != nil || product == nil {
-		return 0, ErrBadRequest("无法获取商品信息")
-	}
-
-	if product.Type == models.ProductTypeBenefit { // 服务权益 - 邮件推广
-		return order.Quantity, nil
-	}
-
-	return 0, ErrBadRequest("订单中没有邮件推广商品")
-}
-
-func (s *EmailPromotionService) startAsyncEmailSending(promotion *models.EmailPromotion) {
-	go func() {
-		emailService, err := email.NewServiceFromEnv(
-			s.repo.User,
-			s.repo.Project,
-			s.repo.EmailPromotion,
-		)
-		if err != nil {
-			errMsg := "邮件服务未配置: " + err.Error()
-			promotion.Status = models.EmailPromotionStatusFailed
-			promotion.ErrorMessage = &errMsg
-			s.repo.EmailPromotion.Update(context.Background(), promotion)
-			return
-		}
-
-		emailService.SendPromotionEmails(context.Background(), promotion)
-	}()
-}
-
 // GetStatus retrieves a promotion record with ownership check.
 func (s *EmailPromotionService) GetStatus(ctx context.Context, userID, promotionID int) (*models.EmailPromotion, error) {
 	promotion, err := s.repo.EmailPromotion.GetByID(ctx, promotionID)
@@ -49,9 +49,14 @@ func (m *MockOrderRepo) UpdatePaymentStatus(ctx context.Context, id int, status
 	return args.Error(0)
 }
 
-func (m *MockOrderRepo) UpdatePaymentStatusTx(ctx context.Context, tx *sqlx.Tx, id int, status int, wxPayNo string, payTime time.Time) error {
-	args := m.Called(ctx, tx, id, status, wxPayNo, payTime)
-	return args.Error(0)
+func (m *MockOrderRepo) LockStatusTx(ctx context.Context, tx *sqlx.Tx, id int) (int, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockOrderRepo) MarkPaidTx(ctx context.Context, tx *sqlx.Tx, id int, wxPayNo string, payTime time.Time) (bool, error) {
+	args := m.Called(ctx, tx, id, wxPayNo, payTime)
+	return args.Bool(0), args.Error(1)
 }
 
 func (m *MockOrderRepo) UpdateStatus(ctx context.Context, id int, status int) error {
@@ -59,6 +64,29 @@ func (m *MockOrderRepo) UpdateStatus(ctx context.Context, id int, status int) er
 	return args.Error(0)
 }
 
+func (m *MockOrderRepo) SetOutTradeNo(ctx context.Context, id int, outTradeNo string) error {
+	args := m.Called(ctx, id, outTradeNo)
+	return args.Error(0)
+}
+
+func (m *MockOrderRepo) ListExpiredPending(ctx context.Context, before time.Time, limit int) ([]*models.Order, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Order), args.Error(1)
+}
+
+func (m *MockOrderRepo) CancelIfPending(ctx context.Context, id int) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderRepo) TransitionStatusTx(ctx context.Context, tx *sqlx.Tx, id int, from, to int) (bool, error) {
+	args := m.Called(ctx, tx, id, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
 type MockProjectRepo struct {
 	mock.Mock
 }
@@ -84,6 +112,24 @@ func (m *MockProjectRepo) Create(ctx context.Context, p *models.Project) error {
 	return args.Error(0)
 }
 
+func (m *MockProjectRepo) CreateTx(ctx context.Context, tx *sqlx.Tx, p *models.Project) error {
+	args := m.Called(ctx, tx, p)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) LockMemberCountTx(ctx context.Context, tx *sqlx.Tx, id int) (int, error) {
+	args := m.Called(ctx, tx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProjectRepo) ListRecommendCandidates(ctx context.Context, params repository.ProjectCandidateParams) ([]models.Project, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Project), args.Error(1)
+}
+
 func (m *MockProjectRepo) Update(ctx context.Context, p *models.Project) error {
 	args := m.Called(ctx, p)
 	return args.Error(0)
@@ -104,11 +150,26 @@ func (m *MockProjectRepo) UpdateStatus(ctx context.Context, id int, status int)
 	return args.Error(0)
 }
 
+func (m *MockProjectRepo) UpdateStatusTx(ctx context.Context, tx *sqlx.Tx, id int, status int) error {
+	args := m.Called(ctx, tx, id, status)
+	return args.Error(0)
+}
+
 func (m *MockProjectRepo) IncrementViewCount(ctx context.Context, id int) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
 
+func (m *MockProjectRepo) ActivatePromotionTx(ctx context.Context, tx *sqlx.Tx, id int, days int, now time.Time) error {
+	args := m.Called(ctx, tx, id, days, now)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepo) FinishExpiredPromotions(ctx context.Context, now time.Time) (int64, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 type MockProductRepo struct {
 	mock.Mock
 }
@@ -133,8 +194,8 @@ type MockEmailPromotionRepo struct {
 	mock.Mock
 }
 
-func (m *MockEmailPromotionRepo) Create(ctx context.Context, promotion *models.EmailPromotion) error {
-	args := m.Called(ctx, promotion)
+func (m *MockEmailPromotionRepo) CreateTx(ctx context.Context, tx *sqlx.Tx, promotion *models.EmailPromotion) error {
+	args := m.Called(ctx, tx, promotion)
 	return args.Error(0)
 }
 
@@ -146,8 +207,8 @@ func (m *MockEmailPromotionRepo) GetByID(ctx context.Context, id int) (*models.E
 	return args.Get(0).(*models.EmailPromotion), args.Error(1)
 }
 
-func (m *MockEmailPromotionRepo) GetByOrderID(ctx context.Context, orderID int) (*models.EmailPromotion, error) {
-	args := m.Called(ctx, orderID)
+func (m *MockEmailPromotionRepo) GetByOrderIDTx(ctx context.Context, tx *sqlx.Tx, orderID int) (*models.EmailPromotion, error) {
+	args := m.Called(ctx, tx, orderID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -159,6 +220,14 @@ func (m *MockEmailPromotionRepo) Update(ctx context.Context, promotion *models.E
 	return args.Error(0)
 }
 
+func (m *MockEmailPromotionRepo) ListPending(ctx context.Context, limit int) ([]models.EmailPromotion, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EmailPromotion), args.Error(1)
+}
+
 func (m *MockEmailPromotionRepo) ListByCreatorID(ctx context.Context, creatorID int, page, size int) ([]models.EmailPromotion, int64, error) {
 	args := m.Called(ctx, creatorID, page, size)
 	if args.Get(0) == nil {
@@ -318,17 +387,19 @@ func TestTriggerPromotion_AlreadyTriggered(t *testing.T) {
 	mockProject := new(MockProjectRepo)
 	mockProduct := new(MockProductRepo)
 	mockEmailPromotion := new(MockEmailPromotionRepo)
+	mockEntitlement := new(MockEntitlementRepo)
 
 	mockOrder.On("GetByID", mock.Anything, 100).Return(&models.Order{ID: 100, UserID: 1, Status: 1}, nil)
 	mockProject.On("GetByID", mock.Anything, 200).Return(&models.Project{ID: 200, CreatorID: 1}, nil)
-	mockEmailPromotion.On("GetByOrderID", mock.Anything, 100).Return(&models.EmailPromotion{ID: 1, OrderID: 100}, nil)
+	mockEntitlement.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(emailEntitlement(100, 50), nil)
+	mockEmailPromotion.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(&models.EmailPromotion{ID: 1, OrderID: 100}, nil)
 
-	repo := &repository.Repository{
-		Order:          mockOrder,
-		Project:        mockProject,
-		Product:        mockProduct,
-		EmailPromotion: mockEmailPromotion,
-	}
+	repo := newTxTestRepo()
+	repo.Order = mockOrder
+	repo.Project = mockProject
+	repo.Product = mockProduct
+	repo.EmailPromotion = mockEmailPromotion
+	repo.Entitlement = mockEntitlement
 
 	svc := NewEmailPromotionService(repo)
 	_, err := svc.TriggerPromotion(context.Background(), 1, 100, 200)
@@ -337,6 +408,7 @@ func TestTriggerPromotion_AlreadyTriggered(t *testing.T) {
 	mockOrder.AssertExpectations(t)
 	mockProject.AssertExpectations(t)
 	mockEmailPromotion.AssertExpectations(t)
+	mockEntitlement.AssertNotCalled(t, "ConsumeTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestTriggerPromotion_NoEmailPromotionProduct(t *testing.T) {
@@ -344,6 +416,7 @@ func TestTriggerPromotion_NoEmailPromotionProduct(t *testing.T) {
 	mockProject := new(MockProjectRepo)
 	mockProduct := new(MockProductRepo)
 	mockEmailPromotion := new(MockEmailPromotionRepo)
+	mockEntitlement := new(MockEntitlementRepo)
 
 	order := &models.Order{
 		ID:        100,
@@ -355,15 +428,16 @@ func TestTriggerPromotion_NoEmailPromotionProduct(t *testing.T) {
 
 	mockOrder.On("GetByID", mock.Anything, 100).Return(order, nil)
 	mockProject.On("GetByID", mock.Anything, 200).Return(&models.Project{ID: 200, CreatorID: 1}, nil)
-	mockEmailPromotion.On("GetByOrderID", mock.Anything, 100).Return(nil, nil)
-	mockProduct.On("GetByID", mock.Anything, 10).Return(&models.Product{ID: 10, Type: 1}, nil)
+	mockEntitlement.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(&models.UserEntitlement{
+		ID: 5, OrderID: 100, Kind: models.BenefitKindOliveBranch, Total: 1, Status: models.EntitlementStatusActive,
+	}, nil)
 
-	repo := &repository.Repository{
-		Order:          mockOrder,
-		Project:        mockProject,
-		Product:        mockProduct,
-		EmailPromotion: mockEmailPromotion,
-	}
+	repo := newTxTestRepo()
+	repo.Order = mockOrder
+	repo.Project = mockProject
+	repo.Product = mockProduct
+	repo.EmailPromotion = mockEmailPromotion
+	repo.Entitlement = mockEntitlement
 
 	svc := NewEmailPromotionService(repo)
 	_, err := svc.TriggerPromotion(context.Background(), 1, 100, 200)
@@ -371,8 +445,8 @@ func TestTriggerPromotion_NoEmailPromotionProduct(t *testing.T) {
 	assertServiceError(t, err, ErrCodeBadRequest, "订单中没有邮件推广商品")
 	mockOrder.AssertExpectations(t)
 	mockProject.AssertExpectations(t)
-	mockEmailPromotion.AssertExpectations(t)
-	mockProduct.AssertExpectations(t)
+	mockEmailPromotion.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+	mockEntitlement.AssertNotCalled(t, "ConsumeTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestTriggerPromotion_Success(t *testing.T) {
@@ -380,6 +454,7 @@ func TestTriggerPromotion_Success(t *testing.T) {
 	mockProject := new(MockProjectRepo)
 	mockProduct := new(MockProductRepo)
 	mockEmailPromotion := new(MockEmailPromotionRepo)
+	mockEntitlement := new(MockEntitlementRepo)
 
 	order := &models.Order{
 		ID:        100,
@@ -391,23 +466,22 @@ func TestTriggerPromotion_Success(t *testing.T) {
 
 	mockOrder.On("GetByID", mock.Anything, 100).Return(order, nil)
 	mockProject.On("GetByID", mock.Anything, 200).Return(&models.Project{ID: 200, CreatorID: 1}, nil)
-	mockEmailPromotion.On("GetByOrderID", mock.Anything, 100).Return(nil, nil)
-	mockProduct.On("GetByID", mock.Anything, 10).Return(&models.Product{ID: 10, Type: 2}, nil)
-	mockEmailPromotion.On("Create", mock.Anything, mock.MatchedBy(func(p *models.EmailPromotion) bool {
+	mockEmailPromotion.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(nil, nil)
+	mockEntitlement.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(emailEntitlement(100, 50), nil)
+	mockEntitlement.On("ConsumeTx", mock.Anything, mock.Anything, 5, 50).Return(true, nil)
+	mockEmailPromotion.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(p *models.EmailPromotion) bool {
 		return p.OrderID == 100 && p.ProjectID == 200 && p.CreatorID == 1 && p.MaxRecipients == 50
 	})).Run(func(args mock.Arguments) {
-		promotion := args.Get(1).(*models.EmailPromotion)
+		promotion := args.Get(2).(*models.EmailPromotion)
 		promotion.ID = 1
 	}).Return(nil)
-	// Mock the Update call that happens in the async goroutine
-	mockEmailPromotion.On("Update", mock.Anything, mock.Anything).Return(nil).Maybe()
 
-	repo := &repository.Repository{
-		Order:          mockOrder,
-		Project:        mockProject,
-		Product:        mockProduct,
-		EmailPromotion: mockEmailPromotion,
-	}
+	repo := newTxTestRepo()
+	repo.Order = mockOrder
+	repo.Project = mockProject
+	repo.Product = mockProduct
+	repo.EmailPromotion = mockEmailPromotion
+	repo.Entitlement = mockEntitlement
 
 	svc := NewEmailPromotionService(repo)
 	result, err := svc.TriggerPromotion(context.Background(), 1, 100, 200)
@@ -423,7 +497,7 @@ func TestTriggerPromotion_Success(t *testing.T) {
 	mockOrder.AssertExpectations(t)
 	mockProject.AssertExpectations(t)
 	mockEmailPromotion.AssertExpectations(t)
-	mockProduct.AssertExpectations(t)
+	mockEntitlement.AssertExpectations(t)
 }
 
 func TestTriggerPromotion_CreateFails(t *testing.T) {
@@ -431,6 +505,7 @@ func TestTriggerPromotion_CreateFails(t *testing.T) {
 	mockProject := new(MockProjectRepo)
 	mockProduct := new(MockProductRepo)
 	mockEmailPromotion := new(MockEmailPromotionRepo)
+	mockEntitlement := new(MockEntitlementRepo)
 
 	order := &models.Order{
 		ID:        100,
@@ -442,16 +517,17 @@ func TestTriggerPromotion_CreateFails(t *testing.T) {
 
 	mockOrder.On("GetByID", mock.Anything, 100).Return(order, nil)
 	mockProject.On("GetByID", mock.Anything, 200).Return(&models.Project{ID: 200, CreatorID: 1}, nil)
-	mockEmailPromotion.On("GetByOrderID", mock.Anything, 100).Return(nil, nil)
-	mockProduct.On("GetByID", mock.Anything, 10).Return(&models.Product{ID: 10, Type: 2}, nil)
-	mockEmailPromotion.On("Create", mock.Anything, mock.Anything).Return(errors.New("db write error"))
-
-	repo := &repository.Repository{
-		Order:          mockOrder,
-		Project:        mockProject,
-		Product:        mockProduct,
-		EmailPromotion: mockEmailPromotion,
-	}
+	mockEmailPromotion.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(nil, nil)
+	mockEntitlement.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(emailEntitlement(100, 50), nil)
+	mockEntitlement.On("ConsumeTx", mock.Anything, mock.Anything, 5, 50).Return(true, nil)
+	mockEmailPromotion.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db write error"))
+
+	repo := newTxTestRepo()
+	repo.Order = mockOrder
+	repo.Project = mockProject
+	repo.Product = mockProduct
+	repo.EmailPromotion = mockEmailPromotion
+	repo.Entitlement = mockEntitlement
 
 	svc := NewEmailPromotionService(repo)
 	_, err := svc.TriggerPromotion(context.Background(), 1, 100, 200)
@@ -460,7 +536,7 @@ func TestTriggerPromotion_CreateFails(t *testing.T) {
 	mockOrder.AssertExpectations(t)
 	mockProject.AssertExpectations(t)
 	mockEmailPromotion.AssertExpectations(t)
-	mockProduct.AssertExpectations(t)
+	mockEntitlement.AssertExpectations(t)
 }
 
 // --- Tests for GetStatus ---
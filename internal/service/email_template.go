@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/trv3wood/kuaizu-server/internal/email"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// templateCodePattern 模板编码只允许小写字母、数字和下划线
+var templateCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+// EmailTemplateService handles email template management.
+type EmailTemplateService struct {
+	repo     *repository.Repository
+	renderer *email.TemplateRenderer
+}
+
+// NewEmailTemplateService creates a new EmailTemplateService.
+func NewEmailTemplateService(repo *repository.Repository) *EmailTemplateService {
+	return &EmailTemplateService{
+		repo:     repo,
+		renderer: email.NewTemplateRendererFromEnv(repo.EmailTemplate),
+	}
+}
+
+// EmailTemplateListResult holds a page of email templates with pagination info.
+type EmailTemplateListResult struct {
+	List       []models.EmailTemplate
+	Total      int64
+	TotalPages int
+	Page       int
+	Size       int
+}
+
+// EmailTemplateInput contains the editable fields of an email template.
+type EmailTemplateInput struct {
+	TemplateCode string
+	TemplateName string
+	Subject      string
+	HTMLContent  string
+	TextContent  *string
+	Description  *string
+	IsActive     *bool
+}
+
+// ListTemplates returns a paginated list of email templates.
+func (s *EmailTemplateService) ListTemplates(ctx context.Context, params repository.EmailTemplateListParams) (*EmailTemplateListResult, error) {
+	params.Page, params.Size = normalizePageParams(params.Page, params.Size)
+
+	templates, total, err := s.repo.EmailTemplate.List(ctx, params)
+	if err != nil {
+		log.Printf("[EmailTemplateService.ListTemplates] repository error: %v", err)
+		return nil, ErrInternal("获取邮件模板列表失败")
+	}
+
+	totalPages := int((total + int64(params.Size) - 1) / int64(params.Size))
+	return &EmailTemplateListResult{
+		List:       templates,
+		Total:      total,
+		TotalPages: totalPages,
+		Page:       params.Page,
+		Size:       params.Size,
+	}, nil
+}
+
+// GetTemplate retrieves an email template by ID.
+func (s *EmailTemplateService) GetTemplate(ctx context.Context, id int) (*models.EmailTemplate, error) {
+	t, err := s.repo.EmailTemplate.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("[EmailTemplateService.GetTemplate] repository error: %v", err)
+		return nil, ErrInternal("获取邮件模板失败")
+	}
+	if t == nil {
+		return nil, ErrNotFound("邮件模板不存在")
+	}
+	return t, nil
+}
+
+// CreateTemplate validates and creates a new email template.
+func (s *EmailTemplateService) CreateTemplate(ctx context.Context, input EmailTemplateInput) (*models.EmailTemplate, error) {
+	input.TemplateCode = strings.TrimSpace(input.TemplateCode)
+	if !templateCodePattern.MatchString(input.TemplateCode) {
+		return nil, ErrBadRequest("模板编码只能包含小写字母、数字和下划线，且以字母开头")
+	}
+
+	existing, err := s.repo.EmailTemplate.GetByCode(ctx, input.TemplateCode)
+	if err != nil {
+		log.Printf("[EmailTemplateService.CreateTemplate] repository error: %v", err)
+		return nil, ErrInternal("创建邮件模板失败")
+	}
+	if existing != nil {
+		return nil, ErrBadRequest("模板编码已存在")
+	}
+
+	t := &models.EmailTemplate{
+		TemplateCode: input.TemplateCode,
+		IsActive:     true,
+	}
+	if err := s.applyInput(t, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.EmailTemplate.Create(ctx, t); err != nil {
+		log.Printf("[EmailTemplateService.CreateTemplate] repository error: %v", err)
+		return nil, ErrInternal("创建邮件模板失败")
+	}
+
+	s.renderer.Invalidate(t.TemplateCode)
+	return s.GetTemplate(ctx, t.ID)
+}
+
+// UpdateTemplate validates and updates an email template. The template code is immutable.
+func (s *EmailTemplateService) UpdateTemplate(ctx context.Context, id int, input EmailTemplateInput) (*models.EmailTemplate, error) {
+	t, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.TemplateCode != "" && input.TemplateCode != t.TemplateCode {
+		return nil, ErrBadRequest("模板编码不可修改")
+	}
+
+	if err := s.applyInput(t, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.EmailTemplate.Update(ctx, t); err != nil {
+		log.Printf("[EmailTemplateService.UpdateTemplate] repository error: %v", err)
+		return nil, ErrInternal("更新邮件模板失败")
+	}
+
+	s.renderer.Invalidate(t.TemplateCode)
+	return s.GetTemplate(ctx, id)
+}
+
+// DeleteTemplate deletes an email template. Built-in codes fall back to the default template.
+func (s *EmailTemplateService) DeleteTemplate(ctx context.Context, id int) error {
+	t, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.EmailTemplate.Delete(ctx, id); err != nil {
+		log.Printf("[EmailTemplateService.DeleteTemplate] repository error: %v", err)
+		return ErrInternal("删除邮件模板失败")
+	}
+
+	s.renderer.Invalidate(t.TemplateCode)
+	return nil
+}
+
+// PreviewTemplate renders a stored template with sample data overridden by vars.
+func (s *EmailTemplateService) PreviewTemplate(ctx context.Context, id int, vars map[string]interface{}) (*email.RenderedEmail, error) {
+	t, err := s.GetTemplate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.previewData(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := s.renderer.Preview(t, data)
+	if err != nil {
+		return nil, ErrBadRequest("模板渲染失败: " + err.Error())
+	}
+	return rendered, nil
+}
+
+// applyInput copies the editable fields into t and checks the template compiles.
+func (s *EmailTemplateService) applyInput(t *models.EmailTemplate, input EmailTemplateInput) error {
+	input.TemplateName = strings.TrimSpace(input.TemplateName)
+	input.Subject = strings.TrimSpace(input.Subject)
+	if input.TemplateName == "" {
+		return ErrBadRequest("模板名称不能为空")
+	}
+	if input.Subject == "" {
+		return ErrBadRequest("邮件主题不能为空")
+	}
+	if strings.TrimSpace(input.HTMLContent) == "" {
+		return ErrBadRequest("HTML模板内容不能为空")
+	}
+
+	t.TemplateName = input.TemplateName
+	t.Subject = input.Subject
+	t.HTMLContent = &input.HTMLContent
+	t.TextContent = input.TextContent
+	t.Description = input.Description
+	if input.IsActive != nil {
+		t.IsActive = *input.IsActive
+	}
+
+	if err := s.renderer.Validate(t); err != nil {
+		return ErrBadRequest("模板语法错误: " + err.Error())
+	}
+	return nil
+}
+
+// previewData merges caller-supplied variables over the sample promotion data.
+func (s *EmailTemplateService) previewData(vars map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(s.renderer.ProjectPromotionSampleData())
+	if err != nil {
+		return nil, ErrInternal("生成预览数据失败")
+	}
+
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, ErrInternal("生成预览数据失败")
+	}
+	for k, v := range vars {
+		data[k] = v
+	}
+	return data, nil
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// FavoriteService handles saved projects and talent profiles.
+type FavoriteService struct {
+	repo   *repository.Repository
+	events EventPublisher
+}
+
+// NewFavoriteService creates a new FavoriteService.
+func NewFavoriteService(repo *repository.Repository, events EventPublisher) *FavoriteService {
+	return &FavoriteService{repo: repo, events: events}
+}
+
+// TalentListResult holds a page of talent profiles with pagination info.
+type TalentListResult struct {
+	List       []models.TalentProfile
+	Total      int64
+	TotalPages int
+	Page       int
+	Size       int
+}
+
+// favoriteNotices 为收藏了目标的用户生成通知，跳过触发变更的用户本人
+func favoriteNotices(userIDs []int, actorID int, n notice) []notice {
+	notices := make([]notice, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id == actorID {
+			continue
+		}
+		n.UserID = id
+		notices = append(notices, n)
+	}
+	return notices
+}
+
+// AddProject saves a project for the user. Saving it again is a no-op.
+func (s *FavoriteService) AddProject(ctx context.Context, userID, projectID int) error {
+	project, err := s.repo.Project.GetByID(ctx, projectID)
+	if err != nil {
+		log.Printf("[FavoriteService.AddProject] repository error getting project: %v", err)
+		return ErrInternal("获取项目失败")
+	}
+	if project == nil {
+		return ErrNotFound("项目不存在")
+	}
+
+	if err := s.repo.Favorite.Add(ctx, userID, models.FavoriteTargetProject, projectID); err != nil {
+		log.Printf("[FavoriteService.AddProject] repository error: %v", err)
+		return ErrInternal("收藏失败")
+	}
+	return nil
+}
+
+// AddTalent saves a talent profile for the user. Saving it again is a no-op.
+func (s *FavoriteService) AddTalent(ctx context.Context, userID, talentProfileID int) error {
+	profile, err := s.repo.TalentProfile.GetByID(ctx, talentProfileID)
+	if err != nil {
+		log.Printf("[FavoriteService.AddTalent] repository error getting talent profile: %v", err)
+		return ErrInternal("获取人才档案失败")
+	}
+	if profile == nil {
+		return ErrNotFound("人才档案不存在")
+	}
+
+	if err := s.repo.Favorite.Add(ctx, userID, models.FavoriteTargetTalent, talentProfileID); err != nil {
+		log.Printf("[FavoriteService.AddTalent] repository error: %v", err)
+		return ErrInternal("收藏失败")
+	}
+	return nil
+}
+
+// Remove deletes the user's favorite of a project or talent profile.
+func (s *FavoriteService) Remove(ctx context.Context, userID int, targetType string, targetID int) error {
+	if err := s.repo.Favorite.Remove(ctx, userID, targetType, targetID); err != nil {
+		log.Printf("[FavoriteService.Remove] repository error: %v", err)
+		return ErrInternal("取消收藏失败")
+	}
+	return nil
+}
+
+// ListProjects returns the user's saved projects, most recently saved first.
+// Closed projects are kept so the user can see what happened to them.
+func (s *FavoriteService) ListProjects(ctx context.Context, userID, page, size int) (*ProjectListResult, error) {
+	page, size = normalizePageParams(page, size)
+
+	projects, total, err := s.repo.Project.List(ctx, repository.ListParams{
+		Page:           page,
+		Size:           size,
+		FavoriteUserID: &userID,
+	})
+	if err != nil {
+		log.Printf("[FavoriteService.ListProjects] repository error: %v", err)
+		return nil, ErrInternal("获取收藏的项目失败")
+	}
+	for i := range projects {
+		projects[i].Favorited = true
+	}
+
+	totalPages := int((total + int64(size) - 1) / int64(size))
+	return &ProjectListResult{
+		List:       projects,
+		Total:      total,
+		TotalPages: totalPages,
+		Page:       page,
+		Size:       size,
+	}, nil
+}
+
+// ListTalents returns the user's saved talent profiles that are online, most
+// recently saved first.
+func (s *FavoriteService) ListTalents(ctx context.Context, userID, page, size int) (*TalentListResult, error) {
+	page, size = normalizePageParams(page, size)
+
+	profiles, total, err := s.repo.TalentProfile.List(ctx, repository.TalentProfileListParams{
+		Page:           page,
+		Size:           size,
+		FavoriteUserID: &userID,
+	})
+	if err != nil {
+		log.Printf("[FavoriteService.ListTalents] repository error: %v", err)
+		return nil, ErrInternal("获取收藏的人才失败")
+	}
+	for i := range profiles {
+		profiles[i].Favorited = true
+	}
+
+	totalPages := int((total + int64(size) - 1) / int64(size))
+	return &TalentListResult{
+		List:       profiles,
+		Total:      total,
+		TotalPages: totalPages,
+		Page:       page,
+		Size:       size,
+	}, nil
+}
+
+// MarkProjects sets the favorited flag of the projects for the user. Anonymous
+// requests (userID 0) are skipped; failures are logged and leave the flag unset.
+func (s *FavoriteService) MarkProjects(ctx context.Context, userID int, projects []models.Project) {
+	if userID == 0 || len(projects) == 0 {
+		return
+	}
+	ids := make([]int, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID
+	}
+	favorited, err := s.repo.Favorite.ListFavorited(ctx, userID, models.FavoriteTargetProject, ids)
+	if err != nil {
+		log.Printf("[FavoriteService.MarkProjects] repository error: %v", err)
+		return
+	}
+	for i := range projects {
+		projects[i].Favorited = favorited[projects[i].ID]
+	}
+}
+
+// MarkTalents sets the favorited flag of the talent profiles for the user.
+// Anonymous requests (userID 0) are skipped; failures are logged and leave the
+// flag unset.
+func (s *FavoriteService) MarkTalents(ctx context.Context, userID int, profiles []models.TalentProfile) {
+	if userID == 0 || len(profiles) == 0 {
+		return
+	}
+	ids := make([]int, len(profiles))
+	for i := range profiles {
+		ids[i] = profiles[i].ID
+	}
+	favorited, err := s.repo.Favorite.ListFavorited(ctx, userID, models.FavoriteTargetTalent, ids)
+	if err != nil {
+		log.Printf("[FavoriteService.MarkTalents] repository error: %v", err)
+		return
+	}
+	for i := range profiles {
+		profiles[i].Favorited = favorited[profiles[i].ID]
+	}
+}
+
+// TalentOfflineTx notifies the users who saved the talent profile that it went
+// offline, within the transaction that took it offline. With removed set the
+// profile was deleted and its favorites are dropped as well. The created
+// notifications are returned for publishing once the transaction commits.
+func (s *FavoriteService) TalentOfflineTx(ctx context.Context, tx *sqlx.Tx, profile *models.TalentProfile, removed bool) ([]*models.Notification, error) {
+	userIDs, err := s.repo.Favorite.ListUserIDs(ctx, models.FavoriteTargetTalent, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := "该用户"
+	if profile.Nickname != nil && *profile.Nickname != "" {
+		name = *profile.Nickname
+	}
+	notices := favoriteNotices(userIDs, profile.UserID, notice{
+		Type:      models.NotificationTypeFavoriteOffline,
+		Title:     "收藏的人才已下线",
+		Content:   fmt.Sprintf("您收藏的人才「%s」已下线，不再在人才库中展示。", name),
+		RelatedID: &profile.ID,
+	})
+
+	var notifications []*models.Notification
+	if len(notices) > 0 {
+		if notifications, err = notifyBatchTx(ctx, tx, s.repo, notices); err != nil {
+			return nil, err
+		}
+	}
+	if removed {
+		if err := s.repo.Favorite.DeleteByTargetTx(ctx, tx, models.FavoriteTargetTalent, profile.ID); err != nil {
+			return nil, err
+		}
+	}
+	return notifications, nil
+}
+
+// publish pushes committed notifications to the users' open connections
+func (s *FavoriteService) publish(ctx context.Context, notifications []*models.Notification) {
+	for _, n := range notifications {
+		publishNotification(ctx, s.events, n)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockFavoriteRepo struct {
+	repository.FavoriteRepo
+	mock.Mock
+}
+
+func (m *MockFavoriteRepo) Add(ctx context.Context, userID int, targetType string, targetID int) error {
+	args := m.Called(ctx, userID, targetType, targetID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepo) DeleteByTargetTx(ctx context.Context, tx *sqlx.Tx, targetType string, targetID int) error {
+	args := m.Called(ctx, tx, targetType, targetID)
+	return args.Error(0)
+}
+
+func (m *MockFavoriteRepo) ListFavorited(ctx context.Context, userID int, targetType string, targetIDs []int) (map[int]bool, error) {
+	args := m.Called(ctx, userID, targetType, targetIDs)
+	return args.Get(0).(map[int]bool), args.Error(1)
+}
+
+func (m *MockFavoriteRepo) ListUserIDs(ctx context.Context, targetType string, targetID int) ([]int, error) {
+	args := m.Called(ctx, targetType, targetID)
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func TestCloseProject_NotifiesFavoritersExceptLeader(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组", Status: models.ProjectStatusApproved}, nil)
+	mockProject.On("UpdateStatusTx", mock.Anything, mock.Anything, 1, models.ProjectStatusClosed).Return(nil)
+	mockFavorite := new(MockFavoriteRepo)
+	mockFavorite.On("ListUserIDs", mock.Anything, models.FavoriteTargetProject, 1).Return([]int{10, 20, 11}, nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateBatchTx", mock.Anything, mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		return len(ns) == 2 && ns[0].UserID == 10 && ns[1].UserID == 11 &&
+			ns[0].Type == models.NotificationTypeFavoriteClosed && *ns[0].RelatedID == 1
+	})).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateBatchTx", mock.Anything, mock.Anything, ([]*models.MessageOutbox)(nil)).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Project = mockProject
+	repo.Favorite = mockFavorite
+	repo.Notification = mockNotification
+	repo.MessageOutbox = mockOutbox
+	events := &stubPublisher{}
+
+	project, err := NewProjectService(repo, nil, events, nil, nil).CloseProject(context.Background(), 1, 20)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ProjectStatusClosed, project.Status)
+	mockProject.AssertExpectations(t)
+	mockNotification.AssertExpectations(t)
+	require.Len(t, events.published, 2)
+	assert.Equal(t, 11, events.published[1].userID)
+}
+
+func TestCloseProject_NotOwner(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Status: models.ProjectStatusApproved}, nil)
+
+	repo := &repository.Repository{Project: mockProject}
+	_, err := NewProjectService(repo, nil, nil, nil, nil).CloseProject(context.Background(), 1, 30)
+
+	assertServiceError(t, err, ErrCodeForbidden, "只有队长可以关闭项目")
+	mockProject.AssertNotCalled(t, "UpdateStatusTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCloseProject_AlreadyClosed(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Status: models.ProjectStatusClosed}, nil)
+
+	repo := &repository.Repository{Project: mockProject}
+	_, err := NewProjectService(repo, nil, nil, nil, nil).CloseProject(context.Background(), 1, 20)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "项目已关闭")
+}
+
+func TestMarkProjects(t *testing.T) {
+	mockFavorite := new(MockFavoriteRepo)
+	mockFavorite.On("ListFavorited", mock.Anything, 10, models.FavoriteTargetProject, []int{1, 2}).Return(map[int]bool{2: true}, nil)
+
+	svc := NewFavoriteService(&repository.Repository{Favorite: mockFavorite}, nil)
+	projects := []models.Project{{ID: 1}, {ID: 2}}
+
+	svc.MarkProjects(context.Background(), 0, projects)
+	mockFavorite.AssertNotCalled(t, "ListFavorited", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	svc.MarkProjects(context.Background(), 10, projects)
+	assert.False(t, projects[0].Favorited)
+	assert.True(t, projects[1].Favorited)
+}
+
+func TestAddProject_NotFound(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 404).Return(nil, nil)
+	mockFavorite := new(MockFavoriteRepo)
+
+	repo := &repository.Repository{Project: mockProject, Favorite: mockFavorite}
+	err := NewFavoriteService(repo, nil).AddProject(context.Background(), 10, 404)
+
+	assertServiceError(t, err, ErrCodeNotFound, "项目不存在")
+	mockFavorite.AssertNotCalled(t, "Add", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -2,21 +2,29 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"mime/multipart"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/trv3wood/kuaizu-server/internal/models"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 )
 
+// maxFeedbackImages 每条反馈或消息最多附带的截图数
+const maxFeedbackImages = 3
+
 // FeedbackService handles feedback-related business logic.
 type FeedbackService struct {
 	repo    *repository.Repository
-	message *MessageService
+	commons *CommonsService
+	events  EventPublisher
 }
 
 // NewFeedbackService creates a new FeedbackService.
-func NewFeedbackService(repo *repository.Repository, message *MessageService) *FeedbackService {
-	return &FeedbackService{repo: repo, message: message}
+func NewFeedbackService(repo *repository.Repository, commons *CommonsService, events EventPublisher) *FeedbackService {
+	return &FeedbackService{repo: repo, commons: commons, events: events}
 }
 
 // FeedbackListResult holds a page of feedbacks with pagination info.
@@ -48,7 +56,109 @@ func (s *FeedbackService) ListFeedbacks(ctx context.Context, params repository.F
 	}, nil
 }
 
-// GetFeedback retrieves a feedback by ID.
+// FeedbackInput is the DTO for submitting feedback or a follow-up message.
+type FeedbackInput struct {
+	UserID  int
+	Content string
+	Images  []*multipart.FileHeader
+}
+
+// uploadImages uploads the screenshots of a feedback or message.
+func (s *FeedbackService) uploadImages(images []*multipart.FileHeader) ([]string, error) {
+	if len(images) > maxFeedbackImages {
+		return nil, ErrBadRequest(fmt.Sprintf("最多上传%d张截图", maxFeedbackImages))
+	}
+	if len(images) == 0 {
+		return nil, nil
+	}
+	return s.commons.UploadImages(images)
+}
+
+// SubmitFeedback uploads the screenshots and creates a feedback.
+func (s *FeedbackService) SubmitFeedback(ctx context.Context, input FeedbackInput) (*models.Feedback, error) {
+	keys, err := s.uploadImages(input.Images)
+	if err != nil {
+		return nil, err
+	}
+
+	fb := &models.Feedback{
+		UserID:       input.UserID,
+		Content:      input.Content,
+		ContactImage: models.JoinImageKeys(keys),
+		Status:       models.FeedbackStatusPending,
+	}
+	if err := s.repo.Feedback.Create(ctx, fb); err != nil {
+		log.Printf("[FeedbackService.SubmitFeedback] repository error: %v", err)
+		s.commons.DeleteFiles(keys)
+		return nil, ErrInternal("提交反馈失败")
+	}
+
+	created, err := s.repo.Feedback.GetByID(ctx, fb.ID)
+	if err != nil || created == nil {
+		log.Printf("[FeedbackService.SubmitFeedback] repository error reloading feedback: %v", err)
+		return fb, nil
+	}
+	return created, nil
+}
+
+// ListMyFeedbacks returns the feedbacks submitted by the user, newest first.
+func (s *FeedbackService) ListMyFeedbacks(ctx context.Context, userID, page, size int) (*FeedbackListResult, error) {
+	return s.ListFeedbacks(ctx, repository.FeedbackListParams{
+		Page:   page,
+		Size:   size,
+		UserID: &userID,
+	})
+}
+
+// GetMyFeedback returns a feedback of the user with its messages.
+func (s *FeedbackService) GetMyFeedback(ctx context.Context, userID, id int) (*models.Feedback, error) {
+	fb, err := s.GetFeedback(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if fb.UserID != userID {
+		return nil, ErrNotFound("反馈不存在")
+	}
+	return fb, nil
+}
+
+// AddUserMessage appends a follow-up message from the user to their feedback,
+// which becomes pending again.
+func (s *FeedbackService) AddUserMessage(ctx context.Context, id int, input FeedbackInput) (*models.FeedbackMessage, error) {
+	fb, err := s.repo.Feedback.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("[FeedbackService.AddUserMessage] repository error: %v", err)
+		return nil, ErrInternal("获取反馈信息失败")
+	}
+	if fb == nil || fb.UserID != input.UserID {
+		return nil, ErrNotFound("反馈不存在")
+	}
+
+	keys, err := s.uploadImages(input.Images)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &models.FeedbackMessage{
+		FeedbackID: id,
+		SenderType: models.FeedbackSenderUser,
+		SenderID:   &input.UserID,
+		Content:    input.Content,
+		Images:     models.JoinImageKeys(keys),
+	}
+	err = runInTx(ctx, s.repo, "FeedbackService.AddUserMessage", "发送消息失败", func(tx *sqlx.Tx) error {
+		return s.repo.Feedback.AddMessageTx(ctx, tx, msg, models.FeedbackStatusPending)
+	})
+	if err != nil {
+		s.commons.DeleteFiles(keys)
+		return nil, err
+	}
+
+	msg.CreatedAt = time.Now()
+	return msg, nil
+}
+
+// GetFeedback retrieves a feedback by ID with its messages.
 func (s *FeedbackService) GetFeedback(ctx context.Context, id int) (*models.Feedback, error) {
 	fb, err := s.repo.Feedback.GetByID(ctx, id)
 	if err != nil {
@@ -58,11 +168,22 @@ func (s *FeedbackService) GetFeedback(ctx context.Context, id int) (*models.Feed
 	if fb == nil {
 		return nil, ErrNotFound("反馈不存在")
 	}
+
+	messages, err := s.repo.Feedback.ListMessages(ctx, id)
+	if err != nil {
+		log.Printf("[FeedbackService.GetFeedback] repository error listing messages: %v", err)
+		return nil, ErrInternal("获取反馈详情失败")
+	}
+	fb.Messages = messages
+	if fb.Messages == nil {
+		fb.Messages = []models.FeedbackMessage{}
+	}
 	return fb, nil
 }
 
-// ReplyFeedback (admin only) replies to a feedback and notifies user.
-func (s *FeedbackService) ReplyFeedback(ctx context.Context, id int, reply string) error {
+// ReplyFeedback (admin only) appends an admin message to a feedback, marks it
+// handled and notifies the user.
+func (s *FeedbackService) ReplyFeedback(ctx context.Context, adminID, id int, reply string) error {
 	fb, err := s.repo.Feedback.GetByID(ctx, id)
 	if err != nil {
 		log.Printf("[FeedbackService.ReplyFeedback] repository error: %v", err)
@@ -72,10 +193,38 @@ func (s *FeedbackService) ReplyFeedback(ctx context.Context, id int, reply strin
 		return ErrNotFound("反馈不存在")
 	}
 
-	if err := s.repo.Feedback.Reply(ctx, id, reply); err != nil {
-		log.Printf("[FeedbackService.ReplyFeedback] repository error: %v", err)
-		return ErrInternal("回复反馈失败")
+	// 回复反馈，并在同一事务中通知用户
+	var notification *models.Notification
+	err = runInTx(ctx, s.repo, "FeedbackService.ReplyFeedback", "回复反馈失败", func(tx *sqlx.Tx) (err error) {
+		msg := &models.FeedbackMessage{
+			FeedbackID: id,
+			SenderType: models.FeedbackSenderAdmin,
+			Content:    reply,
+		}
+		if adminID > 0 {
+			msg.SenderID = &adminID
+		}
+		if err := s.repo.Feedback.AddMessageTx(ctx, tx, msg, models.FeedbackStatusDone); err != nil {
+			return err
+		}
+		notification, err = notifyTx(ctx, tx, s.repo, notice{
+			UserID:    fb.UserID,
+			Type:      models.NotificationTypeFeedbackReply,
+			Title:     "您的反馈已收到回复",
+			Content:   reply,
+			RelatedID: &fb.ID,
+			BizKey:    models.MsgBizKeyUserReply,
+			Data: map[string]string{
+				"content": truncateRunes(fb.Content, 20),
+				"reply":   truncateRunes(reply, 20),
+				"remark":  "您的反馈已收到回复，感谢您的支持。",
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return err
 	}
-
+	publishNotification(ctx, s.events, notification)
 	return nil
 }
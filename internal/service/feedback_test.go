@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"mime/multipart"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockFeedbackRepo struct {
+	repository.FeedbackRepo
+	mock.Mock
+}
+
+func (m *MockFeedbackRepo) GetByID(ctx context.Context, id int) (*models.Feedback, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Feedback), args.Error(1)
+}
+
+func (m *MockFeedbackRepo) ListMessages(ctx context.Context, feedbackID int) ([]models.FeedbackMessage, error) {
+	args := m.Called(ctx, feedbackID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.FeedbackMessage), args.Error(1)
+}
+
+func (m *MockFeedbackRepo) AddMessageTx(ctx context.Context, tx *sqlx.Tx, msg *models.FeedbackMessage, status int) error {
+	args := m.Called(ctx, tx, msg, status)
+	return args.Error(0)
+}
+
+func TestSubmitFeedback_TooManyImages(t *testing.T) {
+	mockFeedback := new(MockFeedbackRepo)
+	repo := &repository.Repository{Feedback: mockFeedback}
+
+	_, err := NewFeedbackService(repo, nil, nil).SubmitFeedback(context.Background(), FeedbackInput{
+		UserID:  10,
+		Content: "页面打不开",
+		Images:  make([]*multipart.FileHeader, maxFeedbackImages+1),
+	})
+
+	assertServiceError(t, err, ErrCodeBadRequest, "最多上传3张截图")
+	mockFeedback.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestGetMyFeedback_NotOwned(t *testing.T) {
+	mockFeedback := new(MockFeedbackRepo)
+	mockFeedback.On("GetByID", mock.Anything, 1).Return(&models.Feedback{ID: 1, UserID: 20}, nil)
+	mockFeedback.On("ListMessages", mock.Anything, 1).Return([]models.FeedbackMessage{}, nil)
+
+	repo := &repository.Repository{Feedback: mockFeedback}
+	_, err := NewFeedbackService(repo, nil, nil).GetMyFeedback(context.Background(), 10, 1)
+
+	assertServiceError(t, err, ErrCodeNotFound, "反馈不存在")
+}
+
+func TestAddUserMessage_ReopensFeedback(t *testing.T) {
+	mockFeedback := new(MockFeedbackRepo)
+	mockFeedback.On("GetByID", mock.Anything, 1).Return(&models.Feedback{ID: 1, UserID: 10, Status: models.FeedbackStatusDone}, nil)
+	mockFeedback.On("AddMessageTx", mock.Anything, mock.Anything, mock.MatchedBy(func(m *models.FeedbackMessage) bool {
+		return m.FeedbackID == 1 && m.SenderType == models.FeedbackSenderUser && *m.SenderID == 10 && m.Images == nil
+	}), models.FeedbackStatusPending).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Feedback = mockFeedback
+
+	msg, err := NewFeedbackService(repo, nil, nil).AddUserMessage(context.Background(), 1, FeedbackInput{
+		UserID:  10,
+		Content: "问题还在",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "问题还在", msg.Content)
+	mockFeedback.AssertExpectations(t)
+}
+
+func TestAddUserMessage_NotOwned(t *testing.T) {
+	mockFeedback := new(MockFeedbackRepo)
+	mockFeedback.On("GetByID", mock.Anything, 1).Return(&models.Feedback{ID: 1, UserID: 20}, nil)
+
+	repo := &repository.Repository{Feedback: mockFeedback}
+	_, err := NewFeedbackService(repo, nil, nil).AddUserMessage(context.Background(), 1, FeedbackInput{
+		UserID:  10,
+		Content: "问题还在",
+	})
+
+	assertServiceError(t, err, ErrCodeNotFound, "反馈不存在")
+	mockFeedback.AssertNotCalled(t, "AddMessageTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReplyFeedback_AppendsAdminMessageAndNotifies(t *testing.T) {
+	mockFeedback := new(MockFeedbackRepo)
+	mockFeedback.On("GetByID", mock.Anything, 1).Return(&models.Feedback{ID: 1, UserID: 10, Content: "页面打不开"}, nil)
+	mockFeedback.On("AddMessageTx", mock.Anything, mock.Anything, mock.MatchedBy(func(m *models.FeedbackMessage) bool {
+		return m.SenderType == models.FeedbackSenderAdmin && *m.SenderID == 3 && m.Content == "已修复"
+	}), models.FeedbackStatusDone).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 10 && n.Type == models.NotificationTypeFeedbackReply
+	})).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Feedback = mockFeedback
+	repo.Notification = mockNotification
+	repo.MessageOutbox = mockOutbox
+	events := &stubPublisher{}
+
+	err := NewFeedbackService(repo, nil, events).ReplyFeedback(context.Background(), 3, 1, "已修复")
+
+	require.NoError(t, err)
+	mockFeedback.AssertExpectations(t)
+	mockNotification.AssertExpectations(t)
+	require.Len(t, queuedMessages(mockOutbox), 1)
+	require.Len(t, events.published, 1)
+	assert.Equal(t, 10, events.published[0].userID)
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// benefitFulfiller 按商品的权益配置发放、收回订单权益
+// 所有权益都会记入 user_entitlement 台账；橄榄枝另外直接加到用户余额上并记入橄榄枝流水，
+// 其余权益（邮件推广、项目置顶）使用时从台账中扣减。
+type benefitFulfiller struct {
+	repo *repository.Repository
+}
+
+// orderBenefit 订单对应的权益类型和总数量
+func (f benefitFulfiller) orderBenefit(ctx context.Context, order *models.Order) (string, int, error) {
+	product, err := f.repo.Product.GetByID(ctx, order.ProductID)
+	if err != nil {
+		return "", 0, err
+	}
+	if product == nil {
+		return "", 0, ErrNotFound("商品不存在")
+	}
+
+	cfg, err := product.BenefitConfig()
+	if err != nil {
+		return "", 0, err
+	}
+	return cfg.Kind, cfg.PerUnit() * order.Quantity, nil
+}
+
+// grantTx 在事务中发放订单权益并记入台账
+func (f benefitFulfiller) grantTx(ctx context.Context, tx *sqlx.Tx, order *models.Order) error {
+	kind, amount, err := f.orderBenefit(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	if kind == models.BenefitKindOliveBranch {
+		if err := f.repo.User.AddOliveBranchCountTx(ctx, tx, order.UserID, amount); err != nil {
+			return err
+		}
+		if err := f.recordOliveBranchTx(ctx, tx, order, models.OliveBranchLedgerPurchase, amount); err != nil {
+			return err
+		}
+	}
+
+	return f.repo.Entitlement.CreateTx(ctx, tx, &models.UserEntitlement{
+		UserID:  order.UserID,
+		OrderID: order.ID,
+		Kind:    kind,
+		Total:   amount,
+		Status:  models.EntitlementStatusActive,
+	})
+}
+
+// revokeTx 在事务中收回订单权益，权益已被使用时返回业务错误
+func (f benefitFulfiller) revokeTx(ctx context.Context, tx *sqlx.Tx, order *models.Order) error {
+	kind, amount, err := f.orderBenefit(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	if kind == models.BenefitKindOliveBranch {
+		deducted, err := f.repo.User.DeductOliveBranchCountTx(ctx, tx, order.UserID, amount)
+		if err != nil {
+			return err
+		}
+		if !deducted {
+			return ErrBadRequest("购买的橄榄枝已被使用，无法退款")
+		}
+		if err := f.recordOliveBranchTx(ctx, tx, order, models.OliveBranchLedgerOrderRefund, -amount); err != nil {
+			return err
+		}
+		// 台账上线前的订单没有记录，撤销结果不影响退款
+		_, err = f.repo.Entitlement.RevokeTx(ctx, tx, order.ID)
+		return err
+	}
+
+	revoked, err := f.repo.Entitlement.RevokeTx(ctx, tx, order.ID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrBadRequest("购买的权益已被使用，无法退款")
+	}
+	return nil
+}
+
+// restoreTx 在事务中恢复已收回的订单权益
+func (f benefitFulfiller) restoreTx(ctx context.Context, tx *sqlx.Tx, order *models.Order) error {
+	kind, amount, err := f.orderBenefit(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	if kind == models.BenefitKindOliveBranch {
+		if err := f.repo.User.AddOliveBranchCountTx(ctx, tx, order.UserID, amount); err != nil {
+			return err
+		}
+		if err := f.recordOliveBranchTx(ctx, tx, order, models.OliveBranchLedgerOrderRefund, amount); err != nil {
+			return err
+		}
+	}
+	return f.repo.Entitlement.RestoreTx(ctx, tx, order.ID)
+}
+
+// recordOliveBranchTx 在事务中记录订单引起的橄榄枝余额变动
+func (f benefitFulfiller) recordOliveBranchTx(ctx context.Context, tx *sqlx.Tx, order *models.Order, entryType string, amount int) error {
+	orderID := order.ID
+	return f.repo.OliveBranchLedger.CreateTx(ctx, tx, &models.OliveBranchLedger{
+		UserID:    order.UserID,
+		EntryType: entryType,
+		CostType:  models.OliveBranchCostPaid,
+		Amount:    amount,
+		OrderID:   &orderID,
+	})
+}
+
+// checkRevocable 检查订单权益是否仍可收回，用于受理退款申请前的预检查
+func (f benefitFulfiller) checkRevocable(ctx context.Context, order *models.Order) error {
+	kind, amount, err := f.orderBenefit(ctx, order)
+	if err != nil {
+		var svcErr *ServiceError
+		if errors.As(err, &svcErr) {
+			return err
+		}
+		log.Printf("[benefitFulfiller.checkRevocable] failed to get order benefit: %v", err)
+		return ErrInternal("获取商品信息失败")
+	}
+
+	if kind == models.BenefitKindOliveBranch {
+		user, err := f.repo.User.GetByID(ctx, order.UserID)
+		if err != nil || user == nil {
+			log.Printf("[benefitFulfiller.checkRevocable] repository error getting user: %v", err)
+			return ErrInternal("获取用户信息失败")
+		}
+		if user.OliveBranchCount == nil || *user.OliveBranchCount < amount {
+			return ErrBadRequest("购买的橄榄枝已被使用，无法退款")
+		}
+		return nil
+	}
+
+	entitlement, err := f.repo.Entitlement.GetByOrderID(ctx, order.ID)
+	if err != nil {
+		log.Printf("[benefitFulfiller.checkRevocable] repository error getting entitlement: %v", err)
+		return ErrInternal("获取权益信息失败")
+	}
+	if entitlement == nil || entitlement.Status != models.EntitlementStatusActive || entitlement.Used > 0 {
+		return ErrBadRequest("购买的权益已被使用，无法退款")
+	}
+	return nil
+}
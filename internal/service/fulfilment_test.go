@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockEntitlementRepo struct {
+	mock.Mock
+}
+
+func (m *MockEntitlementRepo) CreateTx(ctx context.Context, tx *sqlx.Tx, entitlement *models.UserEntitlement) error {
+	args := m.Called(ctx, tx, entitlement)
+	return args.Error(0)
+}
+
+func (m *MockEntitlementRepo) GetByOrderID(ctx context.Context, orderID int) (*models.UserEntitlement, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserEntitlement), args.Error(1)
+}
+
+func (m *MockEntitlementRepo) GetByOrderIDTx(ctx context.Context, tx *sqlx.Tx, orderID int) (*models.UserEntitlement, error) {
+	args := m.Called(ctx, tx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserEntitlement), args.Error(1)
+}
+
+func (m *MockEntitlementRepo) ConsumeTx(ctx context.Context, tx *sqlx.Tx, id int, amount int) (bool, error) {
+	args := m.Called(ctx, tx, id, amount)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEntitlementRepo) RevokeTx(ctx context.Context, tx *sqlx.Tx, orderID int) (bool, error) {
+	args := m.Called(ctx, tx, orderID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEntitlementRepo) RestoreTx(ctx context.Context, tx *sqlx.Tx, orderID int) error {
+	args := m.Called(ctx, tx, orderID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) AddOliveBranchCountTx(ctx context.Context, tx *sqlx.Tx, userID int, count int) error {
+	args := m.Called(ctx, tx, userID, count)
+	return args.Error(0)
+}
+
+func emailEntitlement(orderID, total int) *models.UserEntitlement {
+	return &models.UserEntitlement{
+		ID:      5,
+		UserID:  1,
+		OrderID: orderID,
+		Kind:    models.BenefitKindEmailPromotion,
+		Total:   total,
+		Status:  models.EntitlementStatusActive,
+	}
+}
+
+func benefitProduct(config string) *models.Product {
+	return &models.Product{ID: 3, Type: models.ProductTypeBenefit, ConfigJSON: &config}
+}
+
+// --- Tests for Product.BenefitConfig ---
+
+func TestBenefitConfig_CurrencyDefaultsToOneOliveBranch(t *testing.T) {
+	cfg, err := (&models.Product{ID: 1, Type: models.ProductTypeCurrency}).BenefitConfig()
+
+	require.NoError(t, err)
+	assert.Equal(t, models.BenefitKindOliveBranch, cfg.Kind)
+	assert.Equal(t, 1, cfg.PerUnit())
+}
+
+func TestBenefitConfig_BenefitRequiresConfig(t *testing.T) {
+	_, err := (&models.Product{ID: 2, Type: models.ProductTypeBenefit}).BenefitConfig()
+	assert.Error(t, err)
+
+	_, err = benefitProduct(`{"kind":"project_top"}`).BenefitConfig()
+	assert.Error(t, err)
+}
+
+// --- Tests for benefitFulfiller ---
+
+func TestGrantTx_EmailPromotion(t *testing.T) {
+	mockProduct := new(MockProductRepo)
+	mockEntitlement := new(MockEntitlementRepo)
+	mockProduct.On("GetByID", mock.Anything, 3).Return(benefitProduct(`{"kind":"email_promotion","recipients":50}`), nil)
+	mockEntitlement.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e *models.UserEntitlement) bool {
+		return e.UserID == 10 && e.OrderID == 1 && e.Kind == models.BenefitKindEmailPromotion &&
+			e.Total == 100 && e.Status == models.EntitlementStatusActive
+	})).Return(nil)
+
+	f := benefitFulfiller{repo: &repository.Repository{Product: mockProduct, Entitlement: mockEntitlement}}
+	err := f.grantTx(context.Background(), nil, &models.Order{ID: 1, UserID: 10, ProductID: 3, Quantity: 2})
+
+	require.NoError(t, err)
+	mockEntitlement.AssertExpectations(t)
+}
+
+func TestGrantTx_OliveBranchBundle(t *testing.T) {
+	mockProduct := new(MockProductRepo)
+	mockEntitlement := new(MockEntitlementRepo)
+	mockUser := new(MockUserRepo)
+	mockProduct.On("GetByID", mock.Anything, 3).Return(benefitProduct(`{"kind":"olive_branch","oliveBranches":10}`), nil)
+	mockUser.On("AddOliveBranchCountTx", mock.Anything, mock.Anything, 10, 30).Return(nil)
+	mockEntitlement.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e *models.UserEntitlement) bool {
+		return e.Kind == models.BenefitKindOliveBranch && e.Total == 30
+	})).Return(nil)
+	mockLedger := new(MockOliveBranchLedgerRepo)
+	mockLedger.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e *models.OliveBranchLedger) bool {
+		return e.UserID == 10 && e.EntryType == models.OliveBranchLedgerPurchase && e.Amount == 30 &&
+			e.OrderID != nil && *e.OrderID == 1
+	})).Return(nil)
+
+	f := benefitFulfiller{repo: &repository.Repository{
+		Product: mockProduct, Entitlement: mockEntitlement, User: mockUser, OliveBranchLedger: mockLedger,
+	}}
+	err := f.grantTx(context.Background(), nil, &models.Order{ID: 1, UserID: 10, ProductID: 3, Quantity: 3})
+
+	require.NoError(t, err)
+	mockUser.AssertExpectations(t)
+	mockEntitlement.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestRevokeTx_UsedEntitlement(t *testing.T) {
+	mockProduct := new(MockProductRepo)
+	mockEntitlement := new(MockEntitlementRepo)
+	mockProduct.On("GetByID", mock.Anything, 3).Return(benefitProduct(`{"kind":"project_top","days":7}`), nil)
+	mockEntitlement.On("RevokeTx", mock.Anything, mock.Anything, 1).Return(false, nil)
+
+	f := benefitFulfiller{repo: &repository.Repository{Product: mockProduct, Entitlement: mockEntitlement}}
+	err := f.revokeTx(context.Background(), nil, &models.Order{ID: 1, UserID: 10, ProductID: 3, Quantity: 1})
+
+	assertServiceError(t, err, ErrCodeBadRequest, "购买的权益已被使用，无法退款")
+}
+
+func TestCheckRevocable_PartiallyUsed(t *testing.T) {
+	mockProduct := new(MockProductRepo)
+	mockEntitlement := new(MockEntitlementRepo)
+	mockProduct.On("GetByID", mock.Anything, 3).Return(benefitProduct(`{"kind":"email_promotion","recipients":50}`), nil)
+	used := emailEntitlement(1, 50)
+	used.Used = 20
+	mockEntitlement.On("GetByOrderID", mock.Anything, 1).Return(used, nil)
+
+	f := benefitFulfiller{repo: &repository.Repository{Product: mockProduct, Entitlement: mockEntitlement}}
+	err := f.checkRevocable(context.Background(), &models.Order{ID: 1, UserID: 10, ProductID: 3, Quantity: 1})
+
+	assertServiceError(t, err, ErrCodeBadRequest, "购买的权益已被使用，无法退款")
+}
@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"log"
+	"os"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/oss"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/wechat"
+)
+
+// ImageAuditRequest 单次图片审核请求
+type ImageAuditRequest struct {
+	OpenID string
+	Scene  int
+	URL    string
+}
+
+// ImageChecker 图片审核器
+// SubmitImage 提交异步审核并返回 trace_id，审核结论通过 HandleMediaCheckResult 回传。
+type ImageChecker interface {
+	SubmitImage(ctx context.Context, req ImageAuditRequest) (string, error)
+}
+
+// wechatImageChecker 基于微信 media_check_async 的审核器
+type wechatImageChecker struct {
+	client *wechat.Client
+}
+
+// SubmitImage 调用 media_check_async，需要用户近两小时内访问过小程序的 openid
+func (c *wechatImageChecker) SubmitImage(ctx context.Context, req ImageAuditRequest) (string, error) {
+	return c.client.MediaCheckAsync(&wechat.MediaCheckAsyncRequest{
+		MediaURL:  req.URL,
+		MediaType: wechat.MediaTypeImage,
+		Scene:     req.Scene,
+		OpenID:    req.OpenID,
+	})
+}
+
+// imageStore 删除 OSS 上的图片，*oss.Client 实现了该接口
+type imageStore interface {
+	Delete(key string) error
+}
+
+// ImageAuditService 图片审核服务
+// 新上传的头像/封面先保存为待审核记录，审核通过后才写入用户资料；
+// 驳回的图片从 OSS 删除，用户没有可用图片时替换为默认图。
+// 审核器不可用或结论需复审的图片进入管理后台的人工审核队列。
+type ImageAuditService struct {
+	repo        *repository.Repository
+	store       imageStore // 可为空
+	checker     ImageChecker
+	defaultKeys map[string]string // biz_type -> 默认图片 key
+}
+
+// NewImageAuditService creates a new ImageAuditService.
+// 默认图片从 DEFAULT_AVATAR_KEY / DEFAULT_COVER_KEY 读取。
+func NewImageAuditService(repo *repository.Repository, ossClient *oss.Client) *ImageAuditService {
+	var store imageStore
+	if ossClient != nil {
+		store = ossClient
+	}
+
+	defaultKeys := map[string]string{
+		models.ImageAuditBizAvatar: os.Getenv("DEFAULT_AVATAR_KEY"),
+		models.ImageAuditBizCover:  os.Getenv("DEFAULT_COVER_KEY"),
+	}
+
+	return NewImageAuditServiceWithChecker(repo, store, &wechatImageChecker{client: wechat.NewClient()}, defaultKeys)
+}
+
+// NewImageAuditServiceWithChecker creates an ImageAuditService with an explicit checker.
+func NewImageAuditServiceWithChecker(repo *repository.Repository, store imageStore, checker ImageChecker, defaultKeys map[string]string) *ImageAuditService {
+	return &ImageAuditService{repo: repo, store: store, checker: checker, defaultKeys: defaultKeys}
+}
+
+// ImageAuditListResult holds a page of image audits with pagination info.
+type ImageAuditListResult struct {
+	List       []models.ImageAudit
+	Total      int64
+	TotalPages int
+	Page       int
+	Size       int
+}
+
+// ListAudits returns a paginated list of image audits with optional filters.
+func (s *ImageAuditService) ListAudits(ctx context.Context, params repository.ImageAuditListParams) (*ImageAuditListResult, error) {
+	params.Page, params.Size = normalizePageParams(params.Page, params.Size)
+
+	audits, total, err := s.repo.ImageAudit.List(ctx, params)
+	if err != nil {
+		log.Printf("[ImageAuditService.ListAudits] repository error: %v", err)
+		return nil, ErrInternal("获取图片审核列表失败")
+	}
+
+	totalPages := int((total + int64(params.Size) - 1) / int64(params.Size))
+	return &ImageAuditListResult{
+		List:       audits,
+		Total:      total,
+		TotalPages: totalPages,
+		Page:       params.Page,
+		Size:       params.Size,
+	}, nil
+}
+
+// Submit 为已上传的图片创建审核记录并提交机审。
+// 同一用户同一用途尚未审核完的旧图片会被替代并删除。
+func (s *ImageAuditService) Submit(ctx context.Context, userID int, bizType, key string) (*models.ImageAudit, error) {
+	user, err := s.repo.User.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("[ImageAuditService.Submit] repository error getting user: %v", err)
+		return nil, ErrInternal("获取用户信息失败")
+	}
+	if user == nil {
+		return nil, ErrNotFound("用户不存在")
+	}
+
+	// 1. 替代尚未审核完的旧图片
+	pending, err := s.repo.ImageAudit.ListPendingByUser(ctx, userID, bizType)
+	if err != nil {
+		log.Printf("[ImageAuditService.Submit] repository error listing pending audits: %v", err)
+		return nil, ErrInternal("提交图片审核失败")
+	}
+	for i := range pending {
+		old := &pending[i]
+		old.Status = models.ImageAuditStatusSuperseded
+		changed, err := s.repo.ImageAudit.UpdateResult(ctx, old)
+		if err != nil {
+			log.Printf("[ImageAuditService.Submit] supersede audit %d: %v", old.ID, err)
+			continue
+		}
+		if changed {
+			s.deleteImage(old.ImageKey)
+		}
+	}
+
+	// 2. 创建审核记录
+	audit := &models.ImageAudit{
+		UserID:   userID,
+		BizType:  bizType,
+		ImageKey: key,
+		Status:   models.ImageAuditStatusPending,
+	}
+	if err := s.repo.ImageAudit.Create(ctx, audit); err != nil {
+		log.Printf("[ImageAuditService.Submit] repository error creating audit: %v", err)
+		return nil, ErrInternal("提交图片审核失败")
+	}
+
+	// 3. 提交机审，无法机审时转人工复审
+	if s.checker != nil && user.OpenID != "" {
+		traceID, err := s.checker.SubmitImage(ctx, ImageAuditRequest{
+			OpenID: user.OpenID,
+			Scene:  models.ContentAuditSceneProfile,
+			URL:    oss.FullURL(key),
+		})
+		if err == nil {
+			if err := s.repo.ImageAudit.SetTraceID(ctx, audit.ID, traceID); err != nil {
+				log.Printf("[ImageAuditService.Submit] save trace id for audit %d: %v", audit.ID, err)
+			} else {
+				source := models.ImageAuditSourceWechat
+				audit.TraceID = &traceID
+				audit.Source = &source
+				return audit, nil
+			}
+		} else {
+			log.Printf("[ImageAuditService.Submit] checker failed for audit %d, queue for manual review: %v", audit.ID, err)
+		}
+	}
+
+	audit.Status = models.ImageAuditStatusFlagged
+	if _, err := s.repo.ImageAudit.UpdateResult(ctx, audit); err != nil {
+		log.Printf("[ImageAuditService.Submit] flag audit %d: %v", audit.ID, err)
+	}
+
+	return audit, nil
+}
+
+// HandleMediaCheckResult 处理机审结论（wxa_media_check 回调）。
+// 未知的 trace_id 和已处理过的记录会被忽略，微信重复推送同一 trace_id 时结论只生效一次。
+func (s *ImageAuditService) HandleMediaCheckResult(ctx context.Context, traceID, suggest string, label int) error {
+	audit, err := s.repo.ImageAudit.GetByTraceID(ctx, traceID)
+	if err != nil {
+		log.Printf("[ImageAuditService.HandleMediaCheckResult] repository error: %v", err)
+		return ErrInternal("获取图片审核记录失败")
+	}
+	if audit == nil {
+		log.Printf("[ImageAuditService.HandleMediaCheckResult] unknown trace_id %s", traceID)
+		return nil
+	}
+	if audit.Status != models.ImageAuditStatusPending {
+		log.Printf("[ImageAuditService.HandleMediaCheckResult] trace_id %s already handled", traceID)
+		return nil
+	}
+
+	switch suggest {
+	case models.ContentAuditSuggestPass, models.ContentAuditSuggestRisky:
+	default:
+		// 未知建议按人工复审处理
+		suggest = models.ContentAuditSuggestReview
+	}
+
+	return s.resolve(ctx, audit, models.ImageAuditSourceWechat, suggest, label)
+}
+
+// Review (admin only) 人工审核图片
+func (s *ImageAuditService) Review(ctx context.Context, id int64, approve bool) (*models.ImageAudit, error) {
+	audit, err := s.repo.ImageAudit.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("[ImageAuditService.Review] repository error: %v", err)
+		return nil, ErrInternal("获取图片审核记录失败")
+	}
+	if audit == nil {
+		return nil, ErrNotFound("图片审核记录不存在")
+	}
+	if audit.Status != models.ImageAuditStatusPending && audit.Status != models.ImageAuditStatusFlagged {
+		return nil, ErrBadRequest("该图片已审核")
+	}
+
+	suggest := models.ContentAuditSuggestRisky
+	if approve {
+		suggest = models.ContentAuditSuggestPass
+	}
+	if err := s.resolve(ctx, audit, models.ImageAuditSourceAdmin, suggest, 0); err != nil {
+		return nil, err
+	}
+
+	return audit, nil
+}
+
+// resolve 记录审核结论：通过则生效，违规则驳回，其余进入人工复审队列。
+// 结论与用户资料在同一事务中写入：失败时记录保持未处理，回调重试可以完整重做；
+// 已处理的记录不会再次生效。OSS 上的图片在提交后删除。
+func (s *ImageAuditService) resolve(ctx context.Context, audit *models.ImageAudit, source, suggest string, label int) error {
+	switch suggest {
+	case models.ContentAuditSuggestPass:
+		audit.Status = models.ImageAuditStatusPassed
+	case models.ContentAuditSuggestRisky:
+		audit.Status = models.ImageAuditStatusRejected
+	default:
+		audit.Status = models.ImageAuditStatusFlagged
+	}
+	audit.Source = &source
+	audit.Suggest = &suggest
+	if label != 0 {
+		audit.Label = &label
+	}
+
+	var obsolete []string
+	err := runInTx(ctx, s.repo, "ImageAuditService.resolve", "更新图片审核结果失败", func(tx *sqlx.Tx) error {
+		changed, err := s.repo.ImageAudit.UpdateResultTx(ctx, tx, audit)
+		if err != nil || !changed {
+			return err
+		}
+
+		switch audit.Status {
+		case models.ImageAuditStatusPassed:
+			obsolete, err = s.applyTx(ctx, tx, audit)
+		case models.ImageAuditStatusRejected:
+			obsolete, err = s.rejectTx(ctx, tx, audit)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range obsolete {
+		s.deleteImage(key)
+	}
+	return nil
+}
+
+// applyTx 把审核通过的图片写入用户资料，返回提交后需要删除的旧图片
+func (s *ImageAuditService) applyTx(ctx context.Context, tx *sqlx.Tx, audit *models.ImageAudit) ([]string, error) {
+	user, err := s.repo.User.GetByID(ctx, audit.UserID)
+	if err != nil {
+		log.Printf("[ImageAuditService.apply] repository error getting user: %v", err)
+		return nil, ErrInternal("获取用户信息失败")
+	}
+	if user == nil {
+		return []string{audit.ImageKey}, nil
+	}
+
+	oldKey := s.currentKey(user, audit.BizType)
+	if err := s.setImageTx(ctx, tx, audit.UserID, audit.BizType, audit.ImageKey); err != nil {
+		log.Printf("[ImageAuditService.apply] repository error updating user: %v", err)
+		return nil, ErrInternal("更新用户图片失败")
+	}
+
+	// 删除旧图片（默认图为共享资源，不删除）
+	if oldKey != "" && oldKey != audit.ImageKey && oldKey != s.defaultKeys[audit.BizType] {
+		return []string{oldKey}, nil
+	}
+	return nil, nil
+}
+
+// rejectTx 驳回图片；用户当前没有图片时替换为默认图，否则保留原有的已审核图片。
+// 返回提交后需要删除的驳回图片
+func (s *ImageAuditService) rejectTx(ctx context.Context, tx *sqlx.Tx, audit *models.ImageAudit) ([]string, error) {
+	obsolete := []string{audit.ImageKey}
+
+	defaultKey := s.defaultKeys[audit.BizType]
+	if defaultKey == "" {
+		return obsolete, nil
+	}
+
+	user, err := s.repo.User.GetByID(ctx, audit.UserID)
+	if err != nil {
+		log.Printf("[ImageAuditService.reject] repository error getting user: %v", err)
+		return nil, ErrInternal("获取用户信息失败")
+	}
+	if user == nil || s.currentKey(user, audit.BizType) != "" {
+		return obsolete, nil
+	}
+
+	if err := s.setImageTx(ctx, tx, audit.UserID, audit.BizType, defaultKey); err != nil {
+		log.Printf("[ImageAuditService.reject] repository error updating user: %v", err)
+		return nil, ErrInternal("更新用户图片失败")
+	}
+	return obsolete, nil
+}
+
+// currentKey 返回用户当前生效的图片 key
+func (s *ImageAuditService) currentKey(user *models.User, bizType string) string {
+	var key *string
+	switch bizType {
+	case models.ImageAuditBizAvatar:
+		key = user.AvatarUrl
+	case models.ImageAuditBizCover:
+		key = user.CoverImage
+	}
+	if key == nil {
+		return ""
+	}
+	return *key
+}
+
+// setImageTx 在事务中更新用户资料中对应用途的图片
+func (s *ImageAuditService) setImageTx(ctx context.Context, tx *sqlx.Tx, userID int, bizType, key string) error {
+	switch bizType {
+	case models.ImageAuditBizAvatar:
+		return s.repo.User.UpdateAvatarUrlTx(ctx, tx, userID, key)
+	case models.ImageAuditBizCover:
+		return s.repo.User.UpdateCoverImageTx(ctx, tx, userID, key)
+	}
+	return nil
+}
+
+// deleteImage 从 OSS 删除图片，失败只记录日志
+func (s *ImageAuditService) deleteImage(key string) {
+	if s.store == nil || key == "" {
+		return
+	}
+	if err := s.store.Delete(key); err != nil {
+		log.Printf("[ImageAuditService.deleteImage] delete %s: %v", key, err)
+	}
+}
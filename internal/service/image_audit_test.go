@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+func (m *MockUserRepo) UpdateAvatarUrlTx(ctx context.Context, tx *sqlx.Tx, userID int, avatarUrl string) error {
+	args := m.Called(ctx, tx, userID, avatarUrl)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) UpdateCoverImageTx(ctx context.Context, tx *sqlx.Tx, userID int, coverImage string) error {
+	args := m.Called(ctx, tx, userID, coverImage)
+	return args.Error(0)
+}
+
+type MockImageAuditRepo struct {
+	mock.Mock
+}
+
+func (m *MockImageAuditRepo) List(ctx context.Context, params repository.ImageAuditListParams) ([]models.ImageAudit, int64, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]models.ImageAudit), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockImageAuditRepo) GetByID(ctx context.Context, id int64) (*models.ImageAudit, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImageAudit), args.Error(1)
+}
+
+func (m *MockImageAuditRepo) GetByTraceID(ctx context.Context, traceID string) (*models.ImageAudit, error) {
+	args := m.Called(ctx, traceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ImageAudit), args.Error(1)
+}
+
+func (m *MockImageAuditRepo) ListPendingByUser(ctx context.Context, userID int, bizType string) ([]models.ImageAudit, error) {
+	args := m.Called(ctx, userID, bizType)
+	return args.Get(0).([]models.ImageAudit), args.Error(1)
+}
+
+func (m *MockImageAuditRepo) Create(ctx context.Context, audit *models.ImageAudit) error {
+	args := m.Called(ctx, audit)
+	audit.ID = 10
+	return args.Error(0)
+}
+
+func (m *MockImageAuditRepo) SetTraceID(ctx context.Context, id int64, traceID string) error {
+	args := m.Called(ctx, id, traceID)
+	return args.Error(0)
+}
+
+func (m *MockImageAuditRepo) UpdateResult(ctx context.Context, audit *models.ImageAudit) (bool, error) {
+	args := m.Called(ctx, audit)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockImageAuditRepo) UpdateResultTx(ctx context.Context, tx *sqlx.Tx, audit *models.ImageAudit) (bool, error) {
+	args := m.Called(ctx, tx, audit)
+	return args.Bool(0), args.Error(1)
+}
+
+type stubImageChecker struct {
+	traceID string
+	err     error
+	calls   int
+}
+
+func (c *stubImageChecker) SubmitImage(ctx context.Context, req ImageAuditRequest) (string, error) {
+	c.calls++
+	return c.traceID, c.err
+}
+
+type fakeImageStore struct {
+	deleted []string
+}
+
+func (s *fakeImageStore) Delete(key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+func strPtr(s string) *string { return &s }
+
+// --- Tests for ImageAuditService ---
+
+func TestImageAuditSubmit_SupersedesPendingAndSubmits(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockAudit := new(MockImageAuditRepo)
+	store := &fakeImageStore{}
+	checker := &stubImageChecker{traceID: "trace-1"}
+
+	mockUser.On("GetByID", mock.Anything, 1).Return(&models.User{ID: 1, OpenID: "openid-1"}, nil)
+	mockAudit.On("ListPendingByUser", mock.Anything, 1, models.ImageAuditBizAvatar).Return([]models.ImageAudit{
+		{ID: 5, UserID: 1, BizType: models.ImageAuditBizAvatar, ImageKey: "old.png", Status: models.ImageAuditStatusPending},
+	}, nil)
+	mockAudit.On("UpdateResult", mock.Anything, mock.MatchedBy(func(a *models.ImageAudit) bool {
+		return a.ID == 5 && a.Status == models.ImageAuditStatusSuperseded
+	})).Return(true, nil)
+	mockAudit.On("Create", mock.Anything, mock.MatchedBy(func(a *models.ImageAudit) bool {
+		return a.ImageKey == "new.png" && a.Status == models.ImageAuditStatusPending
+	})).Return(nil)
+	mockAudit.On("SetTraceID", mock.Anything, int64(10), "trace-1").Return(nil)
+
+	svc := NewImageAuditServiceWithChecker(&repository.Repository{User: mockUser, ImageAudit: mockAudit}, store, checker, nil)
+	audit, err := svc.Submit(context.Background(), 1, models.ImageAuditBizAvatar, "new.png")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ImageAuditStatusPending, audit.Status)
+	assert.Equal(t, "trace-1", *audit.TraceID)
+	assert.Equal(t, []string{"old.png"}, store.deleted)
+	assert.Equal(t, 1, checker.calls)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestImageAuditSubmit_FlagsWhenCheckerFails(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockAudit := new(MockImageAuditRepo)
+	checker := &stubImageChecker{err: errors.New("wechat unavailable")}
+
+	mockUser.On("GetByID", mock.Anything, 1).Return(&models.User{ID: 1, OpenID: "openid-1"}, nil)
+	mockAudit.On("ListPendingByUser", mock.Anything, 1, models.ImageAuditBizCover).Return([]models.ImageAudit{}, nil)
+	mockAudit.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockAudit.On("UpdateResult", mock.Anything, mock.MatchedBy(func(a *models.ImageAudit) bool {
+		return a.ID == 10 && a.Status == models.ImageAuditStatusFlagged
+	})).Return(true, nil)
+
+	svc := NewImageAuditServiceWithChecker(&repository.Repository{User: mockUser, ImageAudit: mockAudit}, nil, checker, nil)
+	audit, err := svc.Submit(context.Background(), 1, models.ImageAuditBizCover, "cover.png")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ImageAuditStatusFlagged, audit.Status)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestHandleMediaCheckResult_PassAppliesImage(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockAudit := new(MockImageAuditRepo)
+	store := &fakeImageStore{}
+
+	mockAudit.On("GetByTraceID", mock.Anything, "trace-1").Return(&models.ImageAudit{
+		ID: 10, UserID: 1, BizType: models.ImageAuditBizAvatar, ImageKey: "new.png", Status: models.ImageAuditStatusPending,
+	}, nil)
+	mockAudit.On("UpdateResultTx", mock.Anything, mock.Anything, mock.MatchedBy(func(a *models.ImageAudit) bool {
+		return a.Status == models.ImageAuditStatusPassed && *a.Source == models.ImageAuditSourceWechat
+	})).Return(true, nil)
+	mockUser.On("GetByID", mock.Anything, 1).Return(&models.User{ID: 1, AvatarUrl: strPtr("old.png")}, nil)
+	mockUser.On("UpdateAvatarUrlTx", mock.Anything, mock.Anything, 1, "new.png").Return(nil)
+
+	repo := newTxTestRepo()
+	repo.User = mockUser
+	repo.ImageAudit = mockAudit
+	svc := NewImageAuditServiceWithChecker(repo, store, nil, nil)
+	err := svc.HandleMediaCheckResult(context.Background(), "trace-1", models.ContentAuditSuggestPass, 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old.png"}, store.deleted)
+	mockUser.AssertExpectations(t)
+}
+
+func TestHandleMediaCheckResult_RiskyUsesDefaultImage(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockAudit := new(MockImageAuditRepo)
+	store := &fakeImageStore{}
+
+	mockAudit.On("GetByTraceID", mock.Anything, "trace-1").Return(&models.ImageAudit{
+		ID: 10, UserID: 1, BizType: models.ImageAuditBizCover, ImageKey: "bad.png", Status: models.ImageAuditStatusPending,
+	}, nil)
+	mockAudit.On("UpdateResultTx", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mockUser.On("GetByID", mock.Anything, 1).Return(&models.User{ID: 1}, nil)
+	mockUser.On("UpdateCoverImageTx", mock.Anything, mock.Anything, 1, "default/cover.png").Return(nil)
+
+	repo := newTxTestRepo()
+	repo.User = mockUser
+	repo.ImageAudit = mockAudit
+	defaults := map[string]string{models.ImageAuditBizCover: "default/cover.png"}
+	svc := NewImageAuditServiceWithChecker(repo, store, nil, defaults)
+	err := svc.HandleMediaCheckResult(context.Background(), "trace-1", models.ContentAuditSuggestRisky, 20002)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bad.png"}, store.deleted)
+	mockUser.AssertExpectations(t)
+}
+
+func TestHandleMediaCheckResult_UnknownTraceIgnored(t *testing.T) {
+	mockAudit := new(MockImageAuditRepo)
+	mockAudit.On("GetByTraceID", mock.Anything, "missing").Return(nil, nil)
+
+	svc := NewImageAuditServiceWithChecker(&repository.Repository{ImageAudit: mockAudit}, nil, nil, nil)
+	err := svc.HandleMediaCheckResult(context.Background(), "missing", models.ContentAuditSuggestPass, 100)
+
+	assert.NoError(t, err)
+	mockAudit.AssertNotCalled(t, "UpdateResultTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleMediaCheckResult_DuplicatePushIgnored(t *testing.T) {
+	mockAudit := new(MockImageAuditRepo)
+	mockAudit.On("GetByTraceID", mock.Anything, "trace-1").Return(&models.ImageAudit{
+		ID: 10, UserID: 1, BizType: models.ImageAuditBizAvatar, ImageKey: "new.png", Status: models.ImageAuditStatusPassed,
+	}, nil)
+	store := &fakeImageStore{}
+
+	svc := NewImageAuditServiceWithChecker(&repository.Repository{ImageAudit: mockAudit}, store, nil, nil)
+	err := svc.HandleMediaCheckResult(context.Background(), "trace-1", models.ContentAuditSuggestRisky, 20002)
+
+	assert.NoError(t, err)
+	assert.Empty(t, store.deleted)
+	mockAudit.AssertNotCalled(t, "UpdateResultTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHandleMediaCheckResult_ApplyFailureKeepsImage(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockAudit := new(MockImageAuditRepo)
+	store := &fakeImageStore{}
+
+	mockAudit.On("GetByTraceID", mock.Anything, "trace-1").Return(&models.ImageAudit{
+		ID: 10, UserID: 1, BizType: models.ImageAuditBizAvatar, ImageKey: "new.png", Status: models.ImageAuditStatusPending,
+	}, nil)
+	mockAudit.On("UpdateResultTx", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+	mockUser.On("GetByID", mock.Anything, 1).Return(&models.User{ID: 1, AvatarUrl: strPtr("old.png")}, nil)
+	mockUser.On("UpdateAvatarUrlTx", mock.Anything, mock.Anything, 1, "new.png").Return(errors.New("db down"))
+
+	repo := newTxTestRepo()
+	repo.User = mockUser
+	repo.ImageAudit = mockAudit
+	svc := NewImageAuditServiceWithChecker(repo, store, nil, nil)
+	err := svc.HandleMediaCheckResult(context.Background(), "trace-1", models.ContentAuditSuggestPass, 100)
+
+	assertServiceError(t, err, ErrCodeInternal, "更新用户图片失败")
+	assert.Empty(t, store.deleted)
+}
+
+func TestImageAuditReview_AlreadyResolved(t *testing.T) {
+	mockAudit := new(MockImageAuditRepo)
+	mockAudit.On("GetByID", mock.Anything, int64(10)).Return(&models.ImageAudit{ID: 10, Status: models.ImageAuditStatusPassed}, nil)
+
+	svc := NewImageAuditServiceWithChecker(&repository.Repository{ImageAudit: mockAudit}, nil, nil, nil)
+	_, err := svc.Review(context.Background(), 10, false)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "该图片已审核")
+}
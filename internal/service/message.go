@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"log"
@@ -11,6 +12,23 @@ import (
 	"github.com/trv3wood/kuaizu-server/internal/wechat"
 )
 
+// ErrMessageUndeliverable marks send failures that retrying cannot fix, such as a
+// missing openid or template, or a user who refused the subscription.
+var ErrMessageUndeliverable = errors.New("message undeliverable")
+
+// ErrMessageSkipped marks messages that were not sent because the user has no
+// remaining one-time authorization for the template. This is expected rather
+// than a failure.
+var ErrMessageSkipped = errors.New("message skipped")
+
+// permanentWxErrCodes 重试无法恢复的微信订阅消息错误码
+var permanentWxErrCodes = map[int]bool{
+	40003: true, // openid 无效
+	40037: true, // 模板ID无效
+	43101: true, // 用户拒绝接受消息
+	47003: true, // 模板参数不准确
+}
+
 // MessageService handles sending notifications (WeChat, etc.)
 type MessageService struct {
 	repo     *repository.Repository
@@ -32,49 +50,122 @@ func (s *MessageService) SendSubscribeMsgByBizKey(ctx context.Context, userID in
 		return fmt.Errorf("get user: %w", err)
 	}
 	if user == nil || user.OpenID == "" {
-		return fmt.Errorf("user not found or has no openid")
+		return fmt.Errorf("%w: user not found or has no openid", ErrMessageUndeliverable)
 	}
 
-	// 2. Get template config
-	config, err := s.repo.MsgTemplate.GetByBizKey(ctx, bizKey)
+	// 2. Reserve one of the remaining one-time authorizations before sending so
+	// concurrent sends cannot spend the same one; it is given back if the send fails
+	reserved, err := s.repo.SubscribeConfig.DecrementCount(ctx, userID, bizKey)
 	if err != nil {
-		log.Printf("[MessageService.SendSubscribeMsgByBizKey] error getting config for %s: %v", bizKey, err)
-		return fmt.Errorf("get template config: %w", err)
+		return fmt.Errorf("reserve subscribe count: %w", err)
+	}
+	if !reserved {
+		log.Printf("[MessageService.SendSubscribeMsgByBizKey] user %d has no remaining subscription for %s, skipping", userID, bizKey)
+		return fmt.Errorf("%w: no remaining subscription for %s", ErrMessageSkipped, bizKey)
 	}
 
-	// 3. Check local subscription status (Mirror)
-	localSub, err := s.repo.SubscribeConfig.GetByUserIDAndBizKey(ctx, userID, bizKey)
+	// 3. Get template config
+	config, err := s.repo.MsgTemplate.GetByBizKey(ctx, bizKey)
 	if err != nil {
-		log.Printf("[MessageService.SendSubscribeMsgByBizKey] check local sub error: %v", err)
-	}
-	if localSub != nil && localSub.Status == models.SubscribeStatusReject {
-		log.Printf("[MessageService.SendSubscribeMsgByBizKey] user %d rejected %s, skipping", userID, bizKey)
-		return nil
+		log.Printf("[MessageService.SendSubscribeMsgByBizKey] error getting config for %s: %v", bizKey, err)
+		s.restoreCount(ctx, userID, bizKey)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: get template config: %w", ErrMessageUndeliverable, err)
+		}
+		return fmt.Errorf("get template config: %w", err)
 	}
 
 	// 4. Send using client helper
 	err = s.wxClient.SendByConfig(user.OpenID, config.TemplateID, config.ContentJSON, businessData)
 	if err != nil {
-		// 5. Sync state if user rejected on WeChat side
+		// 5. Sync state if user has no authorization left on WeChat side
 		var wxErr wechat.SubscribeMessageResponse
-		if errors.As(err, &wxErr) {
-			if wxErr.ErrCode == 43101 { // User refuse to accept
-				log.Printf("[MessageService.SendSubscribeMsgByBizKey] user %d rejected on WeChat, syncing local state", userID)
-				_ = s.repo.SubscribeConfig.Upsert(ctx, &models.SubscribeConfig{
-					UserID: userID,
-					BizKey: bizKey,
-					Status: models.SubscribeStatusReject,
-				})
-			}
+		if errors.As(err, &wxErr) && wxErr.ErrCode == 43101 { // User refuse to accept
+			log.Printf("[MessageService.SendSubscribeMsgByBizKey] user %d rejected on WeChat, syncing local state", userID)
+			_ = s.repo.SubscribeConfig.Upsert(ctx, &models.SubscribeConfig{
+				UserID:         userID,
+				BizKey:         bizKey,
+				SubscribeCount: 0,
+				Status:         models.SubscribeStatusReject,
+			})
+		} else {
+			s.restoreCount(ctx, userID, bizKey)
 		}
 
 		log.Printf("[MessageService.SendSubscribeMsgByBizKey] error sending message: %v", err)
+		if permanentWxErrCodes[wxErr.ErrCode] {
+			return fmt.Errorf("%w: send message: %w", ErrMessageUndeliverable, err)
+		}
 		return fmt.Errorf("send message: %w", err)
 	}
 
 	return nil
 }
 
+// restoreCount gives back the authorization reserved for a message that was not sent
+func (s *MessageService) restoreCount(ctx context.Context, userID int, bizKey string) {
+	if err := s.repo.SubscribeConfig.RestoreCount(ctx, userID, bizKey); err != nil {
+		log.Printf("[MessageService.restoreCount] restore subscribe count of user %d for %s: %v", userID, bizKey, err)
+	}
+}
+
+// MessageOutboxListResult holds a page of outbox messages with pagination info.
+type MessageOutboxListResult struct {
+	List       []models.MessageOutbox
+	Total      int64
+	TotalPages int
+	Page       int
+	Size       int
+}
+
+// ListOutbox (admin only) returns a paginated list of outbox messages with optional filters.
+func (s *MessageService) ListOutbox(ctx context.Context, params repository.MessageOutboxListParams) (*MessageOutboxListResult, error) {
+	params.Page, params.Size = normalizePageParams(params.Page, params.Size)
+
+	msgs, total, err := s.repo.MessageOutbox.List(ctx, params)
+	if err != nil {
+		log.Printf("[MessageService.ListOutbox] repository error: %v", err)
+		return nil, ErrInternal("获取消息发送记录失败")
+	}
+
+	totalPages := int((total + int64(params.Size) - 1) / int64(params.Size))
+	return &MessageOutboxListResult{
+		List:       msgs,
+		Total:      total,
+		TotalPages: totalPages,
+		Page:       params.Page,
+		Size:       params.Size,
+	}, nil
+}
+
+// ReplayOutbox (admin only) re-queues a failed outbox message for delivery.
+func (s *MessageService) ReplayOutbox(ctx context.Context, id int64) (*models.MessageOutbox, error) {
+	msg, err := s.repo.MessageOutbox.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("[MessageService.ReplayOutbox] repository error: %v", err)
+		return nil, ErrInternal("获取消息失败")
+	}
+	if msg == nil {
+		return nil, ErrNotFound("消息不存在")
+	}
+
+	replayed, err := s.repo.MessageOutbox.Replay(ctx, id)
+	if err != nil {
+		log.Printf("[MessageService.ReplayOutbox] repository error replaying: %v", err)
+		return nil, ErrInternal("重放消息失败")
+	}
+	if !replayed {
+		return nil, ErrBadRequest("只能重放发送失败的消息")
+	}
+
+	msg, err = s.repo.MessageOutbox.GetByID(ctx, id)
+	if err != nil || msg == nil {
+		log.Printf("[MessageService.ReplayOutbox] repository error reloading: %v", err)
+		return nil, ErrInternal("获取消息失败")
+	}
+	return msg, nil
+}
+
 // GetMsgTemplatesByBizKeys retrieves multiple message template configurations by their business keys
 func (s *MessageService) GetMsgTemplatesByBizKeys(ctx context.Context, bizKeys []string) ([]models.MsgTemplateConfig, error) {
 	configs, err := s.repo.MsgTemplate.GetByBizKeys(ctx, bizKeys)
@@ -91,28 +182,44 @@ type TemplateSyncResult struct {
 	Result string // accept, reject, ban
 }
 
+// SyncSubscribeStatus records the result of a wx.requestSubscribeMessage call.
+// Every accept grants one more one-time authorization for the template.
 func (s *MessageService) SyncSubscribeStatus(ctx context.Context, userID int, syncResults []TemplateSyncResult) error {
 	for _, res := range syncResults {
-		// 1. Map result to status
-		var status models.SubscribeStatus
+		var err error
 		switch res.Result {
 		case "accept":
-			status = models.SubscribeStatusAccept
+			err = s.repo.SubscribeConfig.IncrementCount(ctx, userID, res.BizKey, 1)
 		case "reject":
-			status = models.SubscribeStatusReject
+			err = s.repo.SubscribeConfig.UpsertStatus(ctx, userID, res.BizKey, models.SubscribeStatusReject)
 		default:
-			status = models.SubscribeStatusReject // treat ban or other as reject
+			// treat ban or other as reject
+			err = s.repo.SubscribeConfig.UpsertStatus(ctx, userID, res.BizKey, models.SubscribeStatusReject)
 		}
-
-		// 2. Upsert
-		err := s.repo.SubscribeConfig.Upsert(ctx, &models.SubscribeConfig{
-			UserID: userID,
-			BizKey: res.BizKey,
-			Status: status,
-		})
 		if err != nil {
-			log.Printf("[MessageService.SyncSubscribeStatus] upsert failed for %s: %v", res.BizKey, err)
+			log.Printf("[MessageService.SyncSubscribeStatus] sync failed for %s: %v", res.BizKey, err)
 		}
 	}
 	return nil
 }
+
+// GetSubscribeCounts returns the remaining one-time authorizations of a user per
+// business key; keys the user never subscribed to report 0.
+func (s *MessageService) GetSubscribeCounts(ctx context.Context, userID int, bizKeys []string) (map[string]int, error) {
+	configs, err := s.repo.SubscribeConfig.ListByUserID(ctx, userID)
+	if err != nil {
+		log.Printf("[MessageService.GetSubscribeCounts] repository error: %v", err)
+		return nil, ErrInternal("获取订阅次数失败")
+	}
+
+	counts := make(map[string]int, len(bizKeys))
+	for _, key := range bizKeys {
+		counts[key] = 0
+	}
+	for _, c := range configs {
+		if _, ok := counts[c.BizKey]; ok {
+			counts[c.BizKey] = c.SubscribeCount
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+const (
+	dispatcherPollInterval = 5 * time.Second // 轮询待发送消息的间隔
+	dispatcherBatchSize    = 50              // 每次领取的消息数
+	dispatcherLease        = 2 * time.Minute // 领取消息后的租约，超时未完成的消息会被重新领取
+
+	maxMessageRetries       = 6                // 最大重试次数，超过后消息标记为失败
+	messageRetryBaseBackoff = 30 * time.Second // 首次重试的等待时间，之后指数增长
+	messageRetryMaxBackoff  = time.Hour        // 重试等待时间上限
+
+	maxMessageErrorLen = 500 // message_outbox.error_msg 列长度
+)
+
+// SubscribeNotifier 发送微信订阅消息，*MessageService 实现了该接口
+type SubscribeNotifier interface {
+	SendSubscribeMsgByBizKey(ctx context.Context, userID int, bizKey string, businessData map[string]string) error
+}
+
+// enqueueMessageTx 在业务事务内写入一条待发送的订阅消息，事务提交后由 MessageDispatcher 投递
+func enqueueMessageTx(ctx context.Context, tx *sqlx.Tx, repo *repository.Repository, userID int, bizKey string, data map[string]string) error {
+	msg, err := newOutboxMessage(userID, bizKey, data)
+	if err != nil {
+		return err
+	}
+	return repo.MessageOutbox.CreateTx(ctx, tx, msg)
+}
+
+// newOutboxMessage 构造一条待发送的订阅消息
+func newOutboxMessage(userID int, bizKey string, data map[string]string) (*models.MessageOutbox, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("marshal message payload: %w", err)
+	}
+
+	return &models.MessageOutbox{
+		UserID:  userID,
+		BizKey:  bizKey,
+		Payload: string(payload),
+		Status:  models.MessageOutboxStatusPending,
+	}, nil
+}
+
+// MessageDispatcher 订阅消息发件箱分发器
+// 从 message_outbox 表领取到期消息逐条发送，临时失败按指数退避重试，
+// 不可投递的消息（用户拒收、openid 无效、模板缺失等）直接标记为失败，由管理员排查后重放；
+// 用户没有剩余订阅授权的消息标记为已跳过。
+type MessageDispatcher struct {
+	repo   repository.MessageOutboxRepo
+	sender SubscribeNotifier
+}
+
+// NewMessageDispatcher creates a MessageDispatcher delivering through sender.
+func NewMessageDispatcher(repo repository.MessageOutboxRepo, sender SubscribeNotifier) *MessageDispatcher {
+	return &MessageDispatcher{repo: repo, sender: sender}
+}
+
+// Run 持续投递发件箱消息，直到 ctx 被取消
+func (d *MessageDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcherPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// 领满一批说明可能还有积压，立即继续
+		for d.processBatch(ctx) == dispatcherBatchSize {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processBatch 领取并发送一批消息，返回领取到的消息数
+func (d *MessageDispatcher) processBatch(ctx context.Context) int {
+	msgs, err := d.repo.ClaimDue(ctx, dispatcherBatchSize, time.Now().Add(dispatcherLease))
+	if err != nil {
+		log.Printf("[MessageDispatcher.processBatch] claim messages: %v", err)
+		return 0
+	}
+
+	for i := range msgs {
+		d.deliver(ctx, &msgs[i])
+	}
+	return len(msgs)
+}
+
+// deliver 发送单条消息，按结果更新消息状态
+func (d *MessageDispatcher) deliver(ctx context.Context, msg *models.MessageOutbox) {
+	var data map[string]string
+	if err := json.Unmarshal([]byte(msg.Payload), &data); err != nil {
+		d.fail(ctx, msg, fmt.Sprintf("invalid payload: %v", err))
+		return
+	}
+
+	err := d.sender.SendSubscribeMsgByBizKey(ctx, msg.UserID, msg.BizKey, data)
+	if err == nil {
+		held, err := d.repo.MarkSent(ctx, msg.ID, messageClaimToken(msg), time.Now())
+		if err != nil {
+			log.Printf("[MessageDispatcher.deliver] mark message %d sent: %v", msg.ID, err)
+			return
+		}
+		if !held {
+			log.Printf("[MessageDispatcher.deliver] message %d was reclaimed before its delivery was recorded", msg.ID)
+		}
+		return
+	}
+
+	if errors.Is(err, ErrMessageSkipped) {
+		d.skip(ctx, msg, err.Error())
+		return
+	}
+	if errors.Is(err, ErrMessageUndeliverable) {
+		d.fail(ctx, msg, err.Error())
+		return
+	}
+	d.retry(ctx, msg, err.Error())
+}
+
+// retry 记录一次失败；未超过最大重试次数时按指数退避重新排队
+func (d *MessageDispatcher) retry(ctx context.Context, msg *models.MessageOutbox, errMsg string) {
+	retryCount := msg.RetryCount + 1
+	if retryCount > maxMessageRetries {
+		msg.RetryCount = retryCount
+		d.fail(ctx, msg, errMsg)
+		return
+	}
+
+	nextRetryAt := time.Now().Add(messageRetryBackoff(retryCount))
+	held, err := d.repo.MarkRetry(ctx, msg.ID, messageClaimToken(msg), retryCount, truncateRunes(errMsg, maxMessageErrorLen), nextRetryAt)
+	if err != nil {
+		log.Printf("[MessageDispatcher.retry] mark message %d retry: %v", msg.ID, err)
+		return
+	}
+	if !held {
+		log.Printf("[MessageDispatcher.retry] message %d was reclaimed before its retry was recorded", msg.ID)
+	}
+}
+
+// fail 将消息标记为最终失败
+func (d *MessageDispatcher) fail(ctx context.Context, msg *models.MessageOutbox, errMsg string) {
+	log.Printf("[MessageDispatcher.fail] message %d (%s to user %d) failed: %s", msg.ID, msg.BizKey, msg.UserID, errMsg)
+	held, err := d.repo.MarkFailed(ctx, msg.ID, messageClaimToken(msg), msg.RetryCount, truncateRunes(errMsg, maxMessageErrorLen))
+	if err != nil {
+		log.Printf("[MessageDispatcher.fail] mark message %d failed: %v", msg.ID, err)
+		return
+	}
+	if !held {
+		log.Printf("[MessageDispatcher.fail] message %d was reclaimed before its failure was recorded", msg.ID)
+	}
+}
+
+// skip 将消息标记为已跳过，不计为失败
+func (d *MessageDispatcher) skip(ctx context.Context, msg *models.MessageOutbox, reason string) {
+	held, err := d.repo.MarkSkipped(ctx, msg.ID, messageClaimToken(msg), truncateRunes(reason, maxMessageErrorLen))
+	if err != nil {
+		log.Printf("[MessageDispatcher.skip] mark message %d skipped: %v", msg.ID, err)
+		return
+	}
+	if !held {
+		log.Printf("[MessageDispatcher.skip] message %d was reclaimed before its skip was recorded", msg.ID)
+	}
+}
+
+// messageClaimToken 返回领取消息时分配的令牌
+func messageClaimToken(msg *models.MessageOutbox) string {
+	if msg.ClaimToken == nil {
+		return ""
+	}
+	return *msg.ClaimToken
+}
+
+// messageRetryBackoff 返回第 n 次重试前的等待时间
+func messageRetryBackoff(n int) time.Duration {
+	backoff := messageRetryBaseBackoff
+	for i := 1; i < n; i++ {
+		backoff *= 2
+		if backoff >= messageRetryMaxBackoff {
+			return messageRetryMaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// fakeTxConnector opens connections whose transactions begin, commit and roll
+// back without a database, so service code using repo.DB().BeginTxx can run
+// against mocked repositories.
+type fakeTxConnector struct{}
+
+func (fakeTxConnector) Connect(context.Context) (driver.Conn, error) { return fakeTxConn{}, nil }
+func (fakeTxConnector) Driver() driver.Driver                        { return fakeTxDriver{} }
+
+type fakeTxDriver struct{}
+
+func (fakeTxDriver) Open(string) (driver.Conn, error) { return fakeTxConn{}, nil }
+
+type fakeTxConn struct{}
+
+func (fakeTxConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("fake tx conn: statements are not supported")
+}
+func (fakeTxConn) Close() error              { return nil }
+func (fakeTxConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+// newTxTestRepo returns a Repository backed by the fake transactional DB; tests
+// replace the sub-repositories they need with mocks.
+func newTxTestRepo() *repository.Repository {
+	return repository.New(sqlx.NewDb(sql.OpenDB(fakeTxConnector{}), "mysql"))
+}
+
+type MockMessageOutboxRepo struct {
+	repository.MessageOutboxRepo
+	mock.Mock
+}
+
+func (m *MockMessageOutboxRepo) CreateTx(ctx context.Context, tx *sqlx.Tx, msg *models.MessageOutbox) error {
+	args := m.Called(ctx, tx, msg)
+	return args.Error(0)
+}
+
+func (m *MockMessageOutboxRepo) CreateBatchTx(ctx context.Context, tx *sqlx.Tx, msgs []*models.MessageOutbox) error {
+	args := m.Called(ctx, tx, msgs)
+	return args.Error(0)
+}
+
+func (m *MockMessageOutboxRepo) MarkSent(ctx context.Context, id int64, claimToken string, sentAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, claimToken, sentAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMessageOutboxRepo) MarkRetry(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string, nextRetryAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, claimToken, retryCount, errMsg, nextRetryAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMessageOutboxRepo) MarkFailed(ctx context.Context, id int64, claimToken string, retryCount int, errMsg string) (bool, error) {
+	args := m.Called(ctx, id, claimToken, retryCount, errMsg)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMessageOutboxRepo) MarkSkipped(ctx context.Context, id int64, claimToken string, reason string) (bool, error) {
+	args := m.Called(ctx, id, claimToken, reason)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepo) UpdateAuthStatusTx(ctx context.Context, tx *sqlx.Tx, userID int, authStatus int) error {
+	args := m.Called(ctx, tx, userID, authStatus)
+	return args.Error(0)
+}
+
+// queuedMessages returns the messages written to the outbox mock.
+func queuedMessages(m *MockMessageOutboxRepo) []*models.MessageOutbox {
+	var msgs []*models.MessageOutbox
+	for _, call := range m.Calls {
+		if call.Method == "CreateTx" {
+			msgs = append(msgs, call.Arguments.Get(2).(*models.MessageOutbox))
+		}
+	}
+	return msgs
+}
+
+// --- Tests for MessageDispatcher ---
+
+func TestMessageDispatcher_DeliverSuccess(t *testing.T) {
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("MarkSent", mock.Anything, int64(1), "claim-1", mock.Anything).Return(true, nil)
+
+	sender := &stubNotifier{}
+	d := NewMessageDispatcher(mockOutbox, sender)
+	d.deliver(context.Background(), &models.MessageOutbox{
+		ID: 1, UserID: 10, ClaimToken: strPtr("claim-1"), BizKey: models.MsgBizKeyCardReceived, Payload: `{"project_name":"快组"}`,
+	})
+
+	require.Len(t, sender.calls, 1)
+	assert.Equal(t, 10, sender.calls[0].userID)
+	assert.Equal(t, "快组", sender.calls[0].data["project_name"])
+	mockOutbox.AssertExpectations(t)
+}
+
+func TestMessageDispatcher_TemporaryErrorSchedulesRetry(t *testing.T) {
+	mockOutbox := new(MockMessageOutboxRepo)
+	before := time.Now()
+	mockOutbox.On("MarkRetry", mock.Anything, int64(1), "claim-1", 2, "network down", mock.MatchedBy(func(next time.Time) bool {
+		return !next.Before(before.Add(2 * messageRetryBaseBackoff))
+	})).Return(true, nil)
+
+	d := NewMessageDispatcher(mockOutbox, &stubNotifier{err: errors.New("network down")})
+	d.deliver(context.Background(), &models.MessageOutbox{ID: 1, UserID: 10, ClaimToken: strPtr("claim-1"), RetryCount: 1, Payload: `{}`})
+
+	mockOutbox.AssertExpectations(t)
+}
+
+func TestMessageDispatcher_UndeliverableFailsImmediately(t *testing.T) {
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("MarkFailed", mock.Anything, int64(1), "claim-1", 0, mock.Anything).Return(true, nil)
+
+	sendErr := fmt.Errorf("%w: send message: wechat api error: 43101 - user refuse to accept the msg", ErrMessageUndeliverable)
+	d := NewMessageDispatcher(mockOutbox, &stubNotifier{err: sendErr})
+	d.deliver(context.Background(), &models.MessageOutbox{ID: 1, UserID: 10, ClaimToken: strPtr("claim-1"), Payload: `{}`})
+
+	mockOutbox.AssertExpectations(t)
+	mockOutbox.AssertNotCalled(t, "MarkRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMessageDispatcher_NoAuthorizationSkips(t *testing.T) {
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("MarkSkipped", mock.Anything, int64(1), "claim-1", "message skipped: no remaining subscription for MSG_CARD_RECEIVED").Return(true, nil)
+
+	sendErr := fmt.Errorf("%w: no remaining subscription for %s", ErrMessageSkipped, models.MsgBizKeyCardReceived)
+	d := NewMessageDispatcher(mockOutbox, &stubNotifier{err: sendErr})
+	d.deliver(context.Background(), &models.MessageOutbox{ID: 1, UserID: 10, ClaimToken: strPtr("claim-1"), Payload: `{}`})
+
+	mockOutbox.AssertExpectations(t)
+	mockOutbox.AssertNotCalled(t, "MarkFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMessageDispatcher_GivesUpAfterMaxRetries(t *testing.T) {
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("MarkFailed", mock.Anything, int64(1), "claim-1", maxMessageRetries+1, "timeout").Return(true, nil)
+
+	d := NewMessageDispatcher(mockOutbox, &stubNotifier{err: errors.New("timeout")})
+	d.deliver(context.Background(), &models.MessageOutbox{ID: 1, UserID: 10, ClaimToken: strPtr("claim-1"), RetryCount: maxMessageRetries, Payload: `{}`})
+
+	mockOutbox.AssertExpectations(t)
+}
+
+func TestMessageDispatcher_InvalidPayloadFails(t *testing.T) {
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("MarkFailed", mock.Anything, int64(1), "claim-1", 0, mock.Anything).Return(true, nil)
+
+	sender := &stubNotifier{}
+	d := NewMessageDispatcher(mockOutbox, sender)
+	d.deliver(context.Background(), &models.MessageOutbox{ID: 1, UserID: 10, ClaimToken: strPtr("claim-1"), Payload: `not json`})
+
+	assert.Empty(t, sender.calls)
+	mockOutbox.AssertExpectations(t)
+}
+
+func TestMessageDispatcher_ReclaimedMessageKeepsNewResult(t *testing.T) {
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("MarkRetry", mock.Anything, int64(1), "stale-claim", 1, "network down", mock.Anything).Return(false, nil)
+
+	d := NewMessageDispatcher(mockOutbox, &stubNotifier{err: errors.New("network down")})
+	d.deliver(context.Background(), &models.MessageOutbox{ID: 1, UserID: 10, ClaimToken: strPtr("stale-claim"), Payload: `{}`})
+
+	mockOutbox.AssertExpectations(t)
+	mockOutbox.AssertNotCalled(t, "MarkFailed", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMessageRetryBackoff(t *testing.T) {
+	assert.Equal(t, messageRetryBaseBackoff, messageRetryBackoff(1))
+	assert.Equal(t, 4*messageRetryBaseBackoff, messageRetryBackoff(3))
+	assert.Equal(t, messageRetryMaxBackoff, messageRetryBackoff(20))
+}
+
+// --- Tests for outbox writes in business transactions ---
+
+func TestReviewUserAuth_QueuesNotificationInTx(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10}, nil)
+	mockUser.On("UpdateAuthStatusTx", mock.Anything, mock.Anything, 10, models.UserAuthStatusFailed).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.User = mockUser
+	repo.MessageOutbox = mockOutbox
+	repo.Notification = mockNotification
+
+	err := NewUserService(repo, nil).ReviewUserAuth(context.Background(), 10, models.UserAuthStatusFailed)
+
+	require.NoError(t, err)
+	msgs := queuedMessages(mockOutbox)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, 10, msgs[0].UserID)
+	assert.Equal(t, models.MsgBizKeyIdentityAuth, msgs[0].BizKey)
+	assert.Equal(t, models.MessageOutboxStatusPending, msgs[0].Status)
+	assert.JSONEq(t, `{"status":"未通过","remark":"很抱歉，您的身份认证未通过，请检查上传的信息是否清晰合规。"}`, msgs[0].Payload)
+}
+
+func TestReviewUserAuth_UpdateFailureQueuesNothing(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10}, nil)
+	mockUser.On("UpdateAuthStatusTx", mock.Anything, mock.Anything, 10, models.UserAuthStatusPassed).Return(errors.New("db down"))
+	mockOutbox := new(MockMessageOutboxRepo)
+
+	repo := newTxTestRepo()
+	repo.User = mockUser
+	repo.MessageOutbox = mockOutbox
+
+	err := NewUserService(repo, nil).ReviewUserAuth(context.Background(), 10, models.UserAuthStatusPassed)
+
+	assertServiceError(t, err, ErrCodeInternal, "审核失败")
+	assert.Empty(t, queuedMessages(mockOutbox))
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockSubscribeConfigRepo struct {
+	repository.SubscribeConfigRepo
+	mock.Mock
+}
+
+func (m *MockSubscribeConfigRepo) GetByUserIDAndBizKey(ctx context.Context, userID int, bizKey string) (*models.SubscribeConfig, error) {
+	args := m.Called(ctx, userID, bizKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SubscribeConfig), args.Error(1)
+}
+
+func (m *MockSubscribeConfigRepo) ListByUserID(ctx context.Context, userID int) ([]models.SubscribeConfig, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SubscribeConfig), args.Error(1)
+}
+
+func (m *MockSubscribeConfigRepo) IncrementCount(ctx context.Context, userID int, bizKey string, count int) error {
+	args := m.Called(ctx, userID, bizKey, count)
+	return args.Error(0)
+}
+
+func (m *MockSubscribeConfigRepo) DecrementCount(ctx context.Context, userID int, bizKey string) (bool, error) {
+	args := m.Called(ctx, userID, bizKey)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSubscribeConfigRepo) RestoreCount(ctx context.Context, userID int, bizKey string) error {
+	args := m.Called(ctx, userID, bizKey)
+	return args.Error(0)
+}
+
+type MockMsgTemplateConfigRepo struct {
+	repository.MsgTemplateConfigRepo
+	mock.Mock
+}
+
+func (m *MockMsgTemplateConfigRepo) GetByBizKey(ctx context.Context, bizKey string) (*models.MsgTemplateConfig, error) {
+	args := m.Called(ctx, bizKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.MsgTemplateConfig), args.Error(1)
+}
+
+func (m *MockSubscribeConfigRepo) UpsertStatus(ctx context.Context, userID int, bizKey string, status models.SubscribeStatus) error {
+	args := m.Called(ctx, userID, bizKey, status)
+	return args.Error(0)
+}
+
+// --- Tests for MessageService subscribe quota ---
+
+func TestSendSubscribeMsg_SkipsWhenNoRemainingCount(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10, OpenID: "openid"}, nil)
+	mockSub := new(MockSubscribeConfigRepo)
+	mockSub.On("DecrementCount", mock.Anything, 10, models.MsgBizKeyCardReceived).Return(false, nil)
+
+	svc := NewMessageService(&repository.Repository{User: mockUser, SubscribeConfig: mockSub})
+	err := svc.SendSubscribeMsgByBizKey(context.Background(), 10, models.MsgBizKeyCardReceived, map[string]string{})
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrMessageSkipped))
+}
+
+func TestSendSubscribeMsg_SkipsWhenNeverSubscribed(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10, OpenID: "openid"}, nil)
+	mockSub := new(MockSubscribeConfigRepo)
+	mockSub.On("DecrementCount", mock.Anything, 10, models.MsgBizKeyIdentityAuth).Return(false, nil)
+
+	svc := NewMessageService(&repository.Repository{User: mockUser, SubscribeConfig: mockSub})
+	err := svc.SendSubscribeMsgByBizKey(context.Background(), 10, models.MsgBizKeyIdentityAuth, map[string]string{})
+
+	assert.True(t, errors.Is(err, ErrMessageSkipped))
+}
+
+func TestSendSubscribeMsg_RestoresCountWhenNotSent(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10, OpenID: "openid"}, nil)
+	mockSub := new(MockSubscribeConfigRepo)
+	mockSub.On("DecrementCount", mock.Anything, 10, models.MsgBizKeyCardReceived).Return(true, nil)
+	mockSub.On("RestoreCount", mock.Anything, 10, models.MsgBizKeyCardReceived).Return(nil)
+	mockTemplate := new(MockMsgTemplateConfigRepo)
+	mockTemplate.On("GetByBizKey", mock.Anything, models.MsgBizKeyCardReceived).Return(nil, errors.New("connection reset"))
+
+	svc := NewMessageService(&repository.Repository{User: mockUser, SubscribeConfig: mockSub, MsgTemplate: mockTemplate})
+	err := svc.SendSubscribeMsgByBizKey(context.Background(), 10, models.MsgBizKeyCardReceived, map[string]string{})
+
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, ErrMessageSkipped))
+	mockSub.AssertExpectations(t)
+}
+
+func TestSyncSubscribeStatus_AcceptAddsCount(t *testing.T) {
+	mockSub := new(MockSubscribeConfigRepo)
+	mockSub.On("IncrementCount", mock.Anything, 10, models.MsgBizKeyCardReceived, 1).Return(nil)
+	mockSub.On("UpsertStatus", mock.Anything, 10, models.MsgBizKeyIdentityAuth, models.SubscribeStatusReject).Return(nil)
+	mockSub.On("UpsertStatus", mock.Anything, 10, models.MsgBizKeyUserReply, models.SubscribeStatusReject).Return(nil)
+
+	svc := NewMessageService(&repository.Repository{SubscribeConfig: mockSub})
+	err := svc.SyncSubscribeStatus(context.Background(), 10, []TemplateSyncResult{
+		{BizKey: models.MsgBizKeyCardReceived, Result: "accept"},
+		{BizKey: models.MsgBizKeyIdentityAuth, Result: "reject"},
+		{BizKey: models.MsgBizKeyUserReply, Result: "ban"},
+	})
+
+	require.NoError(t, err)
+	mockSub.AssertExpectations(t)
+}
+
+func TestGetSubscribeCounts_DefaultsToZero(t *testing.T) {
+	mockSub := new(MockSubscribeConfigRepo)
+	mockSub.On("ListByUserID", mock.Anything, 10).Return([]models.SubscribeConfig{
+		{BizKey: models.MsgBizKeyCardReceived, SubscribeCount: 3},
+		{BizKey: models.MsgBizKeyUserReply, SubscribeCount: 1},
+	}, nil)
+
+	svc := NewMessageService(&repository.Repository{SubscribeConfig: mockSub})
+	counts, err := svc.GetSubscribeCounts(context.Background(), 10, []string{models.MsgBizKeyCardReceived, models.MsgBizKeyIdentityAuth})
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		models.MsgBizKeyCardReceived: 3,
+		models.MsgBizKeyIdentityAuth: 0,
+	}, counts)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/realtime"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// notice 一次业务事件对用户的通知：写入站内通知，同时投递微信订阅消息
+type notice struct {
+	UserID    int
+	Type      string // 站内通知类型，见 models.NotificationType*
+	Title     string
+	Content   string
+	RelatedID *int
+	BizKey    string            // 订阅消息业务键，见 models.MsgBizKey*；为空时只发站内通知
+	Data      map[string]string // 订阅消息业务数据
+}
+
+// notifyTx 在业务事务内写入站内通知和待发送的订阅消息，返回写入的站内通知，
+// 事务提交后交给 publishNotification 实时推送
+func notifyTx(ctx context.Context, tx *sqlx.Tx, repo *repository.Repository, n notice) (*models.Notification, error) {
+	notification := &models.Notification{
+		UserID:    n.UserID,
+		Type:      n.Type,
+		Title:     n.Title,
+		Content:   n.Content,
+		RelatedID: n.RelatedID,
+	}
+	if err := repo.Notification.CreateTx(ctx, tx, notification); err != nil {
+		return nil, err
+	}
+	if n.BizKey == "" {
+		return notification, nil
+	}
+	if err := enqueueMessageTx(ctx, tx, repo, n.UserID, n.BizKey, n.Data); err != nil {
+		return nil, err
+	}
+	return notification, nil
+}
+
+// notifyBatchTx 在业务事务内批量写入站内通知和订阅消息，各用一条多行 INSERT，
+// 返回的站内通知与 notices 一一对应
+func notifyBatchTx(ctx context.Context, tx *sqlx.Tx, repo *repository.Repository, notices []notice) ([]*models.Notification, error) {
+	notifications := make([]*models.Notification, len(notices))
+	var msgs []*models.MessageOutbox
+	for i, n := range notices {
+		notifications[i] = &models.Notification{
+			UserID:    n.UserID,
+			Type:      n.Type,
+			Title:     n.Title,
+			Content:   n.Content,
+			RelatedID: n.RelatedID,
+		}
+		if n.BizKey == "" {
+			continue
+		}
+		msg, err := newOutboxMessage(n.UserID, n.BizKey, n.Data)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if err := repo.Notification.CreateBatchTx(ctx, tx, notifications); err != nil {
+		return nil, err
+	}
+	if err := repo.MessageOutbox.CreateBatchTx(ctx, tx, msgs); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// EventPublisher 向在线用户推送实时事件，由 realtime.Hub 实现
+type EventPublisher interface {
+	Publish(ctx context.Context, userID int, event realtime.Event) error
+}
+
+// publishNotification 把已提交的站内通知推送给在线的接收者，推送失败只记录日志，
+// 用户仍可在通知列表中看到
+func publishNotification(ctx context.Context, events EventPublisher, n *models.Notification) {
+	if events == nil || n == nil {
+		return
+	}
+	err := events.Publish(ctx, n.UserID, realtime.Event{Type: realtime.EventNotification, Data: n.ToVO()})
+	if err != nil {
+		log.Printf("[publishNotification] failed to publish notification %d: %v", n.ID, err)
+	}
+}
+
+// NotificationService handles in-app notification business logic.
+type NotificationService struct {
+	repo *repository.Repository
+}
+
+// NewNotificationService creates a new NotificationService.
+func NewNotificationService(repo *repository.Repository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+// NotificationListResult holds a page of notifications with pagination info.
+type NotificationListResult struct {
+	List       []models.Notification
+	Total      int64
+	TotalPages int
+	Page       int
+	Size       int
+}
+
+// ListNotifications returns the notifications of a user, newest first.
+func (s *NotificationService) ListNotifications(ctx context.Context, params repository.NotificationListParams) (*NotificationListResult, error) {
+	params.Page, params.Size = normalizePageParams(params.Page, params.Size)
+
+	notifications, total, err := s.repo.Notification.ListByUserID(ctx, params)
+	if err != nil {
+		log.Printf("[NotificationService.ListNotifications] repository error: %v", err)
+		return nil, ErrInternal("获取通知列表失败")
+	}
+
+	totalPages := int((total + int64(params.Size) - 1) / int64(params.Size))
+	return &NotificationListResult{
+		List:       notifications,
+		Total:      total,
+		TotalPages: totalPages,
+		Page:       params.Page,
+		Size:       params.Size,
+	}, nil
+}
+
+// CountUnread returns the number of unread notifications of a user.
+func (s *NotificationService) CountUnread(ctx context.Context, userID int) (int64, error) {
+	count, err := s.repo.Notification.CountUnread(ctx, userID)
+	if err != nil {
+		log.Printf("[NotificationService.CountUnread] repository error: %v", err)
+		return 0, ErrInternal("获取未读通知数量失败")
+	}
+	return count, nil
+}
+
+// MarkRead marks a notification of the user as read and returns it.
+func (s *NotificationService) MarkRead(ctx context.Context, userID int, id int64) (*models.Notification, error) {
+	n, err := s.repo.Notification.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("[NotificationService.MarkRead] repository error: %v", err)
+		return nil, ErrInternal("获取通知失败")
+	}
+	if n == nil || n.UserID != userID {
+		return nil, ErrNotFound("通知不存在")
+	}
+	if n.IsRead {
+		return n, nil
+	}
+
+	now := time.Now()
+	if err := s.repo.Notification.MarkRead(ctx, userID, id, now); err != nil {
+		log.Printf("[NotificationService.MarkRead] repository error marking read: %v", err)
+		return nil, ErrInternal("标记已读失败")
+	}
+
+	n.IsRead = true
+	n.ReadAt = &now
+	return n, nil
+}
+
+// MarkAllRead marks every unread notification of the user as read and returns how many were updated.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID int) (int64, error) {
+	count, err := s.repo.Notification.MarkAllRead(ctx, userID, time.Now())
+	if err != nil {
+		log.Printf("[NotificationService.MarkAllRead] repository error: %v", err)
+		return 0, ErrInternal("标记已读失败")
+	}
+	return count, nil
+}
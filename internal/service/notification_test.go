@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/api"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/realtime"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockNotificationRepo struct {
+	repository.NotificationRepo
+	mock.Mock
+}
+
+func (m *MockNotificationRepo) CreateTx(ctx context.Context, tx *sqlx.Tx, n *models.Notification) error {
+	args := m.Called(ctx, tx, n)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) CreateBatchTx(ctx context.Context, tx *sqlx.Tx, ns []*models.Notification) error {
+	args := m.Called(ctx, tx, ns)
+	for i, n := range ns {
+		n.ID = int64(i + 1)
+	}
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) GetByID(ctx context.Context, id int64) (*models.Notification, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepo) MarkRead(ctx context.Context, userID int, id int64, readAt time.Time) error {
+	args := m.Called(ctx, userID, id, readAt)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepo) MarkAllRead(ctx context.Context, userID int, readAt time.Time) (int64, error) {
+	args := m.Called(ctx, userID, readAt)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type publishedEvent struct {
+	userID int
+	event  realtime.Event
+}
+
+type stubPublisher struct {
+	published []publishedEvent
+}
+
+func (p *stubPublisher) Publish(_ context.Context, userID int, event realtime.Event) error {
+	p.published = append(p.published, publishedEvent{userID: userID, event: event})
+	return nil
+}
+
+func TestNotifyTx_WritesInboxAndOutbox(t *testing.T) {
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 10 && n.Type == models.NotificationTypeOliveBranchReceived && *n.RelatedID == 5
+	})).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Notification = mockNotification
+	repo.MessageOutbox = mockOutbox
+
+	n, err := notifyTx(context.Background(), nil, repo, notice{
+		UserID:    10,
+		Type:      models.NotificationTypeOliveBranchReceived,
+		Title:     "收到新的橄榄枝邀请",
+		Content:   "张三 邀请您加入项目「快组」，请及时处理。",
+		RelatedID: intPtr(5),
+		BizKey:    models.MsgBizKeyInviteJoin,
+		Data:      map[string]string{"project_name": "快组"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "收到新的橄榄枝邀请", n.Title)
+	mockNotification.AssertExpectations(t)
+	msgs := queuedMessages(mockOutbox)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, models.MsgBizKeyInviteJoin, msgs[0].BizKey)
+}
+
+func TestPublishNotification(t *testing.T) {
+	events := &stubPublisher{}
+	publishNotification(context.Background(), events, &models.Notification{ID: 7, UserID: 10, Title: "项目审核结果"})
+
+	require.Len(t, events.published, 1)
+	assert.Equal(t, 10, events.published[0].userID)
+	assert.Equal(t, realtime.EventNotification, events.published[0].event.Type)
+	assert.Equal(t, int64(7), events.published[0].event.Data.(*api.NotificationVO).Id)
+
+	// 没有推送通道或没有通知时什么也不做
+	publishNotification(context.Background(), nil, &models.Notification{ID: 8})
+	publishNotification(context.Background(), events, nil)
+	assert.Len(t, events.published, 1)
+}
+
+func TestMarkRead_NotOwned(t *testing.T) {
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("GetByID", mock.Anything, int64(1)).Return(&models.Notification{ID: 1, UserID: 20}, nil)
+
+	repo := &repository.Repository{Notification: mockNotification}
+	_, err := NewNotificationService(repo).MarkRead(context.Background(), 10, 1)
+
+	assertServiceError(t, err, ErrCodeNotFound, "通知不存在")
+	mockNotification.AssertNotCalled(t, "MarkRead", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMarkRead_Success(t *testing.T) {
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("GetByID", mock.Anything, int64(1)).Return(&models.Notification{ID: 1, UserID: 10}, nil)
+	mockNotification.On("MarkRead", mock.Anything, 10, int64(1), mock.Anything).Return(nil)
+
+	repo := &repository.Repository{Notification: mockNotification}
+	n, err := NewNotificationService(repo).MarkRead(context.Background(), 10, 1)
+
+	require.NoError(t, err)
+	assert.True(t, n.IsRead)
+	assert.NotNil(t, n.ReadAt)
+	mockNotification.AssertExpectations(t)
+}
+
+func TestMarkAllRead(t *testing.T) {
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("MarkAllRead", mock.Anything, 10, mock.Anything).Return(int64(3), nil)
+
+	repo := &repository.Repository{Notification: mockNotification}
+	count, err := NewNotificationService(repo).MarkAllRead(context.Background(), 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+}
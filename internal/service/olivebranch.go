@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/trv3wood/kuaizu-server/internal/models"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 )
@@ -13,12 +15,13 @@ const dailyFreeQuota = 5
 
 // OliveBranchService handles olive branch business logic.
 type OliveBranchService struct {
-	repo *repository.Repository
+	repo   *repository.Repository
+	events EventPublisher
 }
 
 // NewOliveBranchService creates a new OliveBranchService.
-func NewOliveBranchService(repo *repository.Repository) *OliveBranchService {
-	return &OliveBranchService{repo: repo}
+func NewOliveBranchService(repo *repository.Repository, events EventPublisher) *OliveBranchService {
+	return &OliveBranchService{repo: repo, events: events}
 }
 
 // SendRequest holds the input for sending an olive branch.
@@ -69,64 +72,101 @@ func (s *OliveBranchService) SendOliveBranch(ctx context.Context, userID int, re
 		return nil, ErrBadRequest("已有待处理的橄榄枝，请等待对方处理后再发送")
 	}
 
-	// Get sender for quota check
-	sender, err := s.repo.User.GetByID(ctx, userID)
+	isMember, isFull, err := memberState(ctx, s.repo, project, req.ReceiverID)
 	if err != nil {
-		log.Printf("[OliveBranchService.SendOliveBranch] repository error getting sender: %v", err)
-		return nil, ErrInternal("获取用户信息失败")
+		log.Printf("[OliveBranchService.SendOliveBranch] repository error checking members: %v", err)
+		return nil, ErrInternal("查询项目成员失败")
 	}
-
-	// Determine cost type with daily free quota logic
-	today := time.Now().Truncate(24 * time.Hour)
-	costType := 0
-
-	// Reset free quota if last active date is not today
-	if sender.LastActiveDate == nil || sender.LastActiveDate.Truncate(24*time.Hour).Before(today) {
-		zero := 0
-		sender.FreeBranchUsedToday = &zero
-		sender.LastActiveDate = &today
+	if isMember {
+		return nil, ErrBadRequest("对方已是该项目成员")
 	}
-
-	// Check quota: free first, then paid
-	freeBranchUsedToday := 0
-	if sender.FreeBranchUsedToday != nil {
-		freeBranchUsedToday = *sender.FreeBranchUsedToday
-	}
-	oliveBranchCount := 0
-	if sender.OliveBranchCount != nil {
-		oliveBranchCount = *sender.OliveBranchCount
-	}
-
-	if freeBranchUsedToday < dailyFreeQuota {
-		costType = models.OliveBranchCostFree // Free quota
-		freeBranchUsedToday++
-		sender.FreeBranchUsedToday = &freeBranchUsedToday
-	} else if oliveBranchCount > 0 {
-		costType = models.OliveBranchCostPaid // Paid quota
-		oliveBranchCount--
-		sender.OliveBranchCount = &oliveBranchCount
-	} else {
-		return nil, &ServiceError{Code: ErrorCode(4002), Message: "橄榄枝额度不足，今日免费额度已用完且无付费余额"}
+	if isFull {
+		return nil, ErrBadRequest("项目成员已满，无法继续邀请")
 	}
 
-	// Update user quota
-	if err := s.repo.User.UpdateQuota(ctx, sender); err != nil {
-		log.Printf("[OliveBranchService.SendOliveBranch] repository error updating quota: %v", err)
-		return nil, ErrInternal("更新额度失败")
-	}
-
-	// Create olive branch record
 	ob := &models.OliveBranch{
 		SenderID:         userID,
 		ReceiverID:       req.ReceiverID,
 		RelatedProjectID: req.RelatedProjectID,
-		CostType:         costType,
 		Status:           models.OliveBranchStatusPending,
 	}
 
-	if err := s.repo.OliveBranch.Create(ctx, ob); err != nil {
-		log.Printf("[OliveBranchService.SendOliveBranch] repository error creating olive branch: %v", err)
-		return nil, ErrInternal("发送橄榄枝失败")
+	sender, err := s.repo.User.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("[OliveBranchService.SendOliveBranch] repository error getting sender: %v", err)
+		return nil, ErrInternal("查询用户失败")
+	}
+	senderName := "匿名用户"
+	if sender != nil && sender.Nickname != nil {
+		senderName = *sender.Nickname
+	}
+
+	// Deduct quota, create the record, append the ledger entry and queue the
+	// receiver's notification atomically. The project row is locked first so
+	// concurrent sends for it re-check for a pending branch one at a time.
+	var notification *models.Notification
+	err = runInTx(ctx, s.repo, "OliveBranchService.SendOliveBranch", "发送橄榄枝失败", func(tx *sqlx.Tx) error {
+		if _, err := s.repo.Project.LockMemberCountTx(ctx, tx, req.RelatedProjectID); err != nil {
+			return err
+		}
+		exists, err := s.repo.OliveBranch.ExistsPendingTx(ctx, tx, userID, req.ReceiverID, req.RelatedProjectID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return ErrBadRequest("已有待处理的橄榄枝，请等待对方处理后再发送")
+		}
+
+		costType, err := s.deductQuotaTx(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		ob.CostType = costType
+
+		if err := s.repo.OliveBranch.CreateTx(ctx, tx, ob); err != nil {
+			return err
+		}
+
+		entryType := models.OliveBranchLedgerFreeUse
+		if costType == models.OliveBranchCostPaid {
+			entryType = models.OliveBranchLedgerSend
+		}
+		err = s.repo.OliveBranchLedger.CreateTx(ctx, tx, &models.OliveBranchLedger{
+			UserID:        userID,
+			EntryType:     entryType,
+			CostType:      costType,
+			Amount:        -1,
+			OliveBranchID: &ob.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// 通知接收者收到橄榄枝邀请
+		notification, err = notifyTx(ctx, tx, s.repo, notice{
+			UserID:    req.ReceiverID,
+			Type:      models.NotificationTypeOliveBranchReceived,
+			Title:     "收到新的橄榄枝邀请",
+			Content:   fmt.Sprintf("%s 邀请您加入项目「%s」，请及时处理。", senderName, project.Name),
+			RelatedID: &ob.ID,
+			BizKey:    models.MsgBizKeyInviteJoin,
+			Data: map[string]string{
+				"sender":       senderName,
+				"project_name": project.Name,
+				"remark":       "您收到了新的橄榄枝邀请，请及时处理。",
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	publishNotification(ctx, s.events, notification)
+
+	// Reload sender so the response carries the updated quota
+	sender, err = s.repo.User.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("[OliveBranchService.SendOliveBranch] repository error reloading sender: %v", err)
 	}
 
 	ob.ProjectName = projectName
@@ -169,11 +209,165 @@ func (s *OliveBranchService) HandleOliveBranch(ctx context.Context, userID, bran
 		return nil, err
 	}
 
-	if err := s.repo.OliveBranch.UpdateStatus(ctx, branchID, newStatus); err != nil {
-		log.Printf("[OliveBranchService.HandleOliveBranch] repository error updating status: %v", err)
-		return nil, ErrInternal("处理邀请失败")
+	// 通知发送者邀请处理结果
+	resultStr := "已接受"
+	remark := "对方已接受您的邀请，快去联系TA吧。"
+	if newStatus == models.OliveBranchStatusRejected {
+		resultStr = "已拒绝"
+		remark = "很抱歉，对方婉拒了您的邀请，您可以继续寻找其他人才。"
+	}
+	projectName := ""
+	if ob.ProjectName != nil {
+		projectName = *ob.ProjectName
+	}
+
+	// Conditional update so a branch expired in the meantime cannot be handled
+	var notification *models.Notification
+	err = runInTx(ctx, s.repo, "OliveBranchService.HandleOliveBranch", "处理邀请失败", func(tx *sqlx.Tx) error {
+		updated, err := s.repo.OliveBranch.TransitionStatusTx(ctx, tx, branchID, models.OliveBranchStatusPending, newStatus)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrBadRequest("此邀请已被处理")
+		}
+		// 接受邀请即加入关联项目
+		if newStatus == models.OliveBranchStatusAccepted && ob.RelatedProjectID > 0 {
+			if err := joinProjectTx(ctx, tx, s.repo, ob.RelatedProjectID, ob.ReceiverID, models.ProjectMemberSourceOliveBranch); err != nil {
+				return err
+			}
+		}
+		notification, err = notifyTx(ctx, tx, s.repo, notice{
+			UserID:    ob.SenderID,
+			Type:      models.NotificationTypeOliveBranchHandled,
+			Title:     "橄榄枝邀请" + resultStr,
+			Content:   fmt.Sprintf("您为项目「%s」发出的邀请%s。%s", projectName, resultStr, remark),
+			RelatedID: &ob.ID,
+			BizKey:    models.MsgBizKeyCardDeliveryResult,
+			Data: map[string]string{
+				"project_name":    projectName,
+				"delivery_result": resultStr,
+				"remark":          remark,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+	publishNotification(ctx, s.events, notification)
 
 	ob.Status = newStatus
 	return ob, nil
 }
+
+// deductQuotaTx consumes one olive branch for the sender, today's free quota
+// first and then the paid balance. It returns the cost type that was used.
+func (s *OliveBranchService) deductQuotaTx(ctx context.Context, tx *sqlx.Tx, userID int) (int, error) {
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	used, err := s.repo.User.UseFreeBranchTx(ctx, tx, userID, dailyFreeQuota, today)
+	if err != nil {
+		return 0, err
+	}
+	if used {
+		return models.OliveBranchCostFree, nil
+	}
+
+	deducted, err := s.repo.User.DeductOliveBranchCountTx(ctx, tx, userID, 1)
+	if err != nil {
+		return 0, err
+	}
+	if !deducted {
+		return 0, &ServiceError{Code: ErrorCode(4002), Message: "橄榄枝额度不足，今日免费额度已用完且无付费余额"}
+	}
+	return models.OliveBranchCostPaid, nil
+}
+
+// OliveBranchLedgerResult holds a page of ledger entries with pagination info.
+type OliveBranchLedgerResult struct {
+	List       []models.OliveBranchLedger
+	Total      int64
+	TotalPages int
+	Page       int
+	Size       int
+}
+
+// ListLedger returns the olive branch ledger of a user, newest first.
+func (s *OliveBranchService) ListLedger(ctx context.Context, userID, page, size int) (*OliveBranchLedgerResult, error) {
+	page, size = normalizePageParams(page, size)
+
+	entries, total, err := s.repo.OliveBranchLedger.ListByUserID(ctx, repository.OliveBranchLedgerListParams{
+		UserID: userID,
+		Page:   page,
+		Size:   size,
+	})
+	if err != nil {
+		log.Printf("[OliveBranchService.ListLedger] repository error: %v", err)
+		return nil, ErrInternal("获取橄榄枝流水失败")
+	}
+
+	totalPages := int((total + int64(size) - 1) / int64(size))
+	return &OliveBranchLedgerResult{
+		List:       entries,
+		Total:      total,
+		TotalPages: totalPages,
+		Page:       page,
+		Size:       size,
+	}, nil
+}
+
+// AdminGrant (admin only) adjusts a user's paid olive branch balance and records
+// it in the ledger. A negative amount deducts from the balance.
+func (s *OliveBranchService) AdminGrant(ctx context.Context, userID, amount int, remark string) (*models.User, error) {
+	if amount == 0 {
+		return nil, ErrBadRequest("调整数量不能为0")
+	}
+
+	user, err := s.repo.User.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("[OliveBranchService.AdminGrant] repository error getting user: %v", err)
+		return nil, ErrInternal("获取用户信息失败")
+	}
+	if user == nil {
+		return nil, ErrNotFound("用户不存在")
+	}
+
+	err = runInTx(ctx, s.repo, "OliveBranchService.AdminGrant", "调整橄榄枝余额失败", func(tx *sqlx.Tx) error {
+		if amount > 0 {
+			if err := s.repo.User.AddOliveBranchCountTx(ctx, tx, userID, amount); err != nil {
+				return err
+			}
+		} else {
+			deducted, err := s.repo.User.DeductOliveBranchCountTx(ctx, tx, userID, -amount)
+			if err != nil {
+				return err
+			}
+			if !deducted {
+				return ErrBadRequest("用户橄榄枝余额不足")
+			}
+		}
+
+		entry := &models.OliveBranchLedger{
+			UserID:    userID,
+			EntryType: models.OliveBranchLedgerAdminGrant,
+			CostType:  models.OliveBranchCostPaid,
+			Amount:    amount,
+		}
+		if remark != "" {
+			entry.Remark = &remark
+		}
+		return s.repo.OliveBranchLedger.CreateTx(ctx, tx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	user, err = s.repo.User.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		log.Printf("[OliveBranchService.AdminGrant] repository error reloading user: %v", err)
+		return nil, ErrInternal("获取用户信息失败")
+	}
+	return user, nil
+}
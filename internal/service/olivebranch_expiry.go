@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+const (
+	defaultOliveBranchExpireDays = 7         // 待处理橄榄枝的默认过期天数
+	oliveBranchExpiryInterval    = time.Hour // 扫描过期橄榄枝的间隔
+	oliveBranchExpiryBatchSize   = 100       // 每次处理的橄榄枝数
+)
+
+// oliveBranchTimeoutFromEnv 读取 OLIVE_BRANCH_EXPIRE_DAYS（天数），未配置或格式错误时使用默认值
+func oliveBranchTimeoutFromEnv() time.Duration {
+	days := defaultOliveBranchExpireDays
+	if v := os.Getenv("OLIVE_BRANCH_EXPIRE_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			log.Printf("[oliveBranchTimeoutFromEnv] invalid OLIVE_BRANCH_EXPIRE_DAYS %q, using %d", v, defaultOliveBranchExpireDays)
+		} else {
+			days = n
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// OliveBranchExpiryScheduler 待处理橄榄枝超时忽略
+// 定时把超过期限仍未处理的橄榄枝置为已忽略，使发送者可以重新发送；
+// 使用付费额度发送的橄榄枝退还一次额度，并通知发送者投递结果。
+type OliveBranchExpiryScheduler struct {
+	repo    *repository.Repository
+	events  EventPublisher
+	timeout time.Duration
+}
+
+// NewOliveBranchExpiryScheduler creates an OliveBranchExpiryScheduler using the
+// timeout from environment variables.
+func NewOliveBranchExpiryScheduler(repo *repository.Repository, events EventPublisher) *OliveBranchExpiryScheduler {
+	return NewOliveBranchExpirySchedulerWithTimeout(repo, events, oliveBranchTimeoutFromEnv())
+}
+
+// NewOliveBranchExpirySchedulerWithTimeout creates an OliveBranchExpiryScheduler with an explicit timeout.
+func NewOliveBranchExpirySchedulerWithTimeout(repo *repository.Repository, events EventPublisher, timeout time.Duration) *OliveBranchExpiryScheduler {
+	return &OliveBranchExpiryScheduler{repo: repo, events: events, timeout: timeout}
+}
+
+// Run 持续处理过期橄榄枝，直到 ctx 被取消
+func (s *OliveBranchExpiryScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(oliveBranchExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 处理一批过期橄榄枝，返回置为已忽略的数量
+func (s *OliveBranchExpiryScheduler) RunOnce(ctx context.Context) int {
+	records, err := s.repo.OliveBranch.ListExpiredPending(ctx, time.Now().Add(-s.timeout), oliveBranchExpiryBatchSize)
+	if err != nil {
+		log.Printf("[OliveBranchExpiryScheduler.RunOnce] repository error: %v", err)
+		return 0
+	}
+
+	expired := 0
+	for i := range records {
+		if s.expire(ctx, &records[i]) {
+			expired++
+		}
+	}
+	return expired
+}
+
+// expire 将单个橄榄枝置为已忽略，付费额度一并退还，并在同一事务中通知发送者，
+// 返回是否由本次调用置为已忽略
+func (s *OliveBranchExpiryScheduler) expire(ctx context.Context, ob *models.OliveBranch) bool {
+	tx, err := s.repo.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("[OliveBranchExpiryScheduler.expire] failed to begin transaction: %v", err)
+		return false
+	}
+	defer tx.Rollback()
+
+	ignored, err := s.repo.OliveBranch.TransitionStatusTx(ctx, tx, ob.ID, models.OliveBranchStatusPending, models.OliveBranchStatusIgnored)
+	if err != nil || !ignored {
+		if err != nil {
+			log.Printf("[OliveBranchExpiryScheduler.expire] repository error expiring olive branch %d: %v", ob.ID, err)
+		}
+		return false
+	}
+
+	if ob.CostType == models.OliveBranchCostPaid {
+		if err := s.refundTx(ctx, tx, ob); err != nil {
+			log.Printf("[OliveBranchExpiryScheduler.expire] repository error refunding olive branch %d: %v", ob.ID, err)
+			return false
+		}
+	}
+
+	notification, err := notifyTx(ctx, tx, s.repo, s.notice(ob))
+	if err != nil {
+		log.Printf("[OliveBranchExpiryScheduler.expire] repository error queueing notification for olive branch %d: %v", ob.ID, err)
+		return false
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[OliveBranchExpiryScheduler.expire] failed to commit transaction: %v", err)
+		return false
+	}
+	publishNotification(ctx, s.events, notification)
+	return true
+}
+
+// refundTx 退还发送者一次付费额度并记入流水
+func (s *OliveBranchExpiryScheduler) refundTx(ctx context.Context, tx *sqlx.Tx, ob *models.OliveBranch) error {
+	if err := s.repo.User.AddOliveBranchCountTx(ctx, tx, ob.SenderID, 1); err != nil {
+		return err
+	}
+
+	remark := "对方超时未处理，退还付费额度"
+	branchID := ob.ID
+	return s.repo.OliveBranchLedger.CreateTx(ctx, tx, &models.OliveBranchLedger{
+		UserID:        ob.SenderID,
+		EntryType:     models.OliveBranchLedgerRefund,
+		CostType:      models.OliveBranchCostPaid,
+		Amount:        1,
+		OliveBranchID: &branchID,
+		Remark:        &remark,
+	})
+}
+
+// notice 构造发送给发送者的橄榄枝过期通知
+func (s *OliveBranchExpiryScheduler) notice(ob *models.OliveBranch) notice {
+	projectName := ""
+	if ob.ProjectName != nil {
+		projectName = *ob.ProjectName
+	}
+
+	remark := fmt.Sprintf("对方%d天内未处理，邀请已失效，可以重新发送。", int(s.timeout/(24*time.Hour)))
+	if ob.CostType == models.OliveBranchCostPaid {
+		remark = fmt.Sprintf("对方%d天内未处理，邀请已失效，已退还1个橄榄枝。", int(s.timeout/(24*time.Hour)))
+	}
+
+	branchID := ob.ID
+	return notice{
+		UserID:    ob.SenderID,
+		Type:      models.NotificationTypeOliveBranchExpired,
+		Title:     "橄榄枝邀请已过期",
+		Content:   fmt.Sprintf("您为项目「%s」发出的邀请已过期。%s", projectName, remark),
+		RelatedID: &branchID,
+		BizKey:    models.MsgBizKeyCardDeliveryResult,
+		Data: map[string]string{
+			"project_name":    projectName,
+			"delivery_result": "已过期",
+			"remark":          remark,
+		},
+	}
+}
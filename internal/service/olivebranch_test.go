@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockOliveBranchRepo struct {
+	repository.OliveBranchRepo
+	mock.Mock
+}
+
+func (m *MockOliveBranchRepo) ListExpiredPending(ctx context.Context, before time.Time, limit int) ([]models.OliveBranch, error) {
+	args := m.Called(ctx, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OliveBranch), args.Error(1)
+}
+
+func (m *MockOliveBranchRepo) GetByID(ctx context.Context, id int) (*models.OliveBranch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OliveBranch), args.Error(1)
+}
+
+func (m *MockOliveBranchRepo) TransitionStatusTx(ctx context.Context, tx *sqlx.Tx, id int, from, to int) (bool, error) {
+	args := m.Called(ctx, tx, id, from, to)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOliveBranchRepo) ExistsPending(ctx context.Context, senderID, receiverID, relatedProjectID int) (bool, error) {
+	args := m.Called(ctx, senderID, receiverID, relatedProjectID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOliveBranchRepo) ExistsPendingTx(ctx context.Context, tx *sqlx.Tx, senderID, receiverID, relatedProjectID int) (bool, error) {
+	args := m.Called(ctx, tx, senderID, receiverID, relatedProjectID)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockOliveBranchLedgerRepo struct {
+	mock.Mock
+}
+
+func (m *MockOliveBranchLedgerRepo) CreateTx(ctx context.Context, tx *sqlx.Tx, entry *models.OliveBranchLedger) error {
+	args := m.Called(ctx, tx, entry)
+	return args.Error(0)
+}
+
+func (m *MockOliveBranchLedgerRepo) ListByUserID(ctx context.Context, params repository.OliveBranchLedgerListParams) ([]models.OliveBranchLedger, int64, error) {
+	args := m.Called(ctx, params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]models.OliveBranchLedger), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepo) UseFreeBranchTx(ctx context.Context, tx *sqlx.Tx, userID int, dailyLimit int, today time.Time) (bool, error) {
+	args := m.Called(ctx, tx, userID, dailyLimit, today)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepo) DeductOliveBranchCountTx(ctx context.Context, tx *sqlx.Tx, userID int, count int) (bool, error) {
+	args := m.Called(ctx, tx, userID, count)
+	return args.Bool(0), args.Error(1)
+}
+
+// --- Tests for OliveBranchService quota deduction ---
+
+func TestDeductQuotaTx_FreeQuotaFirst(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("UseFreeBranchTx", mock.Anything, mock.Anything, 10, dailyFreeQuota, mock.Anything).Return(true, nil)
+
+	svc := NewOliveBranchService(&repository.Repository{User: mockUser}, nil)
+	costType, err := svc.deductQuotaTx(context.Background(), nil, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.OliveBranchCostFree, costType)
+	mockUser.AssertNotCalled(t, "DeductOliveBranchCountTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeductQuotaTx_FallsBackToPaidBalance(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("UseFreeBranchTx", mock.Anything, mock.Anything, 10, dailyFreeQuota, mock.Anything).Return(false, nil)
+	mockUser.On("DeductOliveBranchCountTx", mock.Anything, mock.Anything, 10, 1).Return(true, nil)
+
+	svc := NewOliveBranchService(&repository.Repository{User: mockUser}, nil)
+	costType, err := svc.deductQuotaTx(context.Background(), nil, 10)
+
+	require.NoError(t, err)
+	assert.Equal(t, models.OliveBranchCostPaid, costType)
+	mockUser.AssertExpectations(t)
+}
+
+func TestDeductQuotaTx_InsufficientQuota(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("UseFreeBranchTx", mock.Anything, mock.Anything, 10, dailyFreeQuota, mock.Anything).Return(false, nil)
+	mockUser.On("DeductOliveBranchCountTx", mock.Anything, mock.Anything, 10, 1).Return(false, nil)
+
+	svc := NewOliveBranchService(&repository.Repository{User: mockUser}, nil)
+	_, err := svc.deductQuotaTx(context.Background(), nil, 10)
+
+	assertServiceError(t, err, ErrorCode(4002), "橄榄枝额度不足，今日免费额度已用完且无付费余额")
+}
+
+func TestSendOliveBranch_PendingCreatedConcurrently(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, mock.Anything).Return(&models.User{}, nil)
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 3).Return(&models.Project{ID: 3, CreatorID: 10, Name: "快组"}, nil)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 3).Return(0, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("GetActive", mock.Anything, 3, 20).Return(nil, nil)
+	// The pre-check passes, but another send committed before the lock was taken
+	mockBranch := new(MockOliveBranchRepo)
+	mockBranch.On("ExistsPending", mock.Anything, 10, 20, 3).Return(false, nil)
+	mockBranch.On("ExistsPendingTx", mock.Anything, mock.Anything, 10, 20, 3).Return(true, nil)
+
+	repo := newTxTestRepo()
+	repo.User = mockUser
+	repo.Project = mockProject
+	repo.ProjectMember = mockMember
+	repo.OliveBranch = mockBranch
+
+	_, err := NewOliveBranchService(repo, nil).SendOliveBranch(context.Background(), 10, SendRequest{ReceiverID: 20, RelatedProjectID: 3})
+
+	assertServiceError(t, err, ErrCodeBadRequest, "已有待处理的橄榄枝，请等待对方处理后再发送")
+	mockUser.AssertNotCalled(t, "UseFreeBranchTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminGrant_ZeroAmount(t *testing.T) {
+	svc := NewOliveBranchService(&repository.Repository{}, nil)
+	_, err := svc.AdminGrant(context.Background(), 10, 0, "")
+
+	assertServiceError(t, err, ErrCodeBadRequest, "调整数量不能为0")
+}
+
+func TestListLedger_NormalizesPage(t *testing.T) {
+	mockLedger := new(MockOliveBranchLedgerRepo)
+	entries := []models.OliveBranchLedger{{ID: 2, UserID: 10, EntryType: models.OliveBranchLedgerSend, Amount: -1}}
+	mockLedger.On("ListByUserID", mock.Anything, repository.OliveBranchLedgerListParams{UserID: 10, Page: 1, Size: 10}).
+		Return(entries, int64(21), nil)
+
+	svc := NewOliveBranchService(&repository.Repository{OliveBranchLedger: mockLedger}, nil)
+	result, err := svc.ListLedger(context.Background(), 10, 0, 500)
+
+	require.NoError(t, err)
+	assert.Equal(t, entries, result.List)
+	assert.Equal(t, 3, result.TotalPages)
+}
+
+type notifyCall struct {
+	userID int
+	bizKey string
+	data   map[string]string
+}
+
+// stubNotifier records every send and fails them all with err when set.
+type stubNotifier struct {
+	calls []notifyCall
+	err   error
+}
+
+func (n *stubNotifier) SendSubscribeMsgByBizKey(ctx context.Context, userID int, bizKey string, businessData map[string]string) error {
+	n.calls = append(n.calls, notifyCall{userID: userID, bizKey: bizKey, data: businessData})
+	return n.err
+}
+
+// --- Tests for OliveBranchService.HandleOliveBranch ---
+
+func TestHandleOliveBranch_AcceptNotifiesSender(t *testing.T) {
+	mockOB := new(MockOliveBranchRepo)
+	mockOB.On("GetByID", mock.Anything, 1).Return(&models.OliveBranch{
+		ID: 1, SenderID: 10, ReceiverID: 20, RelatedProjectID: 3, Status: models.OliveBranchStatusPending, ProjectName: strPtr("快组"),
+	}, nil)
+	mockOB.On("TransitionStatusTx", mock.Anything, mock.Anything, 1, models.OliveBranchStatusPending, models.OliveBranchStatusAccepted).Return(true, nil)
+	mockProject := new(MockProjectRepo)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 3).Return(0, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("ListActiveUserIDsTx", mock.Anything, mock.Anything, 3, mock.Anything).Return([]int{}, nil)
+	mockMember.On("CountMembersTx", mock.Anything, mock.Anything, 3).Return(5, nil)
+	mockMember.On("AddTx", mock.Anything, mock.Anything, mock.MatchedBy(func(m *models.ProjectMember) bool {
+		return m.ProjectID == 3 && m.UserID == 20 && m.Source == models.ProjectMemberSourceOliveBranch
+	})).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.OliveBranch = mockOB
+	repo.Project = mockProject
+	repo.ProjectMember = mockMember
+	repo.MessageOutbox = mockOutbox
+	repo.Notification = mockNotification
+	events := &stubPublisher{}
+	ob, err := NewOliveBranchService(repo, events).HandleOliveBranch(context.Background(), 20, 1, "ACCEPT")
+
+	require.NoError(t, err)
+	mockMember.AssertExpectations(t)
+	assert.Equal(t, models.OliveBranchStatusAccepted, ob.Status)
+	require.Len(t, events.published, 1)
+	assert.Equal(t, 10, events.published[0].userID)
+	msgs := queuedMessages(mockOutbox)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, 10, msgs[0].UserID)
+	assert.Equal(t, models.MsgBizKeyCardDeliveryResult, msgs[0].BizKey)
+	assert.Contains(t, msgs[0].Payload, "已接受")
+}
+
+func TestHandleOliveBranch_ExpiredMeanwhile(t *testing.T) {
+	mockOB := new(MockOliveBranchRepo)
+	mockOB.On("GetByID", mock.Anything, 1).Return(&models.OliveBranch{
+		ID: 1, SenderID: 10, ReceiverID: 20, Status: models.OliveBranchStatusPending,
+	}, nil)
+	mockOB.On("TransitionStatusTx", mock.Anything, mock.Anything, 1, models.OliveBranchStatusPending, models.OliveBranchStatusRejected).Return(false, nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+
+	repo := newTxTestRepo()
+	repo.OliveBranch = mockOB
+	repo.MessageOutbox = mockOutbox
+	_, err := NewOliveBranchService(repo, nil).HandleOliveBranch(context.Background(), 20, 1, "REJECT")
+
+	assertServiceError(t, err, ErrCodeBadRequest, "此邀请已被处理")
+	assert.Empty(t, queuedMessages(mockOutbox))
+}
+
+// --- Tests for OliveBranchExpiryScheduler ---
+
+func TestOliveBranchExpiry_IgnoresFreeBranchAndNotifiesSender(t *testing.T) {
+	mockOB := new(MockOliveBranchRepo)
+	mockOB.On("ListExpiredPending", mock.Anything, mock.Anything, oliveBranchExpiryBatchSize).Return([]models.OliveBranch{
+		{ID: 1, SenderID: 10, ReceiverID: 20, CostType: models.OliveBranchCostFree, ProjectName: strPtr("快组")},
+	}, nil)
+	mockOB.On("TransitionStatusTx", mock.Anything, mock.Anything, 1, models.OliveBranchStatusPending, models.OliveBranchStatusIgnored).Return(true, nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.OliveBranch = mockOB
+	repo.MessageOutbox = mockOutbox
+	repo.Notification = mockNotification
+	s := NewOliveBranchExpirySchedulerWithTimeout(repo, nil, 7*24*time.Hour)
+
+	assert.Equal(t, 1, s.RunOnce(context.Background()))
+	msgs := queuedMessages(mockOutbox)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, 10, msgs[0].UserID)
+	assert.Equal(t, models.MsgBizKeyCardDeliveryResult, msgs[0].BizKey)
+	assert.Contains(t, msgs[0].Payload, "快组")
+	mockOB.AssertExpectations(t)
+}
+
+func TestOliveBranchExpiry_RefundsPaidBranch(t *testing.T) {
+	mockOB := new(MockOliveBranchRepo)
+	mockOB.On("ListExpiredPending", mock.Anything, mock.Anything, oliveBranchExpiryBatchSize).Return([]models.OliveBranch{
+		{ID: 3, SenderID: 10, CostType: models.OliveBranchCostPaid},
+	}, nil)
+	mockOB.On("TransitionStatusTx", mock.Anything, mock.Anything, 3, models.OliveBranchStatusPending, models.OliveBranchStatusIgnored).Return(true, nil)
+	mockUser := new(MockUserRepo)
+	mockUser.On("AddOliveBranchCountTx", mock.Anything, mock.Anything, 10, 1).Return(nil)
+	mockLedger := new(MockOliveBranchLedgerRepo)
+	mockLedger.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e *models.OliveBranchLedger) bool {
+		return e.EntryType == models.OliveBranchLedgerRefund && e.Amount == 1 && *e.OliveBranchID == 3
+	})).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.OliveBranch = mockOB
+	repo.User = mockUser
+	repo.OliveBranchLedger = mockLedger
+	repo.MessageOutbox = mockOutbox
+	repo.Notification = mockNotification
+	s := NewOliveBranchExpirySchedulerWithTimeout(repo, nil, 7*24*time.Hour)
+
+	assert.Equal(t, 1, s.RunOnce(context.Background()))
+	require.Len(t, queuedMessages(mockOutbox), 1)
+	assert.Contains(t, queuedMessages(mockOutbox)[0].Payload, "已退还1个橄榄枝")
+	mockUser.AssertExpectations(t)
+	mockLedger.AssertExpectations(t)
+}
+
+func TestOliveBranchExpiry_AlreadyHandledSkipsNotification(t *testing.T) {
+	mockOB := new(MockOliveBranchRepo)
+	mockOB.On("ListExpiredPending", mock.Anything, mock.Anything, oliveBranchExpiryBatchSize).Return([]models.OliveBranch{
+		{ID: 2, SenderID: 10, CostType: models.OliveBranchCostFree},
+	}, nil)
+	mockOB.On("TransitionStatusTx", mock.Anything, mock.Anything, 2, models.OliveBranchStatusPending, models.OliveBranchStatusIgnored).Return(false, nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+
+	repo := newTxTestRepo()
+	repo.OliveBranch = mockOB
+	repo.MessageOutbox = mockOutbox
+	s := NewOliveBranchExpirySchedulerWithTimeout(repo, nil, 7*24*time.Hour)
+
+	assert.Equal(t, 0, s.RunOnce(context.Background()))
+	assert.Empty(t, queuedMessages(mockOutbox))
+}
+
+func TestOliveBranchTimeoutFromEnv(t *testing.T) {
+	t.Setenv("OLIVE_BRANCH_EXPIRE_DAYS", "3")
+	assert.Equal(t, 3*24*time.Hour, oliveBranchTimeoutFromEnv())
+
+	t.Setenv("OLIVE_BRANCH_EXPIRE_DAYS", "abc")
+	assert.Equal(t, defaultOliveBranchExpireDays*24*time.Hour, oliveBranchTimeoutFromEnv())
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/trv3wood/kuaizu-server/internal/models"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
@@ -12,12 +13,13 @@ import (
 
 // OrderService handles order-related business logic.
 type OrderService struct {
-	repo *repository.Repository
+	repo       *repository.Repository
+	payTimeout time.Duration
 }
 
 // NewOrderService creates a new OrderService.
 func NewOrderService(repo *repository.Repository) *OrderService {
-	return &OrderService{repo: repo}
+	return &OrderService{repo: repo, payTimeout: orderPayTimeoutFromEnv()}
 }
 
 // CreateOrderItem is the input DTO for creating an order.
@@ -104,6 +106,12 @@ func (s *OrderService) InitiatePayment(ctx context.Context, userID int, openID s
 		return nil, ErrBadRequest("订单状态不允许支付")
 	}
 
+	// 超时未支付的订单等待定时任务关闭，不再发起支付
+	expireAt := order.CreatedAt.Add(s.payTimeout)
+	if !time.Now().Before(expireAt) {
+		return nil, ErrBadRequest("订单已超时，请重新下单")
+	}
+
 	payConfig, err := wechat.DefaultPayConfig()
 	if err != nil {
 		log.Printf("[OrderService.InitiatePayment] wechat config error: %v", err)
@@ -121,7 +129,17 @@ func (s *OrderService) InitiatePayment(ctx context.Context, userID int, openID s
 		description = *order.ProductName
 	}
 
-	outTradeNo := wechat.GenerateOutTradeNo(order.ID)
+	// 复用已有的商户单号，保证超时关单和查单能找到同一笔微信订单
+	var outTradeNo string
+	if order.OutTradeNo != nil && *order.OutTradeNo != "" {
+		outTradeNo = *order.OutTradeNo
+	} else {
+		outTradeNo = wechat.GenerateOutTradeNo(order.ID)
+		if err := s.repo.Order.SetOutTradeNo(ctx, order.ID, outTradeNo); err != nil {
+			log.Printf("[OrderService.InitiatePayment] repository error saving out_trade_no: %v", err)
+			return nil, ErrInternal("创建支付订单失败")
+		}
+	}
 	amountCents := int(order.ActualPaid * 100)
 
 	paymentParams, err := payClient.CreatePrepayOrderWithPayment(
@@ -130,6 +148,7 @@ func (s *OrderService) InitiatePayment(ctx context.Context, userID int, openID s
 		description,
 		openID,
 		amountCents,
+		expireAt,
 	)
 	if err != nil {
 		log.Printf("[OrderService.InitiatePayment] wechat API error: %v", err)
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/wechat"
+)
+
+const (
+	defaultOrderPayTimeout = 30 * time.Minute // 未支付订单的默认超时时间
+	orderExpiryInterval    = time.Minute      // 扫描超时订单的间隔
+	orderExpiryBatchSize   = 50               // 每次处理的订单数
+)
+
+// orderPayTimeoutFromEnv 读取 ORDER_PAY_TIMEOUT（如 "30m"），未配置或格式错误时使用默认值
+func orderPayTimeoutFromEnv() time.Duration {
+	v := os.Getenv("ORDER_PAY_TIMEOUT")
+	if v == "" {
+		return defaultOrderPayTimeout
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		log.Printf("[orderPayTimeoutFromEnv] invalid ORDER_PAY_TIMEOUT %q, using %s", v, defaultOrderPayTimeout)
+		return defaultOrderPayTimeout
+	}
+	return d
+}
+
+// TradeClient 微信支付订单查询和关单，*wechat.PayClient 实现了该接口
+type TradeClient interface {
+	QueryOrderByOutTradeNo(ctx context.Context, outTradeNo string) (*wechat.OrderQueryResult, error)
+	CloseOrder(ctx context.Context, outTradeNo string) error
+}
+
+// OrderExpiryScheduler 未支付订单超时关闭
+// 定时扫描超时的待支付订单：先向微信支付查单，已支付但漏掉回调的订单补发权益；
+// 未支付的订单先关闭微信支付订单，再取消本地订单，避免用户在取消后完成支付。
+type OrderExpiryScheduler struct {
+	repo    *repository.Repository
+	payment *PaymentService
+	trade   TradeClient // 可为空，未配置微信支付时只取消没有发起过支付的订单
+	timeout time.Duration
+}
+
+// NewOrderExpiryScheduler creates an OrderExpiryScheduler using the WeChat Pay
+// configuration from environment variables.
+func NewOrderExpiryScheduler(repo *repository.Repository, payment *PaymentService) *OrderExpiryScheduler {
+	var trade TradeClient
+	payConfig, err := wechat.DefaultPayConfig()
+	if err == nil {
+		var payClient *wechat.PayClient
+		payClient, err = wechat.NewPayClient(payConfig)
+		if err == nil {
+			trade = payClient
+		}
+	}
+	if err != nil {
+		log.Printf("[OrderExpiryScheduler] wechat pay unavailable, orders with a prepay order will not expire: %v", err)
+	}
+
+	return NewOrderExpirySchedulerWithClient(repo, payment, trade, orderPayTimeoutFromEnv())
+}
+
+// NewOrderExpirySchedulerWithClient creates an OrderExpiryScheduler with an explicit trade client.
+func NewOrderExpirySchedulerWithClient(repo *repository.Repository, payment *PaymentService, trade TradeClient, timeout time.Duration) *OrderExpiryScheduler {
+	return &OrderExpiryScheduler{repo: repo, payment: payment, trade: trade, timeout: timeout}
+}
+
+// Run 持续处理超时订单，直到 ctx 被取消
+func (s *OrderExpiryScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(orderExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 处理一批超时订单，返回取消的订单数
+func (s *OrderExpiryScheduler) RunOnce(ctx context.Context) int {
+	orders, err := s.repo.Order.ListExpiredPending(ctx, time.Now().Add(-s.timeout), orderExpiryBatchSize)
+	if err != nil {
+		log.Printf("[OrderExpiryScheduler.RunOnce] repository error: %v", err)
+		return 0
+	}
+
+	cancelled := 0
+	for _, order := range orders {
+		if s.expire(ctx, order) {
+			cancelled++
+		}
+	}
+	return cancelled
+}
+
+// expire 处理单个超时订单，返回是否已取消
+func (s *OrderExpiryScheduler) expire(ctx context.Context, order *models.Order) bool {
+	// 没有发起过支付，直接取消
+	if order.OutTradeNo == nil || *order.OutTradeNo == "" {
+		return s.cancel(ctx, order)
+	}
+	outTradeNo := *order.OutTradeNo
+
+	if s.trade == nil {
+		return false
+	}
+
+	result, err := s.trade.QueryOrderByOutTradeNo(ctx, outTradeNo)
+	if errors.Is(err, wechat.ErrOrderNotExist) {
+		return s.cancel(ctx, order)
+	}
+	if err != nil {
+		log.Printf("[OrderExpiryScheduler.expire] query order %d: %v", order.ID, err)
+		return false
+	}
+
+	switch result.TradeState {
+	case wechat.TradeStateSuccess:
+		s.reconcile(ctx, order, result)
+		return false
+	case wechat.TradeStatePaying:
+		// 用户正在支付，等待下一轮
+		return false
+	case wechat.TradeStateClosed, wechat.TradeStateRevoked:
+		return s.cancel(ctx, order)
+	case wechat.TradeStateNotPay, wechat.TradeStatePayError:
+	default:
+		log.Printf("[OrderExpiryScheduler.expire] order %d has unexpected trade state %s", order.ID, result.TradeState)
+		return false
+	}
+
+	// 先关闭微信支付订单，关单成功后才取消本地订单
+	err = s.trade.CloseOrder(ctx, outTradeNo)
+	if errors.Is(err, wechat.ErrOrderPaid) {
+		// 查单和关单之间完成了支付，下一轮查单时补单
+		log.Printf("[OrderExpiryScheduler.expire] order %d was paid while closing", order.ID)
+		return false
+	}
+	if err != nil && !errors.Is(err, wechat.ErrOrderNotExist) {
+		log.Printf("[OrderExpiryScheduler.expire] close order %d: %v", order.ID, err)
+		return false
+	}
+
+	return s.cancel(ctx, order)
+}
+
+// cancel 取消仍处于待支付状态的订单
+func (s *OrderExpiryScheduler) cancel(ctx context.Context, order *models.Order) bool {
+	cancelled, err := s.repo.Order.CancelIfPending(ctx, order.ID)
+	if err != nil {
+		log.Printf("[OrderExpiryScheduler.cancel] repository error cancelling order %d: %v", order.ID, err)
+		return false
+	}
+	return cancelled
+}
+
+// reconcile 补处理微信支付已成功但未收到回调的订单
+func (s *OrderExpiryScheduler) reconcile(ctx context.Context, order *models.Order, result *wechat.OrderQueryResult) {
+	// 回调可能刚刚到达，重新读取订单状态
+	current, err := s.payment.GetOrder(ctx, order.ID)
+	if err != nil || current == nil || current.Status != models.OrderStatusPending {
+		return
+	}
+
+	payTime := result.SuccessTime
+	if payTime.IsZero() {
+		payTime = time.Now()
+	}
+
+	log.Printf("[OrderExpiryScheduler.reconcile] order %d was paid without callback, processing payment", order.ID)
+	if err := s.payment.ProcessPayment(ctx, current, result.TransactionID, payTime); err != nil {
+		log.Printf("[OrderExpiryScheduler.reconcile] process payment for order %d: %v", order.ID, err)
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/wechat"
+)
+
+type stubTradeClient struct {
+	result   *wechat.OrderQueryResult
+	queryErr error
+	closeErr error
+	closed   []string
+}
+
+func (c *stubTradeClient) QueryOrderByOutTradeNo(ctx context.Context, outTradeNo string) (*wechat.OrderQueryResult, error) {
+	return c.result, c.queryErr
+}
+
+func (c *stubTradeClient) CloseOrder(ctx context.Context, outTradeNo string) error {
+	c.closed = append(c.closed, outTradeNo)
+	return c.closeErr
+}
+
+func newExpiryScheduler(orderRepo *MockOrderRepo, trade TradeClient) *OrderExpiryScheduler {
+	repo := &repository.Repository{Order: orderRepo}
+	return NewOrderExpirySchedulerWithClient(repo, NewPaymentService(repo), trade, 30*time.Minute)
+}
+
+func TestOrderExpiry_CancelsOrderWithoutPrepay(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockOrder.On("ListExpiredPending", mock.Anything, mock.Anything, orderExpiryBatchSize).
+		Return([]*models.Order{{ID: 1, Status: models.OrderStatusPending}}, nil)
+	mockOrder.On("CancelIfPending", mock.Anything, 1).Return(true, nil)
+
+	s := newExpiryScheduler(mockOrder, nil)
+
+	assert.Equal(t, 1, s.RunOnce(context.Background()))
+	mockOrder.AssertExpectations(t)
+}
+
+func TestOrderExpiry_ClosesBeforeCancelling(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockOrder.On("ListExpiredPending", mock.Anything, mock.Anything, orderExpiryBatchSize).
+		Return([]*models.Order{{ID: 2, Status: models.OrderStatusPending, OutTradeNo: strPtr("KZ1_2")}}, nil)
+	mockOrder.On("CancelIfPending", mock.Anything, 2).Return(true, nil)
+
+	trade := &stubTradeClient{result: &wechat.OrderQueryResult{TradeState: wechat.TradeStateNotPay}}
+	s := newExpiryScheduler(mockOrder, trade)
+
+	assert.Equal(t, 1, s.RunOnce(context.Background()))
+	assert.Equal(t, []string{"KZ1_2"}, trade.closed)
+	mockOrder.AssertExpectations(t)
+}
+
+func TestOrderExpiry_KeepsOrderWhenCloseFails(t *testing.T) {
+	cases := []error{wechat.ErrOrderPaid, errors.New("network error")}
+	for _, closeErr := range cases {
+		mockOrder := new(MockOrderRepo)
+		mockOrder.On("ListExpiredPending", mock.Anything, mock.Anything, orderExpiryBatchSize).
+			Return([]*models.Order{{ID: 3, Status: models.OrderStatusPending, OutTradeNo: strPtr("KZ1_3")}}, nil)
+
+		trade := &stubTradeClient{
+			result:   &wechat.OrderQueryResult{TradeState: wechat.TradeStateNotPay},
+			closeErr: closeErr,
+		}
+		s := newExpiryScheduler(mockOrder, trade)
+
+		assert.Equal(t, 0, s.RunOnce(context.Background()), closeErr.Error())
+		mockOrder.AssertNotCalled(t, "CancelIfPending", mock.Anything, mock.Anything)
+	}
+}
+
+func TestOrderExpiry_SkipsPrepayOrderWithoutTradeClient(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockOrder.On("ListExpiredPending", mock.Anything, mock.Anything, orderExpiryBatchSize).
+		Return([]*models.Order{{ID: 4, Status: models.OrderStatusPending, OutTradeNo: strPtr("KZ1_4")}}, nil)
+
+	s := newExpiryScheduler(mockOrder, nil)
+
+	assert.Equal(t, 0, s.RunOnce(context.Background()))
+	mockOrder.AssertNotCalled(t, "CancelIfPending", mock.Anything, mock.Anything)
+}
+
+func TestOrderExpiry_ReconcileSkipsOrderAlreadyPaid(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockOrder.On("ListExpiredPending", mock.Anything, mock.Anything, orderExpiryBatchSize).
+		Return([]*models.Order{{ID: 5, Status: models.OrderStatusPending, OutTradeNo: strPtr("KZ1_5")}}, nil)
+	// 回调已在查单后到达
+	mockOrder.On("GetByID", mock.Anything, 5).Return(&models.Order{ID: 5, Status: models.OrderStatusPaid}, nil)
+
+	trade := &stubTradeClient{result: &wechat.OrderQueryResult{TradeState: wechat.TradeStateSuccess, TransactionID: "tx-5"}}
+	s := newExpiryScheduler(mockOrder, trade)
+
+	assert.Equal(t, 0, s.RunOnce(context.Background()))
+	assert.Empty(t, trade.closed)
+	mockOrder.AssertNotCalled(t, "CancelIfPending", mock.Anything, mock.Anything)
+}
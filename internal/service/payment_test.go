@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+)
+
+func TestProcessPayment_GrantsPendingOrder(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockOrder.On("MarkPaidTx", mock.Anything, mock.Anything, 1, "wx-1", mock.Anything).Return(true, nil)
+	mockProduct := new(MockProductRepo)
+	mockProduct.On("GetByID", mock.Anything, 3).Return(benefitProduct(`{"kind":"project_top","days":7}`), nil)
+	mockEntitlement := new(MockEntitlementRepo)
+	mockEntitlement.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(e *models.UserEntitlement) bool {
+		return e.OrderID == 1 && e.Kind == models.BenefitKindProjectTop && e.Total == 7
+	})).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Order, repo.Product, repo.Entitlement = mockOrder, mockProduct, mockEntitlement
+	err := NewPaymentService(repo).ProcessPayment(context.Background(),
+		&models.Order{ID: 1, UserID: 10, ProductID: 3, Quantity: 1, Status: models.OrderStatusPending}, "wx-1", time.Now())
+
+	require.NoError(t, err)
+	mockEntitlement.AssertExpectations(t)
+}
+
+func TestProcessPayment_AfterRefundGrantsNothing(t *testing.T) {
+	// 退款完成后才到达的支付通知：订单已不是待支付状态
+	mockOrder := new(MockOrderRepo)
+	mockOrder.On("MarkPaidTx", mock.Anything, mock.Anything, 1, "wx-1", mock.Anything).Return(false, nil)
+	mockProduct := new(MockProductRepo)
+	mockEntitlement := new(MockEntitlementRepo)
+
+	repo := newTxTestRepo()
+	repo.Order, repo.Product, repo.Entitlement = mockOrder, mockProduct, mockEntitlement
+	err := NewPaymentService(repo).ProcessPayment(context.Background(),
+		&models.Order{ID: 1, UserID: 10, ProductID: 3, Quantity: 1, Status: models.OrderStatusRefunded}, "wx-1", time.Now())
+
+	require.NoError(t, err)
+	mockOrder.AssertExpectations(t)
+	mockProduct.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	mockEntitlement.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+}
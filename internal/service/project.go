@@ -2,23 +2,56 @@ package service
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/trv3wood/kuaizu-server/api"
 	"github.com/trv3wood/kuaizu-server/internal/models"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 )
 
+// defaultReapplyCooldownDays 申请被拒绝后再次申请同一项目的默认冷却天数
+const defaultReapplyCooldownDays = 3
+
+// reapplyCooldownFromEnv 读取 APPLICATION_REAPPLY_COOLDOWN_DAYS（天数，0 表示不限制），
+// 未配置或格式错误时使用默认值
+func reapplyCooldownFromEnv() time.Duration {
+	days := defaultReapplyCooldownDays
+	if v := os.Getenv("APPLICATION_REAPPLY_COOLDOWN_DAYS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			log.Printf("[reapplyCooldownFromEnv] invalid APPLICATION_REAPPLY_COOLDOWN_DAYS %q, using %d", v, defaultReapplyCooldownDays)
+		} else {
+			days = n
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
 // ProjectService handles project-related business logic.
 type ProjectService struct {
-	repo         *repository.Repository
-	contentAudit *ContentAuditService
-	message      *MessageService
+	repo            *repository.Repository
+	contentAudit    *ContentAuditService
+	events          EventPublisher
+	search          *SearchService
+	skillTags       *SkillTagService
+	reapplyCooldown time.Duration
 }
 
 // NewProjectService creates a new ProjectService.
-func NewProjectService(repo *repository.Repository, contentAudit *ContentAuditService, message *MessageService) *ProjectService {
-	return &ProjectService{repo: repo, contentAudit: contentAudit, message: message}
+func NewProjectService(repo *repository.Repository, contentAudit *ContentAuditService, events EventPublisher, search *SearchService, skillTags *SkillTagService) *ProjectService {
+	return &ProjectService{
+		repo:            repo,
+		contentAudit:    contentAudit,
+		events:          events,
+		search:          search,
+		skillTags:       skillTags,
+		reapplyCooldown: reapplyCooldownFromEnv(),
+	}
 }
 
 // ProjectListResult holds a page of projects with pagination info.
@@ -28,17 +61,34 @@ type ProjectListResult struct {
 	TotalPages int
 	Page       int
 	Size       int
+	Truncated  bool // 搜索命中超过上限，Total 只统计最相关的部分
 }
 
 // ListProjects returns a paginated list of projects with optional filters.
+// With a keyword or skill the projects come from the search index, ordered by
+// relevance and highlighted.
 func (s *ProjectService) ListProjects(ctx context.Context, params repository.ListParams) (*ProjectListResult, error) {
 	params.Page, params.Size = normalizePageParams(params.Page, params.Size)
+	params.RotationSeed = promotionRotationSeed(time.Now())
+
+	matches, err := s.search.Match(ctx, models.SearchDocProject, params.Keyword, params.Skill)
+	if err != nil {
+		return nil, err
+	}
+	if matches != nil {
+		params.IDs, params.Keyword, params.Skill = matches.IDs, nil, nil
+	}
 
 	projects, total, err := s.repo.Project.List(ctx, params)
 	if err != nil {
 		log.Printf("[ProjectService.ListProjects] repository error: %v", err)
 		return nil, ErrInternal("获取项目列表失败")
 	}
+	if matches != nil {
+		for i := range projects {
+			matches.HighlightProject(&projects[i])
+		}
+	}
 
 	totalPages := int((total + int64(params.Size) - 1) / int64(params.Size))
 	return &ProjectListResult{
@@ -47,6 +97,7 @@ func (s *ProjectService) ListProjects(ctx context.Context, params repository.Lis
 		TotalPages: totalPages,
 		Page:       params.Page,
 		Size:       params.Size,
+		Truncated:  matches != nil && matches.Truncated,
 	}, nil
 }
 
@@ -82,6 +133,13 @@ func (s *ProjectService) GetProject(ctx context.Context, id int) (*models.Projec
 		return nil, ErrNotFound("项目不存在")
 	}
 
+	count, err := s.repo.ProjectMember.CountMembers(ctx, id)
+	if err != nil {
+		log.Printf("[ProjectService.GetProject] repository error counting members: %v", err)
+		return nil, ErrInternal("获取项目详情失败")
+	}
+	project.CurrentMemberCount = &count
+
 	// Increment view count (fire and forget)
 	go func(asyncCtx context.Context) {
 		_ = s.repo.Project.IncrementViewCount(asyncCtx, id)
@@ -112,12 +170,18 @@ func (s *ProjectService) CreateProject(ctx context.Context, input CreateProjectI
 		return nil, ErrBadRequest("需求人数必须大于0")
 	}
 
-	// 文字内容审核
+	// 文字内容审核：违规直接拒绝，需复审的内容随项目进入待审核队列
 	auditTexts := []string{input.Name, input.Description}
 	if input.SkillRequirement != nil {
 		auditTexts = append(auditTexts, *input.SkillRequirement)
 	}
-	if err := s.contentAudit.CheckText(ctx, auditTexts...); err != nil {
+	audit := s.contentAudit.AuditText(ctx, TextAuditInput{
+		UserID:  input.CreatorID,
+		Scene:   models.ContentAuditSceneForum,
+		BizType: models.ContentAuditBizProject,
+		Texts:   auditTexts,
+	})
+	if audit.IsRisky() {
 		return nil, ErrBadRequest("内容包含违规信息，请修改后重试")
 	}
 
@@ -156,11 +220,27 @@ func (s *ProjectService) CreateProject(ctx context.Context, input CreateProjectI
 		}
 	}
 
-	if err := s.repo.Project.Create(ctx, project); err != nil {
-		log.Printf("[ProjectService.CreateProject] repository error: %v", err)
-		return nil, ErrInternal("创建项目失败")
+	// 创建项目的同时把创建者登记为队长
+	err := runInTx(ctx, s.repo, "ProjectService.CreateProject", "创建项目失败", func(tx *sqlx.Tx) error {
+		if err := s.repo.Project.CreateTx(ctx, tx, project); err != nil {
+			return err
+		}
+		return s.repo.ProjectMember.AddTx(ctx, tx, &models.ProjectMember{
+			ProjectID: project.ID,
+			UserID:    project.CreatorID,
+			Role:      models.ProjectMemberRoleLeader,
+			Source:    models.ProjectMemberSourceCreator,
+			Status:    models.ProjectMemberStatusActive,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	s.contentAudit.AttachBiz(ctx, audit, project.ID)
+	s.skillTags.LinkProject(ctx, project)
+	s.search.IndexProject(ctx, project)
+
 	return project, nil
 }
 
@@ -208,10 +288,19 @@ func (s *ProjectService) UpdateProject(ctx context.Context, id, userID int, inpu
 	if input.SkillRequirement != nil {
 		auditTexts = append(auditTexts, *input.SkillRequirement)
 	}
+	needsReview := false
 	if len(auditTexts) > 0 {
-		if err := s.contentAudit.CheckText(ctx, auditTexts...); err != nil {
+		audit := s.contentAudit.AuditText(ctx, TextAuditInput{
+			UserID:  userID,
+			Scene:   models.ContentAuditSceneForum,
+			BizType: models.ContentAuditBizProject,
+			BizID:   &id,
+			Texts:   auditTexts,
+		})
+		if audit.IsRisky() {
 			return nil, ErrBadRequest("内容包含违规信息，请修改后重试")
 		}
+		needsReview = audit.NeedsReview()
 	}
 
 	// Apply updates
@@ -251,12 +340,24 @@ func (s *ProjectService) UpdateProject(ctx context.Context, id, userID int, inpu
 		return nil, ErrInternal("更新项目失败")
 	}
 
+	// 需人工复审的修改让已通过的项目回到待审核队列
+	if needsReview && project.Status == models.ProjectStatusApproved {
+		if err := s.repo.Project.UpdateStatus(ctx, id, models.ProjectStatusPending); err != nil {
+			log.Printf("[ProjectService.UpdateProject] repository error resetting status: %v", err)
+			return nil, ErrInternal("更新项目失败")
+		}
+	}
+
 	// Reload to return fresh data
 	updated, err := s.repo.Project.GetByID(ctx, id)
 	if err != nil {
 		log.Printf("[ProjectService.UpdateProject] repository error reloading: %v", err)
 		return nil, ErrInternal("获取项目信息失败")
 	}
+	if input.SkillRequirement != nil {
+		s.skillTags.LinkProject(ctx, updated)
+	}
+	s.search.IndexProject(ctx, updated)
 
 	return updated, nil
 }
@@ -276,10 +377,64 @@ func (s *ProjectService) DeleteProject(ctx context.Context, id, userID int) erro
 		log.Printf("[ProjectService.DeleteProject] repository error: %v", err)
 		return ErrInternal("删除项目失败")
 	}
+	s.search.RemoveProject(ctx, id)
+	if err := s.repo.Favorite.DeleteByTarget(ctx, models.FavoriteTargetProject, id); err != nil {
+		log.Printf("[ProjectService.DeleteProject] repository error deleting favorites: %v", err)
+	}
 
 	return nil
 }
 
+// CloseProject lets the leader close a project so it no longer takes
+// applications. Users who saved the project are notified in the same
+// transaction.
+func (s *ProjectService) CloseProject(ctx context.Context, id, userID int) (*models.Project, error) {
+	project, err := s.repo.Project.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("[ProjectService.CloseProject] repository error getting project: %v", err)
+		return nil, ErrInternal("获取项目失败")
+	}
+	if project == nil {
+		return nil, ErrNotFound("项目不存在")
+	}
+	if project.CreatorID != userID {
+		return nil, ErrForbidden("只有队长可以关闭项目")
+	}
+	if project.Status == models.ProjectStatusClosed {
+		return nil, ErrBadRequest("项目已关闭")
+	}
+
+	userIDs, err := s.repo.Favorite.ListUserIDs(ctx, models.FavoriteTargetProject, id)
+	if err != nil {
+		log.Printf("[ProjectService.CloseProject] repository error listing favorites: %v", err)
+		return nil, ErrInternal("关闭项目失败")
+	}
+	notices := favoriteNotices(userIDs, userID, notice{
+		Type:      models.NotificationTypeFavoriteClosed,
+		Title:     "收藏的项目已关闭",
+		Content:   fmt.Sprintf("您收藏的项目「%s」已被队长关闭，不再接受申请。", project.Name),
+		RelatedID: &project.ID,
+	})
+
+	var notifications []*models.Notification
+	err = runInTx(ctx, s.repo, "ProjectService.CloseProject", "关闭项目失败", func(tx *sqlx.Tx) (err error) {
+		if err := s.repo.Project.UpdateStatusTx(ctx, tx, id, models.ProjectStatusClosed); err != nil {
+			return err
+		}
+		notifications, err = notifyBatchTx(ctx, tx, s.repo, notices)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notifications {
+		publishNotification(ctx, s.events, n)
+	}
+
+	project.Status = models.ProjectStatusClosed
+	return project, nil
+}
+
 // ApplicationListResult holds a page of applications with pagination info.
 type ApplicationListResult struct {
 	List       []models.ProjectApplication
@@ -332,6 +487,10 @@ func (s *ProjectService) ListMyApplications(ctx context.Context, userID int, par
 		return nil, ErrInternal("获取申请列表失败")
 	}
 
+	for i := range applications {
+		applications[i].RedactContact(userID)
+	}
+
 	totalPages := int((total + int64(params.Size) - 1) / int64(params.Size))
 	return &ApplicationListResult{
 		List:       applications,
@@ -344,8 +503,28 @@ func (s *ProjectService) ListMyApplications(ctx context.Context, userID int, par
 
 // ApplyToProjectInput is the DTO for submitting a project application.
 type ApplyToProjectInput struct {
-	ProjectID int
-	UserID    int
+	ProjectID   int
+	UserID      int
+	ApplyReason *string
+	Contact     *string
+}
+
+// checkReapply rejects a new application while the latest one is pending or
+// was rejected within the cooldown; withdrawn applications can be resubmitted
+// right away.
+func (s *ProjectService) checkReapply(latest *models.ProjectApplication) error {
+	if latest == nil {
+		return nil
+	}
+	switch latest.Status {
+	case models.ApplicationStatusPending:
+		return ErrBadRequest("您已申请过该项目，请等待队长审核")
+	case models.ApplicationStatusRejected:
+		if until := latest.UpdatedAt.Add(s.reapplyCooldown); time.Now().Before(until) {
+			return ErrBadRequest(fmt.Sprintf("申请未通过，请于 %s 后再申请", until.Format("2006-01-02 15:04")))
+		}
+	}
+	return nil
 }
 
 // ApplyToProject validates and creates a project application.
@@ -367,61 +546,133 @@ func (s *ProjectService) ApplyToProject(ctx context.Context, input ApplyToProjec
 		return nil, ErrBadRequest("该项目当前不接受申请")
 	}
 
-	exists, err := s.repo.Application.CheckDuplicate(ctx, input.ProjectID, input.UserID)
+	// 待审核的申请不能重复提交；被拒绝后需等待冷却期结束，撤回后可立即重新申请
+	latest, err := s.repo.Application.GetLatest(ctx, input.ProjectID, input.UserID)
 	if err != nil {
-		log.Printf("[ProjectService.ApplyToProject] repository error checking duplicate: %v", err)
+		log.Printf("[ProjectService.ApplyToProject] repository error getting latest application: %v", err)
 		return nil, ErrInternal("检查申请状态失败")
 	}
-	if exists {
-		return nil, ErrBadRequest("您已申请过该项目")
+	if err := s.checkReapply(latest); err != nil {
+		return nil, err
+	}
+
+	isMember, isFull, err := memberState(ctx, s.repo, project, input.UserID)
+	if err != nil {
+		log.Printf("[ProjectService.ApplyToProject] repository error checking members: %v", err)
+		return nil, ErrInternal("检查申请状态失败")
+	}
+	if isMember {
+		return nil, ErrBadRequest("您已是该项目成员")
+	}
+	if isFull {
+		return nil, ErrBadRequest("项目成员已满，暂不接受申请")
+	}
+
+	applicant, err := s.repo.User.GetByID(ctx, input.UserID)
+	if err != nil {
+		log.Printf("[ProjectService.ApplyToProject] repository error getting applicant: %v", err)
+		return nil, ErrInternal("获取用户信息失败")
+	}
+
+	// 申请理由和联系方式需通过文字内容审核
+	var audit *TextAuditResult
+	var auditTexts []string
+	if input.ApplyReason != nil {
+		auditTexts = append(auditTexts, *input.ApplyReason)
+	}
+	if input.Contact != nil {
+		auditTexts = append(auditTexts, *input.Contact)
+	}
+	if len(auditTexts) > 0 {
+		audit = s.contentAudit.AuditText(ctx, TextAuditInput{
+			UserID:  input.UserID,
+			Scene:   models.ContentAuditSceneComment,
+			BizType: models.ContentAuditBizApplication,
+			Texts:   auditTexts,
+		})
+		if audit.IsRisky() {
+			return nil, ErrBadRequest("内容包含违规信息，请修改后重试")
+		}
 	}
 
 	application := &models.ProjectApplication{
-		ProjectID: input.ProjectID,
-		UserID:    input.UserID,
-		Status:    models.ApplicationStatusPending,
+		ProjectID:        input.ProjectID,
+		UserID:           input.UserID,
+		ApplyReason:      input.ApplyReason,
+		Contact:          input.Contact,
+		Status:           models.ApplicationStatusPending,
+		ProjectName:      &project.Name,
+		ProjectCreatorID: project.CreatorID,
 	}
 
-	if err := s.repo.Application.Create(ctx, application); err != nil {
-		log.Printf("[ProjectService.ApplyToProject] repository error creating application: %v", err)
-		return nil, ErrInternal("提交申请失败")
+	senderName := "匿名用户"
+	if applicant != nil && applicant.Nickname != nil {
+		senderName = *applicant.Nickname
 	}
 
-	// 向项目所有者发送收到名片订阅消息
-	go func(asyncCtx context.Context) {
-		// 1. 获取申请人信息
-		applicant, err := s.repo.User.GetByID(asyncCtx, input.UserID)
+	// 创建申请，并在同一事务中通知项目所有者收到名片。先锁定项目行，
+	// 让同一项目的并发申请依次在锁内重新检查最近一次申请
+	var notification *models.Notification
+	err = runInTx(ctx, s.repo, "ProjectService.ApplyToProject", "提交申请失败", func(tx *sqlx.Tx) (err error) {
+		if _, err := s.repo.Project.LockMemberCountTx(ctx, tx, input.ProjectID); err != nil {
+			return err
+		}
+		latest, err := s.repo.Application.GetLatestTx(ctx, tx, input.ProjectID, input.UserID)
 		if err != nil {
-			log.Printf("[ProjectService.ApplyToProject] error getting applicant: %v", err)
-			return
+			return err
 		}
-
-		senderName := "匿名用户"
-		if applicant.Nickname != nil {
-			senderName = *applicant.Nickname
+		if err := s.checkReapply(latest); err != nil {
+			return err
 		}
 
-		// 2. 发送订阅消息
-		data := map[string]string{
-			"sender":       senderName,
-			"project_name": project.Name,
-			"remark":       "您收到了新的名片投递，请及时处理。",
+		if err := s.repo.Application.CreateTx(ctx, tx, application); err != nil {
+			return err
 		}
-
-		err = s.message.SendSubscribeMsgByBizKey(asyncCtx, project.CreatorID, models.MsgBizKeyCardReceived, data)
+		err = s.repo.Application.CreateLogTx(ctx, tx, &models.ApplicationStatusLog{
+			ApplicationID: application.ID,
+			Status:        models.ApplicationStatusPending,
+			OperatorID:    &input.UserID,
+		})
 		if err != nil {
-			log.Printf("[ProjectService.ApplyToProject] notification error: %v", err)
+			return err
 		}
-	}(context.WithoutCancel(ctx))
+		notification, err = notifyTx(ctx, tx, s.repo, notice{
+			UserID:    project.CreatorID,
+			Type:      models.NotificationTypeApplicationReceived,
+			Title:     "收到新的名片投递",
+			Content:   fmt.Sprintf("%s 申请加入项目「%s」，请及时处理。", senderName, project.Name),
+			RelatedID: &application.ID,
+			BizKey:    models.MsgBizKeyCardReceived,
+			Data: map[string]string{
+				"sender":       senderName,
+				"project_name": project.Name,
+				"remark":       "您收到了新的名片投递，请及时处理。",
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	publishNotification(ctx, s.events, notification)
 
+	if audit != nil {
+		s.contentAudit.AttachBiz(ctx, audit, application.ID)
+	}
+
+	application.RedactContact(input.UserID)
 	return application, nil
 }
 
 // ReviewApplication validates and updates the status of a project application.
-func (s *ProjectService) ReviewApplication(ctx context.Context, applicationID, userID int, status api.ApplicationStatus) error {
+// replyMsg is the leader's optional reply to the applicant.
+func (s *ProjectService) ReviewApplication(ctx context.Context, applicationID, userID int, status api.ApplicationStatus, replyMsg *string) error {
 	if err := IsValidStatus("application.status", int(status)); err != nil {
 		return err
 	}
+	if status != models.ApplicationStatusApproved && status != models.ApplicationStatusRejected {
+		return ErrBadRequest("审核结果只能为通过或拒绝")
+	}
 
 	app, err := s.repo.Application.GetByID(ctx, applicationID)
 	if err != nil {
@@ -431,6 +682,9 @@ func (s *ProjectService) ReviewApplication(ctx context.Context, applicationID, u
 	if app == nil {
 		return ErrNotFound("申请不存在")
 	}
+	if app.Status != models.ApplicationStatusPending {
+		return ErrBadRequest("该申请已处理或已撤回")
+	}
 
 	isOwner, err := s.repo.Project.IsOwner(ctx, app.ProjectID, userID)
 	if err != nil {
@@ -441,42 +695,296 @@ func (s *ProjectService) ReviewApplication(ctx context.Context, applicationID, u
 		return ErrForbidden("只有队长可以审核申请")
 	}
 
-	if err := s.repo.Application.UpdateStatus(ctx, applicationID, int(status)); err != nil {
-		log.Printf("[ProjectService.ReviewApplication] repository error updating status: %v", err)
-		return ErrInternal("更新申请状态失败")
+	project, err := s.repo.Project.GetByID(ctx, app.ProjectID)
+	if err != nil {
+		log.Printf("[ProjectService.ReviewApplication] repository error getting project: %v", err)
+		return ErrInternal("获取项目信息失败")
+	}
+	if project == nil {
+		return ErrNotFound("项目不存在")
 	}
 
-	// 向申请人发送名片投递结果通知
-	go func(asyncCtx context.Context) {
-		// 1. 获取项目信息以拿到名称
-		project, err := s.repo.Project.GetByID(asyncCtx, app.ProjectID)
+	if replyMsg != nil {
+		err := s.contentAudit.CheckText(ctx, TextAuditInput{
+			UserID:  userID,
+			Scene:   models.ContentAuditSceneComment,
+			BizType: models.ContentAuditBizApplication,
+			BizID:   &applicationID,
+			Texts:   []string{*replyMsg},
+		})
 		if err != nil {
-			log.Printf("[ProjectService.ReviewApplication] error getting project: %v", err)
-			return
+			return err
 		}
+	}
 
-		// 2. 准备通知数据
-		resultStr := "已通过"
-		remark := "恭喜！您已成功加入项目，请主动联系队长。"
-		if status == models.ApplicationStatusRejected {
-			resultStr = "已拒绝"
-			remark = "很抱歉，您的申请未通过。您可以尝试申请其他感兴趣的项目。"
+	var notification *models.Notification
+	err = runInTx(ctx, s.repo, "ProjectService.ReviewApplication", "更新申请状态失败", func(tx *sqlx.Tx) (err error) {
+		// 条件更新，申请人同时撤回时不会被覆盖
+		updated, err := s.repo.Application.TransitionStatusTx(ctx, tx, applicationID, models.ApplicationStatusPending, int(status), replyMsg)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrBadRequest("该申请已处理或已撤回")
+		}
+		err = s.repo.Application.CreateLogTx(ctx, tx, &models.ApplicationStatusLog{
+			ApplicationID: applicationID,
+			Status:        int(status),
+			OperatorID:    &userID,
+			Remark:        replyMsg,
+		})
+		if err != nil {
+			return err
+		}
+		if status == models.ApplicationStatusApproved {
+			if err := joinProjectTx(ctx, tx, s.repo, app.ProjectID, app.UserID, models.ProjectMemberSourceApplication); err != nil {
+				return err
+			}
 		}
+		notification, err = notifyTx(ctx, tx, s.repo, reviewNotice(app, project.Name, status, replyMsg))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	publishNotification(ctx, s.events, notification)
+	return nil
+}
 
-		data := map[string]string{
-			"project_name":    project.Name,
+// reviewNotice 向申请人发送的名片投递结果通知
+func reviewNotice(app *models.ProjectApplication, projectName string, status api.ApplicationStatus, replyMsg *string) notice {
+	resultStr := "已通过"
+	remark := "恭喜！您已成功加入项目，请主动联系队长。"
+	if status == models.ApplicationStatusRejected {
+		resultStr = "已拒绝"
+		remark = "很抱歉，您的申请未通过。您可以尝试申请其他感兴趣的项目。"
+	}
+	content := fmt.Sprintf("您对项目「%s」的申请%s。%s", projectName, resultStr, remark)
+	if replyMsg != nil {
+		content += "队长回复：" + *replyMsg
+	}
+
+	return notice{
+		UserID:    app.UserID,
+		Type:      models.NotificationTypeApplicationReviewed,
+		Title:     "名片投递结果",
+		Content:   content,
+		RelatedID: &app.ID,
+		BizKey:    models.MsgBizKeyCardDeliveryResult,
+		Data: map[string]string{
+			"project_name":    projectName,
 			"delivery_result": resultStr,
 			"remark":          remark,
+		},
+	}
+}
+
+// maxBatchReviewSize 单次批量审核的申请数上限
+const maxBatchReviewSize = 100
+
+// BatchReviewResult reports which applications a batch review handled.
+type BatchReviewResult struct {
+	ReviewedIDs []int
+	SkippedIDs  []int
+}
+
+// BatchReviewApplications approves or rejects several applications of a project
+// in one transaction. Ownership is checked once; applications of other projects
+// or no longer pending are skipped. Approving more applicants than the remaining
+// roster capacity fails the whole batch.
+func (s *ProjectService) BatchReviewApplications(ctx context.Context, projectID, userID int, applicationIDs []int, status api.ApplicationStatus, replyMsg *string) (*BatchReviewResult, error) {
+	if status != models.ApplicationStatusApproved && status != models.ApplicationStatusRejected {
+		return nil, ErrBadRequest("审核结果只能为通过或拒绝")
+	}
+
+	ids := make([]int, 0, len(applicationIDs))
+	seen := make(map[int]bool, len(applicationIDs))
+	for _, id := range applicationIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, ErrBadRequest("请选择要审核的申请")
+	}
+	if len(ids) > maxBatchReviewSize {
+		return nil, ErrBadRequest(fmt.Sprintf("单次最多审核%d条申请", maxBatchReviewSize))
+	}
+
+	isOwner, err := s.repo.Project.IsOwner(ctx, projectID, userID)
+	if err != nil {
+		log.Printf("[ProjectService.BatchReviewApplications] repository error checking ownership: %v", err)
+		return nil, ErrInternal("检查权限失败")
+	}
+	if !isOwner {
+		return nil, ErrForbidden("只有队长可以审核申请")
+	}
+
+	project, err := s.repo.Project.GetByID(ctx, projectID)
+	if err != nil {
+		log.Printf("[ProjectService.BatchReviewApplications] repository error getting project: %v", err)
+		return nil, ErrInternal("获取项目信息失败")
+	}
+	if project == nil {
+		return nil, ErrNotFound("项目不存在")
+	}
+
+	if replyMsg != nil {
+		err := s.contentAudit.CheckText(ctx, TextAuditInput{
+			UserID:  userID,
+			Scene:   models.ContentAuditSceneComment,
+			BizType: models.ContentAuditBizApplication,
+			Texts:   []string{*replyMsg},
+		})
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// 3. 发送消息给申请人 (app.UserID)
-		err = s.message.SendSubscribeMsgByBizKey(asyncCtx, app.UserID, models.MsgBizKeyCardDeliveryResult, data)
+	result := &BatchReviewResult{ReviewedIDs: []int{}, SkippedIDs: []int{}}
+	var notifications []*models.Notification
+	err = runInTx(ctx, s.repo, "ProjectService.BatchReviewApplications", "批量审核失败", func(tx *sqlx.Tx) (err error) {
+		apps, err := s.repo.Application.LockPendingTx(ctx, tx, projectID, ids)
 		if err != nil {
-			log.Printf("[ProjectService.ReviewApplication] notification error: %v", err)
+			return err
+		}
+		if len(apps) == 0 {
+			return nil
 		}
-	}(context.WithoutCancel(ctx))
 
-	return nil
+		reviewedIDs := make([]int, len(apps))
+		applicantIDs := make([]int, len(apps))
+		logs := make([]*models.ApplicationStatusLog, len(apps))
+		notices := make([]notice, len(apps))
+		for i := range apps {
+			reviewedIDs[i] = apps[i].ID
+			applicantIDs[i] = apps[i].UserID
+			logs[i] = &models.ApplicationStatusLog{
+				ApplicationID: apps[i].ID,
+				Status:        int(status),
+				OperatorID:    &userID,
+				Remark:        replyMsg,
+			}
+			notices[i] = reviewNotice(&apps[i], project.Name, status, replyMsg)
+		}
+
+		if err := s.repo.Application.UpdateStatusBatchTx(ctx, tx, reviewedIDs, int(status), replyMsg); err != nil {
+			return err
+		}
+		if err := s.repo.Application.CreateLogsTx(ctx, tx, logs); err != nil {
+			return err
+		}
+		if status == models.ApplicationStatusApproved {
+			if err := joinProjectBatchTx(ctx, tx, s.repo, projectID, applicantIDs, models.ProjectMemberSourceApplication); err != nil {
+				return err
+			}
+		}
+		notifications, err = notifyBatchTx(ctx, tx, s.repo, notices)
+		if err != nil {
+			return err
+		}
+
+		result.ReviewedIDs = reviewedIDs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notifications {
+		publishNotification(ctx, s.events, n)
+	}
+
+	reviewed := make(map[int]bool, len(result.ReviewedIDs))
+	for _, id := range result.ReviewedIDs {
+		reviewed[id] = true
+	}
+	for _, id := range ids {
+		if !reviewed[id] {
+			result.SkippedIDs = append(result.SkippedIDs, id)
+		}
+	}
+	return result, nil
+}
+
+// WithdrawApplication lets the applicant withdraw a pending application.
+func (s *ProjectService) WithdrawApplication(ctx context.Context, applicationID, userID int) error {
+	app, err := s.repo.Application.GetByID(ctx, applicationID)
+	if err != nil {
+		log.Printf("[ProjectService.WithdrawApplication] repository error getting application: %v", err)
+		return ErrInternal("获取申请信息失败")
+	}
+	if app == nil {
+		return ErrNotFound("申请不存在")
+	}
+	if app.UserID != userID {
+		return ErrForbidden("只能撤回自己的申请")
+	}
+	if app.Status != models.ApplicationStatusPending {
+		return ErrBadRequest("只能撤回待审核的申请")
+	}
+
+	return runInTx(ctx, s.repo, "ProjectService.WithdrawApplication", "撤回申请失败", func(tx *sqlx.Tx) error {
+		updated, err := s.repo.Application.TransitionStatusTx(ctx, tx, applicationID, models.ApplicationStatusPending, models.ApplicationStatusWithdrawn, nil)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrBadRequest("只能撤回待审核的申请")
+		}
+		return s.repo.Application.CreateLogTx(ctx, tx, &models.ApplicationStatusLog{
+			ApplicationID: applicationID,
+			Status:        models.ApplicationStatusWithdrawn,
+			OperatorID:    &userID,
+		})
+	})
+}
+
+// ListApplicationHistory returns every application the applicant of applicationID
+// made to the same project, newest first, each with its status changes. Only the
+// project leader and the applicant may view it.
+func (s *ProjectService) ListApplicationHistory(ctx context.Context, applicationID, userID int) ([]models.ProjectApplication, error) {
+	app, err := s.repo.Application.GetByID(ctx, applicationID)
+	if err != nil {
+		log.Printf("[ProjectService.ListApplicationHistory] repository error getting application: %v", err)
+		return nil, ErrInternal("获取申请信息失败")
+	}
+	if app == nil {
+		return nil, ErrNotFound("申请不存在")
+	}
+	if userID != app.UserID && userID != app.ProjectCreatorID {
+		return nil, ErrForbidden("无权查看该申请")
+	}
+
+	attempts, err := s.repo.Application.ListAttempts(ctx, app.ProjectID, app.UserID)
+	if err != nil {
+		log.Printf("[ProjectService.ListApplicationHistory] repository error listing attempts: %v", err)
+		return nil, ErrInternal("获取申请历史失败")
+	}
+
+	ids := make([]int, len(attempts))
+	for i := range attempts {
+		ids[i] = attempts[i].ID
+	}
+	logs, err := s.repo.Application.ListLogs(ctx, ids)
+	if err != nil {
+		log.Printf("[ProjectService.ListApplicationHistory] repository error listing logs: %v", err)
+		return nil, ErrInternal("获取申请历史失败")
+	}
+
+	logsByApp := make(map[int][]models.ApplicationStatusLog, len(attempts))
+	for _, l := range logs {
+		logsByApp[l.ApplicationID] = append(logsByApp[l.ApplicationID], l)
+	}
+	for i := range attempts {
+		attempts[i].StatusLogs = logsByApp[attempts[i].ID]
+		if attempts[i].StatusLogs == nil {
+			attempts[i].StatusLogs = []models.ApplicationStatusLog{}
+		}
+		attempts[i].RedactContact(userID)
+	}
+
+	return attempts, nil
 }
 
 // ReviewProject (admin only) updates project status and notifies creator.
@@ -490,32 +998,38 @@ func (s *ProjectService) ReviewProject(ctx context.Context, id, status int) erro
 		return ErrNotFound("项目不存在")
 	}
 
-	if err := s.repo.Project.UpdateStatus(ctx, id, status); err != nil {
-		log.Printf("[ProjectService.ReviewProject] repository error updating status: %v", err)
-		return ErrInternal("审核失败")
-	}
-
 	// 向项目负责人发送审核结果通知
-	go func(asyncCtx context.Context) {
-		statusStr := "已通过"
-		remark := "恭喜！您的项目已通过审核，现在对其他用户可见。"
-		if status == models.ProjectStatusRejected {
-			statusStr = "已驳回"
-			remark = "很抱歉，您的项目未通过审核，请检查内容是否合规。"
-		}
-
-		data := map[string]string{
-			"project_name": project.Name,
-			"status":       statusStr,
-			"apply_time":   project.UpdatedAt.Format("2006-01-02 15:04:05"),
-			"remark":       remark,
-		}
+	statusStr := "已通过"
+	remark := "恭喜！您的项目已通过审核，现在对其他用户可见。"
+	if status == models.ProjectStatusRejected {
+		statusStr = "已驳回"
+		remark = "很抱歉，您的项目未通过审核，请检查内容是否合规。"
+	}
 
-		err = s.message.SendSubscribeMsgByBizKey(asyncCtx, project.CreatorID, models.MsgBizKeyAuditResultProj, data)
-		if err != nil {
-			log.Printf("[ProjectService.ReviewProject] notification error: %v", err)
+	var notification *models.Notification
+	err = runInTx(ctx, s.repo, "ProjectService.ReviewProject", "审核失败", func(tx *sqlx.Tx) (err error) {
+		if err := s.repo.Project.UpdateStatusTx(ctx, tx, id, status); err != nil {
+			return err
 		}
-	}(context.WithoutCancel(ctx))
-
+		notification, err = notifyTx(ctx, tx, s.repo, notice{
+			UserID:    project.CreatorID,
+			Type:      models.NotificationTypeProjectAudit,
+			Title:     "项目审核结果",
+			Content:   fmt.Sprintf("您的项目「%s」审核%s。%s", project.Name, statusStr, remark),
+			RelatedID: &project.ID,
+			BizKey:    models.MsgBizKeyAuditResultProj,
+			Data: map[string]string{
+				"project_name": project.Name,
+				"status":       statusStr,
+				"apply_time":   project.UpdatedAt.Format("2006-01-02 15:04:05"),
+				"remark":       remark,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	publishNotification(ctx, s.events, notification)
 	return nil
 }
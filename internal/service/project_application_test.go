@@ -0,0 +1,509 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockApplicationRepo struct {
+	repository.ApplicationRepo
+	mock.Mock
+}
+
+func (m *MockApplicationRepo) List(ctx context.Context, params repository.ApplicationListParams) ([]models.ProjectApplication, int64, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]models.ProjectApplication), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockApplicationRepo) GetByID(ctx context.Context, id int) (*models.ProjectApplication, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectApplication), args.Error(1)
+}
+
+func (m *MockApplicationRepo) GetLatest(ctx context.Context, projectID, userID int) (*models.ProjectApplication, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectApplication), args.Error(1)
+}
+
+func (m *MockApplicationRepo) GetLatestTx(ctx context.Context, tx *sqlx.Tx, projectID, userID int) (*models.ProjectApplication, error) {
+	args := m.Called(ctx, tx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectApplication), args.Error(1)
+}
+
+func (m *MockApplicationRepo) ListAttempts(ctx context.Context, projectID, userID int) ([]models.ProjectApplication, error) {
+	args := m.Called(ctx, projectID, userID)
+	return args.Get(0).([]models.ProjectApplication), args.Error(1)
+}
+
+func (m *MockApplicationRepo) CreateTx(ctx context.Context, tx *sqlx.Tx, app *models.ProjectApplication) error {
+	args := m.Called(ctx, tx, app)
+	app.ID = 100
+	return args.Error(0)
+}
+
+func (m *MockApplicationRepo) TransitionStatusTx(ctx context.Context, tx *sqlx.Tx, id, from, to int, replyMsg *string) (bool, error) {
+	args := m.Called(ctx, tx, id, from, to, replyMsg)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockApplicationRepo) LockPendingTx(ctx context.Context, tx *sqlx.Tx, projectID int, ids []int) ([]models.ProjectApplication, error) {
+	args := m.Called(ctx, tx, projectID, ids)
+	return args.Get(0).([]models.ProjectApplication), args.Error(1)
+}
+
+func (m *MockApplicationRepo) UpdateStatusBatchTx(ctx context.Context, tx *sqlx.Tx, ids []int, status int, replyMsg *string) error {
+	args := m.Called(ctx, tx, ids, status, replyMsg)
+	return args.Error(0)
+}
+
+func (m *MockApplicationRepo) CreateLogsTx(ctx context.Context, tx *sqlx.Tx, logs []*models.ApplicationStatusLog) error {
+	args := m.Called(ctx, tx, logs)
+	return args.Error(0)
+}
+
+func (m *MockApplicationRepo) CreateLogTx(ctx context.Context, tx *sqlx.Tx, l *models.ApplicationStatusLog) error {
+	args := m.Called(ctx, tx, l)
+	return args.Error(0)
+}
+
+func (m *MockApplicationRepo) ListLogs(ctx context.Context, applicationIDs []int) ([]models.ApplicationStatusLog, error) {
+	args := m.Called(ctx, applicationIDs)
+	return args.Get(0).([]models.ApplicationStatusLog), args.Error(1)
+}
+
+// newApplicationTestService wires a ProjectService whose content audit runs the local rules.
+func newApplicationTestService(t *testing.T, repo *repository.Repository) *ProjectService {
+	local, err := NewLocalRuleChecker(defaultLocalRules)
+	require.NoError(t, err)
+	return NewProjectService(repo, NewContentAuditServiceWithCheckers(repo, nil, local), nil, nil, nil)
+}
+
+func TestApplyToProject_RiskyReasonRejected(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Status: models.ProjectStatusApproved}, nil)
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetLatest", mock.Anything, 1, 10).Return(nil, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("GetActive", mock.Anything, 1, 10).Return(nil, nil)
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10}, nil)
+	mockAudit := new(MockContentAuditRepo)
+	mockAudit.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Project = mockProject
+	repo.Application = mockApp
+	repo.ProjectMember = mockMember
+	repo.User = mockUser
+	repo.ContentAudit = mockAudit
+
+	_, err := newApplicationTestService(t, repo).ApplyToProject(context.Background(), ApplyToProjectInput{
+		ProjectID:   1,
+		UserID:      10,
+		ApplyReason: strPtr("刷单兼职，日结"),
+	})
+
+	assertServiceError(t, err, ErrCodeBadRequest, "内容包含违规信息，请修改后重试")
+	mockApp.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplyToProject_SavesReasonAndHidesContact(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组", Status: models.ProjectStatusApproved, MemberCount: intPtr(3)}, nil)
+	mockApp := new(MockApplicationRepo)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 1).Return(3, nil)
+	mockApp.On("GetLatest", mock.Anything, 1, 10).Return(nil, nil)
+	mockApp.On("GetLatestTx", mock.Anything, mock.Anything, 1, 10).Return(nil, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("GetActive", mock.Anything, 1, 10).Return(nil, nil)
+	mockMember.On("CountMembers", mock.Anything, 1).Return(2, nil)
+	mockApp.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(a *models.ProjectApplication) bool {
+		return *a.ApplyReason == "想参与前端开发" && *a.Contact == "13800000000"
+	})).Return(nil)
+	mockApp.On("CreateLogTx", mock.Anything, mock.Anything, mock.MatchedBy(func(l *models.ApplicationStatusLog) bool {
+		return l.ApplicationID == 100 && l.Status == models.ApplicationStatusPending && *l.OperatorID == 10
+	})).Return(nil)
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10, Nickname: strPtr("张三")}, nil)
+	mockAudit := new(MockContentAuditRepo)
+	mockAudit.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockAudit.On("AttachBizID", mock.Anything, mock.Anything, 100).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Project = mockProject
+	repo.Application = mockApp
+	repo.ProjectMember = mockMember
+	repo.User = mockUser
+	repo.ContentAudit = mockAudit
+	repo.Notification = mockNotification
+	repo.MessageOutbox = mockOutbox
+
+	app, err := newApplicationTestService(t, repo).ApplyToProject(context.Background(), ApplyToProjectInput{
+		ProjectID:   1,
+		UserID:      10,
+		ApplyReason: strPtr("想参与前端开发"),
+		Contact:     strPtr("13800000000"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "想参与前端开发", *app.ApplyReason)
+	assert.Nil(t, app.Contact, "pending application must not reveal contact to the applicant")
+	mockApp.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestApplyToProject_FullTeamRejected(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Status: models.ProjectStatusApproved, MemberCount: intPtr(2)}, nil)
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetLatest", mock.Anything, 1, 10).Return(nil, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("GetActive", mock.Anything, 1, 10).Return(nil, nil)
+	mockMember.On("CountMembers", mock.Anything, 1).Return(2, nil)
+
+	repo := &repository.Repository{Project: mockProject, Application: mockApp, ProjectMember: mockMember}
+	_, err := newApplicationTestService(t, repo).ApplyToProject(context.Background(), ApplyToProjectInput{ProjectID: 1, UserID: 10})
+
+	assertServiceError(t, err, ErrCodeBadRequest, "项目成员已满，暂不接受申请")
+}
+
+func TestReviewApplication_SavesReplyAndNotifies(t *testing.T) {
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetByID", mock.Anything, 5).Return(&models.ProjectApplication{ID: 5, ProjectID: 1, UserID: 10}, nil)
+	mockApp.On("TransitionStatusTx", mock.Anything, mock.Anything, 5, models.ApplicationStatusPending, models.ApplicationStatusApproved, strPtr("欢迎加入")).Return(true, nil)
+	mockApp.On("CreateLogTx", mock.Anything, mock.Anything, mock.MatchedBy(func(l *models.ApplicationStatusLog) bool {
+		return l.Status == models.ApplicationStatusApproved && *l.OperatorID == 20 && *l.Remark == "欢迎加入"
+	})).Return(nil)
+	mockProject := new(MockProjectRepo)
+	mockProject.On("IsOwner", mock.Anything, 1, 20).Return(true, nil)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组"}, nil)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 1).Return(3, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("ListActiveUserIDsTx", mock.Anything, mock.Anything, 1, mock.Anything).Return([]int{}, nil)
+	mockMember.On("CountMembersTx", mock.Anything, mock.Anything, 1).Return(0, nil)
+	mockMember.On("AddTx", mock.Anything, mock.Anything, mock.MatchedBy(func(m *models.ProjectMember) bool {
+		return m.ProjectID == 1 && m.UserID == 10 && m.Source == models.ProjectMemberSourceApplication
+	})).Return(nil)
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 20).Return(&models.User{ID: 20}, nil)
+	mockAudit := new(MockContentAuditRepo)
+	mockAudit.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 10 && n.Type == models.NotificationTypeApplicationReviewed
+	})).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Application = mockApp
+	repo.Project = mockProject
+	repo.ProjectMember = mockMember
+	repo.User = mockUser
+	repo.ContentAudit = mockAudit
+	repo.Notification = mockNotification
+	repo.MessageOutbox = mockOutbox
+
+	err := newApplicationTestService(t, repo).ReviewApplication(context.Background(), 5, 20, models.ApplicationStatusApproved, strPtr("欢迎加入"))
+
+	require.NoError(t, err)
+	mockApp.AssertExpectations(t)
+	mockMember.AssertExpectations(t)
+	n := mockNotification.Calls[0].Arguments.Get(2).(*models.Notification)
+	assert.Contains(t, n.Content, "队长回复：欢迎加入")
+}
+
+func TestListMyApplications_ContactOnlyAfterApproval(t *testing.T) {
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("List", mock.Anything, mock.Anything).Return([]models.ProjectApplication{
+		{ID: 1, UserID: 10, ProjectCreatorID: 20, Status: models.ApplicationStatusPending, Contact: strPtr("wx-a")},
+		{ID: 2, UserID: 10, ProjectCreatorID: 21, Status: models.ApplicationStatusApproved, Contact: strPtr("wx-b")},
+		{ID: 3, UserID: 10, ProjectCreatorID: 22, Status: models.ApplicationStatusRejected, Contact: strPtr("wx-c")},
+	}, int64(3), nil)
+
+	svc := NewProjectService(&repository.Repository{Application: mockApp}, nil, nil, nil, nil)
+	result, err := svc.ListMyApplications(context.Background(), 10, repository.ApplicationListParams{})
+
+	require.NoError(t, err)
+	assert.Nil(t, result.List[0].Contact)
+	assert.Equal(t, "wx-b", *result.List[1].Contact)
+	assert.Nil(t, result.List[2].Contact)
+}
+
+func TestApplyToProject_RejectedWithinCooldown(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Status: models.ProjectStatusApproved}, nil)
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetLatest", mock.Anything, 1, 10).Return(&models.ProjectApplication{
+		ID: 5, Status: models.ApplicationStatusRejected, UpdatedAt: time.Now().Add(-time.Hour),
+	}, nil)
+
+	repo := &repository.Repository{Project: mockProject, Application: mockApp}
+	svc := newApplicationTestService(t, repo)
+	svc.reapplyCooldown = 24 * time.Hour
+	_, err := svc.ApplyToProject(context.Background(), ApplyToProjectInput{ProjectID: 1, UserID: 10})
+
+	var svcErr *ServiceError
+	require.ErrorAs(t, err, &svcErr)
+	assert.Equal(t, ErrCodeBadRequest, svcErr.Code)
+	assert.Contains(t, svcErr.Message, "申请未通过，请于")
+}
+
+func TestApplyToProject_PendingCreatedConcurrently(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组", Status: models.ProjectStatusApproved}, nil)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 1).Return(0, nil)
+	// The pre-check passes, but another submission committed before the lock was taken
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetLatest", mock.Anything, 1, 10).Return(nil, nil)
+	mockApp.On("GetLatestTx", mock.Anything, mock.Anything, 1, 10).Return(&models.ProjectApplication{ID: 6, Status: models.ApplicationStatusPending}, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("GetActive", mock.Anything, 1, 10).Return(nil, nil)
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10}, nil)
+
+	repo := newTxTestRepo()
+	repo.Project = mockProject
+	repo.Application = mockApp
+	repo.ProjectMember = mockMember
+	repo.User = mockUser
+
+	_, err := newApplicationTestService(t, repo).ApplyToProject(context.Background(), ApplyToProjectInput{ProjectID: 1, UserID: 10})
+
+	assertServiceError(t, err, ErrCodeBadRequest, "您已申请过该项目，请等待队长审核")
+	mockApp.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplyToProject_ReapplyAfterCooldownOrWithdraw(t *testing.T) {
+	for name, latest := range map[string]*models.ProjectApplication{
+		"rejected long ago": {ID: 5, Status: models.ApplicationStatusRejected, UpdatedAt: time.Now().Add(-48 * time.Hour)},
+		"withdrawn":         {ID: 5, Status: models.ApplicationStatusWithdrawn, UpdatedAt: time.Now()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockProject := new(MockProjectRepo)
+			mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组", Status: models.ProjectStatusApproved}, nil)
+			mockApp := new(MockApplicationRepo)
+			mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 1).Return(0, nil)
+			mockApp.On("GetLatest", mock.Anything, 1, 10).Return(latest, nil)
+			mockApp.On("GetLatestTx", mock.Anything, mock.Anything, 1, 10).Return(latest, nil)
+			mockApp.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockApp.On("CreateLogTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockMember := new(MockProjectMemberRepo)
+			mockMember.On("GetActive", mock.Anything, 1, 10).Return(nil, nil)
+			mockUser := new(MockUserRepo)
+			mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10}, nil)
+			mockNotification := new(MockNotificationRepo)
+			mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			mockOutbox := new(MockMessageOutboxRepo)
+			mockOutbox.On("CreateTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			repo := newTxTestRepo()
+			repo.Project = mockProject
+			repo.Application = mockApp
+			repo.ProjectMember = mockMember
+			repo.User = mockUser
+			repo.Notification = mockNotification
+			repo.MessageOutbox = mockOutbox
+			svc := newApplicationTestService(t, repo)
+			svc.reapplyCooldown = 24 * time.Hour
+
+			app, err := svc.ApplyToProject(context.Background(), ApplyToProjectInput{ProjectID: 1, UserID: 10})
+
+			require.NoError(t, err)
+			assert.Equal(t, 100, app.ID)
+		})
+	}
+}
+
+func TestWithdrawApplication(t *testing.T) {
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetByID", mock.Anything, 5).Return(&models.ProjectApplication{ID: 5, ProjectID: 1, UserID: 10, Status: models.ApplicationStatusPending}, nil)
+	mockApp.On("TransitionStatusTx", mock.Anything, mock.Anything, 5, models.ApplicationStatusPending, models.ApplicationStatusWithdrawn, (*string)(nil)).Return(true, nil)
+	mockApp.On("CreateLogTx", mock.Anything, mock.Anything, mock.MatchedBy(func(l *models.ApplicationStatusLog) bool {
+		return l.ApplicationID == 5 && l.Status == models.ApplicationStatusWithdrawn
+	})).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Application = mockApp
+
+	err := NewProjectService(repo, nil, nil, nil, nil).WithdrawApplication(context.Background(), 5, 10)
+
+	require.NoError(t, err)
+	mockApp.AssertExpectations(t)
+}
+
+func TestWithdrawApplication_NotPending(t *testing.T) {
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetByID", mock.Anything, 5).Return(&models.ProjectApplication{ID: 5, UserID: 10, Status: models.ApplicationStatusApproved}, nil)
+
+	err := NewProjectService(&repository.Repository{Application: mockApp}, nil, nil, nil, nil).WithdrawApplication(context.Background(), 5, 10)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "只能撤回待审核的申请")
+}
+
+func TestWithdrawApplication_NotApplicant(t *testing.T) {
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetByID", mock.Anything, 5).Return(&models.ProjectApplication{ID: 5, UserID: 10, Status: models.ApplicationStatusPending}, nil)
+
+	err := NewProjectService(&repository.Repository{Application: mockApp}, nil, nil, nil, nil).WithdrawApplication(context.Background(), 5, 11)
+
+	assertServiceError(t, err, ErrCodeForbidden, "只能撤回自己的申请")
+}
+
+func TestReviewApplication_WithdrawnMeanwhile(t *testing.T) {
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetByID", mock.Anything, 5).Return(&models.ProjectApplication{ID: 5, ProjectID: 1, UserID: 10}, nil)
+	mockApp.On("TransitionStatusTx", mock.Anything, mock.Anything, 5, models.ApplicationStatusPending, models.ApplicationStatusRejected, (*string)(nil)).Return(false, nil)
+	mockProject := new(MockProjectRepo)
+	mockProject.On("IsOwner", mock.Anything, 1, 20).Return(true, nil)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组"}, nil)
+	mockNotification := new(MockNotificationRepo)
+
+	repo := newTxTestRepo()
+	repo.Application = mockApp
+	repo.Project = mockProject
+	repo.Notification = mockNotification
+
+	err := NewProjectService(repo, nil, nil, nil, nil).ReviewApplication(context.Background(), 5, 20, models.ApplicationStatusRejected, nil)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "该申请已处理或已撤回")
+	mockNotification.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestListApplicationHistory(t *testing.T) {
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("GetByID", mock.Anything, 7).Return(&models.ProjectApplication{ID: 7, ProjectID: 1, UserID: 10, ProjectCreatorID: 20}, nil)
+	mockApp.On("ListAttempts", mock.Anything, 1, 10).Return([]models.ProjectApplication{
+		{ID: 7, ProjectID: 1, UserID: 10, ProjectCreatorID: 20, Status: models.ApplicationStatusPending, Contact: strPtr("wx")},
+		{ID: 3, ProjectID: 1, UserID: 10, ProjectCreatorID: 20, Status: models.ApplicationStatusRejected, Contact: strPtr("wx")},
+	}, nil)
+	mockApp.On("ListLogs", mock.Anything, []int{7, 3}).Return([]models.ApplicationStatusLog{
+		{ApplicationID: 3, Status: models.ApplicationStatusPending},
+		{ApplicationID: 3, Status: models.ApplicationStatusRejected},
+		{ApplicationID: 7, Status: models.ApplicationStatusPending},
+	}, nil)
+
+	svc := NewProjectService(&repository.Repository{Application: mockApp}, nil, nil, nil, nil)
+
+	attempts, err := svc.ListApplicationHistory(context.Background(), 7, 10)
+	require.NoError(t, err)
+	require.Len(t, attempts, 2)
+	assert.Len(t, attempts[0].StatusLogs, 1)
+	assert.Len(t, attempts[1].StatusLogs, 2)
+	assert.Nil(t, attempts[0].Contact, "applicant cannot see contact before approval")
+
+	_, err = svc.ListApplicationHistory(context.Background(), 7, 30)
+	assertServiceError(t, err, ErrCodeForbidden, "无权查看该申请")
+}
+
+func TestBatchReviewApplications_ApprovesPendingAndSkipsRest(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("IsOwner", mock.Anything, 1, 20).Return(true, nil)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组"}, nil)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 1).Return(3, nil)
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("LockPendingTx", mock.Anything, mock.Anything, 1, []int{5, 6, 7}).Return([]models.ProjectApplication{
+		{ID: 5, ProjectID: 1, UserID: 10},
+		{ID: 6, ProjectID: 1, UserID: 11},
+	}, nil)
+	mockApp.On("UpdateStatusBatchTx", mock.Anything, mock.Anything, []int{5, 6}, models.ApplicationStatusApproved, (*string)(nil)).Return(nil)
+	mockApp.On("CreateLogsTx", mock.Anything, mock.Anything, mock.MatchedBy(func(logs []*models.ApplicationStatusLog) bool {
+		return len(logs) == 2 && logs[1].ApplicationID == 6 && *logs[1].OperatorID == 20
+	})).Return(nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("ListActiveUserIDsTx", mock.Anything, mock.Anything, 1, mock.Anything).Return([]int{}, nil)
+	mockMember.On("CountMembersTx", mock.Anything, mock.Anything, 1).Return(1, nil)
+	mockMember.On("AddTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateBatchTx", mock.Anything, mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		return len(ns) == 2 && ns[0].UserID == 10 && ns[1].UserID == 11
+	})).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateBatchTx", mock.Anything, mock.Anything, mock.MatchedBy(func(msgs []*models.MessageOutbox) bool {
+		return len(msgs) == 2 && msgs[0].BizKey == models.MsgBizKeyCardDeliveryResult
+	})).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.Project = mockProject
+	repo.Application = mockApp
+	repo.ProjectMember = mockMember
+	repo.Notification = mockNotification
+	repo.MessageOutbox = mockOutbox
+	events := &stubPublisher{}
+
+	result, err := NewProjectService(repo, nil, events, nil, nil).BatchReviewApplications(context.Background(), 1, 20, []int{5, 6, 7, 5}, models.ApplicationStatusApproved, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{5, 6}, result.ReviewedIDs)
+	assert.Equal(t, []int{7}, result.SkippedIDs)
+	mockApp.AssertExpectations(t)
+	mockMember.AssertNumberOfCalls(t, "AddTx", 2)
+	mockNotification.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+	mockOutbox.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+	require.Len(t, events.published, 2)
+	assert.Equal(t, 11, events.published[1].userID)
+}
+
+func TestBatchReviewApplications_ExceedsRemainingCapacity(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("IsOwner", mock.Anything, 1, 20).Return(true, nil)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组"}, nil)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 1).Return(3, nil)
+	mockApp := new(MockApplicationRepo)
+	mockApp.On("LockPendingTx", mock.Anything, mock.Anything, 1, []int{5, 6}).Return([]models.ProjectApplication{
+		{ID: 5, ProjectID: 1, UserID: 10},
+		{ID: 6, ProjectID: 1, UserID: 11},
+	}, nil)
+	mockApp.On("UpdateStatusBatchTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockApp.On("CreateLogsTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("ListActiveUserIDsTx", mock.Anything, mock.Anything, 1, mock.Anything).Return([]int{}, nil)
+	mockMember.On("CountMembersTx", mock.Anything, mock.Anything, 1).Return(2, nil)
+	mockNotification := new(MockNotificationRepo)
+
+	repo := newTxTestRepo()
+	repo.Project = mockProject
+	repo.Application = mockApp
+	repo.ProjectMember = mockMember
+	repo.Notification = mockNotification
+
+	_, err := NewProjectService(repo, nil, nil, nil, nil).BatchReviewApplications(context.Background(), 1, 20, []int{5, 6}, models.ApplicationStatusApproved, nil)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "项目剩余名额不足，最多还可加入 1 人")
+	mockMember.AssertNotCalled(t, "AddTx", mock.Anything, mock.Anything, mock.Anything)
+	mockNotification.AssertNotCalled(t, "CreateBatchTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBatchReviewApplications_NotOwner(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("IsOwner", mock.Anything, 1, 30).Return(false, nil)
+	mockApp := new(MockApplicationRepo)
+
+	repo := &repository.Repository{Project: mockProject, Application: mockApp}
+	_, err := NewProjectService(repo, nil, nil, nil, nil).BatchReviewApplications(context.Background(), 1, 30, []int{5}, models.ApplicationStatusRejected, nil)
+
+	assertServiceError(t, err, ErrCodeForbidden, "只有队长可以审核申请")
+	mockApp.AssertNotCalled(t, "LockPendingTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// joinProjectTx 在事务内把用户加入项目成为队员
+func joinProjectTx(ctx context.Context, tx *sqlx.Tx, repo *repository.Repository, projectID, userID int, source string) error {
+	return joinProjectBatchTx(ctx, tx, repo, projectID, []int{userID}, source)
+}
+
+// joinProjectBatchTx 在事务内把一批用户加入项目成为队员。先锁定项目行再统计人数，
+// 保证并发加入时不会超出项目的需求人数（member_count 为空或 0 时不限人数）；
+// 已在项目中的用户跳过且不占名额，剩余名额不足时整批拒绝
+func joinProjectBatchTx(ctx context.Context, tx *sqlx.Tx, repo *repository.Repository, projectID int, userIDs []int, source string) error {
+	capacity, err := repo.Project.LockMemberCountTx(ctx, tx, projectID)
+	if err != nil {
+		return err
+	}
+	active, err := repo.ProjectMember.ListActiveUserIDsTx(ctx, tx, projectID, userIDs)
+	if err != nil {
+		return err
+	}
+	skip := make(map[int]bool, len(userIDs))
+	for _, id := range active {
+		skip[id] = true
+	}
+	newIDs := make([]int, 0, len(userIDs))
+	for _, id := range userIDs {
+		if !skip[id] {
+			skip[id] = true
+			newIDs = append(newIDs, id)
+		}
+	}
+	if len(newIDs) == 0 {
+		return nil
+	}
+
+	count, err := repo.ProjectMember.CountMembersTx(ctx, tx, projectID)
+	if err != nil {
+		return err
+	}
+	if capacity > 0 && count+len(newIDs) > capacity {
+		if count >= capacity {
+			return ErrBadRequest("项目成员已满")
+		}
+		return ErrBadRequest(fmt.Sprintf("项目剩余名额不足，最多还可加入 %d 人", capacity-count))
+	}
+
+	for _, userID := range newIDs {
+		err := repo.ProjectMember.AddTx(ctx, tx, &models.ProjectMember{
+			ProjectID: projectID,
+			UserID:    userID,
+			Role:      models.ProjectMemberRoleMember,
+			Source:    source,
+			Status:    models.ProjectMemberStatusActive,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memberState 查询用户是否已在项目中，以及项目人数是否已满，供申请和邀请前提前拒绝；
+// 最终以 joinProjectTx 在事务内的校验为准
+func memberState(ctx context.Context, repo *repository.Repository, project *models.Project, userID int) (isMember, isFull bool, err error) {
+	member, err := repo.ProjectMember.GetActive(ctx, project.ID, userID)
+	if err != nil {
+		return false, false, err
+	}
+	if member != nil {
+		return true, false, nil
+	}
+
+	if project.MemberCount == nil || *project.MemberCount <= 0 {
+		return false, false, nil
+	}
+	count, err := repo.ProjectMember.CountMembers(ctx, project.ID)
+	if err != nil {
+		return false, false, err
+	}
+	return false, count >= *project.MemberCount, nil
+}
+
+// ProjectMemberService handles project roster business logic.
+type ProjectMemberService struct {
+	repo   *repository.Repository
+	events EventPublisher
+}
+
+// NewProjectMemberService creates a new ProjectMemberService.
+func NewProjectMemberService(repo *repository.Repository, events EventPublisher) *ProjectMemberService {
+	return &ProjectMemberService{repo: repo, events: events}
+}
+
+// TeamListResult holds a page of the teams a user belongs to.
+type TeamListResult struct {
+	List       []models.ProjectMember
+	Total      int64
+	TotalPages int
+	Page       int
+	Size       int
+}
+
+// ListMembers returns the active members of a project, the leader first.
+func (s *ProjectMemberService) ListMembers(ctx context.Context, projectID int) ([]models.ProjectMember, error) {
+	project, err := s.repo.Project.GetByID(ctx, projectID)
+	if err != nil {
+		log.Printf("[ProjectMemberService.ListMembers] repository error getting project: %v", err)
+		return nil, ErrInternal("获取项目信息失败")
+	}
+	if project == nil {
+		return nil, ErrNotFound("项目不存在")
+	}
+
+	members, err := s.repo.ProjectMember.ListActiveByProject(ctx, projectID)
+	if err != nil {
+		log.Printf("[ProjectMemberService.ListMembers] repository error: %v", err)
+		return nil, ErrInternal("获取项目成员失败")
+	}
+	return members, nil
+}
+
+// ListMyTeams returns the projects a user currently belongs to, as leader or member.
+func (s *ProjectMemberService) ListMyTeams(ctx context.Context, userID, page, size int) (*TeamListResult, error) {
+	page, size = normalizePageParams(page, size)
+
+	teams, total, err := s.repo.ProjectMember.ListTeamsByUser(ctx, repository.TeamListParams{
+		UserID: userID,
+		Page:   page,
+		Size:   size,
+	})
+	if err != nil {
+		log.Printf("[ProjectMemberService.ListMyTeams] repository error: %v", err)
+		return nil, ErrInternal("获取我的团队失败")
+	}
+
+	totalPages := int((total + int64(size) - 1) / int64(size))
+
+	return &TeamListResult{
+		List:       teams,
+		Total:      total,
+		TotalPages: totalPages,
+		Page:       page,
+		Size:       size,
+	}, nil
+}
+
+// RemoveMember takes userID off the project roster. A member removing themself
+// leaves the project and the leader is told; otherwise only the leader may remove
+// a member, who is then told. The leader cannot leave their own project.
+func (s *ProjectMemberService) RemoveMember(ctx context.Context, projectID, operatorID, userID int) error {
+	project, err := s.repo.Project.GetByID(ctx, projectID)
+	if err != nil {
+		log.Printf("[ProjectMemberService.RemoveMember] repository error getting project: %v", err)
+		return ErrInternal("获取项目信息失败")
+	}
+	if project == nil {
+		return ErrNotFound("项目不存在")
+	}
+
+	var status int
+	var n notice
+	if operatorID == userID {
+		if project.CreatorID == userID {
+			return ErrBadRequest("队长不能退出自己的项目")
+		}
+
+		user, err := s.repo.User.GetByID(ctx, userID)
+		if err != nil {
+			log.Printf("[ProjectMemberService.RemoveMember] repository error getting user: %v", err)
+			return ErrInternal("获取用户信息失败")
+		}
+		name := "匿名用户"
+		if user != nil && user.Nickname != nil {
+			name = *user.Nickname
+		}
+
+		status = models.ProjectMemberStatusLeft
+		n = notice{
+			UserID:    project.CreatorID,
+			Type:      models.NotificationTypeMemberLeft,
+			Title:     "队员退出项目",
+			Content:   fmt.Sprintf("%s 退出了项目「%s」。", name, project.Name),
+			RelatedID: &project.ID,
+		}
+	} else {
+		if project.CreatorID != operatorID {
+			return ErrForbidden("只有队长可以移出成员")
+		}
+
+		status = models.ProjectMemberStatusRemoved
+		n = notice{
+			UserID:    userID,
+			Type:      models.NotificationTypeMemberRemoved,
+			Title:     "已被移出项目",
+			Content:   fmt.Sprintf("您已被队长移出项目「%s」。", project.Name),
+			RelatedID: &project.ID,
+		}
+	}
+
+	var notification *models.Notification
+	err = runInTx(ctx, s.repo, "ProjectMemberService.RemoveMember", "移出成员失败", func(tx *sqlx.Tx) (err error) {
+		removed, err := s.repo.ProjectMember.DeactivateTx(ctx, tx, projectID, userID, status)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return ErrNotFound("该用户不是项目成员")
+		}
+		notification, err = notifyTx(ctx, tx, s.repo, n)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	publishNotification(ctx, s.events, notification)
+	return nil
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockProjectMemberRepo struct {
+	repository.ProjectMemberRepo
+	mock.Mock
+}
+
+func (m *MockProjectMemberRepo) AddTx(ctx context.Context, tx *sqlx.Tx, member *models.ProjectMember) error {
+	args := m.Called(ctx, tx, member)
+	return args.Error(0)
+}
+
+func (m *MockProjectMemberRepo) GetActive(ctx context.Context, projectID, userID int) (*models.ProjectMember, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProjectMember), args.Error(1)
+}
+
+func (m *MockProjectMemberRepo) CountMembers(ctx context.Context, projectID int) (int, error) {
+	args := m.Called(ctx, projectID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProjectMemberRepo) CountMembersTx(ctx context.Context, tx *sqlx.Tx, projectID int) (int, error) {
+	args := m.Called(ctx, tx, projectID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProjectMemberRepo) ListActiveUserIDsTx(ctx context.Context, tx *sqlx.Tx, projectID int, userIDs []int) ([]int, error) {
+	args := m.Called(ctx, tx, projectID, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int), args.Error(1)
+}
+
+func (m *MockProjectMemberRepo) DeactivateTx(ctx context.Context, tx *sqlx.Tx, projectID, userID, status int) (bool, error) {
+	args := m.Called(ctx, tx, projectID, userID, status)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestJoinProjectTx_FullTeamRejected(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 1).Return(2, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("ListActiveUserIDsTx", mock.Anything, mock.Anything, 1, mock.Anything).Return([]int{}, nil)
+	mockMember.On("CountMembersTx", mock.Anything, mock.Anything, 1).Return(2, nil)
+
+	repo := &repository.Repository{Project: mockProject, ProjectMember: mockMember}
+	err := joinProjectTx(context.Background(), nil, repo, 1, 10, models.ProjectMemberSourceApplication)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "项目成员已满")
+	mockMember.AssertNotCalled(t, "AddTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestJoinProjectTx_AddsMember(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 1).Return(2, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("ListActiveUserIDsTx", mock.Anything, mock.Anything, 1, mock.Anything).Return([]int{}, nil)
+	mockMember.On("CountMembersTx", mock.Anything, mock.Anything, 1).Return(1, nil)
+	mockMember.On("AddTx", mock.Anything, mock.Anything, mock.MatchedBy(func(m *models.ProjectMember) bool {
+		return m.ProjectID == 1 && m.UserID == 10 && m.Role == models.ProjectMemberRoleMember &&
+			m.Source == models.ProjectMemberSourceOliveBranch && m.Status == models.ProjectMemberStatusActive
+	})).Return(nil)
+
+	repo := &repository.Repository{Project: mockProject, ProjectMember: mockMember}
+	err := joinProjectTx(context.Background(), nil, repo, 1, 10, models.ProjectMemberSourceOliveBranch)
+
+	require.NoError(t, err)
+	mockMember.AssertExpectations(t)
+}
+
+func TestJoinProjectBatchTx_ExistingMembersTakeNoSeat(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("LockMemberCountTx", mock.Anything, mock.Anything, 1).Return(2, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("ListActiveUserIDsTx", mock.Anything, mock.Anything, 1, []int{10, 11}).Return([]int{10}, nil)
+	mockMember.On("CountMembersTx", mock.Anything, mock.Anything, 1).Return(1, nil)
+	mockMember.On("AddTx", mock.Anything, mock.Anything, mock.MatchedBy(func(m *models.ProjectMember) bool {
+		return m.UserID == 11
+	})).Return(nil).Once()
+
+	repo := &repository.Repository{Project: mockProject, ProjectMember: mockMember}
+	err := joinProjectBatchTx(context.Background(), nil, repo, 1, []int{10, 11}, models.ProjectMemberSourceApplication)
+
+	require.NoError(t, err)
+	mockMember.AssertExpectations(t)
+}
+
+func TestRemoveMember_LeaderCannotLeave(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20}, nil)
+
+	repo := &repository.Repository{Project: mockProject}
+	err := NewProjectMemberService(repo, nil).RemoveMember(context.Background(), 1, 20, 20)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "队长不能退出自己的项目")
+}
+
+func TestRemoveMember_OnlyLeaderCanKick(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20}, nil)
+
+	repo := &repository.Repository{Project: mockProject}
+	err := NewProjectMemberService(repo, nil).RemoveMember(context.Background(), 1, 10, 11)
+
+	assertServiceError(t, err, ErrCodeForbidden, "只有队长可以移出成员")
+}
+
+func TestRemoveMember_LeaveNotifiesLeaderInApp(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组"}, nil)
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10, Nickname: strPtr("张三")}, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("DeactivateTx", mock.Anything, mock.Anything, 1, 10, models.ProjectMemberStatusLeft).Return(true, nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(n *models.Notification) bool {
+		return n.UserID == 20 && n.Type == models.NotificationTypeMemberLeft
+	})).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+
+	repo := newTxTestRepo()
+	repo.Project = mockProject
+	repo.User = mockUser
+	repo.ProjectMember = mockMember
+	repo.Notification = mockNotification
+	repo.MessageOutbox = mockOutbox
+	events := &stubPublisher{}
+
+	err := NewProjectMemberService(repo, events).RemoveMember(context.Background(), 1, 10, 10)
+
+	require.NoError(t, err)
+	mockMember.AssertExpectations(t)
+	n := mockNotification.Calls[0].Arguments.Get(2).(*models.Notification)
+	assert.Equal(t, "张三 退出了项目「快组」。", n.Content)
+	assert.Empty(t, queuedMessages(mockOutbox), "leaving sends no subscribe message")
+	require.Len(t, events.published, 1)
+}
+
+func TestRemoveMember_NotAMember(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20, Name: "快组"}, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("DeactivateTx", mock.Anything, mock.Anything, 1, 11, models.ProjectMemberStatusRemoved).Return(false, nil)
+	mockNotification := new(MockNotificationRepo)
+
+	repo := newTxTestRepo()
+	repo.Project = mockProject
+	repo.ProjectMember = mockMember
+	repo.Notification = mockNotification
+
+	err := NewProjectMemberService(repo, nil).RemoveMember(context.Background(), 1, 20, 11)
+
+	assertServiceError(t, err, ErrCodeNotFound, "该用户不是项目成员")
+	mockNotification.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+const (
+	promotionRotationWindow = time.Hour   // 推广中项目轮换排序的周期
+	promotionExpiryInterval = time.Minute // 扫描到期推广的间隔
+)
+
+// promotionRotationSeed 返回当前轮换周期的种子，同一周期内分页结果保持稳定
+func promotionRotationSeed(now time.Time) int64 {
+	return now.Unix() / int64(promotionRotationWindow/time.Second)
+}
+
+// PromoteProject 使用项目置顶订单的权益为项目开启推广
+// 整个订单的置顶天数一次性用完；项目已在推广中时顺延结束时间。
+func (s *ProjectService) PromoteProject(ctx context.Context, userID, projectID, orderID int) (*models.Project, error) {
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		log.Printf("[ProjectService.PromoteProject] repository error getting order: %v", err)
+		return nil, ErrInternal("获取订单失败")
+	}
+	if order == nil {
+		return nil, ErrNotFound("订单不存在")
+	}
+	if order.UserID != userID {
+		return nil, ErrForbidden("无权操作此订单")
+	}
+	if order.Status != models.OrderStatusPaid {
+		return nil, ErrBadRequest("订单未支付或状态异常")
+	}
+
+	project, err := s.repo.Project.GetByID(ctx, projectID)
+	if err != nil {
+		log.Printf("[ProjectService.PromoteProject] repository error getting project: %v", err)
+		return nil, ErrInternal("获取项目失败")
+	}
+	if project == nil {
+		return nil, ErrNotFound("项目不存在")
+	}
+	if project.CreatorID != userID {
+		return nil, ErrForbidden("只能推广自己创建的项目")
+	}
+	if project.Status != models.ProjectStatusApproved {
+		return nil, ErrBadRequest("只能推广已通过审核的项目")
+	}
+
+	// 锁定权益后扣减并开启推广，两步在同一事务中完成
+	err = runInTx(ctx, s.repo, "ProjectService.PromoteProject", "开启项目置顶失败", func(tx *sqlx.Tx) error {
+		entitlement, err := s.repo.Entitlement.GetByOrderIDTx(ctx, tx, orderID)
+		if err != nil {
+			return err
+		}
+		if entitlement == nil || entitlement.Kind != models.BenefitKindProjectTop {
+			return ErrBadRequest("订单中没有项目置顶商品")
+		}
+		days := entitlement.Remaining()
+		if days <= 0 {
+			return ErrBadRequest("置顶权益已用完")
+		}
+
+		consumed, err := s.repo.Entitlement.ConsumeTx(ctx, tx, entitlement.ID, days)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return ErrBadRequest("置顶权益已用完")
+		}
+		return s.repo.Project.ActivatePromotionTx(ctx, tx, projectID, days, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	project, err = s.repo.Project.GetByID(ctx, projectID)
+	if err != nil || project == nil {
+		log.Printf("[ProjectService.PromoteProject] repository error reloading project: %v", err)
+		return nil, ErrInternal("获取项目失败")
+	}
+	return project, nil
+}
+
+// ProjectPromotionScheduler 将到期的项目推广标记为已结束
+type ProjectPromotionScheduler struct {
+	repo *repository.Repository
+}
+
+// NewProjectPromotionScheduler creates a new ProjectPromotionScheduler.
+func NewProjectPromotionScheduler(repo *repository.Repository) *ProjectPromotionScheduler {
+	return &ProjectPromotionScheduler{repo: repo}
+}
+
+// Run 持续处理到期推广，直到 ctx 被取消
+func (s *ProjectPromotionScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(promotionExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 结束所有已到期的推广，返回处理的项目数
+func (s *ProjectPromotionScheduler) RunOnce(ctx context.Context) int64 {
+	finished, err := s.repo.Project.FinishExpiredPromotions(ctx, time.Now())
+	if err != nil {
+		log.Printf("[ProjectPromotionScheduler.RunOnce] repository error: %v", err)
+		return 0
+	}
+	return finished
+}
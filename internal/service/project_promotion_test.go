@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+func topEntitlement(orderID, days int) *models.UserEntitlement {
+	return &models.UserEntitlement{
+		ID:      8,
+		UserID:  1,
+		OrderID: orderID,
+		Kind:    models.BenefitKindProjectTop,
+		Total:   days,
+		Status:  models.EntitlementStatusActive,
+	}
+}
+
+func setupPromoteProject() (*MockOrderRepo, *MockProjectRepo, *MockEntitlementRepo, *ProjectService) {
+	mockOrder := new(MockOrderRepo)
+	mockProject := new(MockProjectRepo)
+	mockEntitlement := new(MockEntitlementRepo)
+
+	mockOrder.On("GetByID", mock.Anything, 100).Return(&models.Order{ID: 100, UserID: 1, Status: models.OrderStatusPaid}, nil)
+
+	repo := newTxTestRepo()
+	repo.Order, repo.Project, repo.Entitlement = mockOrder, mockProject, mockEntitlement
+	return mockOrder, mockProject, mockEntitlement, NewProjectService(repo, nil, nil, nil, nil)
+}
+
+// --- Tests for PromoteProject ---
+
+func TestPromoteProject_ProjectNotApproved(t *testing.T) {
+	_, mockProject, _, svc := setupPromoteProject()
+	mockProject.On("GetByID", mock.Anything, 200).Return(&models.Project{ID: 200, CreatorID: 1, Status: models.ProjectStatusPending}, nil)
+
+	_, err := svc.PromoteProject(context.Background(), 1, 200, 100)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "只能推广已通过审核的项目")
+}
+
+func TestPromoteProject_WrongBenefitKind(t *testing.T) {
+	_, mockProject, mockEntitlement, svc := setupPromoteProject()
+	mockProject.On("GetByID", mock.Anything, 200).Return(&models.Project{ID: 200, CreatorID: 1, Status: models.ProjectStatusApproved}, nil)
+	mockEntitlement.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(emailEntitlement(100, 50), nil)
+
+	_, err := svc.PromoteProject(context.Background(), 1, 200, 100)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "订单中没有项目置顶商品")
+}
+
+func TestPromoteProject_AlreadyUsed(t *testing.T) {
+	_, mockProject, mockEntitlement, svc := setupPromoteProject()
+	mockProject.On("GetByID", mock.Anything, 200).Return(&models.Project{ID: 200, CreatorID: 1, Status: models.ProjectStatusApproved}, nil)
+	used := topEntitlement(100, 7)
+	used.Used = 7
+	mockEntitlement.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(used, nil)
+
+	_, err := svc.PromoteProject(context.Background(), 1, 200, 100)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "置顶权益已用完")
+	mockEntitlement.AssertNotCalled(t, "ConsumeTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPromoteProject_Success(t *testing.T) {
+	_, mockProject, mockEntitlement, svc := setupPromoteProject()
+	mockProject.On("GetByID", mock.Anything, 200).Return(&models.Project{ID: 200, CreatorID: 1, Status: models.ProjectStatusApproved}, nil)
+	mockEntitlement.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(topEntitlement(100, 7), nil)
+	mockEntitlement.On("ConsumeTx", mock.Anything, mock.Anything, 8, 7).Return(true, nil)
+	mockProject.On("ActivatePromotionTx", mock.Anything, mock.Anything, 200, 7, mock.Anything).Return(nil)
+
+	project, err := svc.PromoteProject(context.Background(), 1, 200, 100)
+
+	require.NoError(t, err)
+	assert.Equal(t, 200, project.ID)
+	mockProject.AssertExpectations(t)
+	mockEntitlement.AssertExpectations(t)
+}
+
+func TestPromoteProject_ActivateFailsRollsBack(t *testing.T) {
+	_, mockProject, mockEntitlement, svc := setupPromoteProject()
+	mockProject.On("GetByID", mock.Anything, 200).Return(&models.Project{ID: 200, CreatorID: 1, Status: models.ProjectStatusApproved}, nil)
+	mockEntitlement.On("GetByOrderIDTx", mock.Anything, mock.Anything, 100).Return(topEntitlement(100, 7), nil)
+	mockEntitlement.On("ConsumeTx", mock.Anything, mock.Anything, 8, 7).Return(true, nil)
+	mockProject.On("ActivatePromotionTx", mock.Anything, mock.Anything, 200, 7, mock.Anything).Return(errors.New("db error"))
+
+	_, err := svc.PromoteProject(context.Background(), 1, 200, 100)
+
+	assertServiceError(t, err, ErrCodeInternal, "开启项目置顶失败")
+	mockEntitlement.AssertNotCalled(t, "Release", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// --- Tests for ProjectPromotionScheduler ---
+
+func TestProjectPromotionScheduler_RunOnce(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("FinishExpiredPromotions", mock.Anything, mock.Anything).Return(int64(3), nil)
+
+	scheduler := NewProjectPromotionScheduler(&repository.Repository{Project: mockProject})
+
+	assert.Equal(t, int64(3), scheduler.RunOnce(context.Background()))
+}
+
+func TestPromotionRotationSeed_StableWithinWindow(t *testing.T) {
+	start := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, promotionRotationSeed(start), promotionRotationSeed(start.Add(59*time.Minute)))
+	assert.NotEqual(t, promotionRotationSeed(start), promotionRotationSeed(start.Add(time.Hour)))
+}
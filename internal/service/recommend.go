@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/search"
+)
+
+const (
+	recommendCandidateLimit = 300 // 每次参与打分的候选数，按活跃或发布时间取最新的一批
+	recommendDefaultSize    = 10
+	recommendMaxSize        = 50
+)
+
+// 匹配打分权重，满分 100
+const (
+	matchWeightSkill     = 40 // 命中 matchSkillTarget 个技能即满分
+	matchWeightSchool    = 15
+	matchWeightEducation = 10
+	matchWeightMajor     = 10
+	matchWeightDirection = 10
+	matchWeightRecent    = 15 // 7 天内
+	matchWeightActive    = 8  // 30 天内
+
+	matchSkillTarget    = 3
+	matchMajorMinTokens = 2 // 专业名称与项目文本至少共有的词元数
+)
+
+var directionNames = map[int]string{
+	models.ProjectDirectionLaunch:      "落地",
+	models.ProjectDirectionCompetition: "比赛",
+	models.ProjectDirectionLearning:    "学习",
+}
+
+var educationNames = map[int]string{
+	models.EducationJuniorCollege: "大专",
+	models.EducationUndergraduate: "本科",
+	models.EducationPostgraduate:  "研究生",
+}
+
+// matchProfile 参与匹配的人才信息
+type matchProfile struct {
+	Skills     []string
+	SkillTags  []models.SkillTag // 从技能中识别出的技能标签
+	Education  *int
+	SchoolID   *int
+	MajorName  *string
+	Directions map[int]int // 参与过的项目方向 -> 项目数
+}
+
+// scoreMatch 计算人才与项目的匹配分及理由。人才不满足项目的硬性要求
+// （不可跨校的外校人才、学历低于要求）时 ok 为 false。
+func scoreMatch(p *models.Project, m *matchProfile) (score int, reasons []string, ok bool) {
+	sameSchool := p.SchoolID != nil && m.SchoolID != nil && *p.SchoolID == *m.SchoolID
+	if p.IsCrossSchool != nil && *p.IsCrossSchool == models.ProjectCrossSchoolNo && !sameSchool {
+		return 0, nil, false
+	}
+	if p.EducationRequirement != nil && m.Education != nil && *m.Education < *p.EducationRequirement {
+		return 0, nil, false
+	}
+
+	// 技能：项目关联了技能标签时按标签比对，同义词已在识别时归并
+	var matched []string
+	if len(p.SkillTags) > 0 {
+		matched = matchSkillTags(p.SkillTags, m.SkillTags)
+	} else {
+		matched = matchSkillText(p, m.Skills)
+	}
+	if len(matched) > 0 {
+		score += matchWeightSkill * min(len(matched), matchSkillTarget) / matchSkillTarget
+		reasons = append(reasons, "技能匹配："+strings.Join(matched, "、"))
+	}
+
+	if sameSchool {
+		score += matchWeightSchool
+		if p.SchoolName != nil {
+			reasons = append(reasons, "同校（"+*p.SchoolName+"）")
+		} else {
+			reasons = append(reasons, "同校")
+		}
+	}
+
+	if p.EducationRequirement != nil && m.Education != nil {
+		score += matchWeightEducation
+		reasons = append(reasons, fmt.Sprintf("学历符合要求（%s及以上）", educationNames[*p.EducationRequirement]))
+	}
+
+	if m.MajorName != nil && majorRelated(*m.MajorName, p) {
+		score += matchWeightMajor
+		reasons = append(reasons, "专业相关："+*m.MajorName)
+	}
+
+	if p.Direction != nil && m.Directions[*p.Direction] > 0 {
+		score += matchWeightDirection
+		reasons = append(reasons, fmt.Sprintf("参与过 %d 个%s类项目", m.Directions[*p.Direction], directionNames[*p.Direction]))
+	}
+
+	return score, reasons, true
+}
+
+// matchSkillTags 返回人才与项目共有的技能标签名称
+func matchSkillTags(projectTags, talentTags []models.SkillTag) []string {
+	required := make(map[int]bool, len(projectTags))
+	for _, t := range projectTags {
+		required[t.ID] = true
+	}
+	var matched []string
+	for _, t := range talentTags {
+		if required[t.ID] {
+			matched = append(matched, t.Name)
+		}
+	}
+	return matched
+}
+
+// matchSkillText 项目没有关联技能标签时，找出出现在技能要求中的人才技能；
+// 未填写技能要求时对照项目名称和详情
+func matchSkillText(p *models.Project, skills []string) []string {
+	requirement := derefString(p.SkillRequirement)
+	if strings.TrimSpace(requirement) == "" {
+		requirement = p.Name + " " + derefString(p.Description)
+	}
+	requirement = strings.ToLower(requirement)
+	var matched []string
+	for _, skill := range skills {
+		if s := strings.TrimSpace(skill); s != "" && strings.Contains(requirement, strings.ToLower(s)) {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
+
+// majorRelated 专业名称与项目文本共有足够多的词元时视为相关
+func majorRelated(major string, p *models.Project) bool {
+	text := strings.Join([]string{p.Name, derefString(p.Description), derefString(p.SkillRequirement)}, " ")
+	textTokens := make(map[string]bool)
+	for _, t := range search.Tokenize(text) {
+		textTokens[t] = true
+	}
+
+	shared := make(map[string]bool)
+	for _, t := range search.Tokenize(major) {
+		if textTokens[t] {
+			shared[t] = true
+		}
+	}
+	return len(shared) >= matchMajorMinTokens
+}
+
+// recencyScore 按最近活跃时间加分
+func recencyScore(at *time.Time, now time.Time) int {
+	if at == nil {
+		return 0
+	}
+	switch age := now.Sub(*at); {
+	case age <= 7*24*time.Hour:
+		return matchWeightRecent
+	case age <= 30*24*time.Hour:
+		return matchWeightActive
+	default:
+		return 0
+	}
+}
+
+func normalizeRecommendSize(size int) int {
+	if size < 1 {
+		return recommendDefaultSize
+	}
+	return min(size, recommendMaxSize)
+}
+
+// RecommendService matches talent profiles and projects against each other.
+type RecommendService struct {
+	repo *repository.Repository
+	now  func() time.Time
+}
+
+// NewRecommendService creates a new RecommendService.
+func NewRecommendService(repo *repository.Repository) *RecommendService {
+	return &RecommendService{repo: repo, now: time.Now}
+}
+
+// RecommendedTalent is a talent profile recommended for a project.
+type RecommendedTalent struct {
+	Talent  models.TalentProfile
+	Score   int
+	Reasons []string
+}
+
+// RecommendTalents scores published talent profiles against the leader's
+// project and returns the best matches with the reasons they matched.
+func (s *RecommendService) RecommendTalents(ctx context.Context, projectID, userID, size int) ([]RecommendedTalent, error) {
+	project, err := s.repo.Project.GetByID(ctx, projectID)
+	if err != nil {
+		log.Printf("[RecommendService.RecommendTalents] repository error getting project: %v", err)
+		return nil, ErrInternal("获取项目信息失败")
+	}
+	if project == nil {
+		return nil, ErrNotFound("项目不存在")
+	}
+	if project.CreatorID != userID {
+		return nil, ErrForbidden("只有队长可以查看推荐人才")
+	}
+
+	params := repository.TalentCandidateParams{ProjectID: projectID, Limit: recommendCandidateLimit}
+	if project.IsCrossSchool != nil && *project.IsCrossSchool == models.ProjectCrossSchoolNo {
+		if project.SchoolID == nil {
+			return []RecommendedTalent{}, nil
+		}
+		params.SchoolID = project.SchoolID
+	}
+	candidates, err := s.repo.TalentProfile.ListRecommendCandidates(ctx, params)
+	if err != nil {
+		log.Printf("[RecommendService.RecommendTalents] repository error listing candidates: %v", err)
+		return nil, ErrInternal("获取推荐人才失败")
+	}
+
+	userIDs := make([]int, len(candidates))
+	profileIDs := make([]int, len(candidates))
+	for i, c := range candidates {
+		userIDs[i] = c.UserID
+		profileIDs[i] = c.ID
+	}
+	directions, err := s.repo.ProjectMember.CountDirectionsByUsers(ctx, userIDs)
+	if err != nil {
+		log.Printf("[RecommendService.RecommendTalents] repository error counting directions: %v", err)
+		return nil, ErrInternal("获取推荐人才失败")
+	}
+	projectTags, err := s.repo.SkillTag.ListByProjects(ctx, []int{projectID})
+	if err != nil {
+		log.Printf("[RecommendService.RecommendTalents] repository error listing project tags: %v", err)
+		return nil, ErrInternal("获取推荐人才失败")
+	}
+	project.SkillTags = projectTags[projectID]
+	talentTags, err := s.repo.SkillTag.ListByTalents(ctx, profileIDs)
+	if err != nil {
+		log.Printf("[RecommendService.RecommendTalents] repository error listing talent tags: %v", err)
+		return nil, ErrInternal("获取推荐人才失败")
+	}
+
+	now := s.now()
+	results := []RecommendedTalent{}
+	for _, c := range candidates {
+		score, reasons, ok := scoreMatch(project, &matchProfile{
+			Skills:     c.Skills(),
+			SkillTags:  talentTags[c.ID],
+			Education:  c.Education,
+			SchoolID:   c.SchoolID,
+			MajorName:  c.MajorName,
+			Directions: directions[c.UserID],
+		})
+		if !ok {
+			continue
+		}
+		if recent := recencyScore(c.UpdatedAt, now); recent > 0 {
+			score += recent
+			reasons = append(reasons, activityReason(recent, "活跃"))
+		}
+		if score <= 0 {
+			continue
+		}
+		results = append(results, RecommendedTalent{Talent: c, Score: min(score, 100), Reasons: reasons})
+	}
+
+	// 候选已按活跃时间倒序，同分时保持该顺序
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results[:min(len(results), normalizeRecommendSize(size))], nil
+}
+
+// RecommendedProject is a project recommended to a user.
+type RecommendedProject struct {
+	Project models.Project
+	Score   int
+	Reasons []string
+}
+
+// RecommendProjects scores open projects against the user's talent profile,
+// school, major and past project directions and returns the best matches
+// with the reasons they matched.
+func (s *RecommendService) RecommendProjects(ctx context.Context, userID, size int) ([]RecommendedProject, error) {
+	user, err := s.repo.User.GetByID(ctx, userID)
+	if err != nil {
+		log.Printf("[RecommendService.RecommendProjects] repository error getting user: %v", err)
+		return nil, ErrInternal("获取用户信息失败")
+	}
+	if user == nil {
+		return nil, ErrNotFound("用户不存在")
+	}
+	profile, err := s.repo.TalentProfile.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Printf("[RecommendService.RecommendProjects] repository error getting talent profile: %v", err)
+		return nil, ErrInternal("获取人才档案失败")
+	}
+	directions, err := s.repo.ProjectMember.CountDirectionsByUsers(ctx, []int{userID})
+	if err != nil {
+		log.Printf("[RecommendService.RecommendProjects] repository error counting directions: %v", err)
+		return nil, ErrInternal("获取推荐项目失败")
+	}
+
+	candidates, err := s.repo.Project.ListRecommendCandidates(ctx, repository.ProjectCandidateParams{
+		UserID:   userID,
+		SchoolID: user.SchoolID,
+		Limit:    recommendCandidateLimit,
+	})
+	if err != nil {
+		log.Printf("[RecommendService.RecommendProjects] repository error listing candidates: %v", err)
+		return nil, ErrInternal("获取推荐项目失败")
+	}
+
+	projectIDs := make([]int, len(candidates))
+	for i := range candidates {
+		projectIDs[i] = candidates[i].ID
+	}
+	projectTags, err := s.repo.SkillTag.ListByProjects(ctx, projectIDs)
+	if err != nil {
+		log.Printf("[RecommendService.RecommendProjects] repository error listing project tags: %v", err)
+		return nil, ErrInternal("获取推荐项目失败")
+	}
+	for i := range candidates {
+		candidates[i].SkillTags = projectTags[candidates[i].ID]
+	}
+
+	me := &matchProfile{SchoolID: user.SchoolID, MajorName: user.MajorName, Directions: directions[userID]}
+	if profile != nil {
+		talentTags, err := s.repo.SkillTag.ListByTalents(ctx, []int{profile.ID})
+		if err != nil {
+			log.Printf("[RecommendService.RecommendProjects] repository error listing talent tags: %v", err)
+			return nil, ErrInternal("获取推荐项目失败")
+		}
+		me.Skills = profile.Skills()
+		me.SkillTags = talentTags[profile.ID]
+		me.Education = profile.Education
+	}
+
+	now := s.now()
+	results := []RecommendedProject{}
+	for _, p := range candidates {
+		score, reasons, ok := scoreMatch(&p, me)
+		if !ok {
+			continue
+		}
+		if recent := recencyScore(&p.CreatedAt, now); recent > 0 {
+			score += recent
+			reasons = append(reasons, activityReason(recent, "发布"))
+		}
+		if score <= 0 {
+			continue
+		}
+		results = append(results, RecommendedProject{Project: p, Score: min(score, 100), Reasons: reasons})
+	}
+
+	// 候选已按发布时间倒序，同分时保持该顺序
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results[:min(len(results), normalizeRecommendSize(size))], nil
+}
+
+// activityReason 描述近期活跃或发布
+func activityReason(recent int, action string) string {
+	if recent >= matchWeightRecent {
+		return "近 7 天" + action
+	}
+	return "近 30 天" + action
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockTalentProfileRepo struct {
+	repository.TalentProfileRepo
+	mock.Mock
+}
+
+func (m *MockTalentProfileRepo) GetByUserID(ctx context.Context, userID int) (*models.TalentProfile, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TalentProfile), args.Error(1)
+}
+
+func (m *MockTalentProfileRepo) ListRecommendCandidates(ctx context.Context, params repository.TalentCandidateParams) ([]models.TalentProfile, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]models.TalentProfile), args.Error(1)
+}
+
+func (m *MockProjectMemberRepo) CountDirectionsByUsers(ctx context.Context, userIDs []int) (map[int]map[int]int, error) {
+	args := m.Called(ctx, userIDs)
+	return args.Get(0).(map[int]map[int]int), args.Error(1)
+}
+
+var recommendNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newRecommendTestService(repo *repository.Repository) *RecommendService {
+	svc := NewRecommendService(repo)
+	svc.now = func() time.Time { return recommendNow }
+	return svc
+}
+
+func timePtr(t time.Time) *time.Time { return &t }
+
+func TestScoreMatch_ExplainsEachSignal(t *testing.T) {
+	p := &models.Project{
+		Name:                 "校园二手交易小程序",
+		SkillRequirement:     strPtr("熟悉 React 和 Go，有计算机专业背景优先"),
+		SchoolID:             intPtr(1),
+		SchoolName:           strPtr("清华大学"),
+		Direction:            intPtr(models.ProjectDirectionCompetition),
+		EducationRequirement: intPtr(models.EducationUndergraduate),
+	}
+
+	score, reasons, ok := scoreMatch(p, &matchProfile{
+		Skills:     []string{"react", "Go", "Figma"},
+		Education:  intPtr(models.EducationPostgraduate),
+		SchoolID:   intPtr(1),
+		MajorName:  strPtr("计算机科学与技术"),
+		Directions: map[int]int{models.ProjectDirectionCompetition: 2},
+	})
+
+	require.True(t, ok)
+	assert.Equal(t, 26+15+10+10+10, score)
+	assert.Equal(t, []string{
+		"技能匹配：react、Go",
+		"同校（清华大学）",
+		"学历符合要求（本科及以上）",
+		"专业相关：计算机科学与技术",
+		"参与过 2 个比赛类项目",
+	}, reasons)
+}
+
+func TestScoreMatch_HardRequirements(t *testing.T) {
+	notCross := &models.Project{SchoolID: intPtr(1), IsCrossSchool: intPtr(models.ProjectCrossSchoolNo)}
+	_, _, ok := scoreMatch(notCross, &matchProfile{SchoolID: intPtr(2)})
+	assert.False(t, ok, "不可跨校的项目不匹配外校人才")
+
+	undergrad := &models.Project{EducationRequirement: intPtr(models.EducationUndergraduate)}
+	_, _, ok = scoreMatch(undergrad, &matchProfile{Education: intPtr(models.EducationJuniorCollege)})
+	assert.False(t, ok, "学历低于要求不匹配")
+
+	// 未填写学历的人才不排除，但也不加分
+	score, reasons, ok := scoreMatch(undergrad, &matchProfile{})
+	assert.True(t, ok)
+	assert.Zero(t, score)
+	assert.Empty(t, reasons)
+}
+
+func TestRecommendTalents_OnlyLeader(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{ID: 1, CreatorID: 20}, nil)
+
+	repo := &repository.Repository{Project: mockProject}
+	_, err := newRecommendTestService(repo).RecommendTalents(context.Background(), 1, 30, 10)
+
+	assertServiceError(t, err, ErrCodeForbidden, "只有队长可以查看推荐人才")
+}
+
+func TestRecommendTalents_RanksAndLimits(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("GetByID", mock.Anything, 1).Return(&models.Project{
+		ID: 1, CreatorID: 20, Name: "智能家居", SkillRequirement: strPtr("Python 嵌入式"),
+		SchoolID: intPtr(1), IsCrossSchool: intPtr(models.ProjectCrossSchoolNo),
+	}, nil)
+	mockTalent := new(MockTalentProfileRepo)
+	mockTalent.On("ListRecommendCandidates", mock.Anything, repository.TalentCandidateParams{
+		ProjectID: 1, SchoolID: intPtr(1), Limit: recommendCandidateLimit,
+	}).Return([]models.TalentProfile{
+		{ID: 11, UserID: 101, SchoolID: intPtr(1), UpdatedAt: timePtr(recommendNow.Add(-time.Hour))},
+		{ID: 12, UserID: 102, SchoolID: intPtr(1), SkillSummary: strPtr(`["Python","嵌入式"]`), UpdatedAt: timePtr(recommendNow.AddDate(0, -2, 0))},
+		{ID: 13, UserID: 103, SchoolID: intPtr(1), SkillSummary: strPtr(`["Python"]`), UpdatedAt: timePtr(recommendNow.AddDate(0, 0, -10))},
+	}, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("CountDirectionsByUsers", mock.Anything, []int{101, 102, 103}).Return(map[int]map[int]int{}, nil)
+
+	repo := newUntaggedRepo()
+	repo.Project, repo.TalentProfile, repo.ProjectMember = mockProject, mockTalent, mockMember
+	results, err := newRecommendTestService(repo).RecommendTalents(context.Background(), 1, 20, 2)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, 12, results[0].Talent.ID)
+	assert.Equal(t, 26+15, results[0].Score)
+	assert.Equal(t, 13, results[1].Talent.ID)
+	assert.Equal(t, []string{"技能匹配：Python", "同校", "近 30 天活跃"}, results[1].Reasons)
+}
+
+func TestRecommendProjects_UsesProfileAndHistory(t *testing.T) {
+	mockUser := new(MockUserRepo)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10, SchoolID: intPtr(2)}, nil)
+	mockTalent := new(MockTalentProfileRepo)
+	mockTalent.On("GetByUserID", mock.Anything, 10).Return(&models.TalentProfile{ID: 5, UserID: 10, SkillSummary: strPtr(`["Vue.js"]`)}, nil)
+	mockMember := new(MockProjectMemberRepo)
+	mockMember.On("CountDirectionsByUsers", mock.Anything, []int{10}).Return(map[int]map[int]int{
+		10: {models.ProjectDirectionLearning: 1},
+	}, nil)
+	mockProject := new(MockProjectRepo)
+	mockProject.On("ListRecommendCandidates", mock.Anything, repository.ProjectCandidateParams{
+		UserID: 10, SchoolID: intPtr(2), Limit: recommendCandidateLimit,
+	}).Return([]models.Project{
+		{ID: 1, Name: "考研互助", Direction: intPtr(models.ProjectDirectionLearning), CreatedAt: recommendNow.AddDate(-1, 0, 0)},
+		{ID: 2, Name: "前端作品集", SkillRequirement: strPtr("Vue"), CreatedAt: recommendNow.AddDate(-1, 0, 0)},
+		{ID: 3, Name: "无关项目", CreatedAt: recommendNow.AddDate(-1, 0, 0)},
+	}, nil)
+	// "Vue.js" 不出现在技能要求中，靠同义词识别出的标签匹配
+	vue := models.SkillTag{ID: 6, Name: "Vue"}
+	mockTags := new(MockSkillTagRepo)
+	mockTags.On("ListByProjects", mock.Anything, []int{1, 2, 3}).Return(map[int][]models.SkillTag{2: {vue}}, nil)
+	mockTags.On("ListByTalents", mock.Anything, []int{5}).Return(map[int][]models.SkillTag{5: {vue}}, nil)
+
+	repo := &repository.Repository{User: mockUser, TalentProfile: mockTalent, ProjectMember: mockMember, Project: mockProject, SkillTag: mockTags}
+	results, err := newRecommendTestService(repo).RecommendProjects(context.Background(), 10, 0)
+
+	require.NoError(t, err)
+	require.Len(t, results, 2, "没有任何匹配的项目不推荐")
+	assert.Equal(t, 2, results[0].Project.ID)
+	assert.Equal(t, []string{"技能匹配：Vue"}, results[0].Reasons)
+	assert.Equal(t, []string{"参与过 1 个学习类项目"}, results[1].Reasons)
+}
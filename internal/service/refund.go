@@ -0,0 +1,434 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/wechat"
+)
+
+// Refunder 微信支付退款，*wechat.PayClient 实现了该接口
+type Refunder interface {
+	CreateRefund(ctx context.Context, req *wechat.RefundRequest) (*wechat.RefundResult, error)
+	QueryRefund(ctx context.Context, outRefundNo string) (*wechat.RefundResult, error)
+}
+
+// envRefunder 每次退款时按环境变量创建微信支付客户端
+type envRefunder struct{}
+
+func (envRefunder) CreateRefund(ctx context.Context, req *wechat.RefundRequest) (*wechat.RefundResult, error) {
+	payClient, err := envPayClient()
+	if err != nil {
+		return nil, err
+	}
+	return payClient.CreateRefund(ctx, req)
+}
+
+func (envRefunder) QueryRefund(ctx context.Context, outRefundNo string) (*wechat.RefundResult, error) {
+	payClient, err := envPayClient()
+	if err != nil {
+		return nil, err
+	}
+	return payClient.QueryRefund(ctx, outRefundNo)
+}
+
+func envPayClient() (*wechat.PayClient, error) {
+	payConfig, err := wechat.DefaultPayConfig()
+	if err != nil {
+		return nil, err
+	}
+	return wechat.NewPayClient(payConfig)
+}
+
+// RefundService 订单退款服务
+// 退款前在同一事务内收回已发放的权益（橄榄枝或权益台账），权益已被使用时拒绝退款；
+// 微信明确拒绝或关闭退款时恢复权益，订单回到已支付状态。
+type RefundService struct {
+	repo     *repository.Repository
+	refunder Refunder
+	benefits benefitFulfiller
+}
+
+// NewRefundService creates a new RefundService.
+func NewRefundService(repo *repository.Repository) *RefundService {
+	return NewRefundServiceWithRefunder(repo, envRefunder{})
+}
+
+// NewRefundServiceWithRefunder creates a RefundService with an explicit refunder.
+func NewRefundServiceWithRefunder(repo *repository.Repository, refunder Refunder) *RefundService {
+	return &RefundService{repo: repo, refunder: refunder, benefits: benefitFulfiller{repo: repo}}
+}
+
+// RefundListResult holds a page of refunds with pagination info.
+type RefundListResult struct {
+	List       []models.OrderRefund
+	Total      int64
+	TotalPages int
+	Page       int
+	Size       int
+}
+
+// ListRefunds returns a paginated list of refunds with optional filters.
+func (s *RefundService) ListRefunds(ctx context.Context, params repository.OrderRefundListParams) (*RefundListResult, error) {
+	params.Page, params.Size = normalizePageParams(params.Page, params.Size)
+
+	refunds, total, err := s.repo.OrderRefund.List(ctx, params)
+	if err != nil {
+		log.Printf("[RefundService.ListRefunds] repository error: %v", err)
+		return nil, ErrInternal("获取退款列表失败")
+	}
+
+	totalPages := int((total + int64(params.Size) - 1) / int64(params.Size))
+	return &RefundListResult{
+		List:       refunds,
+		Total:      total,
+		TotalPages: totalPages,
+		Page:       params.Page,
+		Size:       params.Size,
+	}, nil
+}
+
+// RequestRefund 用户申请退款，等待管理员审核
+func (s *RefundService) RequestRefund(ctx context.Context, userID, orderID int, reason string) (*models.OrderRefund, error) {
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		log.Printf("[RefundService.RequestRefund] repository error getting order: %v", err)
+		return nil, ErrInternal("获取订单详情失败")
+	}
+	if order == nil {
+		return nil, ErrNotFound("订单不存在")
+	}
+	if order.UserID != userID {
+		return nil, ErrForbidden("无权操作此订单")
+	}
+	if err := s.checkRefundable(ctx, order); err != nil {
+		return nil, err
+	}
+
+	// 提前检查权益是否已使用，审核通过时会在事务内再次检查
+	if err := s.benefits.checkRevocable(ctx, order); err != nil {
+		return nil, err
+	}
+
+	refund := newRefund(order, reason, models.RefundStatusPending, models.RefundSourceUser)
+	err = runInTx(ctx, s.repo, "RefundService.RequestRefund", "申请退款失败", func(tx *sqlx.Tx) error {
+		if err := s.lockRefundableTx(ctx, tx, order.ID); err != nil {
+			return err
+		}
+		return s.repo.OrderRefund.CreateTx(ctx, tx, refund)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.reload(ctx, refund.ID)
+}
+
+// AdminRefund (admin only) 管理员直接为已支付订单发起退款
+func (s *RefundService) AdminRefund(ctx context.Context, orderID int, reason string) (*models.OrderRefund, error) {
+	order, err := s.repo.Order.GetByID(ctx, orderID)
+	if err != nil {
+		log.Printf("[RefundService.AdminRefund] repository error getting order: %v", err)
+		return nil, ErrInternal("获取订单详情失败")
+	}
+	if order == nil {
+		return nil, ErrNotFound("订单不存在")
+	}
+	if err := s.checkRefundable(ctx, order); err != nil {
+		return nil, err
+	}
+
+	refund := newRefund(order, reason, models.RefundStatusProcessing, models.RefundSourceAdmin)
+	err = runInTx(ctx, s.repo, "RefundService.AdminRefund", "处理退款失败", func(tx *sqlx.Tx) error {
+		if err := s.lockRefundableTx(ctx, tx, order.ID); err != nil {
+			return err
+		}
+		if err := s.startRefundTx(ctx, tx, order); err != nil {
+			return err
+		}
+		return s.repo.OrderRefund.CreateTx(ctx, tx, refund)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.submit(ctx, refund, order)
+	return s.reload(ctx, refund.ID)
+}
+
+// ReviewRefund (admin only) 审核用户的退款申请，通过后立即发起退款
+func (s *RefundService) ReviewRefund(ctx context.Context, refundID int, approve bool, note string) (*models.OrderRefund, error) {
+	refund, err := s.repo.OrderRefund.GetByID(ctx, refundID)
+	if err != nil {
+		log.Printf("[RefundService.ReviewRefund] repository error getting refund: %v", err)
+		return nil, ErrInternal("获取退款详情失败")
+	}
+	if refund == nil {
+		return nil, ErrNotFound("退款申请不存在")
+	}
+	if refund.Status != models.RefundStatusPending {
+		return nil, ErrBadRequest("该退款申请已处理")
+	}
+
+	if !approve {
+		if note == "" {
+			return nil, ErrBadRequest("请填写驳回原因")
+		}
+		updated, err := s.repo.OrderRefund.UpdateStatus(ctx, refund.ID, models.RefundStatusPending, models.RefundStatusRejected, &note)
+		if err != nil {
+			log.Printf("[RefundService.ReviewRefund] repository error rejecting refund: %v", err)
+			return nil, ErrInternal("驳回退款申请失败")
+		}
+		if !updated {
+			return nil, ErrBadRequest("该退款申请已处理")
+		}
+		return s.reload(ctx, refund.ID)
+	}
+
+	order, err := s.repo.Order.GetByID(ctx, refund.OrderID)
+	if err != nil || order == nil {
+		log.Printf("[RefundService.ReviewRefund] repository error getting order: %v", err)
+		return nil, ErrInternal("获取订单详情失败")
+	}
+
+	err = runInTx(ctx, s.repo, "RefundService.ReviewRefund", "处理退款失败", func(tx *sqlx.Tx) error {
+		updated, err := s.repo.OrderRefund.UpdateStatusTx(ctx, tx, refund.ID, models.RefundStatusPending, models.RefundStatusProcessing, nil)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return ErrBadRequest("该退款申请已处理")
+		}
+		return s.startRefundTx(ctx, tx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refund.Status = models.RefundStatusProcessing
+	s.submit(ctx, refund, order)
+	return s.reload(ctx, refund.ID)
+}
+
+// HandleRefundNotification 处理微信退款结果通知，重复通知会被忽略
+func (s *RefundService) HandleRefundNotification(ctx context.Context, n *wechat.RefundNotification) error {
+	refund, err := s.repo.OrderRefund.GetByOutRefundNo(ctx, n.OutRefundNo)
+	if err != nil {
+		log.Printf("[RefundService.HandleRefundNotification] repository error getting refund: %v", err)
+		return ErrInternal("查询退款失败")
+	}
+	if refund == nil {
+		log.Printf("[RefundService.HandleRefundNotification] unknown out_refund_no %s", n.OutRefundNo)
+		return nil
+	}
+
+	order, err := s.repo.Order.GetByID(ctx, refund.OrderID)
+	if err != nil || order == nil {
+		log.Printf("[RefundService.HandleRefundNotification] repository error getting order: %v", err)
+		return ErrInternal("查询订单失败")
+	}
+
+	switch n.RefundStatus {
+	case wechat.RefundStateSuccess:
+		successTime, _ := time.Parse(time.RFC3339, n.SuccessTime)
+		return s.succeed(ctx, refund, order, n.RefundID, successTime)
+	case wechat.RefundStateClosed:
+		return s.fail(ctx, refund, order, "退款已关闭")
+	case wechat.RefundStateAbnormal:
+		log.Printf("[RefundService.HandleRefundNotification] refund %d is abnormal, needs manual handling on the merchant platform", refund.ID)
+	}
+	return nil
+}
+
+// submit 调用微信退款接口。结果未知时保持退款中，等待退款通知或 RefundReconcileScheduler 查单。
+func (s *RefundService) submit(ctx context.Context, refund *models.OrderRefund, order *models.Order) {
+	req := &wechat.RefundRequest{
+		OutRefundNo: refund.OutRefundNo,
+		RefundCents: toCents(refund.Amount),
+		TotalCents:  toCents(order.ActualPaid),
+	}
+	if order.OutTradeNo != nil {
+		req.OutTradeNo = *order.OutTradeNo
+	}
+	if order.WxPayNo != nil {
+		req.TransactionID = *order.WxPayNo
+	}
+	if refund.Reason != nil {
+		req.Reason = *refund.Reason
+	}
+
+	result, err := s.refunder.CreateRefund(ctx, req)
+	if err != nil {
+		if errors.Is(err, wechat.ErrRefundRejected) {
+			_ = s.fail(ctx, refund, order, truncateRunes(err.Error(), 500))
+			return
+		}
+		log.Printf("[RefundService.submit] refund %d outcome unknown, waiting for notification: %v", refund.ID, err)
+		return
+	}
+
+	s.applyResult(ctx, refund, order, result)
+}
+
+// reconcile 查询退款结果，补做退款通知丢失或申请结果未知时的处理。
+// 微信侧没有该退款单时用原退款单号重新申请，同一退款单号只会退一笔。
+func (s *RefundService) reconcile(ctx context.Context, refund *models.OrderRefund) {
+	order, err := s.repo.Order.GetByID(ctx, refund.OrderID)
+	if err != nil || order == nil {
+		log.Printf("[RefundService.reconcile] repository error getting order for refund %d: %v", refund.ID, err)
+		return
+	}
+
+	result, err := s.refunder.QueryRefund(ctx, refund.OutRefundNo)
+	if errors.Is(err, wechat.ErrRefundNotExist) {
+		s.submit(ctx, refund, order)
+		return
+	}
+	if err != nil {
+		log.Printf("[RefundService.reconcile] query refund %d: %v", refund.ID, err)
+		return
+	}
+
+	if result.Status == wechat.RefundStateAbnormal {
+		log.Printf("[RefundService.reconcile] refund %d is abnormal, needs manual handling on the merchant platform", refund.ID)
+	}
+	s.applyResult(ctx, refund, order, result)
+}
+
+// applyResult 按微信返回的退款状态完成退款；处理中时记录微信退款单号
+func (s *RefundService) applyResult(ctx context.Context, refund *models.OrderRefund, order *models.Order, result *wechat.RefundResult) {
+	switch result.Status {
+	case wechat.RefundStateSuccess:
+		_ = s.succeed(ctx, refund, order, result.RefundID, result.SuccessTime)
+	case wechat.RefundStateClosed:
+		_ = s.fail(ctx, refund, order, "退款已关闭")
+	default:
+		if result.RefundID != "" {
+			if err := s.repo.OrderRefund.SetRefundID(ctx, refund.ID, result.RefundID); err != nil {
+				log.Printf("[RefundService.applyResult] save refund id for refund %d: %v", refund.ID, err)
+			}
+		}
+	}
+}
+
+// succeed 退款成功：订单标记为已退款
+func (s *RefundService) succeed(ctx context.Context, refund *models.OrderRefund, order *models.Order, refundID string, successTime time.Time) error {
+	if successTime.IsZero() {
+		successTime = time.Now()
+	}
+	return runInTx(ctx, s.repo, "RefundService.succeed", "处理退款失败", func(tx *sqlx.Tx) error {
+		updated, err := s.repo.OrderRefund.MarkSuccessTx(ctx, tx, refund.ID, refundID, successTime)
+		if err != nil || !updated {
+			return err
+		}
+		_, err = s.repo.Order.TransitionStatusTx(ctx, tx, order.ID, models.OrderStatusRefunding, models.OrderStatusRefunded)
+		return err
+	})
+}
+
+// fail 退款失败：恢复权益，订单回到已支付
+func (s *RefundService) fail(ctx context.Context, refund *models.OrderRefund, order *models.Order, note string) error {
+	return runInTx(ctx, s.repo, "RefundService.fail", "处理退款失败", func(tx *sqlx.Tx) error {
+		updated, err := s.repo.OrderRefund.UpdateStatusTx(ctx, tx, refund.ID, models.RefundStatusProcessing, models.RefundStatusFailed, &note)
+		if err != nil || !updated {
+			return err
+		}
+		if _, err := s.repo.Order.TransitionStatusTx(ctx, tx, order.ID, models.OrderStatusRefunding, models.OrderStatusPaid); err != nil {
+			return err
+		}
+		return s.benefits.restoreTx(ctx, tx, order)
+	})
+}
+
+// startRefundTx 订单进入退款中并收回权益
+func (s *RefundService) startRefundTx(ctx context.Context, tx *sqlx.Tx, order *models.Order) error {
+	updated, err := s.repo.Order.TransitionStatusTx(ctx, tx, order.ID, models.OrderStatusPaid, models.OrderStatusRefunding)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return ErrBadRequest("订单状态不允许退款")
+	}
+	return s.benefits.revokeTx(ctx, tx, order)
+}
+
+// checkRefundable 订单必须已支付且没有进行中的退款
+func (s *RefundService) checkRefundable(ctx context.Context, order *models.Order) error {
+	if order.Status != models.OrderStatusPaid {
+		return ErrBadRequest("订单状态不允许退款")
+	}
+
+	active, err := s.repo.OrderRefund.GetActiveByOrderID(ctx, order.ID)
+	if err != nil {
+		log.Printf("[RefundService.checkRefundable] repository error: %v", err)
+		return ErrInternal("查询退款记录失败")
+	}
+	if active != nil {
+		return ErrBadRequest("该订单已有进行中的退款")
+	}
+	return nil
+}
+
+// lockRefundableTx 锁定订单后重新检查 checkRefundable 的条件，避免并发申请创建多笔退款
+func (s *RefundService) lockRefundableTx(ctx context.Context, tx *sqlx.Tx, orderID int) error {
+	status, err := s.repo.Order.LockStatusTx(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if status != models.OrderStatusPaid {
+		return ErrBadRequest("订单状态不允许退款")
+	}
+
+	active, err := s.repo.OrderRefund.GetActiveByOrderID(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if active != nil {
+		return ErrBadRequest("该订单已有进行中的退款")
+	}
+	return nil
+}
+
+func (s *RefundService) reload(ctx context.Context, id int) (*models.OrderRefund, error) {
+	refund, err := s.repo.OrderRefund.GetByID(ctx, id)
+	if err != nil || refund == nil {
+		log.Printf("[RefundService.reload] repository error: %v", err)
+		return nil, ErrInternal("获取退款详情失败")
+	}
+	return refund, nil
+}
+
+func newRefund(order *models.Order, reason string, status int, source string) *models.OrderRefund {
+	refund := &models.OrderRefund{
+		OrderID:     order.ID,
+		UserID:      order.UserID,
+		OutRefundNo: wechat.GenerateOutRefundNo(order.ID),
+		Amount:      order.ActualPaid,
+		Status:      status,
+		Source:      source,
+	}
+	if reason != "" {
+		refund.Reason = &reason
+	}
+	return refund
+}
+
+// toCents 金额（元）转为分
+func toCents(amount float64) int {
+	return int(math.Round(amount * 100))
+}
+
+// truncateRunes 按字符数截断文本
+func truncateRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
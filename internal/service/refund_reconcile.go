@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	refundReconcileInterval  = 5 * time.Minute  // 扫描退款中退款的间隔
+	refundReconcileAfter     = 10 * time.Minute // 退款中超过该时间没有变化时主动查单
+	refundReconcileBatchSize = 50               // 每次处理的退款数
+)
+
+// RefundReconcileScheduler 退款结果补偿
+// 申请退款超时、微信返回 5xx 或退款通知丢失时，退款会一直停留在退款中；
+// 定时扫描长时间没有变化的退款中退款，向微信支付查询结果并完成退款或恢复权益。
+type RefundReconcileScheduler struct {
+	refunds *RefundService
+}
+
+// NewRefundReconcileScheduler creates a RefundReconcileScheduler.
+func NewRefundReconcileScheduler(refunds *RefundService) *RefundReconcileScheduler {
+	return &RefundReconcileScheduler{refunds: refunds}
+}
+
+// Run 持续补偿结果未知的退款，直到 ctx 被取消
+func (s *RefundReconcileScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(refundReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		s.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce 查询一批停留在退款中的退款，返回处理的退款数
+func (s *RefundReconcileScheduler) RunOnce(ctx context.Context) int {
+	refunds, err := s.refunds.repo.OrderRefund.ListStaleProcessing(ctx, time.Now().Add(-refundReconcileAfter), refundReconcileBatchSize)
+	if err != nil {
+		log.Printf("[RefundReconcileScheduler.RunOnce] repository error: %v", err)
+		return 0
+	}
+
+	for i := range refunds {
+		s.refunds.reconcile(ctx, &refunds[i])
+	}
+	return len(refunds)
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/wechat"
+)
+
+type MockOrderRefundRepo struct {
+	mock.Mock
+}
+
+func (m *MockOrderRefundRepo) List(ctx context.Context, params repository.OrderRefundListParams) ([]models.OrderRefund, int64, error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).([]models.OrderRefund), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockOrderRefundRepo) GetByID(ctx context.Context, id int) (*models.OrderRefund, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrderRefund), args.Error(1)
+}
+
+func (m *MockOrderRefundRepo) GetByOutRefundNo(ctx context.Context, outRefundNo string) (*models.OrderRefund, error) {
+	args := m.Called(ctx, outRefundNo)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrderRefund), args.Error(1)
+}
+
+func (m *MockOrderRefundRepo) GetActiveByOrderID(ctx context.Context, orderID int) (*models.OrderRefund, error) {
+	args := m.Called(ctx, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OrderRefund), args.Error(1)
+}
+
+func (m *MockOrderRefundRepo) CreateTx(ctx context.Context, tx *sqlx.Tx, refund *models.OrderRefund) error {
+	args := m.Called(ctx, tx, refund)
+	refund.ID = 7
+	return args.Error(0)
+}
+
+func (m *MockOrderRefundRepo) UpdateStatus(ctx context.Context, id int, from, to int, note *string) (bool, error) {
+	args := m.Called(ctx, id, from, to, note)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderRefundRepo) UpdateStatusTx(ctx context.Context, tx *sqlx.Tx, id int, from, to int, note *string) (bool, error) {
+	args := m.Called(ctx, tx, id, from, to, note)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderRefundRepo) SetRefundID(ctx context.Context, id int, refundID string) error {
+	args := m.Called(ctx, id, refundID)
+	return args.Error(0)
+}
+
+func (m *MockOrderRefundRepo) MarkSuccessTx(ctx context.Context, tx *sqlx.Tx, id int, refundID string, successTime time.Time) (bool, error) {
+	args := m.Called(ctx, tx, id, refundID, successTime)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOrderRefundRepo) ListStaleProcessing(ctx context.Context, before time.Time, limit int) ([]models.OrderRefund, error) {
+	args := m.Called(ctx, before, limit)
+	return args.Get(0).([]models.OrderRefund), args.Error(1)
+}
+
+// stubRefunder 返回固定的申请和查询结果，并记录申请的退款
+type stubRefunder struct {
+	created   []*wechat.RefundRequest
+	createRes *wechat.RefundResult
+	queryRes  *wechat.RefundResult
+	queryErr  error
+}
+
+func (r *stubRefunder) CreateRefund(ctx context.Context, req *wechat.RefundRequest) (*wechat.RefundResult, error) {
+	r.created = append(r.created, req)
+	return r.createRes, nil
+}
+
+func (r *stubRefunder) QueryRefund(ctx context.Context, outRefundNo string) (*wechat.RefundResult, error) {
+	return r.queryRes, r.queryErr
+}
+
+func intPtr(v int) *int { return &v }
+
+func paidOrder() *models.Order {
+	return &models.Order{ID: 1, UserID: 10, ProductID: 3, Quantity: 5, ActualPaid: 9.9, Status: models.OrderStatusPaid}
+}
+
+// --- Tests for RefundService ---
+
+func TestRequestRefund_NotOwner(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockOrder.On("GetByID", mock.Anything, 1).Return(paidOrder(), nil)
+
+	svc := NewRefundServiceWithRefunder(&repository.Repository{Order: mockOrder}, nil)
+	_, err := svc.RequestRefund(context.Background(), 99, 1, "")
+
+	assertServiceError(t, err, ErrCodeForbidden, "无权操作此订单")
+}
+
+func TestRequestRefund_OrderNotPaid(t *testing.T) {
+	order := paidOrder()
+	order.Status = models.OrderStatusPending
+	mockOrder := new(MockOrderRepo)
+	mockOrder.On("GetByID", mock.Anything, 1).Return(order, nil)
+
+	svc := NewRefundServiceWithRefunder(&repository.Repository{Order: mockOrder}, nil)
+	_, err := svc.RequestRefund(context.Background(), 10, 1, "")
+
+	assertServiceError(t, err, ErrCodeBadRequest, "订单状态不允许退款")
+}
+
+func TestRequestRefund_ActiveRefundExists(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockRefund := new(MockOrderRefundRepo)
+	mockOrder.On("GetByID", mock.Anything, 1).Return(paidOrder(), nil)
+	mockRefund.On("GetActiveByOrderID", mock.Anything, 1).Return(&models.OrderRefund{ID: 3, Status: models.RefundStatusPending}, nil)
+
+	svc := NewRefundServiceWithRefunder(&repository.Repository{Order: mockOrder, OrderRefund: mockRefund}, nil)
+	_, err := svc.RequestRefund(context.Background(), 10, 1, "")
+
+	assertServiceError(t, err, ErrCodeBadRequest, "该订单已有进行中的退款")
+}
+
+func TestRequestRefund_OliveBranchesSpent(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockRefund := new(MockOrderRefundRepo)
+	mockProduct := new(MockProductRepo)
+	mockUser := new(MockUserRepo)
+	mockOrder.On("GetByID", mock.Anything, 1).Return(paidOrder(), nil)
+	mockRefund.On("GetActiveByOrderID", mock.Anything, 1).Return(nil, nil)
+	mockProduct.On("GetByID", mock.Anything, 3).Return(&models.Product{ID: 3, Type: models.ProductTypeCurrency}, nil)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10, OliveBranchCount: intPtr(2)}, nil)
+
+	svc := NewRefundServiceWithRefunder(&repository.Repository{
+		Order: mockOrder, OrderRefund: mockRefund, Product: mockProduct, User: mockUser,
+	}, nil)
+	_, err := svc.RequestRefund(context.Background(), 10, 1, "")
+
+	assertServiceError(t, err, ErrCodeBadRequest, "购买的橄榄枝已被使用，无法退款")
+	mockRefund.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRequestRefund_CreatesPendingRefund(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockRefund := new(MockOrderRefundRepo)
+	mockProduct := new(MockProductRepo)
+	mockUser := new(MockUserRepo)
+	mockOrder.On("GetByID", mock.Anything, 1).Return(paidOrder(), nil)
+	mockOrder.On("LockStatusTx", mock.Anything, mock.Anything, 1).Return(models.OrderStatusPaid, nil)
+	mockRefund.On("GetActiveByOrderID", mock.Anything, 1).Return(nil, nil)
+	mockProduct.On("GetByID", mock.Anything, 3).Return(&models.Product{ID: 3, Type: models.ProductTypeCurrency}, nil)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10, OliveBranchCount: intPtr(5)}, nil)
+	mockRefund.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(r *models.OrderRefund) bool {
+		return r.Status == models.RefundStatusPending && r.Source == models.RefundSourceUser &&
+			r.Amount == 9.9 && *r.Reason == "买错了"
+	})).Return(nil)
+	mockRefund.On("GetByID", mock.Anything, 7).Return(&models.OrderRefund{ID: 7, Status: models.RefundStatusPending}, nil)
+
+	repo := newTxTestRepo()
+	repo.Order, repo.OrderRefund, repo.Product, repo.User = mockOrder, mockRefund, mockProduct, mockUser
+	svc := NewRefundServiceWithRefunder(repo, nil)
+	refund, err := svc.RequestRefund(context.Background(), 10, 1, "买错了")
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, refund.ID)
+	mockOrder.AssertExpectations(t)
+	mockRefund.AssertExpectations(t)
+}
+
+func TestRequestRefund_ConcurrentRequestCreatedRefund(t *testing.T) {
+	mockOrder := new(MockOrderRepo)
+	mockRefund := new(MockOrderRefundRepo)
+	mockProduct := new(MockProductRepo)
+	mockUser := new(MockUserRepo)
+	mockOrder.On("GetByID", mock.Anything, 1).Return(paidOrder(), nil)
+	mockOrder.On("LockStatusTx", mock.Anything, mock.Anything, 1).Return(models.OrderStatusPaid, nil)
+	// 并发的申请在锁定订单前提交了退款
+	mockRefund.On("GetActiveByOrderID", mock.Anything, 1).Return(nil, nil).Once()
+	mockRefund.On("GetActiveByOrderID", mock.Anything, 1).Return(&models.OrderRefund{ID: 3, Status: models.RefundStatusPending}, nil)
+	mockProduct.On("GetByID", mock.Anything, 3).Return(&models.Product{ID: 3, Type: models.ProductTypeCurrency}, nil)
+	mockUser.On("GetByID", mock.Anything, 10).Return(&models.User{ID: 10, OliveBranchCount: intPtr(5)}, nil)
+
+	repo := newTxTestRepo()
+	repo.Order, repo.OrderRefund, repo.Product, repo.User = mockOrder, mockRefund, mockProduct, mockUser
+	svc := NewRefundServiceWithRefunder(repo, nil)
+	_, err := svc.RequestRefund(context.Background(), 10, 1, "买错了")
+
+	assertServiceError(t, err, ErrCodeBadRequest, "该订单已有进行中的退款")
+	mockRefund.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReviewRefund_RejectRequiresNote(t *testing.T) {
+	mockRefund := new(MockOrderRefundRepo)
+	mockRefund.On("GetByID", mock.Anything, 7).Return(&models.OrderRefund{ID: 7, Status: models.RefundStatusPending}, nil)
+
+	svc := NewRefundServiceWithRefunder(&repository.Repository{OrderRefund: mockRefund}, nil)
+	_, err := svc.ReviewRefund(context.Background(), 7, false, "")
+
+	assertServiceError(t, err, ErrCodeBadRequest, "请填写驳回原因")
+}
+
+func TestReviewRefund_AlreadyHandled(t *testing.T) {
+	mockRefund := new(MockOrderRefundRepo)
+	mockRefund.On("GetByID", mock.Anything, 7).Return(&models.OrderRefund{ID: 7, Status: models.RefundStatusSuccess}, nil)
+
+	svc := NewRefundServiceWithRefunder(&repository.Repository{OrderRefund: mockRefund}, nil)
+	_, err := svc.ReviewRefund(context.Background(), 7, true, "")
+
+	assertServiceError(t, err, ErrCodeBadRequest, "该退款申请已处理")
+}
+
+func TestHandleRefundNotification_UnknownRefundIgnored(t *testing.T) {
+	mockRefund := new(MockOrderRefundRepo)
+	mockRefund.On("GetByOutRefundNo", mock.Anything, "RF1_1").Return(nil, nil)
+
+	svc := NewRefundServiceWithRefunder(&repository.Repository{OrderRefund: mockRefund}, nil)
+	err := svc.HandleRefundNotification(context.Background(), &wechat.RefundNotification{
+		OutRefundNo:  "RF1_1",
+		RefundStatus: wechat.RefundStateSuccess,
+	})
+
+	assert.NoError(t, err)
+}
+
+func processingRefund() models.OrderRefund {
+	return models.OrderRefund{ID: 7, OrderID: 1, UserID: 10, OutRefundNo: "RF1_1", Amount: 9.9, Status: models.RefundStatusProcessing}
+}
+
+func TestRefundReconcile_QueriedSuccessCompletesRefund(t *testing.T) {
+	mockRefund := new(MockOrderRefundRepo)
+	mockRefund.On("ListStaleProcessing", mock.Anything, mock.Anything, refundReconcileBatchSize).Return([]models.OrderRefund{processingRefund()}, nil)
+	mockRefund.On("MarkSuccessTx", mock.Anything, mock.Anything, 7, "wx-refund-1", mock.Anything).Return(true, nil)
+	mockOrder := new(MockOrderRepo)
+	order := paidOrder()
+	order.Status = models.OrderStatusRefunding
+	mockOrder.On("GetByID", mock.Anything, 1).Return(order, nil)
+	mockOrder.On("TransitionStatusTx", mock.Anything, mock.Anything, 1, models.OrderStatusRefunding, models.OrderStatusRefunded).Return(true, nil)
+
+	repo := newTxTestRepo()
+	repo.OrderRefund = mockRefund
+	repo.Order = mockOrder
+	refunder := &stubRefunder{queryRes: &wechat.RefundResult{RefundID: "wx-refund-1", Status: wechat.RefundStateSuccess}}
+
+	n := NewRefundReconcileScheduler(NewRefundServiceWithRefunder(repo, refunder)).RunOnce(context.Background())
+
+	assert.Equal(t, 1, n)
+	assert.Empty(t, refunder.created)
+	mockRefund.AssertExpectations(t)
+	mockOrder.AssertExpectations(t)
+}
+
+func TestRefundReconcile_MissingRefundResubmitted(t *testing.T) {
+	mockRefund := new(MockOrderRefundRepo)
+	mockRefund.On("ListStaleProcessing", mock.Anything, mock.Anything, refundReconcileBatchSize).Return([]models.OrderRefund{processingRefund()}, nil)
+	mockRefund.On("SetRefundID", mock.Anything, 7, "wx-refund-1").Return(nil)
+	mockOrder := new(MockOrderRepo)
+	mockOrder.On("GetByID", mock.Anything, 1).Return(paidOrder(), nil)
+
+	repo := &repository.Repository{OrderRefund: mockRefund, Order: mockOrder}
+	refunder := &stubRefunder{
+		queryErr:  wechat.ErrRefundNotExist,
+		createRes: &wechat.RefundResult{RefundID: "wx-refund-1", Status: wechat.RefundStateProcessing},
+	}
+
+	NewRefundReconcileScheduler(NewRefundServiceWithRefunder(repo, refunder)).RunOnce(context.Background())
+
+	require.Len(t, refunder.created, 1)
+	assert.Equal(t, "RF1_1", refunder.created[0].OutRefundNo)
+	assert.Equal(t, 990, refunder.created[0].RefundCents)
+	mockRefund.AssertExpectations(t)
+}
+
+func TestToCents(t *testing.T) {
+	assert.Equal(t, 990, toCents(9.9))
+	assert.Equal(t, 1999, toCents(19.99))
+}
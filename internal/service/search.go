@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/search"
+)
+
+const (
+	// searchMaxHits 一次搜索最多取回的命中数，结构化筛选和分页都在其中进行；
+	// 命中更多时结果标记为截断，列表接口通过 pageInfo.truncated 告知客户端
+	searchMaxHits = 500
+	// searchSnippetRunes 高亮片段的最大字数
+	searchSnippetRunes = 80
+	// searchRebuildBatch 重建索引时每批读取的项目或档案数
+	searchRebuildBatch = 200
+)
+
+// SearchService maintains the full-text index of projects and talent profiles
+// and runs keyword / skill searches against it.
+type SearchService struct {
+	index   search.Index
+	repo    *repository.Repository
+	maxHits int
+}
+
+// NewSearchService creates a new SearchService.
+func NewSearchService(index search.Index, repo *repository.Repository) *SearchService {
+	return &SearchService{index: index, repo: repo, maxHits: searchMaxHits}
+}
+
+// SearchMatches holds the ids matched by a search, most relevant first.
+type SearchMatches struct {
+	IDs []int
+	// Truncated reports that the search hit more documents than it keeps; the
+	// listing then only filters and counts the most relevant ones.
+	Truncated bool
+	query     string // 用于高亮的关键词与技能
+}
+
+// Match searches documents of the given type. It returns nil when neither a
+// keyword nor a skill is given, in which case the caller lists as usual.
+func (s *SearchService) Match(ctx context.Context, docType string, keyword, skill *string) (*SearchMatches, error) {
+	if s == nil {
+		return nil, nil
+	}
+	kw, sk := strings.TrimSpace(derefString(keyword)), strings.TrimSpace(derefString(skill))
+	if kw == "" && sk == "" {
+		return nil, nil
+	}
+
+	// 多取一条，用于判断命中数是否超过上限
+	hits, err := s.index.Search(ctx, search.Query{Type: docType, Keyword: kw, Skill: sk, Limit: s.maxHits + 1})
+	if err != nil {
+		log.Printf("[SearchService.Match] index error: %v", err)
+		return nil, ErrInternal("搜索失败")
+	}
+	truncated := len(hits) > s.maxHits
+	if truncated {
+		hits = hits[:s.maxHits]
+	}
+
+	ids := make([]int, len(hits))
+	for i, h := range hits {
+		ids[i] = h.RefID
+	}
+	return &SearchMatches{IDs: ids, Truncated: truncated, query: strings.TrimSpace(kw + " " + sk)}, nil
+}
+
+// HighlightProject fills the highlighted snippets of a matched project.
+func (m *SearchMatches) HighlightProject(p *models.Project) {
+	p.Highlights = m.highlights(map[string]string{
+		"name":             p.Name,
+		"description":      derefString(p.Description),
+		"skillRequirement": derefString(p.SkillRequirement),
+	})
+}
+
+// HighlightTalent fills the highlighted snippets of a matched talent profile.
+func (m *SearchMatches) HighlightTalent(p *models.TalentProfile) {
+	p.Highlights = m.highlights(map[string]string{
+		"nickname":          derefString(p.Nickname),
+		"selfEvaluation":    derefString(p.SelfEvaluation),
+		"projectExperience": derefString(p.ProjectExperience),
+		"skills":            strings.Join(p.Skills(), "、"),
+	})
+}
+
+func (m *SearchMatches) highlights(fields map[string]string) map[string]string {
+	h := make(map[string]string)
+	for name, text := range fields {
+		if snippet := search.Highlight(text, m.query, searchSnippetRunes); snippet != "" {
+			h[name] = snippet
+		}
+	}
+	return h
+}
+
+// IndexProject writes the project into the search index together with the
+// names and synonyms of its linked skill tags. Indexing is best effort:
+// failures are logged and never fail the write that triggered them; Rebuild
+// repairs documents that were missed.
+func (s *SearchService) IndexProject(ctx context.Context, p *models.Project) {
+	if s == nil || p == nil {
+		return
+	}
+	terms, err := s.skillTerms(ctx, s.repo.SkillTag.ListByProjects, []int{p.ID})
+	if err != nil {
+		log.Printf("[SearchService.IndexProject] repository error listing skill tags of project %d: %v", p.ID, err)
+	}
+	s.upsert(ctx, projectDocument(p, terms[p.ID]))
+}
+
+func projectDocument(p *models.Project, tagTerms []string) search.Document {
+	var skills []string
+	if p.SkillRequirement != nil {
+		skills = []string{*p.SkillRequirement}
+	}
+	return search.Document{
+		Type:    models.SearchDocProject,
+		RefID:   p.ID,
+		Title:   p.Name,
+		Content: derefString(p.Description),
+		Skills:  append(skills, tagTerms...),
+	}
+}
+
+// RemoveProject removes a deleted project from the search index.
+func (s *SearchService) RemoveProject(ctx context.Context, id int) {
+	if s == nil {
+		return
+	}
+	if err := s.index.Delete(ctx, models.SearchDocProject, id); err != nil {
+		log.Printf("[SearchService.RemoveProject] index error for project %d: %v", id, err)
+	}
+}
+
+// RemoveTalent removes a deleted talent profile from the search index.
+func (s *SearchService) RemoveTalent(ctx context.Context, id int) {
+	if s == nil {
+		return
+	}
+	if err := s.index.Delete(ctx, models.SearchDocTalent, id); err != nil {
+		log.Printf("[SearchService.RemoveTalent] index error for talent profile %d: %v", id, err)
+	}
+}
+
+// IndexTalent writes the talent profile into the search index together with
+// the names and synonyms of its linked skill tags. Hidden profiles stay
+// indexed; the listing filters them by status.
+func (s *SearchService) IndexTalent(ctx context.Context, p *models.TalentProfile) {
+	if s == nil || p == nil {
+		return
+	}
+	terms, err := s.skillTerms(ctx, s.repo.SkillTag.ListByTalents, []int{p.ID})
+	if err != nil {
+		log.Printf("[SearchService.IndexTalent] repository error listing skill tags of talent profile %d: %v", p.ID, err)
+	}
+	s.upsert(ctx, talentDocument(p, terms[p.ID]))
+}
+
+func talentDocument(p *models.TalentProfile, tagTerms []string) search.Document {
+	return search.Document{
+		Type:    models.SearchDocTalent,
+		RefID:   p.ID,
+		Title:   derefString(p.Nickname),
+		Content: strings.TrimSpace(derefString(p.SelfEvaluation) + "\n" + derefString(p.ProjectExperience)),
+		Skills:  append(p.Skills(), tagTerms...),
+	}
+}
+
+// skillTerms 返回各项目或档案关联的技能标签名称及其同义词，使搜索同义词也能命中
+func (s *SearchService) skillTerms(
+	ctx context.Context,
+	listTags func(ctx context.Context, refIDs []int) (map[int][]models.SkillTag, error),
+	refIDs []int,
+) (map[int][]string, error) {
+	tags, err := listTags(ctx, refIDs)
+	if err != nil {
+		return nil, err
+	}
+	var tagIDs []int
+	for _, list := range tags {
+		for _, t := range list {
+			tagIDs = append(tagIDs, t.ID)
+		}
+	}
+	synonyms, err := s.repo.SkillTag.ListSynonyms(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := make(map[int][]string, len(tags))
+	for refID, list := range tags {
+		for _, t := range list {
+			terms[refID] = append(append(terms[refID], t.Name), synonyms[t.ID]...)
+		}
+	}
+	return terms, nil
+}
+
+func (s *SearchService) upsert(ctx context.Context, doc search.Document) {
+	if err := s.index.Upsert(ctx, doc); err != nil {
+		log.Printf("[SearchService.upsert] index error for %s %d: %v", doc.Type, doc.RefID, err)
+	}
+}
+
+// SearchRebuildResult counts the projects and talent profiles that were re-indexed.
+type SearchRebuildResult struct {
+	Projects int
+	Talents  int
+}
+
+// Rebuild rewrites the search document of every project and talent profile,
+// repairing documents whose best-effort indexing failed.
+func (s *SearchService) Rebuild(ctx context.Context) (*SearchRebuildResult, error) {
+	result := &SearchRebuildResult{}
+	var err error
+	result.Projects, err = s.rebuild(ctx, func(ctx context.Context, afterID int) ([]search.Document, error) {
+		projects, err := s.repo.SearchDocument.ListProjectSources(ctx, afterID, searchRebuildBatch)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int, len(projects))
+		for i := range projects {
+			ids[i] = projects[i].ID
+		}
+		terms, err := s.skillTerms(ctx, s.repo.SkillTag.ListByProjects, ids)
+		if err != nil {
+			return nil, err
+		}
+		docs := make([]search.Document, len(projects))
+		for i := range projects {
+			docs[i] = projectDocument(&projects[i], terms[projects[i].ID])
+		}
+		return docs, nil
+	})
+	if err != nil {
+		log.Printf("[SearchService.Rebuild] error rebuilding projects: %v", err)
+		return nil, ErrInternal("重建搜索索引失败")
+	}
+	result.Talents, err = s.rebuild(ctx, func(ctx context.Context, afterID int) ([]search.Document, error) {
+		profiles, err := s.repo.SearchDocument.ListTalentSources(ctx, afterID, searchRebuildBatch)
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int, len(profiles))
+		for i := range profiles {
+			ids[i] = profiles[i].ID
+		}
+		terms, err := s.skillTerms(ctx, s.repo.SkillTag.ListByTalents, ids)
+		if err != nil {
+			return nil, err
+		}
+		docs := make([]search.Document, len(profiles))
+		for i := range profiles {
+			docs[i] = talentDocument(&profiles[i], terms[profiles[i].ID])
+		}
+		return docs, nil
+	})
+	if err != nil {
+		log.Printf("[SearchService.Rebuild] error rebuilding talent profiles: %v", err)
+		return nil, ErrInternal("重建搜索索引失败")
+	}
+	return result, nil
+}
+
+// rebuild 分批读取文档并写入索引，返回处理的文档数
+func (s *SearchService) rebuild(ctx context.Context, list func(ctx context.Context, afterID int) ([]search.Document, error)) (int, error) {
+	count, afterID := 0, 0
+	for {
+		docs, err := list(ctx, afterID)
+		if err != nil {
+			return count, err
+		}
+		for _, doc := range docs {
+			if err := s.index.Upsert(ctx, doc); err != nil {
+				return count, err
+			}
+			afterID = doc.RefID
+			count++
+		}
+		if len(docs) < searchRebuildBatch {
+			return count, nil
+		}
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
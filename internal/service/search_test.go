@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/search"
+)
+
+type MockSearchDocumentRepo struct {
+	repository.SearchDocumentRepo
+	mock.Mock
+}
+
+func (m *MockSearchDocumentRepo) ListProjectSources(ctx context.Context, afterID, limit int) ([]models.Project, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]models.Project), args.Error(1)
+}
+
+func (m *MockSearchDocumentRepo) ListTalentSources(ctx context.Context, afterID, limit int) ([]models.TalentProfile, error) {
+	args := m.Called(ctx, afterID, limit)
+	return args.Get(0).([]models.TalentProfile), args.Error(1)
+}
+
+// newUntaggedRepo returns a repository whose projects and talent profiles have no skill tags.
+func newUntaggedRepo() *repository.Repository {
+	mockTags := new(MockSkillTagRepo)
+	mockTags.On("ListByProjects", mock.Anything, mock.Anything).Return(map[int][]models.SkillTag{}, nil)
+	mockTags.On("ListByTalents", mock.Anything, mock.Anything).Return(map[int][]models.SkillTag{}, nil)
+	mockTags.On("ListSynonyms", mock.Anything, mock.Anything).Return(map[int][]string{}, nil)
+	return &repository.Repository{SkillTag: mockTags}
+}
+
+func newSearchTestService(t *testing.T, projects ...*models.Project) *SearchService {
+	svc := NewSearchService(search.NewMemoryIndex(), newUntaggedRepo())
+	for _, p := range projects {
+		svc.IndexProject(context.Background(), p)
+	}
+	return svc
+}
+
+func TestListProjects_SearchRanksAndHighlights(t *testing.T) {
+	svc := newSearchTestService(t,
+		&models.Project{ID: 1, Name: "考研互助小组", Description: strPtr("也需要会做小程序的同学")},
+		&models.Project{ID: 2, Name: "校园小程序开发", Description: strPtr("招募前端")},
+		&models.Project{ID: 3, Name: "数学建模", Description: strPtr("Python")},
+	)
+	mockProject := new(MockProjectRepo)
+	mockProject.On("List", mock.Anything, mock.MatchedBy(func(p repository.ListParams) bool {
+		return assert.ObjectsAreEqual([]int{2, 1}, p.IDs) && p.Keyword == nil && *p.SchoolID == 7
+	})).Return([]models.Project{
+		{ID: 2, Name: "校园小程序开发", Description: strPtr("招募前端")},
+		{ID: 1, Name: "考研互助小组", Description: strPtr("也需要会做小程序的同学")},
+	}, int64(2), nil)
+
+	repo := &repository.Repository{Project: mockProject}
+	result, err := NewProjectService(repo, nil, nil, svc, nil).ListProjects(context.Background(), repository.ListParams{
+		Keyword:  strPtr("小程序"),
+		SchoolID: intPtr(7),
+	})
+
+	require.NoError(t, err)
+	mockProject.AssertExpectations(t)
+	require.Len(t, result.List, 2)
+	assert.Equal(t, "校园<em>小程序</em>开发", result.List[0].Highlights["name"])
+	assert.Equal(t, "也需要会做<em>小程序</em>的同学", result.List[1].Highlights["description"])
+	assert.NotContains(t, result.List[1].Highlights, "name")
+}
+
+func TestListProjects_WithoutQueryListsAsUsual(t *testing.T) {
+	mockProject := new(MockProjectRepo)
+	mockProject.On("List", mock.Anything, mock.MatchedBy(func(p repository.ListParams) bool {
+		return p.IDs == nil
+	})).Return([]models.Project{{ID: 1}}, int64(1), nil)
+
+	repo := &repository.Repository{Project: mockProject}
+	result, err := NewProjectService(repo, nil, nil, newSearchTestService(t), nil).ListProjects(context.Background(), repository.ListParams{
+		Keyword: strPtr("  "),
+	})
+
+	require.NoError(t, err)
+	assert.Len(t, result.List, 1)
+	assert.Nil(t, result.List[0].Highlights)
+}
+
+func TestSearchService_MatchReportsTruncation(t *testing.T) {
+	svc := newSearchTestService(t,
+		&models.Project{ID: 1, Name: "校园小程序开发"},
+		&models.Project{ID: 2, Name: "小程序商城"},
+	)
+
+	svc.maxHits = 2
+	matches, err := svc.Match(context.Background(), models.SearchDocProject, strPtr("小程序"), nil)
+	require.NoError(t, err)
+	assert.Len(t, matches.IDs, 2)
+	assert.False(t, matches.Truncated)
+
+	svc.maxHits = 1
+	matches, err = svc.Match(context.Background(), models.SearchDocProject, strPtr("小程序"), nil)
+	require.NoError(t, err)
+	assert.Len(t, matches.IDs, 1)
+	assert.True(t, matches.Truncated)
+}
+
+func TestSearchService_RebuildIndexesMissedDocuments(t *testing.T) {
+	mockSources := new(MockSearchDocumentRepo)
+	mockSources.On("ListProjectSources", mock.Anything, 0, searchRebuildBatch).
+		Return([]models.Project{{ID: 3, Name: "校园小程序开发"}}, nil)
+	mockSources.On("ListTalentSources", mock.Anything, 0, searchRebuildBatch).
+		Return([]models.TalentProfile{{ID: 5, Nickname: strPtr("小王"), SkillSummary: strPtr(`["React"]`)}}, nil)
+	repo := newUntaggedRepo()
+	repo.SearchDocument = mockSources
+	svc := NewSearchService(search.NewMemoryIndex(), repo)
+
+	result, err := svc.Rebuild(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, &SearchRebuildResult{Projects: 1, Talents: 1}, result)
+	matches, err := svc.Match(context.Background(), models.SearchDocProject, strPtr("小程序"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, matches.IDs)
+	matches, err = svc.Match(context.Background(), models.SearchDocTalent, nil, strPtr("react"))
+	require.NoError(t, err)
+	assert.Equal(t, []int{5}, matches.IDs)
+}
+
+func TestSearchService_IndexesTagNamesAndSynonyms(t *testing.T) {
+	mockTags := new(MockSkillTagRepo)
+	mockTags.On("ListByProjects", mock.Anything, []int{1}).Return(map[int][]models.SkillTag{1: {{ID: 1, Name: "Go"}}}, nil)
+	mockTags.On("ListSynonyms", mock.Anything, []int{1}).Return(map[int][]string{1: {"Golang"}}, nil)
+	svc := NewSearchService(search.NewMemoryIndex(), &repository.Repository{SkillTag: mockTags})
+
+	svc.IndexProject(context.Background(), &models.Project{ID: 1, Name: "后端开发", SkillRequirement: strPtr("熟悉 Go")})
+	matches, err := svc.Match(context.Background(), models.SearchDocProject, nil, strPtr("golang"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, matches.IDs)
+}
+
+func TestSearchService_RemoveProject(t *testing.T) {
+	svc := newSearchTestService(t, &models.Project{ID: 1, Name: "校园小程序开发"})
+
+	svc.RemoveProject(context.Background(), 1)
+	matches, err := svc.Match(context.Background(), models.SearchDocProject, strPtr("小程序"), nil)
+
+	require.NoError(t, err)
+	assert.Empty(t, matches.IDs)
+}
+
+func TestSearchService_RemoveTalent(t *testing.T) {
+	svc := newSearchTestService(t)
+	svc.IndexTalent(context.Background(), &models.TalentProfile{ID: 5, Nickname: strPtr("小王"), SkillSummary: strPtr(`["React"]`)})
+
+	svc.RemoveTalent(context.Background(), 5)
+	matches, err := svc.Match(context.Background(), models.SearchDocTalent, nil, strPtr("react"))
+
+	require.NoError(t, err)
+	assert.Empty(t, matches.IDs)
+}
+
+func TestSearchService_MatchTalentBySkill(t *testing.T) {
+	svc := newSearchTestService(t)
+	svc.IndexTalent(context.Background(), &models.TalentProfile{ID: 5, Nickname: strPtr("小王"), SkillSummary: strPtr(`["React","Go"]`)})
+	svc.IndexTalent(context.Background(), &models.TalentProfile{ID: 6, Nickname: strPtr("小李"), SkillSummary: strPtr(`["Python"]`)})
+
+	matches, err := svc.Match(context.Background(), models.SearchDocTalent, nil, strPtr("react"))
+	require.NoError(t, err)
+	assert.Equal(t, []int{5}, matches.IDs)
+
+	p := &models.TalentProfile{ID: 5, SkillSummary: strPtr(`["React","Go"]`)}
+	matches.HighlightTalent(p)
+	assert.Equal(t, "<em>React</em>、Go", p.Highlights["skills"])
+}
@@ -3,41 +3,67 @@ package service
 import (
 	"github.com/trv3wood/kuaizu-server/internal/oss"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
+	"github.com/trv3wood/kuaizu-server/internal/search"
 )
 
 // Services aggregates all service instances.
 type Services struct {
 	Auth             *AuthService
 	EmailPromotion   *EmailPromotionService
+	EmailTemplate    *EmailTemplateService
 	Payment          *PaymentService
 	EmailUnsubscribe *EmailUnsubscribeService
 	Order            *OrderService
+	Refund           *RefundService
 	OliveBranch      *OliveBranchService
 	Commons          *CommonsService
 	ContentAudit     *ContentAuditService
+	ImageAudit       *ImageAuditService
 	Project          *ProjectService
+	ProjectMember    *ProjectMemberService
 	Message          *MessageService
+	Notification     *NotificationService
 	User             *UserService
 	Feedback         *FeedbackService
+	Search           *SearchService
+	Recommend        *RecommendService
+	SkillTag         *SkillTagService
+	Favorite         *FavoriteService
+	TalentProfile    *TalentProfileService
 }
 
 // New creates a new Services instance with all sub-services.
-func New(repo *repository.Repository, ossClient *oss.Client) *Services {
-	contentAudit := NewContentAuditService()
+func New(repo *repository.Repository, ossClient *oss.Client, events EventPublisher) *Services {
+	contentAudit := NewContentAuditService(repo)
 	message := NewMessageService(repo)
+	imageAudit := NewImageAuditService(repo, ossClient)
+	commons := NewCommonsService(ossClient, repo.User, imageAudit)
+	searchSvc := NewSearchService(search.NewIndexFromEnv(repo.SearchDocument), repo)
+	skillTag := NewSkillTagService(repo)
+	favorite := NewFavoriteService(repo, events)
 	return &Services{
 		Auth:             NewAuthService(repo),
 		EmailPromotion:   NewEmailPromotionService(repo),
+		EmailTemplate:    NewEmailTemplateService(repo),
 		Payment:          NewPaymentService(repo),
 		EmailUnsubscribe: NewEmailUnsubscribeService(repo),
 		Order:            NewOrderService(repo),
-		OliveBranch:      NewOliveBranchService(repo),
-		Commons:          NewCommonsService(ossClient, repo.User),
+		Refund:           NewRefundService(repo),
+		OliveBranch:      NewOliveBranchService(repo, events),
+		Commons:          commons,
 		ContentAudit:     contentAudit,
-		Project:          NewProjectService(repo, contentAudit, message),
+		ImageAudit:       imageAudit,
+		Project:          NewProjectService(repo, contentAudit, events, searchSvc, skillTag),
+		ProjectMember:    NewProjectMemberService(repo, events),
 		Message:          message,
-		User:             NewUserService(repo, message),
-		Feedback:         NewFeedbackService(repo, message),
+		Notification:     NewNotificationService(repo),
+		User:             NewUserService(repo, events),
+		Feedback:         NewFeedbackService(repo, commons, events),
+		Search:           searchSvc,
+		Recommend:        NewRecommendService(repo),
+		SkillTag:         skillTag,
+		Favorite:         favorite,
+		TalentProfile:    NewTalentProfileService(repo, searchSvc, skillTag, favorite),
 	}
 }
 
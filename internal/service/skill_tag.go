@@ -0,0 +1,527 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+const (
+	skillSuggestDefaultSize = 10
+	skillSuggestMaxSize     = 50
+	skillNameMaxRunes       = 30
+	skillSynonymMaxCount    = 20
+	skillRelinkBatch        = 200 // 重建关联时每批读取的项目或档案数
+)
+
+// SkillTagService manages the skill tag dictionary and links projects and
+// talent profiles to the tags found in their free skill text.
+type SkillTagService struct {
+	repo *repository.Repository
+}
+
+// NewSkillTagService creates a new SkillTagService.
+func NewSkillTagService(repo *repository.Repository) *SkillTagService {
+	return &SkillTagService{repo: repo}
+}
+
+// extractSkillTags 返回文本中出现的标签ID，按首次出现的位置排序。匹配不区分大小写；
+// 词条首尾是字母或数字时，要求相邻字符不是字母或数字，避免 "Go" 命中 "Google"。
+func extractSkillTags(text string, terms []models.SkillTagTerm) []int {
+	text = strings.ToLower(text)
+	first := make(map[int]int) // 标签ID -> 首次出现位置
+	for _, t := range terms {
+		term := strings.ToLower(strings.TrimSpace(t.Term))
+		if term == "" {
+			continue
+		}
+		if pos := indexSkillTerm(text, term); pos >= 0 {
+			if p, ok := first[t.TagID]; !ok || pos < p {
+				first[t.TagID] = pos
+			}
+		}
+	}
+
+	ids := make([]int, 0, len(first))
+	for id := range first {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if first[ids[i]] != first[ids[j]] {
+			return first[ids[i]] < first[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// indexSkillTerm 返回 term 在 text 中第一个满足边界要求的位置，未出现时返回 -1
+func indexSkillTerm(text, term string) int {
+	head, _ := utf8.DecodeRuneInString(term)
+	tail, _ := utf8.DecodeLastRuneInString(term)
+	for offset := 0; offset < len(text); {
+		i := strings.Index(text[offset:], term)
+		if i < 0 {
+			return -1
+		}
+		start, end := offset+i, offset+i+len(term)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if (!isASCIIWord(head) || !isASCIIWord(before)) && (!isASCIIWord(tail) || !isASCIIWord(after)) {
+			return start
+		}
+		_, size := utf8.DecodeRuneInString(text[start:])
+		offset = start + size
+	}
+	return -1
+}
+
+func isASCIIWord(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
+
+// projectSkillText 项目中用于识别技能标签的文本
+func projectSkillText(p *models.Project) string {
+	return derefString(p.SkillRequirement)
+}
+
+// talentSkillText 人才档案中用于识别技能标签的文本
+func talentSkillText(p *models.TalentProfile) string {
+	return strings.Join(p.Skills(), "\n")
+}
+
+// Suggest returns active tags matching the keyword for autocomplete.
+func (s *SkillTagService) Suggest(ctx context.Context, keyword string, categoryID *int, size int) ([]models.SkillTag, error) {
+	if size < 1 {
+		size = skillSuggestDefaultSize
+	}
+	tags, err := s.repo.SkillTag.Suggest(ctx, repository.SkillTagSuggestParams{
+		Keyword:    strings.TrimSpace(keyword),
+		CategoryID: categoryID,
+		Limit:      min(size, skillSuggestMaxSize),
+	})
+	if err != nil {
+		log.Printf("[SkillTagService.Suggest] repository error: %v", err)
+		return nil, ErrInternal("获取技能标签失败")
+	}
+	return tags, nil
+}
+
+// ListCategoryTree returns the categories that have active tags, each with its tags.
+func (s *SkillTagService) ListCategoryTree(ctx context.Context) ([]models.SkillCategory, error) {
+	categories, err := s.repo.SkillTag.ListCategories(ctx)
+	if err != nil {
+		log.Printf("[SkillTagService.ListCategoryTree] repository error listing categories: %v", err)
+		return nil, ErrInternal("获取技能分类失败")
+	}
+	tags, err := s.repo.SkillTag.ListActive(ctx)
+	if err != nil {
+		log.Printf("[SkillTagService.ListCategoryTree] repository error listing tags: %v", err)
+		return nil, ErrInternal("获取技能分类失败")
+	}
+
+	byCategory := make(map[int][]models.SkillTag)
+	for _, t := range tags {
+		byCategory[t.CategoryID] = append(byCategory[t.CategoryID], t)
+	}
+	result := []models.SkillCategory{}
+	for _, c := range categories {
+		if c.Tags = byCategory[c.ID]; len(c.Tags) > 0 {
+			result = append(result, c)
+		}
+	}
+	return result, nil
+}
+
+// LinkProject re-extracts the skill tags of a project from its skill
+// requirement. Linking is best effort: failures are logged and never fail
+// the write that triggered them.
+func (s *SkillTagService) LinkProject(ctx context.Context, p *models.Project) {
+	if s == nil || p == nil {
+		return
+	}
+	terms, err := s.repo.SkillTag.ListTerms(ctx)
+	if err != nil {
+		log.Printf("[SkillTagService.LinkProject] repository error listing terms: %v", err)
+		return
+	}
+	if err := s.repo.SkillTag.SetProjectTags(ctx, p.ID, extractSkillTags(projectSkillText(p), terms)); err != nil {
+		log.Printf("[SkillTagService.LinkProject] repository error for project %d: %v", p.ID, err)
+	}
+}
+
+// LinkTalent re-extracts the skill tags of a talent profile from its skills.
+// Linking is best effort like LinkProject.
+func (s *SkillTagService) LinkTalent(ctx context.Context, p *models.TalentProfile) {
+	if s == nil || p == nil {
+		return
+	}
+	terms, err := s.repo.SkillTag.ListTerms(ctx)
+	if err != nil {
+		log.Printf("[SkillTagService.LinkTalent] repository error listing terms: %v", err)
+		return
+	}
+	if err := s.repo.SkillTag.SetTalentTags(ctx, p.ID, extractSkillTags(talentSkillText(p), terms)); err != nil {
+		log.Printf("[SkillTagService.LinkTalent] repository error for talent profile %d: %v", p.ID, err)
+	}
+}
+
+// AttachProjects fills the linked skill tags of the projects. Failures are
+// logged and leave the tags unset.
+func (s *SkillTagService) AttachProjects(ctx context.Context, projects []models.Project) {
+	ids := make([]int, len(projects))
+	for i := range projects {
+		ids[i] = projects[i].ID
+	}
+	tags, err := s.repo.SkillTag.ListByProjects(ctx, ids)
+	if err != nil {
+		log.Printf("[SkillTagService.AttachProjects] repository error: %v", err)
+		return
+	}
+	for i := range projects {
+		projects[i].SkillTags = append([]models.SkillTag{}, tags[projects[i].ID]...)
+	}
+}
+
+// AttachTalents fills the linked skill tags of the talent profiles. Failures
+// are logged and leave the tags unset.
+func (s *SkillTagService) AttachTalents(ctx context.Context, profiles []models.TalentProfile) {
+	ids := make([]int, len(profiles))
+	for i := range profiles {
+		ids[i] = profiles[i].ID
+	}
+	tags, err := s.repo.SkillTag.ListByTalents(ctx, ids)
+	if err != nil {
+		log.Printf("[SkillTagService.AttachTalents] repository error: %v", err)
+		return
+	}
+	for i := range profiles {
+		profiles[i].SkillTags = append([]models.SkillTag{}, tags[profiles[i].ID]...)
+	}
+}
+
+// SkillCategoryInput contains the editable fields of a skill category.
+type SkillCategoryInput struct {
+	Name      string
+	SortOrder int
+}
+
+// ListCategories returns all skill categories for curation.
+func (s *SkillTagService) ListCategories(ctx context.Context) ([]models.SkillCategory, error) {
+	categories, err := s.repo.SkillTag.ListCategories(ctx)
+	if err != nil {
+		log.Printf("[SkillTagService.ListCategories] repository error: %v", err)
+		return nil, ErrInternal("获取技能分类失败")
+	}
+	return categories, nil
+}
+
+// CreateCategory validates and creates a skill category.
+func (s *SkillTagService) CreateCategory(ctx context.Context, input SkillCategoryInput) (*models.SkillCategory, error) {
+	c := &models.SkillCategory{}
+	if err := s.applyCategoryInput(ctx, c, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SkillTag.CreateCategory(ctx, c); err != nil {
+		log.Printf("[SkillTagService.CreateCategory] repository error: %v", err)
+		return nil, ErrInternal("创建技能分类失败")
+	}
+	return s.getCategory(ctx, c.ID)
+}
+
+// UpdateCategory validates and updates a skill category.
+func (s *SkillTagService) UpdateCategory(ctx context.Context, id int, input SkillCategoryInput) (*models.SkillCategory, error) {
+	c, err := s.getCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyCategoryInput(ctx, c, input); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SkillTag.UpdateCategory(ctx, c); err != nil {
+		log.Printf("[SkillTagService.UpdateCategory] repository error: %v", err)
+		return nil, ErrInternal("更新技能分类失败")
+	}
+	return s.getCategory(ctx, id)
+}
+
+func (s *SkillTagService) getCategory(ctx context.Context, id int) (*models.SkillCategory, error) {
+	c, err := s.repo.SkillTag.GetCategoryByID(ctx, id)
+	if err != nil {
+		log.Printf("[SkillTagService.getCategory] repository error: %v", err)
+		return nil, ErrInternal("获取技能分类失败")
+	}
+	if c == nil {
+		return nil, ErrNotFound("技能分类不存在")
+	}
+	return c, nil
+}
+
+func (s *SkillTagService) applyCategoryInput(ctx context.Context, c *models.SkillCategory, input SkillCategoryInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > skillNameMaxRunes {
+		return ErrBadRequest(fmt.Sprintf("分类名称不能为空且不超过%d个字", skillNameMaxRunes))
+	}
+	existing, err := s.repo.SkillTag.GetCategoryByName(ctx, name)
+	if err != nil {
+		log.Printf("[SkillTagService.applyCategoryInput] repository error: %v", err)
+		return ErrInternal("保存技能分类失败")
+	}
+	if existing != nil && existing.ID != c.ID {
+		return ErrBadRequest("分类名称已存在")
+	}
+
+	c.Name = name
+	c.SortOrder = input.SortOrder
+	return nil
+}
+
+// SkillTagListResult holds a page of skill tags with pagination info.
+type SkillTagListResult struct {
+	List  []models.SkillTag
+	Total int64
+	Page  int
+	Size  int
+}
+
+// SkillTagInput contains the editable fields of a skill tag. Synonyms
+// replace the existing ones.
+type SkillTagInput struct {
+	CategoryID int
+	Name       string
+	Status     *int
+	Synonyms   []string
+}
+
+// ListTags returns a paginated list of skill tags with synonyms and usage counts.
+func (s *SkillTagService) ListTags(ctx context.Context, params repository.SkillTagListParams) (*SkillTagListResult, error) {
+	params.Page, params.Size = normalizePageParams(params.Page, params.Size)
+
+	tags, total, err := s.repo.SkillTag.List(ctx, params)
+	if err != nil {
+		log.Printf("[SkillTagService.ListTags] repository error: %v", err)
+		return nil, ErrInternal("获取技能标签列表失败")
+	}
+	if err := s.fillSynonyms(ctx, tags); err != nil {
+		return nil, err
+	}
+
+	return &SkillTagListResult{List: tags, Total: total, Page: params.Page, Size: params.Size}, nil
+}
+
+// GetTag retrieves a skill tag with its synonyms.
+func (s *SkillTagService) GetTag(ctx context.Context, id int) (*models.SkillTag, error) {
+	t, err := s.repo.SkillTag.GetByID(ctx, id)
+	if err != nil {
+		log.Printf("[SkillTagService.GetTag] repository error: %v", err)
+		return nil, ErrInternal("获取技能标签失败")
+	}
+	if t == nil {
+		return nil, ErrNotFound("技能标签不存在")
+	}
+	tags := []models.SkillTag{*t}
+	if err := s.fillSynonyms(ctx, tags); err != nil {
+		return nil, err
+	}
+	return &tags[0], nil
+}
+
+func (s *SkillTagService) fillSynonyms(ctx context.Context, tags []models.SkillTag) error {
+	ids := make([]int, len(tags))
+	for i := range tags {
+		ids[i] = tags[i].ID
+	}
+	synonyms, err := s.repo.SkillTag.ListSynonyms(ctx, ids)
+	if err != nil {
+		log.Printf("[SkillTagService.fillSynonyms] repository error: %v", err)
+		return ErrInternal("获取技能标签同义词失败")
+	}
+	for i := range tags {
+		tags[i].Synonyms = append([]string{}, synonyms[tags[i].ID]...)
+	}
+	return nil
+}
+
+// CreateTag validates and creates a skill tag with its synonyms.
+func (s *SkillTagService) CreateTag(ctx context.Context, input SkillTagInput) (*models.SkillTag, error) {
+	t := &models.SkillTag{Status: models.SkillTagStatusActive}
+	synonyms, err := s.applyTagInput(ctx, t, input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = runInTx(ctx, s.repo, "SkillTagService.CreateTag", "创建技能标签失败", func(tx *sqlx.Tx) error {
+		if err := s.repo.SkillTag.CreateTx(ctx, tx, t); err != nil {
+			return err
+		}
+		return s.repo.SkillTag.ReplaceSynonymsTx(ctx, tx, t.ID, synonyms)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTag(ctx, t.ID)
+}
+
+// UpdateTag validates and updates a skill tag and replaces its synonyms.
+// Existing links are kept; run Relink to apply changed names or synonyms to them.
+func (s *SkillTagService) UpdateTag(ctx context.Context, id int, input SkillTagInput) (*models.SkillTag, error) {
+	t, err := s.GetTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	synonyms, err := s.applyTagInput(ctx, t, input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = runInTx(ctx, s.repo, "SkillTagService.UpdateTag", "更新技能标签失败", func(tx *sqlx.Tx) error {
+		if err := s.repo.SkillTag.UpdateTx(ctx, tx, t); err != nil {
+			return err
+		}
+		return s.repo.SkillTag.ReplaceSynonymsTx(ctx, tx, t.ID, synonyms)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTag(ctx, id)
+}
+
+// applyTagInput validates the input, copies it into t and returns the
+// normalized synonyms. Names and synonyms must not identify another tag.
+func (s *SkillTagService) applyTagInput(ctx context.Context, t *models.SkillTag, input SkillTagInput) ([]string, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > skillNameMaxRunes {
+		return nil, ErrBadRequest(fmt.Sprintf("标签名称不能为空且不超过%d个字", skillNameMaxRunes))
+	}
+	if input.Status != nil {
+		if err := IsValidStatus("skill_tag.status", *input.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := map[string]bool{strings.ToLower(name): true}
+	synonyms := []string{}
+	for _, syn := range input.Synonyms {
+		syn = strings.TrimSpace(syn)
+		if syn == "" || seen[strings.ToLower(syn)] {
+			continue
+		}
+		if utf8.RuneCountInString(syn) > skillNameMaxRunes {
+			return nil, ErrBadRequest(fmt.Sprintf("同义词不超过%d个字", skillNameMaxRunes))
+		}
+		seen[strings.ToLower(syn)] = true
+		synonyms = append(synonyms, syn)
+	}
+	if len(synonyms) > skillSynonymMaxCount {
+		return nil, ErrBadRequest(fmt.Sprintf("同义词最多%d个", skillSynonymMaxCount))
+	}
+
+	if _, err := s.getCategory(ctx, input.CategoryID); err != nil {
+		return nil, err
+	}
+	for _, term := range append([]string{name}, synonyms...) {
+		existing, err := s.repo.SkillTag.FindByTerm(ctx, term)
+		if err != nil {
+			log.Printf("[SkillTagService.applyTagInput] repository error: %v", err)
+			return nil, ErrInternal("保存技能标签失败")
+		}
+		if existing != nil && existing.ID != t.ID {
+			return nil, ErrBadRequest(fmt.Sprintf("「%s」已被标签「%s」使用", term, existing.Name))
+		}
+	}
+
+	t.CategoryID = input.CategoryID
+	t.Name = name
+	if input.Status != nil {
+		t.Status = *input.Status
+	}
+	return synonyms, nil
+}
+
+// MergeTags merges a duplicate tag into target: links and synonyms move to
+// target, and the source name becomes a synonym of target.
+func (s *SkillTagService) MergeTags(ctx context.Context, sourceID, targetID int) (*models.SkillTag, error) {
+	if sourceID == targetID {
+		return nil, ErrBadRequest("不能合并到自身")
+	}
+	source, err := s.GetTag(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetTag(ctx, targetID); err != nil {
+		return nil, err
+	}
+
+	err = runInTx(ctx, s.repo, "SkillTagService.MergeTags", "合并技能标签失败", func(tx *sqlx.Tx) error {
+		return s.repo.SkillTag.MergeTx(ctx, tx, source, targetID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTag(ctx, targetID)
+}
+
+// SkillRelinkResult counts the projects and talent profiles whose tags were re-extracted.
+type SkillRelinkResult struct {
+	Projects int
+	Talents  int
+}
+
+// Relink re-extracts the skill tags of every project and talent profile from
+// their free text with the current dictionary.
+func (s *SkillTagService) Relink(ctx context.Context) (*SkillRelinkResult, error) {
+	terms, err := s.repo.SkillTag.ListTerms(ctx)
+	if err != nil {
+		log.Printf("[SkillTagService.Relink] repository error listing terms: %v", err)
+		return nil, ErrInternal("重建技能标签关联失败")
+	}
+
+	result := &SkillRelinkResult{}
+	result.Projects, err = s.relink(ctx, terms, s.repo.SkillTag.ListProjectTexts, s.repo.SkillTag.SetProjectTags,
+		func(text *string) string { return projectSkillText(&models.Project{SkillRequirement: text}) })
+	if err != nil {
+		log.Printf("[SkillTagService.Relink] repository error relinking projects: %v", err)
+		return nil, ErrInternal("重建技能标签关联失败")
+	}
+	result.Talents, err = s.relink(ctx, terms, s.repo.SkillTag.ListTalentTexts, s.repo.SkillTag.SetTalentTags,
+		func(text *string) string { return talentSkillText(&models.TalentProfile{SkillSummary: text}) })
+	if err != nil {
+		log.Printf("[SkillTagService.Relink] repository error relinking talent profiles: %v", err)
+		return nil, ErrInternal("重建技能标签关联失败")
+	}
+	return result, nil
+}
+
+// relink 分批读取文本并重写关联，返回处理的记录数
+func (s *SkillTagService) relink(
+	ctx context.Context,
+	terms []models.SkillTagTerm,
+	list func(ctx context.Context, afterID, limit int) ([]models.SkillText, error),
+	set func(ctx context.Context, refID int, tagIDs []int) error,
+	skillText func(text *string) string,
+) (int, error) {
+	count, afterID := 0, 0
+	for {
+		texts, err := list(ctx, afterID, skillRelinkBatch)
+		if err != nil {
+			return count, err
+		}
+		for _, t := range texts {
+			if err := set(ctx, t.RefID, extractSkillTags(skillText(t.Text), terms)); err != nil {
+				return count, err
+			}
+			afterID = t.RefID
+			count++
+		}
+		if len(texts) < skillRelinkBatch {
+			return count, nil
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+type MockSkillTagRepo struct {
+	repository.SkillTagRepo
+	mock.Mock
+}
+
+func (m *MockSkillTagRepo) GetCategoryByID(ctx context.Context, id int) (*models.SkillCategory, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SkillCategory), args.Error(1)
+}
+
+func (m *MockSkillTagRepo) GetByID(ctx context.Context, id int) (*models.SkillTag, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SkillTag), args.Error(1)
+}
+
+func (m *MockSkillTagRepo) FindByTerm(ctx context.Context, term string) (*models.SkillTag, error) {
+	args := m.Called(ctx, term)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SkillTag), args.Error(1)
+}
+
+func (m *MockSkillTagRepo) CreateTx(ctx context.Context, tx *sqlx.Tx, t *models.SkillTag) error {
+	args := m.Called(ctx, tx, t)
+	t.ID = 9
+	return args.Error(0)
+}
+
+func (m *MockSkillTagRepo) ReplaceSynonymsTx(ctx context.Context, tx *sqlx.Tx, tagID int, synonyms []string) error {
+	args := m.Called(ctx, tx, tagID, synonyms)
+	return args.Error(0)
+}
+
+func (m *MockSkillTagRepo) ListSynonyms(ctx context.Context, tagIDs []int) (map[int][]string, error) {
+	args := m.Called(ctx, tagIDs)
+	return args.Get(0).(map[int][]string), args.Error(1)
+}
+
+func (m *MockSkillTagRepo) ListByProjects(ctx context.Context, projectIDs []int) (map[int][]models.SkillTag, error) {
+	args := m.Called(ctx, projectIDs)
+	return args.Get(0).(map[int][]models.SkillTag), args.Error(1)
+}
+
+func (m *MockSkillTagRepo) ListByTalents(ctx context.Context, talentProfileIDs []int) (map[int][]models.SkillTag, error) {
+	args := m.Called(ctx, talentProfileIDs)
+	return args.Get(0).(map[int][]models.SkillTag), args.Error(1)
+}
+
+func (m *MockSkillTagRepo) ListTerms(ctx context.Context) ([]models.SkillTagTerm, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.SkillTagTerm), args.Error(1)
+}
+
+func (m *MockSkillTagRepo) SetTalentTags(ctx context.Context, talentProfileID int, tagIDs []int) error {
+	args := m.Called(ctx, talentProfileID, tagIDs)
+	return args.Error(0)
+}
+
+func (m *MockSkillTagRepo) MergeTx(ctx context.Context, tx *sqlx.Tx, source *models.SkillTag, targetID int) error {
+	args := m.Called(ctx, tx, source, targetID)
+	return args.Error(0)
+}
+
+var testSkillTerms = []models.SkillTagTerm{
+	{TagID: 1, Term: "Go"},
+	{TagID: 1, Term: "Golang"},
+	{TagID: 2, Term: "Java"},
+	{TagID: 3, Term: "JavaScript"},
+	{TagID: 3, Term: "JS"},
+	{TagID: 4, Term: "C++"},
+	{TagID: 5, Term: "前端"},
+	{TagID: 6, Term: "Vue"},
+}
+
+func TestExtractSkillTags(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []int
+	}{
+		{"synonym and case-insensitive", "熟悉golang和vue", []int{1, 6}},
+		{"ordered by first occurrence", "JavaScript、Java、C++", []int{3, 2, 4}},
+		{"ascii term inside a word", "Google 工程师，会 Django", []int{}},
+		{"cjk term next to ascii", "web前端，Vue3", []int{5}},
+		{"tag counted once", "Go / Golang / go", []int{1}},
+		{"empty text", "", []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractSkillTags(tt.text, testSkillTerms))
+		})
+	}
+}
+
+func TestLinkTalent_ExtractsFromSkills(t *testing.T) {
+	mockTags := new(MockSkillTagRepo)
+	mockTags.On("ListTerms", mock.Anything).Return(testSkillTerms, nil)
+	mockTags.On("SetTalentTags", mock.Anything, 7, []int{6, 1}).Return(nil)
+
+	summary := `["Vue","后端 Go"]`
+	repo := &repository.Repository{SkillTag: mockTags}
+	NewSkillTagService(repo).LinkTalent(context.Background(), &models.TalentProfile{ID: 7, SkillSummary: &summary})
+
+	mockTags.AssertExpectations(t)
+}
+
+func TestCreateTag_SynonymUsedByAnotherTag(t *testing.T) {
+	mockTags := new(MockSkillTagRepo)
+	mockTags.On("GetCategoryByID", mock.Anything, 1).Return(&models.SkillCategory{ID: 1, Name: "编程语言"}, nil)
+	mockTags.On("FindByTerm", mock.Anything, "TypeScript").Return(nil, nil)
+	mockTags.On("FindByTerm", mock.Anything, "JS").Return(&models.SkillTag{ID: 3, Name: "JavaScript"}, nil)
+
+	repo := &repository.Repository{SkillTag: mockTags}
+	_, err := NewSkillTagService(repo).CreateTag(context.Background(), SkillTagInput{
+		CategoryID: 1,
+		Name:       "TypeScript",
+		Synonyms:   []string{"JS"},
+	})
+
+	assertServiceError(t, err, ErrCodeBadRequest, "「JS」已被标签「JavaScript」使用")
+	mockTags.AssertNotCalled(t, "CreateTx", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateTag_NormalizesSynonyms(t *testing.T) {
+	mockTags := new(MockSkillTagRepo)
+	mockTags.On("GetCategoryByID", mock.Anything, 1).Return(&models.SkillCategory{ID: 1}, nil)
+	mockTags.On("FindByTerm", mock.Anything, mock.Anything).Return(nil, nil)
+	mockTags.On("CreateTx", mock.Anything, mock.Anything, mock.MatchedBy(func(tag *models.SkillTag) bool {
+		return tag.Name == "TypeScript" && tag.CategoryID == 1 && tag.Status == models.SkillTagStatusActive
+	})).Return(nil)
+	mockTags.On("ReplaceSynonymsTx", mock.Anything, mock.Anything, 9, []string{"TS"}).Return(nil)
+	mockTags.On("GetByID", mock.Anything, 9).Return(&models.SkillTag{ID: 9, Name: "TypeScript"}, nil)
+	mockTags.On("ListSynonyms", mock.Anything, []int{9}).Return(map[int][]string{9: {"TS"}}, nil)
+
+	repo := newTxTestRepo()
+	repo.SkillTag = mockTags
+
+	tag, err := NewSkillTagService(repo).CreateTag(context.Background(), SkillTagInput{
+		CategoryID: 1,
+		Name:       " TypeScript ",
+		Synonyms:   []string{"TS", " ts ", "typescript", ""},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"TS"}, tag.Synonyms)
+	mockTags.AssertExpectations(t)
+}
+
+func TestMergeTags_IntoItself(t *testing.T) {
+	mockTags := new(MockSkillTagRepo)
+	repo := &repository.Repository{SkillTag: mockTags}
+
+	_, err := NewSkillTagService(repo).MergeTags(context.Background(), 3, 3)
+
+	assertServiceError(t, err, ErrCodeBadRequest, "不能合并到自身")
+	mockTags.AssertNotCalled(t, "MergeTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// TalentProfileService handles saving talent profiles together with their
+// skill tags, search document and favorite notifications.
+type TalentProfileService struct {
+	repo      *repository.Repository
+	search    *SearchService
+	skillTags *SkillTagService
+	favorite  *FavoriteService
+}
+
+// NewTalentProfileService creates a new TalentProfileService.
+func NewTalentProfileService(repo *repository.Repository, search *SearchService, skillTags *SkillTagService, favorite *FavoriteService) *TalentProfileService {
+	return &TalentProfileService{repo: repo, search: search, skillTags: skillTags, favorite: favorite}
+}
+
+// SaveProfile creates or replaces the user's talent profile and returns the
+// saved profile. Taking an online profile offline notifies the users who saved
+// it in the same transaction, so concurrent saves cannot notify twice.
+func (s *TalentProfileService) SaveProfile(ctx context.Context, profile *models.TalentProfile) (*models.TalentProfile, error) {
+	var notifications []*models.Notification
+	err := runInTx(ctx, s.repo, "TalentProfileService.SaveProfile", "保存人才档案失败", func(tx *sqlx.Tx) (err error) {
+		previous, err := s.repo.TalentProfile.GetByUserIDTx(ctx, tx, profile.UserID)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.TalentProfile.UpsertTx(ctx, tx, profile); err != nil {
+			return err
+		}
+		// 从上线改为下线时通知收藏者
+		if previous != nil && isTalentOnline(previous) && !isTalentOnline(profile) {
+			notifications, err = s.favorite.TalentOfflineTx(ctx, tx, previous, false)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.favorite.publish(ctx, notifications)
+
+	// Fetch the updated profile to return
+	updated, err := s.repo.TalentProfile.GetByUserID(ctx, profile.UserID)
+	if err != nil || updated == nil {
+		log.Printf("[TalentProfileService.SaveProfile] repository error reloading: %v", err)
+		return nil, ErrInternal("获取人才档案失败")
+	}
+	s.skillTags.LinkTalent(ctx, updated)
+	s.search.IndexTalent(ctx, updated)
+
+	return updated, nil
+}
+
+// DeleteProfile takes the user's talent profile offline for good and drops
+// the favorites of it in the same transaction. Favoriters are only notified
+// when the profile was still online; a hidden profile already told them.
+func (s *TalentProfileService) DeleteProfile(ctx context.Context, userID int) error {
+	var (
+		profileID     int
+		notifications []*models.Notification
+	)
+	err := runInTx(ctx, s.repo, "TalentProfileService.DeleteProfile", "删除人才档案失败", func(tx *sqlx.Tx) (err error) {
+		profile, err := s.repo.TalentProfile.GetByUserIDTx(ctx, tx, userID)
+		if err != nil || profile == nil {
+			return err
+		}
+		if err := s.repo.TalentProfile.DeleteByUserIDTx(ctx, tx, userID); err != nil {
+			return err
+		}
+		profileID = profile.ID
+		if isTalentOnline(profile) {
+			notifications, err = s.favorite.TalentOfflineTx(ctx, tx, profile, true)
+			return err
+		}
+		return s.repo.Favorite.DeleteByTargetTx(ctx, tx, models.FavoriteTargetTalent, profile.ID)
+	})
+	if err != nil {
+		return err
+	}
+	s.favorite.publish(ctx, notifications)
+	if profileID != 0 {
+		s.search.RemoveTalent(ctx, profileID)
+	}
+	return nil
+}
+
+// isTalentOnline 人才档案是否处于上线状态
+func isTalentOnline(p *models.TalentProfile) bool {
+	return p.Status != nil && *p.Status == models.TalentStatusOnline
+}
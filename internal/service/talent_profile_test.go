@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/trv3wood/kuaizu-server/internal/models"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+func (m *MockTalentProfileRepo) GetByUserIDTx(ctx context.Context, tx *sqlx.Tx, userID int) (*models.TalentProfile, error) {
+	args := m.Called(ctx, tx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.TalentProfile), args.Error(1)
+}
+
+func (m *MockTalentProfileRepo) UpsertTx(ctx context.Context, tx *sqlx.Tx, p *models.TalentProfile) error {
+	args := m.Called(ctx, tx, p)
+	return args.Error(0)
+}
+
+func (m *MockTalentProfileRepo) DeleteByUserIDTx(ctx context.Context, tx *sqlx.Tx, userID int) error {
+	args := m.Called(ctx, tx, userID)
+	return args.Error(0)
+}
+
+func TestSaveProfile_LinksTagsAndIndexes(t *testing.T) {
+	summary := `["Vue"]`
+	saved := &models.TalentProfile{ID: 7, UserID: 10, Nickname: strPtr("小王"), SkillSummary: &summary, Status: intPtr(models.TalentStatusOnline)}
+	mockProfile := new(MockTalentProfileRepo)
+	mockProfile.On("GetByUserIDTx", mock.Anything, mock.Anything, 10).Return(nil, nil)
+	mockProfile.On("UpsertTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockProfile.On("GetByUserID", mock.Anything, 10).Return(saved, nil)
+	mockTags := new(MockSkillTagRepo)
+	mockTags.On("ListTerms", mock.Anything).Return(testSkillTerms, nil)
+	mockTags.On("SetTalentTags", mock.Anything, 7, []int{6}).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.TalentProfile, repo.SkillTag = mockProfile, mockTags
+	search := newSearchTestService(t)
+	svc := NewTalentProfileService(repo, search, NewSkillTagService(repo), NewFavoriteService(repo, nil))
+
+	profile, err := svc.SaveProfile(context.Background(), &models.TalentProfile{UserID: 10, SkillSummary: &summary})
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, profile.ID)
+	mockTags.AssertExpectations(t)
+	matches, err := search.Match(context.Background(), models.SearchDocTalent, nil, strPtr("vue"))
+	require.NoError(t, err)
+	assert.Equal(t, []int{7}, matches.IDs)
+}
+
+// newOfflineTestRepo returns a transactional repository where talent profile 7
+// of user 20 is online and saved by user 10.
+func newOfflineTestRepo() (*repository.Repository, *MockTalentProfileRepo, *MockFavoriteRepo, *MockNotificationRepo) {
+	mockProfile := new(MockTalentProfileRepo)
+	mockProfile.On("GetByUserIDTx", mock.Anything, mock.Anything, 20).
+		Return(&models.TalentProfile{ID: 7, UserID: 20, Nickname: strPtr("小明"), Status: intPtr(models.TalentStatusOnline)}, nil)
+	mockFavorite := new(MockFavoriteRepo)
+	mockFavorite.On("ListUserIDs", mock.Anything, models.FavoriteTargetTalent, 7).Return([]int{10}, nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateBatchTx", mock.Anything, mock.Anything, mock.MatchedBy(func(ns []*models.Notification) bool {
+		return len(ns) == 1 && ns[0].UserID == 10 && ns[0].Type == models.NotificationTypeFavoriteOffline &&
+			ns[0].Content == "您收藏的人才「小明」已下线，不再在人才库中展示。"
+	})).Return(nil)
+	mockOutbox := new(MockMessageOutboxRepo)
+	mockOutbox.On("CreateBatchTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	repo := newTxTestRepo()
+	repo.TalentProfile = mockProfile
+	repo.Favorite = mockFavorite
+	repo.Notification = mockNotification
+	repo.MessageOutbox = mockOutbox
+	return repo, mockProfile, mockFavorite, mockNotification
+}
+
+func TestSaveProfile_NotifiesWhenTakenOffline(t *testing.T) {
+	repo, mockProfile, mockFavorite, mockNotification := newOfflineTestRepo()
+	mockProfile.On("UpsertTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockProfile.On("GetByUserID", mock.Anything, 20).Return(&models.TalentProfile{ID: 7, UserID: 20, Status: intPtr(models.TalentStatusOffline)}, nil)
+	repo.SkillTag = newUntaggedRepo().SkillTag
+	events := &stubPublisher{}
+	svc := NewTalentProfileService(repo, newSearchTestService(t), nil, NewFavoriteService(repo, events))
+
+	_, err := svc.SaveProfile(context.Background(), &models.TalentProfile{UserID: 20, Status: intPtr(models.TalentStatusOffline)})
+
+	require.NoError(t, err)
+	mockNotification.AssertExpectations(t)
+	mockFavorite.AssertNotCalled(t, "DeleteByTargetTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	require.Len(t, events.published, 1)
+}
+
+func TestSaveProfile_StaysOnlineDoesNotNotify(t *testing.T) {
+	repo, mockProfile, mockFavorite, mockNotification := newOfflineTestRepo()
+	mockProfile.On("UpsertTx", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockProfile.On("GetByUserID", mock.Anything, 20).Return(&models.TalentProfile{ID: 7, UserID: 20, Status: intPtr(models.TalentStatusOnline)}, nil)
+	events := &stubPublisher{}
+	svc := NewTalentProfileService(repo, newSearchTestService(t), nil, NewFavoriteService(repo, events))
+
+	_, err := svc.SaveProfile(context.Background(), &models.TalentProfile{UserID: 20, Status: intPtr(models.TalentStatusOnline)})
+
+	require.NoError(t, err)
+	mockFavorite.AssertNotCalled(t, "ListUserIDs", mock.Anything, mock.Anything, mock.Anything)
+	mockNotification.AssertNotCalled(t, "CreateBatchTx", mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, events.published)
+}
+
+func TestDeleteProfile_NotifiesAndDropsFavorites(t *testing.T) {
+	repo, mockProfile, mockFavorite, mockNotification := newOfflineTestRepo()
+	mockProfile.On("DeleteByUserIDTx", mock.Anything, mock.Anything, 20).Return(nil)
+	mockFavorite.On("DeleteByTargetTx", mock.Anything, mock.Anything, models.FavoriteTargetTalent, 7).Return(nil)
+	events := &stubPublisher{}
+	svc := NewTalentProfileService(repo, nil, nil, NewFavoriteService(repo, events))
+
+	err := svc.DeleteProfile(context.Background(), 20)
+
+	require.NoError(t, err)
+	mockFavorite.AssertExpectations(t)
+	mockNotification.AssertExpectations(t)
+	require.Len(t, events.published, 1)
+}
+
+func TestDeleteProfile_OfflineProfileDropsFavoritesWithoutNotice(t *testing.T) {
+	mockProfile := new(MockTalentProfileRepo)
+	mockProfile.On("GetByUserIDTx", mock.Anything, mock.Anything, 20).
+		Return(&models.TalentProfile{ID: 7, UserID: 20, Status: intPtr(models.TalentStatusOffline)}, nil)
+	mockProfile.On("DeleteByUserIDTx", mock.Anything, mock.Anything, 20).Return(nil)
+	mockFavorite := new(MockFavoriteRepo)
+	mockFavorite.On("DeleteByTargetTx", mock.Anything, mock.Anything, models.FavoriteTargetTalent, 7).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	repo := newTxTestRepo()
+	repo.TalentProfile = mockProfile
+	repo.Favorite = mockFavorite
+	repo.Notification = mockNotification
+	search := newSearchTestService(t)
+	search.IndexTalent(context.Background(), &models.TalentProfile{ID: 7, Nickname: strPtr("小明"), SkillSummary: strPtr(`["Vue"]`)})
+	events := &stubPublisher{}
+	svc := NewTalentProfileService(repo, search, nil, NewFavoriteService(repo, events))
+
+	err := svc.DeleteProfile(context.Background(), 20)
+
+	require.NoError(t, err)
+	mockFavorite.AssertExpectations(t)
+	mockFavorite.AssertNotCalled(t, "ListUserIDs", mock.Anything, mock.Anything, mock.Anything)
+	mockNotification.AssertNotCalled(t, "CreateBatchTx", mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, events.published)
+	matches, err := search.Match(context.Background(), models.SearchDocTalent, nil, strPtr("vue"))
+	require.NoError(t, err)
+	assert.Empty(t, matches.IDs)
+}
+
+func TestDeleteProfile_KeepsFavoritesWhenNoticeFails(t *testing.T) {
+	repo, mockProfile, mockFavorite, _ := newOfflineTestRepo()
+	mockProfile.On("DeleteByUserIDTx", mock.Anything, mock.Anything, 20).Return(nil)
+	mockNotification := new(MockNotificationRepo)
+	mockNotification.On("CreateBatchTx", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db down"))
+	repo.Notification = mockNotification
+	events := &stubPublisher{}
+	svc := NewTalentProfileService(repo, nil, nil, NewFavoriteService(repo, events))
+
+	err := svc.DeleteProfile(context.Background(), 20)
+
+	assertServiceError(t, err, ErrCodeInternal, "删除人才档案失败")
+	mockFavorite.AssertNotCalled(t, "DeleteByTargetTx", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	assert.Empty(t, events.published)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/trv3wood/kuaizu-server/internal/repository"
+)
+
+// runInTx runs fn in a transaction; non-business errors are logged under op and reported as failMsg.
+func runInTx(ctx context.Context, repo *repository.Repository, op, failMsg string, fn func(tx *sqlx.Tx) error) error {
+	tx, err := repo.DB().BeginTxx(ctx, nil)
+	if err != nil {
+		log.Printf("[%s] failed to begin transaction: %v", op, err)
+		return ErrInternal(failMsg)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		var svcErr *ServiceError
+		if errors.As(err, &svcErr) {
+			return err
+		}
+		log.Printf("[%s] %v", op, err)
+		return ErrInternal(failMsg)
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("[%s] failed to commit transaction: %v", op, err)
+		return ErrInternal(failMsg)
+	}
+	return nil
+}
@@ -4,19 +4,20 @@ import (
 	"context"
 	"log"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/trv3wood/kuaizu-server/internal/models"
 	"github.com/trv3wood/kuaizu-server/internal/repository"
 )
 
 // UserService handles user-related business logic.
 type UserService struct {
-	repo    *repository.Repository
-	message *MessageService
+	repo   *repository.Repository
+	events EventPublisher
 }
 
 // NewUserService creates a new UserService.
-func NewUserService(repo *repository.Repository, message *MessageService) *UserService {
-	return &UserService{repo: repo, message: message}
+func NewUserService(repo *repository.Repository, events EventPublisher) *UserService {
+	return &UserService{repo: repo, events: events}
 }
 
 // UserListResult holds a page of users with pagination info.
@@ -72,30 +73,35 @@ func (s *UserService) ReviewUserAuth(ctx context.Context, id, status int) error
 		return ErrNotFound("用户不存在")
 	}
 
-	if err := s.repo.User.UpdateAuthStatus(ctx, id, status); err != nil {
-		log.Printf("[UserService.ReviewUserAuth] repository error updating status: %v", err)
-		return ErrInternal("审核失败")
-	}
-
 	// 向用户发送认证结果通知
-	go func(asyncCtx context.Context) {
-		statusStr := "已通过"
-		remark := "恭喜！您的身份认证已通过。"
-		if status == models.UserAuthStatusFailed {
-			statusStr = "未通过"
-			remark = "很抱歉，您的身份认证未通过，请检查上传的信息是否清晰合规。"
-		}
-
-		data := map[string]string{
-			"status": statusStr,
-			"remark": remark,
-		}
+	statusStr := "已通过"
+	remark := "恭喜！您的身份认证已通过。"
+	if status == models.UserAuthStatusFailed {
+		statusStr = "未通过"
+		remark = "很抱歉，您的身份认证未通过，请检查上传的信息是否清晰合规。"
+	}
 
-		err = s.message.SendSubscribeMsgByBizKey(asyncCtx, id, models.MsgBizKeyIdentityAuth, data)
-		if err != nil {
-			log.Printf("[UserService.ReviewUserAuth] notification error: %v", err)
+	var notification *models.Notification
+	err = runInTx(ctx, s.repo, "UserService.ReviewUserAuth", "审核失败", func(tx *sqlx.Tx) (err error) {
+		if err := s.repo.User.UpdateAuthStatusTx(ctx, tx, id, status); err != nil {
+			return err
 		}
-	}(context.WithoutCancel(ctx))
-
+		notification, err = notifyTx(ctx, tx, s.repo, notice{
+			UserID:  id,
+			Type:    models.NotificationTypeCertification,
+			Title:   "身份认证" + statusStr,
+			Content: remark,
+			BizKey:  models.MsgBizKeyIdentityAuth,
+			Data: map[string]string{
+				"status": statusStr,
+				"remark": remark,
+			},
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	publishNotification(ctx, s.events, notification)
 	return nil
 }
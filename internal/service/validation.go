@@ -15,8 +15,8 @@ func IsValidStatus(field string, status int) error {
 			return ErrBadRequest(fmt.Sprintf("无效的橄榄枝状态: %d", status))
 		}
 	case "order.status":
-		// 支付状态:0-待支付,1-已支付,2-已取消,3-已退款
-		if status < models.OrderStatusPending || status > models.OrderStatusRefunded {
+		// 支付状态:0-待支付,1-已支付,2-已取消,3-已退款,4-退款中
+		if status < models.OrderStatusPending || status > models.OrderStatusRefunding {
 			return ErrBadRequest(fmt.Sprintf("无效的订单状态: %d", status))
 		}
 	case "product.type":
@@ -50,8 +50,8 @@ func IsValidStatus(field string, status int) error {
 			return ErrBadRequest(fmt.Sprintf("无效的学历要求: %d", status))
 		}
 	case "application.status":
-		// 状态:0-待审核,1-已通过,2-已拒绝
-		if status < models.ApplicationStatusPending || status > models.ApplicationStatusRejected {
+		// 状态:0-待审核,1-已通过,2-已拒绝,3-已撤回
+		if status < models.ApplicationStatusPending || status > models.ApplicationStatusWithdrawn {
 			return ErrBadRequest(fmt.Sprintf("无效的申请状态: %d", status))
 		}
 	case "talent_profile.status":
@@ -59,6 +59,16 @@ func IsValidStatus(field string, status int) error {
 		if status < models.TalentStatusOffline || status > models.TalentStatusOnline {
 			return ErrBadRequest(fmt.Sprintf("无效的人才档案状态: %d", status))
 		}
+	case "talent_profile.education":
+		// 学历:1-大专,2-本科,3-研究生
+		if status < models.EducationJuniorCollege || status > models.EducationPostgraduate {
+			return ErrBadRequest(fmt.Sprintf("无效的学历: %d", status))
+		}
+	case "skill_tag.status":
+		// 状态:1-启用,0-停用
+		if status < models.SkillTagStatusDisabled || status > models.SkillTagStatusActive {
+			return ErrBadRequest(fmt.Sprintf("无效的技能标签状态: %d", status))
+		}
 	case "user.auth_status":
 		// 认证状态:0-未认证,1-已认证,2-认证失败
 		if status < models.UserAuthStatusNone || status > models.UserAuthStatusFailed {
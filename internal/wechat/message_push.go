@@ -0,0 +1,64 @@
+package wechat
+
+import (
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EventMediaCheck 多媒体内容安全识别结果推送事件
+const EventMediaCheck = "wxa_media_check"
+
+// pushMaxClockSkew 推送 timestamp 与服务器时间允许的最大偏差，超出视为重放
+const pushMaxClockSkew = 5 * time.Minute
+
+// PushMessage 消息推送（JSON 明文模式）的公共字段
+type PushMessage struct {
+	ToUserName   string `json:"ToUserName"`
+	FromUserName string `json:"FromUserName"`
+	CreateTime   int64  `json:"CreateTime"`
+	MsgType      string `json:"MsgType"`
+	Event        string `json:"Event"`
+}
+
+// MediaCheckEvent wxa_media_check 事件内容
+type MediaCheckEvent struct {
+	PushMessage
+	AppID   string              `json:"appid"`
+	TraceID string              `json:"trace_id"`
+	Version int                 `json:"version"`
+	Detail  []MsgSecCheckDetail `json:"detail"`
+	ErrCode int                 `json:"errcode"`
+	ErrMsg  string              `json:"errmsg"`
+	Result  MsgSecCheckResult   `json:"result"`
+}
+
+// VerifyPushSignature 校验消息推送签名：sha1(sort(token, timestamp, nonce))
+// https://developers.weixin.qq.com/miniprogram/dev/framework/server-ability/message-push.html
+func VerifyPushSignature(token, signature, timestamp, nonce string) bool {
+	if token == "" || signature == "" {
+		return false
+	}
+
+	parts := []string{token, timestamp, nonce}
+	sort.Strings(parts)
+	sum := sha1.Sum([]byte(strings.Join(parts, "")))
+	expected := hex.EncodeToString(sum[:])
+
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(signature)) == 1
+}
+
+// PushTimestampFresh 检查推送的 timestamp（秒）是否在允许的时间窗口内。
+// 签名只能证明请求来自微信，窗口限制可防止截获的旧请求被重放。
+func PushTimestampFresh(timestamp string, now time.Time) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	skew := now.Sub(time.Unix(ts, 0))
+	return skew <= pushMaxClockSkew && skew >= -pushMaxClockSkew
+}
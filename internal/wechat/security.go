@@ -0,0 +1,182 @@
+package wechat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// MsgSecCheckRequest 文本内容安全识别请求（v2）
+type MsgSecCheckRequest struct {
+	Content   string `json:"content"`
+	Version   int    `json:"version"`
+	Scene     int    `json:"scene"`
+	OpenID    string `json:"openid"`
+	Title     string `json:"title,omitempty"`
+	Nickname  string `json:"nickname,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// MsgSecCheckResult 综合结果
+type MsgSecCheckResult struct {
+	Suggest string `json:"suggest"` // risky/pass/review
+	Label   int    `json:"label"`   // 命中标签，100 为正常
+}
+
+// MsgSecCheckDetail 各策略的详细结果
+type MsgSecCheckDetail struct {
+	Strategy string `json:"strategy"`
+	ErrCode  int    `json:"errcode"`
+	Suggest  string `json:"suggest"`
+	Label    int    `json:"label"`
+	Prob     int    `json:"prob"`
+	Level    int    `json:"level"`
+	Keyword  string `json:"keyword"`
+}
+
+// MsgSecCheckResponse 文本内容安全识别响应
+type MsgSecCheckResponse struct {
+	ErrCode int                 `json:"errcode"`
+	ErrMsg  string              `json:"errmsg"`
+	TraceID string              `json:"trace_id"`
+	Result  MsgSecCheckResult   `json:"result"`
+	Detail  []MsgSecCheckDetail `json:"detail"`
+}
+
+// Keyword 返回命中的第一个关键词
+func (r *MsgSecCheckResponse) Keyword() string {
+	for _, d := range r.Detail {
+		if d.Keyword != "" {
+			return d.Keyword
+		}
+	}
+	return ""
+}
+
+// MsgSecCheck 文本内容安全识别
+// https://developers.weixin.qq.com/miniprogram/dev/OpenApiDoc/sec-center/sec-check/msgSecCheck.html
+func (c *Client) MsgSecCheck(req *MsgSecCheckRequest) (*MsgSecCheckResponse, error) {
+	if req.OpenID == "" {
+		return nil, fmt.Errorf("openid is required")
+	}
+	if req.Scene == 0 {
+		return nil, fmt.Errorf("scene is required")
+	}
+	req.Version = 2
+
+	accessToken, err := c.GetAccessToken()
+	if err != nil {
+		return nil, fmt.Errorf("get access token: %w", err)
+	}
+
+	url := fmt.Sprintf(
+		"https://api.weixin.qq.com/wxa/msg_sec_check?access_token=%s",
+		accessToken,
+	)
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result MsgSecCheckResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	if result.ErrCode != 0 {
+		return nil, fmt.Errorf("wechat api error: %d - %s", result.ErrCode, result.ErrMsg)
+	}
+
+	return &result, nil
+}
+
+// MediaCheckAsyncRequest 多媒体内容安全识别请求（v2）
+type MediaCheckAsyncRequest struct {
+	MediaURL  string `json:"media_url"`
+	MediaType int    `json:"media_type"` // 1-音频 2-图片
+	Version   int    `json:"version"`
+	Scene     int    `json:"scene"`
+	OpenID    string `json:"openid"`
+}
+
+type mediaCheckAsyncResponse struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+	TraceID string `json:"trace_id"`
+}
+
+// MediaTypeImage media_check_async 的图片类型
+const MediaTypeImage = 2
+
+// MediaCheckAsync 提交图片异步检测，返回 trace_id，结果通过消息推送（wxa_media_check 事件）回调
+// https://developers.weixin.qq.com/miniprogram/dev/OpenApiDoc/sec-center/sec-check/mediaCheckAsync.html
+func (c *Client) MediaCheckAsync(req *MediaCheckAsyncRequest) (string, error) {
+	if req.MediaURL == "" {
+		return "", fmt.Errorf("media_url is required")
+	}
+	if req.OpenID == "" {
+		return "", fmt.Errorf("openid is required")
+	}
+	if req.Scene == 0 {
+		return "", fmt.Errorf("scene is required")
+	}
+	if req.MediaType == 0 {
+		req.MediaType = MediaTypeImage
+	}
+	req.Version = 2
+
+	accessToken, err := c.GetAccessToken()
+	if err != nil {
+		return "", fmt.Errorf("get access token: %w", err)
+	}
+
+	url := fmt.Sprintf(
+		"https://api.weixin.qq.com/wxa/media_check_async?access_token=%s",
+		accessToken,
+	)
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return "", fmt.Errorf("create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result mediaCheckAsyncResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decode response: %w", err)
+	}
+
+	if result.ErrCode != 0 {
+		return "", fmt.Errorf("wechat api error: %d - %s", result.ErrCode, result.ErrMsg)
+	}
+	if result.TraceID == "" {
+		return "", fmt.Errorf("wechat api returned empty trace_id")
+	}
+
+	return result.TraceID, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rsa"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -16,6 +17,7 @@ import (
 	"github.com/wechatpay-apiv3/wechatpay-go/core/option"
 	"github.com/wechatpay-apiv3/wechatpay-go/services/payments"
 	"github.com/wechatpay-apiv3/wechatpay-go/services/payments/jsapi"
+	"github.com/wechatpay-apiv3/wechatpay-go/services/refunddomestic"
 	"github.com/wechatpay-apiv3/wechatpay-go/utils"
 )
 
@@ -27,6 +29,7 @@ type PayConfig struct {
 	PrivateKey           string // 商户API私钥(PEM格式)
 	AppID                string // 小程序AppID
 	NotifyURL            string // 支付回调地址
+	RefundNotifyURL      string // 退款结果回调地址
 	WechatPayPublicKey   string // 微信支付公钥(PEM格式)
 	WechatPayPublicKeyID string // 微信支付公钥ID
 }
@@ -36,6 +39,7 @@ type PayClient struct {
 	config          *PayConfig
 	client          *core.Client
 	jsapiSvc        *jsapi.JsapiApiService
+	refundSvc       *refunddomestic.RefundsApiService
 	notifyHandler   *notify.Handler
 	wechatPayPubKey *rsa.PublicKey
 }
@@ -57,6 +61,7 @@ func DefaultPayConfig() (*PayConfig, error) {
 	privateKeyInput := os.Getenv("WECHAT_MCH_PRIVATE_KEY")
 	appID := os.Getenv("WECHAT_APPID")
 	notifyURL := os.Getenv("WECHAT_NOTIFY_URL")
+	refundNotifyURL := os.Getenv("WECHAT_REFUND_NOTIFY_URL")
 	wechatPayPublicKeyInput := os.Getenv("WECHAT_PAY_PUBLIC_KEY")
 	wechatPayPublicKeyID := os.Getenv("WECHAT_PAY_PUBLIC_KEY_ID")
 
@@ -87,6 +92,7 @@ func DefaultPayConfig() (*PayConfig, error) {
 		PrivateKey:           privateKeyPEM,
 		AppID:                appID,
 		NotifyURL:            notifyURL,
+		RefundNotifyURL:      refundNotifyURL,
 		WechatPayPublicKey:   wechatPayPublicKeyPEM,
 		WechatPayPublicKeyID: wechatPayPublicKeyID,
 	}, nil
@@ -175,15 +181,16 @@ func NewPayClient(config *PayConfig) (*PayClient, error) {
 		config:          config,
 		client:          client,
 		jsapiSvc:        jsapiSvc,
+		refundSvc:       &refunddomestic.RefundsApiService{Client: client},
 		notifyHandler:   notifyHandler,
 		wechatPayPubKey: wechatPayPublicKey,
 	}, nil
 }
 
-// CreatePrepayOrderWithPayment creates a prepay order and returns payment params directly
-func (c *PayClient) CreatePrepayOrderWithPayment(ctx context.Context, outTradeNo, description, openID string, amountCents int) (*PaymentParams, error) {
-	// 使用 PrepayWithRequestPayment 一次性获取prepay_id和调起支付所需参数
-	resp, _, err := c.jsapiSvc.PrepayWithRequestPayment(ctx, jsapi.PrepayRequest{
+// CreatePrepayOrderWithPayment creates a prepay order and returns payment params directly.
+// A non-zero timeExpire stops WeChat Pay from accepting payment after that time.
+func (c *PayClient) CreatePrepayOrderWithPayment(ctx context.Context, outTradeNo, description, openID string, amountCents int, timeExpire time.Time) (*PaymentParams, error) {
+	req := jsapi.PrepayRequest{
 		Appid:       core.String(c.config.AppID),
 		Mchid:       core.String(c.config.MchID),
 		Description: core.String(description),
@@ -196,7 +203,13 @@ func (c *PayClient) CreatePrepayOrderWithPayment(ctx context.Context, outTradeNo
 		Payer: &jsapi.Payer{
 			Openid: core.String(openID),
 		},
-	})
+	}
+	if !timeExpire.IsZero() {
+		req.TimeExpire = core.Time(timeExpire)
+	}
+
+	// 使用 PrepayWithRequestPayment 一次性获取prepay_id和调起支付所需参数
+	resp, _, err := c.jsapiSvc.PrepayWithRequestPayment(ctx, req)
 
 	if err != nil {
 		return nil, fmt.Errorf("prepay: %w", err)
@@ -222,6 +235,217 @@ func (c *PayClient) ParseNotification(ctx context.Context, request *http.Request
 	return transaction, nil
 }
 
+// Trade states returned by the order query API
+const (
+	TradeStateSuccess  = "SUCCESS"    // 支付成功
+	TradeStateRefund   = "REFUND"     // 转入退款
+	TradeStateNotPay   = "NOTPAY"     // 未支付
+	TradeStateClosed   = "CLOSED"     // 已关闭
+	TradeStateRevoked  = "REVOKED"    // 已撤销
+	TradeStatePaying   = "USERPAYING" // 用户支付中
+	TradeStatePayError = "PAYERROR"   // 支付失败
+)
+
+// ErrOrderNotExist is returned when WeChat Pay has no order for the out_trade_no
+var ErrOrderNotExist = errors.New("wechat pay order not exist")
+
+// ErrOrderPaid is returned when closing an order that has already been paid
+var ErrOrderPaid = errors.New("wechat pay order already paid")
+
+// OrderQueryResult 商户订单号查询结果
+type OrderQueryResult struct {
+	TradeState    string
+	TransactionID string
+	SuccessTime   time.Time
+}
+
+// QueryOrderByOutTradeNo queries the trade state of an order by out_trade_no
+func (c *PayClient) QueryOrderByOutTradeNo(ctx context.Context, outTradeNo string) (*OrderQueryResult, error) {
+	transaction, _, err := c.jsapiSvc.QueryOrderByOutTradeNo(ctx, jsapi.QueryOrderByOutTradeNoRequest{
+		OutTradeNo: core.String(outTradeNo),
+		Mchid:      core.String(c.config.MchID),
+	})
+	if err != nil {
+		if core.IsAPIError(err, "ORDER_NOT_EXIST") || core.IsAPIError(err, "ORDERNOTEXIST") {
+			return nil, ErrOrderNotExist
+		}
+		return nil, fmt.Errorf("query order: %w", err)
+	}
+
+	result := &OrderQueryResult{}
+	if transaction.TradeState != nil {
+		result.TradeState = *transaction.TradeState
+	}
+	if transaction.TransactionId != nil {
+		result.TransactionID = *transaction.TransactionId
+	}
+	if transaction.SuccessTime != nil {
+		result.SuccessTime, _ = time.Parse(time.RFC3339, *transaction.SuccessTime)
+	}
+
+	return result, nil
+}
+
+// CloseOrder closes an unpaid order so that it can no longer be paid
+func (c *PayClient) CloseOrder(ctx context.Context, outTradeNo string) error {
+	_, err := c.jsapiSvc.CloseOrder(ctx, jsapi.CloseOrderRequest{
+		OutTradeNo: core.String(outTradeNo),
+		Mchid:      core.String(c.config.MchID),
+	})
+	if err != nil {
+		if core.IsAPIError(err, "ORDERPAID") {
+			return ErrOrderPaid
+		}
+		if core.IsAPIError(err, "ORDER_NOT_EXIST") || core.IsAPIError(err, "ORDERNOTEXIST") {
+			return ErrOrderNotExist
+		}
+		return fmt.Errorf("close order: %w", err)
+	}
+
+	return nil
+}
+
+// Refund states returned by the refunds API and refund notifications
+const (
+	RefundStateSuccess    = "SUCCESS"    // 退款成功
+	RefundStateClosed     = "CLOSED"     // 退款关闭
+	RefundStateProcessing = "PROCESSING" // 退款处理中
+	RefundStateAbnormal   = "ABNORMAL"   // 退款异常，需在商户平台处理
+)
+
+// ErrRefundRejected is returned when WeChat Pay definitively rejects a refund
+// request (e.g. insufficient balance or invalid parameters). Other errors leave
+// the refund outcome unknown.
+var ErrRefundRejected = errors.New("wechat pay refund rejected")
+
+// ErrRefundNotExist is returned when WeChat Pay has no refund with the given out_refund_no
+var ErrRefundNotExist = errors.New("wechat pay refund not exist")
+
+// refundRejectCodes 申请退款接口中表示退款未被受理的错误码
+// 其他错误（超时、5xx、FREQUENCY_LIMITED 等）不能确定结果，需查询退款单
+var refundRejectCodes = map[string]bool{
+	"PARAM_ERROR":           true, // 参数错误
+	"INVALID_REQUEST":       true, // 参数符合规则但不满足业务要求，如退款金额超出可退金额
+	"NOT_ENOUGH":            true, // 商户账户余额不足
+	"USER_ACCOUNT_ABNORMAL": true, // 用户账户异常或已注销
+	"NO_AUTH":               true, // 没有退款权限
+	"MCH_NOT_EXISTS":        true, // 商户号不存在
+	"RESOURCE_NOT_EXISTS":   true, // 订单不存在
+}
+
+// RefundRequest 申请退款参数，OutTradeNo 和 TransactionID 二选一
+type RefundRequest struct {
+	OutTradeNo    string
+	TransactionID string
+	OutRefundNo   string
+	Reason        string
+	RefundCents   int
+	TotalCents    int
+}
+
+// RefundResult 申请退款结果
+type RefundResult struct {
+	RefundID    string
+	Status      string
+	SuccessTime time.Time
+}
+
+// CreateRefund applies for a refund of a paid order
+// https://pay.weixin.qq.com/doc/v3/merchant/4012791862
+func (c *PayClient) CreateRefund(ctx context.Context, req *RefundRequest) (*RefundResult, error) {
+	createReq := refunddomestic.CreateRequest{
+		OutRefundNo: core.String(req.OutRefundNo),
+		Amount: &refunddomestic.AmountReq{
+			Refund:   core.Int64(int64(req.RefundCents)),
+			Total:    core.Int64(int64(req.TotalCents)),
+			Currency: core.String("CNY"),
+		},
+	}
+	if req.OutTradeNo != "" {
+		createReq.OutTradeNo = core.String(req.OutTradeNo)
+	} else {
+		createReq.TransactionId = core.String(req.TransactionID)
+	}
+	if req.Reason != "" {
+		createReq.Reason = core.String(req.Reason)
+	}
+	if c.config.RefundNotifyURL != "" {
+		createReq.NotifyUrl = core.String(c.config.RefundNotifyURL)
+	}
+
+	refund, _, err := c.refundSvc.Create(ctx, createReq)
+	if err != nil {
+		var apiErr *core.APIError
+		if errors.As(err, &apiErr) && refundRejectCodes[apiErr.Code] {
+			return nil, fmt.Errorf("%w: %s %s", ErrRefundRejected, apiErr.Code, apiErr.Message)
+		}
+		return nil, fmt.Errorf("create refund: %w", err)
+	}
+
+	return newRefundResult(refund), nil
+}
+
+// QueryRefund queries a refund by out_refund_no
+// https://pay.weixin.qq.com/doc/v3/merchant/4012791863
+func (c *PayClient) QueryRefund(ctx context.Context, outRefundNo string) (*RefundResult, error) {
+	refund, _, err := c.refundSvc.QueryByOutRefundNo(ctx, refunddomestic.QueryByOutRefundNoRequest{
+		OutRefundNo: core.String(outRefundNo),
+	})
+	if err != nil {
+		if core.IsAPIError(err, "RESOURCE_NOT_EXISTS") {
+			return nil, ErrRefundNotExist
+		}
+		return nil, fmt.Errorf("query refund: %w", err)
+	}
+
+	return newRefundResult(refund), nil
+}
+
+// newRefundResult converts a refund returned by the refunds API
+func newRefundResult(refund *refunddomestic.Refund) *RefundResult {
+	result := &RefundResult{}
+	if refund.RefundId != nil {
+		result.RefundID = *refund.RefundId
+	}
+	if refund.Status != nil {
+		result.Status = string(*refund.Status)
+	}
+	if refund.SuccessTime != nil {
+		result.SuccessTime = *refund.SuccessTime
+	}
+
+	return result
+}
+
+// RefundNotification 退款结果通知解密后的内容
+type RefundNotification struct {
+	MchID         string `json:"mchid"`
+	OutTradeNo    string `json:"out_trade_no"`
+	TransactionID string `json:"transaction_id"`
+	OutRefundNo   string `json:"out_refund_no"`
+	RefundID      string `json:"refund_id"`
+	RefundStatus  string `json:"refund_status"`
+	SuccessTime   string `json:"success_time"`
+}
+
+// ParseRefundNotification parses and verifies the refund notification
+func (c *PayClient) ParseRefundNotification(ctx context.Context, request *http.Request) (*RefundNotification, error) {
+	content := new(RefundNotification)
+	_, err := c.notifyHandler.ParseNotifyRequest(ctx, request, content)
+	if err != nil {
+		return nil, fmt.Errorf("parse refund notify: %w", err)
+	}
+
+	return content, nil
+}
+
+// GenerateOutRefundNo generates a unique refund number
+// Format: RF{timestamp}_{orderID}
+func GenerateOutRefundNo(orderID int) string {
+	timestamp := time.Now().Unix()
+	return fmt.Sprintf("RF%d_%d", timestamp, orderID)
+}
+
 // GenerateOutTradeNo generates a unique order number
 // Format: KZ{timestamp}_{orderID} to ensure minimum 6 bytes and uniqueness
 func GenerateOutTradeNo(orderID int) string {
@@ -0,0 +1,27 @@
+-- 项目申请：支持撤回与被拒后重新申请，每次申请单独一行，并记录状态变更历史
+ALTER TABLE `project_application`
+  DROP INDEX `uk_project_user`,
+  ADD KEY `idx_application_project_user` (`project_id`,`user_id`),
+  MODIFY `status` int(11) DEFAULT '0' COMMENT '状态:0-待审核,1-已通过,2-已拒绝,3-已撤回';
+
+CREATE TABLE IF NOT EXISTS `project_application_log` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `application_id` int(11) NOT NULL COMMENT '申请ID',
+  `status` int(11) NOT NULL COMMENT '变更后状态:0-待审核,1-已通过,2-已拒绝,3-已撤回',
+  `operator_id` int(11) DEFAULT NULL COMMENT '操作人ID(提交和撤回为申请人,审核为队长)',
+  `remark` text COMMENT '备注(审核时为队长回复)',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '变更时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_log_application` (`application_id`),
+  CONSTRAINT `fk_log_application` FOREIGN KEY (`application_id`) REFERENCES `project_application` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目申请状态变更记录表';
+
+-- 回填已有申请的历史：提交记录，以及已审核申请的审核结果
+INSERT INTO `project_application_log` (`application_id`, `status`, `operator_id`, `created_at`)
+SELECT `id`, 0, `user_id`, COALESCE(`applied_at`, CURRENT_TIMESTAMP) FROM `project_application`;
+
+INSERT INTO `project_application_log` (`application_id`, `status`, `operator_id`, `remark`, `created_at`)
+SELECT pa.`id`, pa.`status`, p.`creator_id`, pa.`reply_msg`, COALESCE(pa.`updated_at`, CURRENT_TIMESTAMP)
+FROM `project_application` pa
+JOIN `project` p ON pa.`project_id` = p.`id`
+WHERE pa.`status` IN (1, 2);
@@ -0,0 +1,19 @@
+-- 内容审核记录
+CREATE TABLE IF NOT EXISTS `content_audit_log` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` int(11) NOT NULL COMMENT '提交内容的用户ID',
+  `biz_type` varchar(50) NOT NULL COMMENT '业务类型：project/user/talent_profile',
+  `biz_id` int(11) DEFAULT NULL COMMENT '业务ID',
+  `scene` tinyint(4) NOT NULL COMMENT '审核场景：1-资料 2-评论 3-论坛 4-社交日志',
+  `content` text NOT NULL COMMENT '审核的文本',
+  `source` varchar(20) NOT NULL COMMENT '审核来源：wechat/local',
+  `suggest` varchar(20) NOT NULL COMMENT '审核建议：pass/review/risky',
+  `label` int(11) NOT NULL DEFAULT '100' COMMENT '命中标签，100-正常',
+  `keyword` varchar(255) DEFAULT NULL COMMENT '命中的关键词',
+  `trace_id` varchar(100) DEFAULT NULL COMMENT '微信返回的trace_id',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_biz` (`biz_type`,`biz_id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_suggest` (`suggest`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='内容审核记录表';
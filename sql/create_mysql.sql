@@ -44,6 +44,33 @@ CREATE TABLE `admin_user` (
 ) ENGINE=InnoDB AUTO_INCREMENT=5 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='管理员用户表';
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `content_audit_log`
+--
+
+DROP TABLE IF EXISTS `content_audit_log`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `content_audit_log` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` int(11) NOT NULL COMMENT '提交内容的用户ID',
+  `biz_type` varchar(50) NOT NULL COMMENT '业务类型：project/user/talent_profile',
+  `biz_id` int(11) DEFAULT NULL COMMENT '业务ID',
+  `scene` tinyint(4) NOT NULL COMMENT '审核场景：1-资料 2-评论 3-论坛 4-社交日志',
+  `content` text NOT NULL COMMENT '审核的文本',
+  `source` varchar(20) NOT NULL COMMENT '审核来源：wechat/local',
+  `suggest` varchar(20) NOT NULL COMMENT '审核建议：pass/review/risky',
+  `label` int(11) NOT NULL DEFAULT '100' COMMENT '命中标签，100-正常',
+  `keyword` varchar(255) DEFAULT NULL COMMENT '命中的关键词',
+  `trace_id` varchar(100) DEFAULT NULL COMMENT '微信返回的trace_id',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_biz` (`biz_type`,`biz_id`),
+  KEY `idx_user_id` (`user_id`),
+  KEY `idx_suggest` (`suggest`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='内容审核记录表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `email_promotion`
 --
@@ -81,7 +108,7 @@ DROP TABLE IF EXISTS `email_provider_config`;
 /*!50503 SET character_set_client = utf8mb4 */;
 CREATE TABLE `email_provider_config` (
   `id` int(11) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
-  `provider_type` varchar(50) NOT NULL COMMENT '服务商类型：aliyun/smtp/sendgrid',
+  `provider_type` varchar(50) NOT NULL COMMENT '服务商类型：aliyun/smtp/sendgrid/file',
   `config_name` varchar(100) NOT NULL COMMENT '配置名称',
   `config_json` json NOT NULL COMMENT '配置参数JSON',
   `is_default` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否默认：0-否 1-是',
@@ -112,10 +139,14 @@ CREATE TABLE `email_task` (
   `retry_count` int(11) NOT NULL DEFAULT '0' COMMENT '重试次数',
   `error_msg` varchar(500) DEFAULT NULL COMMENT '错误信息',
   `send_time` timestamp NULL DEFAULT NULL COMMENT '实际发送时间',
+  `next_retry_at` timestamp NULL DEFAULT NULL COMMENT '下次可执行时间(重试退避/发送租约)',
+  `claim_token` char(36) DEFAULT NULL COMMENT '领取令牌，只有持有当前租约的发送者才能写回结果',
+  `provider_id` int(11) DEFAULT NULL COMMENT '实际发送的服务商配置ID(email_provider_config.id)，0-环境变量SMTP',
   `create_time` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   PRIMARY KEY (`id`),
   KEY `idx_promotion_id` (`promotion_id`),
   KEY `idx_status` (`status`),
+  KEY `idx_status_next_retry` (`status`,`next_retry_at`),
   KEY `idx_create_time` (`create_time`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='邮件发送任务表';
 /*!40101 SET character_set_client = @saved_cs_client */;
@@ -144,6 +175,26 @@ CREATE TABLE `email_template` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='邮件模板表';
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `favorite`
+--
+
+DROP TABLE IF EXISTS `favorite`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `favorite` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '收藏者用户ID',
+  `target_type` varchar(20) NOT NULL COMMENT '收藏对象类型:project-项目,talent-人才档案',
+  `target_id` int(11) NOT NULL COMMENT '项目ID或人才档案ID',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '收藏时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_favorite_user_target` (`user_id`,`target_type`,`target_id`),
+  KEY `idx_favorite_target` (`target_type`,`target_id`),
+  CONSTRAINT `fk_favorite_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目与人才收藏表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `feedback`
 --
@@ -155,9 +206,8 @@ CREATE TABLE `feedback` (
   `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
   `user_id` int(11) NOT NULL COMMENT '用户ID',
   `content` text NOT NULL COMMENT '反馈内容',
-  `contact_image` text COMMENT '图片凭证',
+  `contact_image` text COMMENT '截图OSS key，多张以逗号分隔',
   `status` int(11) DEFAULT '0' COMMENT '处理状态:0-待处理,1-已处理',
-  `admin_reply` text COMMENT '管理员回复',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   PRIMARY KEY (`id`),
@@ -167,6 +217,53 @@ CREATE TABLE `feedback` (
 ) ENGINE=InnoDB AUTO_INCREMENT=6 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='意见反馈表';
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `feedback_message`
+--
+
+DROP TABLE IF EXISTS `feedback_message`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `feedback_message` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `feedback_id` int(11) NOT NULL COMMENT '反馈ID',
+  `sender_type` varchar(16) NOT NULL COMMENT '发送方:user-用户,admin-管理员',
+  `sender_id` int(11) DEFAULT NULL COMMENT '发送方ID（用户ID或管理员ID）',
+  `content` text NOT NULL COMMENT '消息内容',
+  `images` text COMMENT '截图OSS key，多张以逗号分隔',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_feedback_message_feedback` (`feedback_id`,`id`),
+  CONSTRAINT `fk_feedback_message_feedback` FOREIGN KEY (`feedback_id`) REFERENCES `feedback` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='反馈沟通记录表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `image_audit`
+--
+
+DROP TABLE IF EXISTS `image_audit`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `image_audit` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` int(11) NOT NULL COMMENT '上传用户ID',
+  `biz_type` varchar(20) NOT NULL COMMENT '图片用途：avatar/cover',
+  `image_key` varchar(100) NOT NULL COMMENT 'OSS相对路径',
+  `status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '状态：0-待机审 1-通过 2-待人工复审 3-驳回 4-已被新图片替代',
+  `source` varchar(20) DEFAULT NULL COMMENT '审核来源：wechat/admin',
+  `trace_id` varchar(100) DEFAULT NULL COMMENT '微信media_check_async的trace_id',
+  `suggest` varchar(20) DEFAULT NULL COMMENT '审核建议：pass/review/risky',
+  `label` int(11) DEFAULT NULL COMMENT '命中标签',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_trace_id` (`trace_id`),
+  KEY `idx_user_biz` (`user_id`,`biz_type`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='图片审核表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `major`
 --
@@ -202,6 +299,81 @@ CREATE TABLE `major_class` (
 ) ENGINE=InnoDB AUTO_INCREMENT=113 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='专业大类表';
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `message_outbox`
+--
+
+DROP TABLE IF EXISTS `message_outbox`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `message_outbox` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '接收用户ID',
+  `biz_key` varchar(50) NOT NULL COMMENT '消息业务键',
+  `payload` json NOT NULL COMMENT '消息业务数据',
+  `status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '状态:0-待发送,1-发送中,2-已发送,3-失败,4-重试中,5-已跳过',
+  `retry_count` int(11) NOT NULL DEFAULT '0' COMMENT '已重试次数',
+  `error_msg` varchar(500) DEFAULT NULL COMMENT '最近一次失败原因',
+  `next_retry_at` datetime DEFAULT NULL COMMENT '下次发送时间/发送租约到期时间',
+  `claim_token` char(36) DEFAULT NULL COMMENT '领取令牌，只有持有当前租约的发送者才能写回结果',
+  `sent_at` datetime DEFAULT NULL COMMENT '发送成功时间',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_status_next_retry` (`status`,`next_retry_at`),
+  KEY `idx_user_status` (`user_id`,`status`),
+  CONSTRAINT `fk_msg_outbox_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='订阅消息发件箱';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `notification`
+--
+
+DROP TABLE IF EXISTS `notification`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `notification` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '接收用户ID',
+  `type` varchar(32) NOT NULL COMMENT '通知类型:application_received,application_reviewed,olive_branch_received,olive_branch_handled,olive_branch_expired,project_audit,certification,feedback_reply,member_left,member_removed',
+  `title` varchar(100) NOT NULL COMMENT '标题',
+  `content` varchar(1000) NOT NULL COMMENT '正文',
+  `related_id` int(11) DEFAULT NULL COMMENT '关联业务对象ID',
+  `is_read` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否已读',
+  `read_at` datetime DEFAULT NULL COMMENT '阅读时间',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_read` (`user_id`,`is_read`,`id`),
+  CONSTRAINT `fk_notification_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='站内通知';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `olive_branch_ledger`
+--
+
+DROP TABLE IF EXISTS `olive_branch_ledger`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `olive_branch_ledger` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '用户ID',
+  `entry_type` varchar(32) NOT NULL COMMENT '流水类型:purchase-购买,order_refund-订单退款收回,free_use-使用免费额度,send-使用付费余额,refund-退还额度,admin_grant-管理员调整',
+  `cost_type` int(11) NOT NULL COMMENT '额度类型:1-免费额度,2-付费额度',
+  `amount` int(11) NOT NULL COMMENT '变动数量,增加为正、扣减为负',
+  `olive_branch_id` int(11) DEFAULT NULL COMMENT '关联橄榄枝ID',
+  `order_id` int(11) DEFAULT NULL COMMENT '关联订单ID',
+  `remark` varchar(200) DEFAULT NULL COMMENT '备注',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`,`id`),
+  KEY `idx_olive_branch_id` (`olive_branch_id`),
+  KEY `idx_order_id` (`order_id`),
+  CONSTRAINT `fk_olive_ledger_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='橄榄枝额度流水';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `olive_branch_record`
 --
@@ -245,14 +417,15 @@ CREATE TABLE `order` (
   `price` decimal(10,2) NOT NULL COMMENT '下单时的单价快照',
   `quantity` int(11) NOT NULL COMMENT '数量',
   `actual_paid` decimal(10,2) NOT NULL COMMENT '实付金额',
-  `status` int(11) DEFAULT '0' COMMENT '支付状态:0-待支付,1-已支付,2-已取消,3-已退款',
+  `status` int(11) DEFAULT '0' COMMENT '支付状态:0-待支付,1-已支付,2-已取消,3-已退款,4-退款中',
   `wx_pay_no` varchar(100) DEFAULT NULL COMMENT '微信支付订单号',
-  `out_trade_no` varchar(32) NOT NULL COMMENT '商户单号',
+  `out_trade_no` varchar(32) DEFAULT NULL COMMENT '商户单号',
   `pay_time` timestamp NULL DEFAULT NULL COMMENT '支付时间',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   PRIMARY KEY (`id`),
   KEY `idx_wx_pay_no` (`wx_pay_no`),
+  KEY `idx_status_created` (`status`,`created_at`),
   KEY `fk_order_merged_user` (`user_id`),
   KEY `fk_order_merged_product` (`product_id`),
   CONSTRAINT `fk_order_merged_product` FOREIGN KEY (`product_id`) REFERENCES `product` (`id`) ON DELETE RESTRICT,
@@ -260,6 +433,35 @@ CREATE TABLE `order` (
 ) ENGINE=InnoDB AUTO_INCREMENT=4 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='订单总表(合并主表与详情)';
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `order_refund`
+--
+
+DROP TABLE IF EXISTS `order_refund`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `order_refund` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `order_id` int(11) NOT NULL COMMENT '订单ID',
+  `user_id` int(11) NOT NULL COMMENT '用户ID',
+  `out_refund_no` varchar(64) NOT NULL COMMENT '商户退款单号',
+  `refund_id` varchar(64) DEFAULT NULL COMMENT '微信退款单号',
+  `amount` decimal(10,2) NOT NULL COMMENT '退款金额',
+  `reason` varchar(200) DEFAULT NULL COMMENT '退款原因',
+  `status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '状态:0-待审核,1-退款中,2-退款成功,3-退款失败,4-已驳回',
+  `source` varchar(20) NOT NULL COMMENT '发起方:user/admin',
+  `note` varchar(500) DEFAULT NULL COMMENT '驳回原因或失败原因',
+  `success_time` timestamp NULL DEFAULT NULL COMMENT '退款到账时间',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_out_refund_no` (`out_refund_no`),
+  KEY `idx_order_id` (`order_id`),
+  KEY `idx_status` (`status`),
+  CONSTRAINT `fk_order_refund_order` FOREIGN KEY (`order_id`) REFERENCES `order` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='订单退款表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `product`
 --
@@ -273,7 +475,7 @@ CREATE TABLE `product` (
   `type` int(11) NOT NULL COMMENT '类型:1-虚拟币,2-服务权益',
   `description` text COMMENT '商品描述',
   `price` decimal(10,2) NOT NULL COMMENT '商品价格',
-  `config_json` json DEFAULT NULL COMMENT '配置参数(如增加多少个橄榄枝)',
+  `config_json` json DEFAULT NULL COMMENT '权益配置,如{"kind":"email_promotion","recipients":50}',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
   `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   PRIMARY KEY (`id`)
@@ -309,6 +511,7 @@ CREATE TABLE `project` (
   KEY `idx_project_school` (`school_id`),
   KEY `idx_project_status` (`status`),
   KEY `idx_project_created` (`created_at`),
+  KEY `idx_project_promotion` (`promotion_status`,`promotion_expire_time`),
   CONSTRAINT `fk_project_creator` FOREIGN KEY (`creator_id`) REFERENCES `user` (`id`) ON DELETE CASCADE,
   CONSTRAINT `fk_project_school` FOREIGN KEY (`school_id`) REFERENCES `school` (`id`) ON DELETE SET NULL
 ) ENGINE=InnoDB AUTO_INCREMENT=342 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目表';
@@ -327,12 +530,12 @@ CREATE TABLE `project_application` (
   `user_id` int(11) NOT NULL COMMENT '申请人',
   `apply_reason` text COMMENT '申请理由/留言',
   `contact` text COMMENT '联系方式',
-  `status` int(11) DEFAULT '0' COMMENT '状态:0-待审核,1-已通过,2-已拒绝',
+  `status` int(11) DEFAULT '0' COMMENT '状态:0-待审核,1-已通过,2-已拒绝,3-已撤回',
   `reply_msg` text COMMENT '队长回复',
   `applied_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '申请时间',
   `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
   PRIMARY KEY (`id`),
-  UNIQUE KEY `uk_project_user` (`project_id`,`user_id`),
+  KEY `idx_application_project_user` (`project_id`,`user_id`),
   KEY `idx_application_project` (`project_id`),
   KEY `idx_application_user` (`user_id`),
   KEY `idx_application_status` (`status`),
@@ -341,6 +544,86 @@ CREATE TABLE `project_application` (
 ) ENGINE=InnoDB AUTO_INCREMENT=562 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目申请表';
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `project_application_log`
+--
+
+DROP TABLE IF EXISTS `project_application_log`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `project_application_log` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `application_id` int(11) NOT NULL COMMENT '申请ID',
+  `status` int(11) NOT NULL COMMENT '变更后状态:0-待审核,1-已通过,2-已拒绝,3-已撤回',
+  `operator_id` int(11) DEFAULT NULL COMMENT '操作人ID(提交和撤回为申请人,审核为队长)',
+  `remark` text COMMENT '备注(审核时为队长回复)',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '变更时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_log_application` (`application_id`),
+  CONSTRAINT `fk_log_application` FOREIGN KEY (`application_id`) REFERENCES `project_application` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目申请状态变更记录表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `project_member`
+--
+
+DROP TABLE IF EXISTS `project_member`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `project_member` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `project_id` int(11) NOT NULL COMMENT '项目ID',
+  `user_id` int(11) NOT NULL COMMENT '成员用户ID',
+  `role` varchar(16) NOT NULL COMMENT '角色:leader-队长,member-队员',
+  `source` varchar(16) NOT NULL COMMENT '加入方式:creator-创建项目,application-申请通过,olive_branch-接受邀请',
+  `status` tinyint(4) NOT NULL DEFAULT '1' COMMENT '状态:1-在队,2-已退出,3-被移出',
+  `joined_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '加入时间',
+  `left_at` timestamp NULL DEFAULT NULL COMMENT '退出/移出时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_project_member` (`project_id`,`user_id`),
+  KEY `idx_member_user` (`user_id`,`status`),
+  CONSTRAINT `fk_member_project` FOREIGN KEY (`project_id`) REFERENCES `project` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_member_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目成员表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `project_skill_tag`
+--
+
+DROP TABLE IF EXISTS `project_skill_tag`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `project_skill_tag` (
+  `project_id` int(11) NOT NULL COMMENT '项目ID',
+  `tag_id` int(11) NOT NULL COMMENT '技能标签ID',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`project_id`,`tag_id`),
+  KEY `idx_project_skill_tag_tag` (`tag_id`),
+  CONSTRAINT `fk_project_skill_tag_project` FOREIGN KEY (`project_id`) REFERENCES `project` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_project_skill_tag_tag` FOREIGN KEY (`tag_id`) REFERENCES `skill_tag` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目技能标签关联表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `realtime_event`
+--
+
+DROP TABLE IF EXISTS `realtime_event`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `realtime_event` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '接收用户ID',
+  `type` varchar(32) NOT NULL COMMENT '事件类型',
+  `data` json NOT NULL COMMENT '事件数据',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='实时事件日志';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `school`
 --
@@ -360,6 +643,86 @@ CREATE TABLE `school` (
 ) ENGINE=InnoDB AUTO_INCREMENT=2979 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='学校字典表';
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `search_document`
+--
+
+DROP TABLE IF EXISTS `search_document`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `search_document` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `doc_type` varchar(16) NOT NULL COMMENT '文档类型:project-项目,talent-人才档案',
+  `ref_id` int(11) NOT NULL COMMENT '项目ID或人才档案ID',
+  `title` varchar(200) NOT NULL DEFAULT '' COMMENT '项目名称或昵称',
+  `content` text NOT NULL COMMENT '项目详情，或自我评价与项目经历',
+  `skills` text NOT NULL COMMENT '技能，空格分隔',
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_search_document` (`doc_type`,`ref_id`),
+  FULLTEXT KEY `ft_search_all` (`title`,`content`,`skills`) /*!50100 WITH PARSER `ngram` */,
+  FULLTEXT KEY `ft_search_title` (`title`) /*!50100 WITH PARSER `ngram` */,
+  FULLTEXT KEY `ft_search_skills` (`skills`) /*!50100 WITH PARSER `ngram` */
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='全文搜索文档表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `skill_category`
+--
+
+DROP TABLE IF EXISTS `skill_category`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `skill_category` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `name` varchar(30) NOT NULL COMMENT '分类名称',
+  `sort_order` int(11) NOT NULL DEFAULT '0' COMMENT '排序，越小越靠前',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_skill_category_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='技能分类字典表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `skill_tag`
+--
+
+DROP TABLE IF EXISTS `skill_tag`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `skill_tag` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `category_id` int(11) NOT NULL COMMENT '技能分类ID',
+  `name` varchar(30) NOT NULL COMMENT '标签名称',
+  `status` tinyint(4) NOT NULL DEFAULT '1' COMMENT '状态:1-启用,0-停用',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_skill_tag_name` (`name`),
+  KEY `idx_skill_tag_category` (`category_id`,`status`),
+  CONSTRAINT `fk_skill_tag_category` FOREIGN KEY (`category_id`) REFERENCES `skill_category` (`id`) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='技能标签字典表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Table structure for table `skill_tag_synonym`
+--
+
+DROP TABLE IF EXISTS `skill_tag_synonym`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `skill_tag_synonym` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `tag_id` int(11) NOT NULL COMMENT '技能标签ID',
+  `synonym` varchar(30) NOT NULL COMMENT '同义词',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_skill_tag_synonym` (`synonym`),
+  KEY `idx_skill_tag_synonym_tag` (`tag_id`),
+  CONSTRAINT `fk_skill_tag_synonym_tag` FOREIGN KEY (`tag_id`) REFERENCES `skill_tag` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='技能标签同义词表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `subscribe`
 --
@@ -370,7 +733,7 @@ DROP TABLE IF EXISTS `subscribe`;
 CREATE TABLE `subscribe` (
   `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
   `user_id` int(11) NOT NULL COMMENT '用户ID',
-  `subscribe_count` int(11) DEFAULT NULL COMMENT '剩余可发送次数(估计)',
+  `subscribe_count` int(11) NOT NULL DEFAULT '0' COMMENT '剩余一次性订阅次数,每次授权+1,每次发送成功-1',
   `status` tinyint(4) DEFAULT '1' COMMENT '状态（0-允许/1-拒绝/2-总是保持）',
   `biz_key` varchar(100) NOT NULL COMMENT '业务标识',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
@@ -414,6 +777,7 @@ CREATE TABLE `talent_profile` (
   `skill_summary` text COMMENT '技能标签',
   `project_experience` text COMMENT '项目经历',
   `mbti` varchar(10) DEFAULT NULL COMMENT 'MBTI性格类型',
+  `education` tinyint(4) DEFAULT NULL COMMENT '学历:1-大专,2-本科,3-研究生',
   `status` int(11) DEFAULT '1' COMMENT '状态:1-上架,0-下架',
   `is_public_contact` tinyint(1) DEFAULT '0' COMMENT '是否公开联系方式',
   `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
@@ -426,6 +790,24 @@ CREATE TABLE `talent_profile` (
 ) ENGINE=InnoDB AUTO_INCREMENT=46 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='人才档案表';
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `talent_skill_tag`
+--
+
+DROP TABLE IF EXISTS `talent_skill_tag`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `talent_skill_tag` (
+  `talent_profile_id` int(11) NOT NULL COMMENT '人才档案ID',
+  `tag_id` int(11) NOT NULL COMMENT '技能标签ID',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`talent_profile_id`,`tag_id`),
+  KEY `idx_talent_skill_tag_tag` (`tag_id`),
+  CONSTRAINT `fk_talent_skill_tag_profile` FOREIGN KEY (`talent_profile_id`) REFERENCES `talent_profile` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_talent_skill_tag_tag` FOREIGN KEY (`tag_id`) REFERENCES `skill_tag` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='人才档案技能标签关联表';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `user`
 --
@@ -462,6 +844,31 @@ CREATE TABLE `user` (
 ) ENGINE=InnoDB AUTO_INCREMENT=2153 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='用户表';
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `user_entitlement`
+--
+
+DROP TABLE IF EXISTS `user_entitlement`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `user_entitlement` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '用户ID',
+  `order_id` int(11) NOT NULL COMMENT '发放权益的订单ID',
+  `kind` varchar(32) NOT NULL COMMENT '权益类型:email_promotion-邮件推广,project_top-项目置顶,olive_branch-橄榄枝',
+  `total` int(11) NOT NULL COMMENT '发放数量(收件人数/天数/橄榄枝数)',
+  `used` int(11) NOT NULL DEFAULT '0' COMMENT '已使用数量',
+  `status` tinyint(4) NOT NULL DEFAULT '1' COMMENT '状态:1-有效,2-已撤销',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_order_id` (`order_id`),
+  KEY `idx_user_kind` (`user_id`,`kind`),
+  CONSTRAINT `fk_user_entitlement_order` FOREIGN KEY (`order_id`) REFERENCES `order` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_user_entitlement_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='用户权益台账';
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Dumping routines for database 'lianxi'
 --
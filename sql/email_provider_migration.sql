@@ -0,0 +1,9 @@
+-- 邮件多服务商发送：记录每封邮件由哪个服务商发出
+ALTER TABLE `email_task`
+    ADD COLUMN `provider_id` INT(11) DEFAULT NULL COMMENT '实际发送的服务商配置ID(email_provider_config.id)，0-环境变量SMTP' AFTER `next_retry_at`;
+
+-- email_provider_config.config_json 示例：
+--   smtp / aliyun: {"host":"smtpdm.aliyun.com","port":465,"user":"noreply@mail.kuaizu.xyz","password":"***","fromName":"快组校园团队","rateLimitPerMinute":60}
+--   file:          {"path":"/tmp/kuaizu.mbox","user":"noreply@localhost"}
+ALTER TABLE `email_provider_config`
+    MODIFY COLUMN `provider_type` VARCHAR(50) NOT NULL COMMENT '服务商类型：aliyun/smtp/sendgrid/file';
@@ -0,0 +1,9 @@
+-- 邮件发送任务：重试退避与发送租约
+ALTER TABLE `email_task`
+    ADD COLUMN `next_retry_at` TIMESTAMP NULL DEFAULT NULL COMMENT '下次可执行时间(重试退避/发送租约)' AFTER `send_time`;
+
+CREATE INDEX idx_status_next_retry ON `email_task`(`status`, `next_retry_at`);
+
+-- 领取令牌：租约过期被重新领取后，原发送者不能再写回结果
+ALTER TABLE `email_task`
+    ADD COLUMN `claim_token` CHAR(36) NULL DEFAULT NULL COMMENT '领取令牌，只有持有当前租约的发送者才能写回结果' AFTER `next_retry_at`;
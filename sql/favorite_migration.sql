@@ -0,0 +1,12 @@
+-- 项目与人才档案收藏；target_id 按 target_type 指向 project.id 或 talent_profile.id
+CREATE TABLE IF NOT EXISTS `favorite` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '收藏者用户ID',
+  `target_type` varchar(20) NOT NULL COMMENT '收藏对象类型:project-项目,talent-人才档案',
+  `target_id` int(11) NOT NULL COMMENT '项目ID或人才档案ID',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '收藏时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_favorite_user_target` (`user_id`,`target_type`,`target_id`),
+  KEY `idx_favorite_target` (`target_type`,`target_id`),
+  CONSTRAINT `fk_favorite_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目与人才收藏表';
@@ -0,0 +1,22 @@
+-- 反馈沟通记录：用户追问与管理员回复按时间线保存，截图以逗号分隔的 OSS key 存储
+CREATE TABLE IF NOT EXISTS `feedback_message` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `feedback_id` int(11) NOT NULL COMMENT '反馈ID',
+  `sender_type` varchar(16) NOT NULL COMMENT '发送方:user-用户,admin-管理员',
+  `sender_id` int(11) DEFAULT NULL COMMENT '发送方ID（用户ID或管理员ID）',
+  `content` text NOT NULL COMMENT '消息内容',
+  `images` text COMMENT '截图OSS key，多张以逗号分隔',
+  `created_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_feedback_message_feedback` (`feedback_id`,`id`),
+  CONSTRAINT `fk_feedback_message_feedback` FOREIGN KEY (`feedback_id`) REFERENCES `feedback` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='反馈沟通记录表';
+
+-- 迁移已有的管理员回复为沟通记录
+INSERT INTO `feedback_message` (`feedback_id`, `sender_type`, `sender_id`, `content`, `created_at`)
+SELECT `id`, 'admin', NULL, `admin_reply`, COALESCE(`updated_at`, CURRENT_TIMESTAMP)
+FROM `feedback` WHERE `admin_reply` IS NOT NULL AND `admin_reply` <> '';
+
+ALTER TABLE `feedback`
+  MODIFY COLUMN `contact_image` text COMMENT '截图OSS key，多张以逗号分隔',
+  DROP COLUMN `admin_reply`;
@@ -0,0 +1,18 @@
+-- 头像/封面图片审核
+CREATE TABLE IF NOT EXISTS `image_audit` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT '主键ID',
+  `user_id` int(11) NOT NULL COMMENT '上传用户ID',
+  `biz_type` varchar(20) NOT NULL COMMENT '图片用途：avatar/cover',
+  `image_key` varchar(100) NOT NULL COMMENT 'OSS相对路径',
+  `status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '状态：0-待机审 1-通过 2-待人工复审 3-驳回 4-已被新图片替代',
+  `source` varchar(20) DEFAULT NULL COMMENT '审核来源：wechat/admin',
+  `trace_id` varchar(100) DEFAULT NULL COMMENT '微信media_check_async的trace_id',
+  `suggest` varchar(20) DEFAULT NULL COMMENT '审核建议：pass/review/risky',
+  `label` int(11) DEFAULT NULL COMMENT '命中标签',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_trace_id` (`trace_id`),
+  KEY `idx_user_biz` (`user_id`,`biz_type`),
+  KEY `idx_status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='图片审核表';
@@ -0,0 +1,19 @@
+-- 订阅消息发件箱：与业务变更同一事务写入，由 MessageDispatcher 异步投递
+CREATE TABLE IF NOT EXISTS `message_outbox` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '接收用户ID',
+  `biz_key` varchar(50) NOT NULL COMMENT '消息业务键',
+  `payload` json NOT NULL COMMENT '消息业务数据',
+  `status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '状态:0-待发送,1-发送中,2-已发送,3-失败,4-重试中,5-已跳过',
+  `retry_count` int(11) NOT NULL DEFAULT '0' COMMENT '已重试次数',
+  `error_msg` varchar(500) DEFAULT NULL COMMENT '最近一次失败原因',
+  `next_retry_at` datetime DEFAULT NULL COMMENT '下次发送时间/发送租约到期时间',
+  `claim_token` char(36) DEFAULT NULL COMMENT '领取令牌，只有持有当前租约的发送者才能写回结果',
+  `sent_at` datetime DEFAULT NULL COMMENT '发送成功时间',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_status_next_retry` (`status`,`next_retry_at`),
+  KEY `idx_user_status` (`user_id`,`status`),
+  CONSTRAINT `fk_msg_outbox_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='订阅消息发件箱';
@@ -0,0 +1,15 @@
+-- 站内通知：与订阅消息同一事务写入，订阅额度不足时用户仍可在消息中心查看
+CREATE TABLE IF NOT EXISTS `notification` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '接收用户ID',
+  `type` varchar(32) NOT NULL COMMENT '通知类型:application_received,application_reviewed,olive_branch_received,olive_branch_handled,olive_branch_expired,project_audit,certification,feedback_reply',
+  `title` varchar(100) NOT NULL COMMENT '标题',
+  `content` varchar(1000) NOT NULL COMMENT '正文',
+  `related_id` int(11) DEFAULT NULL COMMENT '关联业务对象ID',
+  `is_read` tinyint(1) NOT NULL DEFAULT '0' COMMENT '是否已读',
+  `read_at` datetime DEFAULT NULL COMMENT '阅读时间',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_read` (`user_id`,`is_read`,`id`),
+  CONSTRAINT `fk_notification_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='站内通知';
@@ -0,0 +1,23 @@
+-- 橄榄枝额度流水
+CREATE TABLE IF NOT EXISTS `olive_branch_ledger` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '用户ID',
+  `entry_type` varchar(32) NOT NULL COMMENT '流水类型:purchase-购买,order_refund-订单退款收回,free_use-使用免费额度,send-使用付费余额,refund-退还额度,admin_grant-管理员调整',
+  `cost_type` int(11) NOT NULL COMMENT '额度类型:1-免费额度,2-付费额度',
+  `amount` int(11) NOT NULL COMMENT '变动数量,增加为正、扣减为负',
+  `olive_branch_id` int(11) DEFAULT NULL COMMENT '关联橄榄枝ID',
+  `order_id` int(11) DEFAULT NULL COMMENT '关联订单ID',
+  `remark` varchar(200) DEFAULT NULL COMMENT '备注',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_user_id` (`user_id`,`id`),
+  KEY `idx_olive_branch_id` (`olive_branch_id`),
+  KEY `idx_order_id` (`order_id`),
+  CONSTRAINT `fk_olive_ledger_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='橄榄枝额度流水';
+
+-- 流水上线前的付费余额记为一条期初调整，使流水合计与余额一致
+INSERT INTO `olive_branch_ledger` (`user_id`, `entry_type`, `cost_type`, `amount`, `remark`)
+SELECT u.`id`, 'admin_grant', 2, u.`olive_branch_count`, '流水上线前的余额'
+FROM `user` u
+WHERE u.`olive_branch_count` > 0;
@@ -0,0 +1,4 @@
+-- 未支付订单超时关闭：out_trade_no 在发起支付时才生成，下单时为空
+ALTER TABLE `order`
+    MODIFY COLUMN `out_trade_no` VARCHAR(32) DEFAULT NULL COMMENT '商户单号',
+    ADD KEY `idx_status_created` (`status`, `created_at`);
@@ -0,0 +1,24 @@
+-- 订单退款
+CREATE TABLE IF NOT EXISTS `order_refund` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `order_id` int(11) NOT NULL COMMENT '订单ID',
+  `user_id` int(11) NOT NULL COMMENT '用户ID',
+  `out_refund_no` varchar(64) NOT NULL COMMENT '商户退款单号',
+  `refund_id` varchar(64) DEFAULT NULL COMMENT '微信退款单号',
+  `amount` decimal(10,2) NOT NULL COMMENT '退款金额',
+  `reason` varchar(200) DEFAULT NULL COMMENT '退款原因',
+  `status` tinyint(4) NOT NULL DEFAULT '0' COMMENT '状态:0-待审核,1-退款中,2-退款成功,3-退款失败,4-已驳回',
+  `source` varchar(20) NOT NULL COMMENT '发起方:user/admin',
+  `note` varchar(500) DEFAULT NULL COMMENT '驳回原因或失败原因',
+  `success_time` timestamp NULL DEFAULT NULL COMMENT '退款到账时间',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_out_refund_no` (`out_refund_no`),
+  KEY `idx_order_id` (`order_id`),
+  KEY `idx_status` (`status`),
+  CONSTRAINT `fk_order_refund_order` FOREIGN KEY (`order_id`) REFERENCES `order` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='订单退款表';
+
+ALTER TABLE `order`
+    MODIFY COLUMN `status` INT(11) DEFAULT '0' COMMENT '支付状态:0-待支付,1-已支付,2-已取消,3-已退款,4-退款中';
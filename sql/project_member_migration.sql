@@ -0,0 +1,28 @@
+-- 项目成员：记录队长与队员，支持退出、移出和人数上限
+CREATE TABLE IF NOT EXISTS `project_member` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `project_id` int(11) NOT NULL COMMENT '项目ID',
+  `user_id` int(11) NOT NULL COMMENT '成员用户ID',
+  `role` varchar(16) NOT NULL COMMENT '角色:leader-队长,member-队员',
+  `source` varchar(16) NOT NULL COMMENT '加入方式:creator-创建项目,application-申请通过,olive_branch-接受邀请',
+  `status` tinyint(4) NOT NULL DEFAULT '1' COMMENT '状态:1-在队,2-已退出,3-被移出',
+  `joined_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '加入时间',
+  `left_at` timestamp NULL DEFAULT NULL COMMENT '退出/移出时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_project_member` (`project_id`,`user_id`),
+  KEY `idx_member_user` (`user_id`,`status`),
+  CONSTRAINT `fk_member_project` FOREIGN KEY (`project_id`) REFERENCES `project` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_member_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目成员表';
+
+-- 回填已有数据：项目创建者为队长，已通过的申请和已接受的橄榄枝为队员
+INSERT IGNORE INTO `project_member` (`project_id`, `user_id`, `role`, `source`, `status`, `joined_at`)
+SELECT `id`, `creator_id`, 'leader', 'creator', 1, `created_at` FROM `project`;
+
+INSERT IGNORE INTO `project_member` (`project_id`, `user_id`, `role`, `source`, `status`, `joined_at`)
+SELECT `project_id`, `user_id`, 'member', 'application', 1, COALESCE(`updated_at`, `applied_at`, CURRENT_TIMESTAMP)
+FROM `project_application` WHERE `status` = 1;
+
+INSERT IGNORE INTO `project_member` (`project_id`, `user_id`, `role`, `source`, `status`, `joined_at`)
+SELECT `related_project_id`, `receiver_id`, 'member', 'olive_branch', 1, COALESCE(`updated_at`, `created_at`, CURRENT_TIMESTAMP)
+FROM `olive_branch_record` WHERE `status` = 1;
@@ -0,0 +1,3 @@
+-- 项目置顶：按推广状态和结束时间扫描到期推广
+ALTER TABLE `project`
+    ADD KEY `idx_project_promotion` (`promotion_status`, `promotion_expire_time`);
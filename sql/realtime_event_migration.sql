@@ -0,0 +1,10 @@
+-- 实时事件日志：多实例部署时各实例轮询该表互相转发推送事件，只保留最近几分钟
+CREATE TABLE IF NOT EXISTS `realtime_event` (
+  `id` bigint(20) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '接收用户ID',
+  `type` varchar(32) NOT NULL COMMENT '事件类型',
+  `data` json NOT NULL COMMENT '事件数据',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`id`),
+  KEY `idx_created_at` (`created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='实时事件日志';
@@ -0,0 +1,33 @@
+-- 全文搜索：项目与人才档案的搜索文档，FULLTEXT 索引使用 ngram 解析器切分中文
+-- 分词粒度由 ngram_token_size 决定（默认 2），需在建索引前于 MySQL 配置中设置
+CREATE TABLE IF NOT EXISTS `search_document` (
+  `id` bigint NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `doc_type` varchar(16) NOT NULL COMMENT '文档类型:project-项目,talent-人才档案',
+  `ref_id` int(11) NOT NULL COMMENT '项目ID或人才档案ID',
+  `title` varchar(200) NOT NULL DEFAULT '' COMMENT '项目名称或昵称',
+  `content` text NOT NULL COMMENT '项目详情，或自我评价与项目经历',
+  `skills` text NOT NULL COMMENT '技能，空格分隔',
+  `updated_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_search_document` (`doc_type`,`ref_id`),
+  FULLTEXT KEY `ft_search_all` (`title`,`content`,`skills`) /*!50100 WITH PARSER `ngram` */,
+  FULLTEXT KEY `ft_search_title` (`title`) /*!50100 WITH PARSER `ngram` */,
+  FULLTEXT KEY `ft_search_skills` (`skills`) /*!50100 WITH PARSER `ngram` */
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='全文搜索文档表';
+
+-- 回填已有项目
+INSERT INTO `search_document` (`doc_type`, `ref_id`, `title`, `content`, `skills`)
+SELECT 'project', `id`, `name`, COALESCE(`description`, ''), COALESCE(`skill_requirement`, '')
+FROM `project`
+ON DUPLICATE KEY UPDATE
+  `title` = VALUES(`title`), `content` = VALUES(`content`), `skills` = VALUES(`skills`);
+
+-- 回填已有人才档案，技能标签由 JSON 数组转为空格分隔
+INSERT INTO `search_document` (`doc_type`, `ref_id`, `title`, `content`, `skills`)
+SELECT 'talent', tp.`id`, COALESCE(u.`nickname`, ''),
+  TRIM(CONCAT(COALESCE(tp.`self_evaluation`, ''), '\n', COALESCE(tp.`project_experience`, ''))),
+  TRIM(REPLACE(REPLACE(REPLACE(REPLACE(COALESCE(tp.`skill_summary`, ''), '[', ''), ']', ''), '"', ''), ',', ' '))
+FROM `talent_profile` tp
+LEFT JOIN `user` u ON tp.`user_id` = u.`id`
+ON DUPLICATE KEY UPDATE
+  `title` = VALUES(`title`), `content` = VALUES(`content`), `skills` = VALUES(`skills`);
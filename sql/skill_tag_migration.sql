@@ -0,0 +1,208 @@
+-- 技能标签字典：分类、标签、同义词，以及项目和人才档案与标签的多对多关联
+-- 需要 MySQL 8.0（公共表表达式与 REGEXP_LIKE）
+CREATE TABLE IF NOT EXISTS `skill_category` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `name` varchar(30) NOT NULL COMMENT '分类名称',
+  `sort_order` int(11) NOT NULL DEFAULT '0' COMMENT '排序，越小越靠前',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_skill_category_name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='技能分类字典表';
+
+CREATE TABLE IF NOT EXISTS `skill_tag` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `category_id` int(11) NOT NULL COMMENT '技能分类ID',
+  `name` varchar(30) NOT NULL COMMENT '标签名称',
+  `status` tinyint(4) NOT NULL DEFAULT '1' COMMENT '状态:1-启用,0-停用',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_skill_tag_name` (`name`),
+  KEY `idx_skill_tag_category` (`category_id`,`status`),
+  CONSTRAINT `fk_skill_tag_category` FOREIGN KEY (`category_id`) REFERENCES `skill_category` (`id`) ON DELETE RESTRICT
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='技能标签字典表';
+
+CREATE TABLE IF NOT EXISTS `skill_tag_synonym` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `tag_id` int(11) NOT NULL COMMENT '技能标签ID',
+  `synonym` varchar(30) NOT NULL COMMENT '同义词',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_skill_tag_synonym` (`synonym`),
+  KEY `idx_skill_tag_synonym_tag` (`tag_id`),
+  CONSTRAINT `fk_skill_tag_synonym_tag` FOREIGN KEY (`tag_id`) REFERENCES `skill_tag` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='技能标签同义词表';
+
+CREATE TABLE IF NOT EXISTS `project_skill_tag` (
+  `project_id` int(11) NOT NULL COMMENT '项目ID',
+  `tag_id` int(11) NOT NULL COMMENT '技能标签ID',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`project_id`,`tag_id`),
+  KEY `idx_project_skill_tag_tag` (`tag_id`),
+  CONSTRAINT `fk_project_skill_tag_project` FOREIGN KEY (`project_id`) REFERENCES `project` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_project_skill_tag_tag` FOREIGN KEY (`tag_id`) REFERENCES `skill_tag` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='项目技能标签关联表';
+
+CREATE TABLE IF NOT EXISTS `talent_skill_tag` (
+  `talent_profile_id` int(11) NOT NULL COMMENT '人才档案ID',
+  `tag_id` int(11) NOT NULL COMMENT '技能标签ID',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  PRIMARY KEY (`talent_profile_id`,`tag_id`),
+  KEY `idx_talent_skill_tag_tag` (`tag_id`),
+  CONSTRAINT `fk_talent_skill_tag_profile` FOREIGN KEY (`talent_profile_id`) REFERENCES `talent_profile` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_talent_skill_tag_tag` FOREIGN KEY (`tag_id`) REFERENCES `skill_tag` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='人才档案技能标签关联表';
+
+-- 初始字典，可在后台继续维护
+INSERT IGNORE INTO `skill_category` (`name`, `sort_order`) VALUES
+  ('编程语言', 1),
+  ('前端开发', 2),
+  ('后端开发', 3),
+  ('移动开发', 4),
+  ('数据与人工智能', 5),
+  ('硬件与嵌入式', 6),
+  ('设计', 7),
+  ('产品与运营', 8),
+  ('学术与竞赛', 9);
+
+INSERT IGNORE INTO `skill_tag` (`category_id`, `name`)
+SELECT c.`id`, v.`name`
+FROM (
+  SELECT '编程语言' AS `category`, 'Java' AS `name`
+  UNION ALL SELECT '编程语言', 'Python'
+  UNION ALL SELECT '编程语言', 'C++'
+  UNION ALL SELECT '编程语言', 'C语言'
+  UNION ALL SELECT '编程语言', 'Go'
+  UNION ALL SELECT '编程语言', 'JavaScript'
+  UNION ALL SELECT '编程语言', 'TypeScript'
+  UNION ALL SELECT '编程语言', 'PHP'
+  UNION ALL SELECT '编程语言', 'Rust'
+  UNION ALL SELECT '编程语言', 'MATLAB'
+  UNION ALL SELECT '前端开发', '前端'
+  UNION ALL SELECT '前端开发', 'Vue'
+  UNION ALL SELECT '前端开发', 'React'
+  UNION ALL SELECT '前端开发', 'HTML/CSS'
+  UNION ALL SELECT '前端开发', '小程序'
+  UNION ALL SELECT '后端开发', '后端'
+  UNION ALL SELECT '后端开发', 'Spring Boot'
+  UNION ALL SELECT '后端开发', 'Node.js'
+  UNION ALL SELECT '后端开发', 'Django'
+  UNION ALL SELECT '后端开发', 'MySQL'
+  UNION ALL SELECT '后端开发', 'Redis'
+  UNION ALL SELECT '后端开发', 'Linux'
+  UNION ALL SELECT '后端开发', 'Docker'
+  UNION ALL SELECT '移动开发', 'Android'
+  UNION ALL SELECT '移动开发', 'iOS'
+  UNION ALL SELECT '移动开发', 'Flutter'
+  UNION ALL SELECT '数据与人工智能', '机器学习'
+  UNION ALL SELECT '数据与人工智能', '深度学习'
+  UNION ALL SELECT '数据与人工智能', '数据分析'
+  UNION ALL SELECT '数据与人工智能', '计算机视觉'
+  UNION ALL SELECT '数据与人工智能', '自然语言处理'
+  UNION ALL SELECT '数据与人工智能', 'PyTorch'
+  UNION ALL SELECT '数据与人工智能', '大模型'
+  UNION ALL SELECT '硬件与嵌入式', '嵌入式'
+  UNION ALL SELECT '硬件与嵌入式', '单片机'
+  UNION ALL SELECT '硬件与嵌入式', '电路设计'
+  UNION ALL SELECT '设计', 'UI设计'
+  UNION ALL SELECT '设计', '平面设计'
+  UNION ALL SELECT '设计', 'Figma'
+  UNION ALL SELECT '设计', 'Photoshop'
+  UNION ALL SELECT '设计', '视频剪辑'
+  UNION ALL SELECT '设计', '三维建模'
+  UNION ALL SELECT '产品与运营', '产品经理'
+  UNION ALL SELECT '产品与运营', '运营'
+  UNION ALL SELECT '产品与运营', '市场营销'
+  UNION ALL SELECT '产品与运营', '文案'
+  UNION ALL SELECT '学术与竞赛', '数学建模'
+  UNION ALL SELECT '学术与竞赛', '算法'
+  UNION ALL SELECT '学术与竞赛', '英语'
+  UNION ALL SELECT '学术与竞赛', '财务'
+) v
+JOIN `skill_category` c ON c.`name` = v.`category`;
+
+INSERT IGNORE INTO `skill_tag_synonym` (`tag_id`, `synonym`)
+SELECT t.`id`, v.`synonym`
+FROM (
+  SELECT 'Python' AS `tag`, 'py' AS `synonym`
+  UNION ALL SELECT 'C++', 'cpp'
+  UNION ALL SELECT 'Go', 'Golang'
+  UNION ALL SELECT 'JavaScript', 'JS'
+  UNION ALL SELECT 'TypeScript', 'TS'
+  UNION ALL SELECT '前端', '前端开发'
+  UNION ALL SELECT '前端', 'Web前端'
+  UNION ALL SELECT 'Vue', 'Vue.js'
+  UNION ALL SELECT 'Vue', 'Vue3'
+  UNION ALL SELECT 'React', 'React.js'
+  UNION ALL SELECT 'HTML/CSS', 'HTML'
+  UNION ALL SELECT 'HTML/CSS', 'CSS'
+  UNION ALL SELECT '小程序', '微信小程序'
+  UNION ALL SELECT '后端', '后端开发'
+  UNION ALL SELECT '后端', '服务端'
+  UNION ALL SELECT 'Spring Boot', 'SpringBoot'
+  UNION ALL SELECT 'Spring Boot', 'Spring'
+  UNION ALL SELECT 'Node.js', 'Node'
+  UNION ALL SELECT 'Node.js', 'NodeJS'
+  UNION ALL SELECT 'Android', '安卓'
+  UNION ALL SELECT '机器学习', 'Machine Learning'
+  UNION ALL SELECT '机器学习', 'ML'
+  UNION ALL SELECT '深度学习', 'Deep Learning'
+  UNION ALL SELECT '计算机视觉', 'CV'
+  UNION ALL SELECT '自然语言处理', 'NLP'
+  UNION ALL SELECT '大模型', 'LLM'
+  UNION ALL SELECT '嵌入式', '嵌入式开发'
+  UNION ALL SELECT '单片机', 'STM32'
+  UNION ALL SELECT '单片机', '51单片机'
+  UNION ALL SELECT '电路设计', 'PCB'
+  UNION ALL SELECT 'UI设计', 'UI'
+  UNION ALL SELECT 'UI设计', '界面设计'
+  UNION ALL SELECT 'Photoshop', 'PS'
+  UNION ALL SELECT '视频剪辑', '剪辑'
+  UNION ALL SELECT '视频剪辑', 'Premiere'
+  UNION ALL SELECT '三维建模', 'Blender'
+  UNION ALL SELECT '三维建模', '3D建模'
+  UNION ALL SELECT '产品经理', '产品设计'
+  UNION ALL SELECT '运营', '新媒体运营'
+  UNION ALL SELECT '运营', '内容运营'
+  UNION ALL SELECT '市场营销', '营销'
+  UNION ALL SELECT '市场营销', '推广'
+  UNION ALL SELECT '文案', '文案写作'
+  UNION ALL SELECT '文案', '写作'
+  UNION ALL SELECT '数学建模', '数模'
+  UNION ALL SELECT '算法', '算法竞赛'
+  UNION ALL SELECT '算法', 'ACM'
+  UNION ALL SELECT '财务', '财务分析'
+  UNION ALL SELECT '财务', '会计'
+) v
+JOIN `skill_tag` t ON t.`name` = v.`tag`;
+
+-- 从已有的自由文本中抽取标签，规则与 SkillTagService 一致：按标签名称或同义词匹配，
+-- 不区分大小写；词条首尾是字母或数字时要求相邻字符不是字母或数字（"Go" 不命中 "Google"）
+INSERT IGNORE INTO `project_skill_tag` (`project_id`, `tag_id`)
+WITH `term` AS (
+  SELECT t.`id` AS `tag_id`, t.`name` AS `term` FROM `skill_tag` t WHERE t.`status` = 1
+  UNION ALL
+  SELECT sy.`tag_id`, sy.`synonym` FROM `skill_tag_synonym` sy
+  JOIN `skill_tag` t ON sy.`tag_id` = t.`id` WHERE t.`status` = 1
+)
+SELECT DISTINCT p.`id`, term.`tag_id`
+FROM `project` p
+JOIN `term` ON REGEXP_LIKE(p.`skill_requirement`, CONCAT(
+  IF(term.`term` REGEXP '^[a-z0-9]', '(?<![a-z0-9])', ''), '\\Q', term.`term`, '\\E',
+  IF(term.`term` REGEXP '[a-z0-9]$', '(?![a-z0-9])', '')), 'i')
+WHERE p.`skill_requirement` IS NOT NULL AND p.`skill_requirement` <> '';
+
+-- 人才档案的技能是 JSON 数组，直接在序列化文本上匹配
+INSERT IGNORE INTO `talent_skill_tag` (`talent_profile_id`, `tag_id`)
+WITH `term` AS (
+  SELECT t.`id` AS `tag_id`, t.`name` AS `term` FROM `skill_tag` t WHERE t.`status` = 1
+  UNION ALL
+  SELECT sy.`tag_id`, sy.`synonym` FROM `skill_tag_synonym` sy
+  JOIN `skill_tag` t ON sy.`tag_id` = t.`id` WHERE t.`status` = 1
+)
+SELECT DISTINCT tp.`id`, term.`tag_id`
+FROM `talent_profile` tp
+JOIN `term` ON REGEXP_LIKE(tp.`skill_summary`, CONCAT(
+  IF(term.`term` REGEXP '^[a-z0-9]', '(?<![a-z0-9])', ''), '\\Q', term.`term`, '\\E',
+  IF(term.`term` REGEXP '[a-z0-9]$', '(?![a-z0-9])', '')), 'i')
+WHERE tp.`skill_summary` IS NOT NULL AND tp.`skill_summary` <> '';
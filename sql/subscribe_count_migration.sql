@@ -0,0 +1,8 @@
+-- 订阅次数：每次 accept 授权加 1，每次发送成功减 1，为 0 时不再发送
+-- 上线前已允许订阅的记录按剩余 1 次处理，其余记为 0
+UPDATE `subscribe`
+SET `subscribe_count` = IF(`status` IN (0, 2), 1, 0)
+WHERE `subscribe_count` IS NULL;
+
+ALTER TABLE `subscribe`
+  MODIFY COLUMN `subscribe_count` int(11) NOT NULL DEFAULT '0' COMMENT '剩余一次性订阅次数,每次授权+1,每次发送成功-1';
@@ -0,0 +1,3 @@
+-- 人才档案学历：与项目的学历要求对应，用于人才与项目的匹配推荐
+ALTER TABLE `talent_profile`
+  ADD COLUMN `education` tinyint(4) DEFAULT NULL COMMENT '学历:1-大专,2-本科,3-研究生' AFTER `mbti`;
@@ -0,0 +1,35 @@
+-- 用户权益台账
+CREATE TABLE IF NOT EXISTS `user_entitlement` (
+  `id` int(11) NOT NULL AUTO_INCREMENT COMMENT 'ID',
+  `user_id` int(11) NOT NULL COMMENT '用户ID',
+  `order_id` int(11) NOT NULL COMMENT '发放权益的订单ID',
+  `kind` varchar(32) NOT NULL COMMENT '权益类型:email_promotion-邮件推广,project_top-项目置顶,olive_branch-橄榄枝',
+  `total` int(11) NOT NULL COMMENT '发放数量(收件人数/天数/橄榄枝数)',
+  `used` int(11) NOT NULL DEFAULT '0' COMMENT '已使用数量',
+  `status` tinyint(4) NOT NULL DEFAULT '1' COMMENT '状态:1-有效,2-已撤销',
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+  `updated_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uk_order_id` (`order_id`),
+  KEY `idx_user_kind` (`user_id`,`kind`),
+  CONSTRAINT `fk_user_entitlement_order` FOREIGN KEY (`order_id`) REFERENCES `order` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `fk_user_entitlement_user` FOREIGN KEY (`user_id`) REFERENCES `user` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci COMMENT='用户权益台账';
+
+ALTER TABLE `product`
+    MODIFY COLUMN `config_json` json DEFAULT NULL COMMENT '权益配置,如{"kind":"email_promotion","recipients":50}';
+
+-- 现有服务权益商品原先按购买数量作为收件人数
+UPDATE `product`
+SET `config_json` = JSON_OBJECT('kind', 'email_promotion', 'recipients', 1)
+WHERE `type` = 2 AND `config_json` IS NULL;
+
+-- 为已支付的服务权益订单补记台账，已触发过推广的订单视为已用完
+INSERT INTO `user_entitlement` (`user_id`, `order_id`, `kind`, `total`, `used`, `status`)
+SELECT o.`user_id`, o.`id`, 'email_promotion', o.`quantity`,
+       IF(ep.`id` IS NULL, 0, o.`quantity`), 1
+FROM `order` o
+JOIN `product` p ON o.`product_id` = p.`id`
+LEFT JOIN `email_promotion` ep ON ep.`order_id` = o.`id`
+LEFT JOIN `user_entitlement` ue ON ue.`order_id` = o.`id`
+WHERE p.`type` = 2 AND o.`status` = 1 AND ue.`id` IS NULL;